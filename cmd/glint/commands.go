@@ -0,0 +1,234 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/config"
+	"github.com/darshan-rambhia/glint/internal/store"
+)
+
+// commands are the subcommands run as `glint <command> [flags]`. Each returns
+// a process exit code.
+var commands = map[string]func(args []string) int{
+	"export": runExport,
+	"import": runImport,
+	"db":     runDB,
+}
+
+// runCommand runs the named subcommand, or reports an unknown one.
+func runCommand(name string, args []string) int {
+	cmd, ok := commands[name]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "error: unknown command %q (available: %v)\n", name, names)
+		return 2
+	}
+	return cmd(args)
+}
+
+// dbFlags registers the flags that locate the database. The path is taken
+// from -db, then db_path in -config, then GLINT_DB_PATH, then the default.
+func dbFlags(fs *flag.FlagSet) func() (string, error) {
+	dbPath := fs.String("db", "", "path to the glint SQLite database")
+	configPath := fs.String("config", "", "path to glint.yml (used for db_path when -db is not set)")
+	return func() (string, error) {
+		if *dbPath != "" {
+			return *dbPath, nil
+		}
+		if *configPath != "" {
+			cfg, err := config.Load(*configPath)
+			if err != nil {
+				return "", fmt.Errorf("loading config (%s): %w", *configPath, err)
+			}
+			return cfg.DBPath, nil
+		}
+		if v := os.Getenv("GLINT_DB_PATH"); v != "" {
+			return v, nil
+		}
+		return "/data/glint.db", nil
+	}
+}
+
+// runExport writes the database to a portable archive.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: glint export [-db path | -config glint.yml] [-o archive.zip]\n\n")
+		fmt.Fprintf(fs.Output(), "Writes every table as JSON Lines to a zip archive. Use -o - for stdout.\n\n")
+		fs.PrintDefaults()
+	}
+	dbPath := dbFlags(fs)
+	out := fs.String("o", "glint-export-"+time.Now().Format("20060102-150405")+".zip", "output archive path, or - for stdout")
+	_ = fs.Parse(args)
+
+	path, err := dbPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	// Open read-only: exporting must not migrate, vacuum or back up the
+	// database it is reading.
+	st, err := store.OpenReadOnly(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: opening database: %s\n", err)
+		return 1
+	}
+	defer st.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: creating archive: %s\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := st.Export(w); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	if *out != "-" {
+		fmt.Fprintf(os.Stderr, "exported %s to %s\n", path, *out)
+	}
+	return 0
+}
+
+// runImport loads an archive written by `glint export` into the database.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: glint import [-db path | -config glint.yml] archive.zip\n\n")
+		fmt.Fprintf(fs.Output(), "Loads an export archive, replacing rows with the same key. Stop glint first.\n\n")
+		fs.PrintDefaults()
+	}
+	dbPath := dbFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	path, err := dbPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: opening archive: %s\n", err)
+		return 1
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: reading archive: %s\n", err)
+		return 1
+	}
+
+	st, err := store.New(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: opening database: %s\n", err)
+		return 1
+	}
+	defer st.Close()
+
+	counts, err := st.Import(f, info.Size())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	tables := make([]string, 0, len(counts))
+	for t := range counts {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	for _, t := range tables {
+		fmt.Printf("%-24s %d rows\n", t, counts[t])
+	}
+	return 0
+}
+
+// runDB runs database maintenance subcommands (glint db check).
+func runDB(args []string) int {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Fprintf(os.Stderr, "Usage: glint db check [-db path | -config glint.yml]\n")
+		return 2
+	}
+	return runDBCheck(args[1:])
+}
+
+// runDBCheck runs an integrity check and prints per-table row counts. It
+// exits non-zero when the database is damaged.
+func runDBCheck(args []string) int {
+	fs := flag.NewFlagSet("db check", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: glint db check [-db path | -config glint.yml]\n\n")
+		fmt.Fprintf(fs.Output(), "Runs PRAGMA integrity_check and reports table row counts. The database is\nopened read-only and is not migrated.\n\n")
+		fs.PrintDefaults()
+	}
+	dbPath := dbFlags(fs)
+	_ = fs.Parse(args)
+
+	path, err := dbPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	// Open read-only so a damaged database is inspected as it is, without
+	// the migrations and vacuum that store.New would run first.
+	st, err := store.OpenReadOnly(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	defer st.Close()
+
+	problems, err := st.IntegrityCheck()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	counts, err := st.RowCounts()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	size, free, err := st.Size()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+
+	fmt.Printf("database: %s (%d bytes, %d free)\n\n", path, size, free)
+	tables := make([]string, 0, len(counts))
+	for t := range counts {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	for _, t := range tables {
+		fmt.Printf("%-24s %d rows\n", t, counts[t])
+	}
+	fmt.Println()
+
+	if len(problems) > 0 {
+		fmt.Printf("integrity: FAILED (%d problems)\n", len(problems))
+		for _, p := range problems {
+			fmt.Printf("  %s\n", p)
+		}
+		return 1
+	}
+	fmt.Println("integrity: ok")
+	return 0
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"runtime"
 	"runtime/debug"
 	"strings"
@@ -22,6 +23,8 @@ import (
 	"github.com/darshan-rambhia/glint/internal/collector"
 	"github.com/darshan-rambhia/glint/internal/config"
 	"github.com/darshan-rambhia/glint/internal/notify"
+	"github.com/darshan-rambhia/glint/internal/sink"
+	"github.com/darshan-rambhia/glint/internal/smart"
 	"github.com/darshan-rambhia/glint/internal/store"
 	"github.com/darshan-rambhia/glint/templates"
 	"golang.org/x/sync/errgroup"
@@ -108,6 +111,11 @@ func runHealthcheck() int {
 }
 
 func main() {
+	// Subcommands (glint export, glint import, ...) take their own flags.
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	configPath := flag.String("config", "", "path to glint.yml config file")
 	showVersion := flag.Bool("version", false, "print version and exit")
 	healthcheck := flag.Bool("healthcheck", false, "probe the local /healthz endpoint and exit (0 healthy, 1 unhealthy)")
@@ -180,6 +188,16 @@ func main() {
 	}
 	defer st.Close()
 
+	// Forward snapshots to external time-series databases
+	for _, mcfg := range cfg.MetricsSinks {
+		switch mcfg.Type {
+		case "influx":
+			st.AddSink(sink.NewInflux(mcfg.URL, mcfg.Headers))
+		case "remote_write":
+			st.AddSink(sink.NewRemoteWrite(mcfg.URL, mcfg.Headers))
+		}
+	}
+
 	// Initialize cache
 	c := cache.New()
 
@@ -196,6 +214,7 @@ func main() {
 	g, ctx := errgroup.WithContext(sigCtx)
 
 	// Start PVE collectors
+	rules := smartRules(cfg.SMART)
 	for _, pveCfg := range cfg.PVE {
 		collCfg := collector.PVEConfig{
 			Name:             pveCfg.Name,
@@ -205,6 +224,9 @@ func main() {
 			Insecure:         pveCfg.Insecure,
 			PollInterval:     pveCfg.PollInterval.Duration,
 			DiskPollInterval: pveCfg.DiskPollInterval.Duration,
+			DiskMissingPolls: pveCfg.DiskMissingPolls,
+			SMARTRules:       rules,
+			HistoryWindow:    time.Duration(cfg.HistoryHours) * time.Hour,
 		}
 		if collCfg.PollInterval == 0 {
 			collCfg.PollInterval = 15 * time.Second
@@ -212,6 +234,9 @@ func main() {
 		if collCfg.DiskPollInterval == 0 {
 			collCfg.DiskPollInterval = 1 * time.Hour
 		}
+		if collCfg.DiskMissingPolls == 0 {
+			collCfg.DiskMissingPolls = 2
+		}
 
 		// Register PVE instance
 		if err := st.UpsertPVEInstance(pveCfg.Name, pveCfg.Host, false, ""); err != nil {
@@ -219,9 +244,9 @@ func main() {
 		}
 
 		pveCollector := collector.NewPVECollector(collCfg, pool, c, st)
-		g.Go(func() error { return collector.Run(ctx, pveCollector) })
 
-		// Start temperature collector if SSH configured
+		// With SSH configured, read the SMART self-test logs the PVE API omits
+		// and start a temperature collector.
 		if pveCfg.SSH != nil {
 			sshCfg := collector.SSHConfig{
 				Host:           pveCfg.SSH.Host,
@@ -229,6 +254,13 @@ func main() {
 				KeyPath:        pveCfg.SSH.KeyPath,
 				KnownHostsFile: pveCfg.SSH.KnownHostsFile,
 			}
+			smartShell, err := collector.NewSMARTShell(pveCfg.Name, sshCfg)
+			if err != nil {
+				slog.Error("failed to create SMART reader", "instance", pveCfg.Name, "error", err)
+			} else {
+				pveCollector.SetSMARTShell(smartShell)
+			}
+
 			// Temperature collector runs for the first discovered node
 			// In a real multi-node setup, we'd create one per node
 			tempCollector, err := collector.NewTempCollector(pveCfg.Name, pveCfg.Name, sshCfg, c)
@@ -238,6 +270,7 @@ func main() {
 				g.Go(func() error { return collector.Run(ctx, tempCollector) })
 			}
 		}
+		g.Go(func() error { return collector.Run(ctx, pveCollector) })
 	}
 
 	// Start PBS collectors
@@ -265,9 +298,21 @@ func main() {
 	}
 
 	// Start pruner
-	pruner := store.NewPruner(st, store.DefaultRetention())
+	retention := retentionConfig(cfg)
+	st.SetRetention(retention)
+	pruner := store.NewPruner(st, retention)
 	g.Go(func() error { return pruner.Run(ctx) })
 
+	// Start scheduled database backups
+	if b := cfg.DBBackup; b != nil {
+		backups := store.NewBackupScheduler(st, store.BackupConfig{
+			Dir:      b.Dir,
+			Interval: b.Interval.Duration,
+			Keep:     b.Keep,
+		})
+		g.Go(func() error { return backups.Run(ctx) })
+	}
+
 	// Build notification providers
 	var providers []notify.Provider
 	for _, ncfg := range cfg.Notifications {
@@ -304,16 +349,71 @@ func main() {
 			alertCfg.BackupStale.Severity = cfg.Alerts.BackupStale.Severity
 		}
 	}
+	if cfg.Alerts.DiskDegrading != nil && cfg.Alerts.DiskDegrading.Severity != "" {
+		alertCfg.DiskDegrading.Severity = cfg.Alerts.DiskDegrading.Severity
+	}
+	if cfg.Alerts.DiskSelfTestFailed != nil && cfg.Alerts.DiskSelfTestFailed.Severity != "" {
+		alertCfg.DiskSelfTestFailed.Severity = cfg.Alerts.DiskSelfTestFailed.Severity
+	}
+	if cfg.Alerts.DiskNoLongTest != nil && cfg.Alerts.DiskNoLongTest.Severity != "" {
+		alertCfg.DiskNoLongTest.Severity = cfg.Alerts.DiskNoLongTest.Severity
+	}
+	if cfg.Alerts.DiskMissing != nil && cfg.Alerts.DiskMissing.Severity != "" {
+		alertCfg.DiskMissing.Severity = cfg.Alerts.DiskMissing.Severity
+	}
+	if cfg.Alerts.DiskEndurance != nil {
+		alertCfg.DiskEndurance.Threshold = cfg.Alerts.DiskEndurance.Months
+		if cfg.Alerts.DiskEndurance.Severity != "" {
+			alertCfg.DiskEndurance.Severity = cfg.Alerts.DiskEndurance.Severity
+		}
+	}
+	if a := cfg.Alerts.DiskTempHigh; a != nil {
+		t := alertCfg.DiskTempHigh
+		if a.HDD > 0 {
+			t.HDD = a.HDD
+		}
+		if a.SSD > 0 {
+			t.SSD = a.SSD
+		}
+		if a.NVMe > 0 {
+			t.NVMe = a.NVMe
+		}
+		if a.Duration.Duration > 0 {
+			t.Duration = a.Duration.Duration
+		}
+		if a.Severity != "" {
+			t.Severity = a.Severity
+		}
+	}
 	if cfg.Alerts.DatastoreFull != nil {
 		alertCfg.DatastoreFull.Threshold = cfg.Alerts.DatastoreFull.Threshold
 		if cfg.Alerts.DatastoreFull.Severity != "" {
 			alertCfg.DatastoreFull.Severity = cfg.Alerts.DatastoreFull.Severity
 		}
 	}
+	if cfg.Alerts.CephHealth != nil && cfg.Alerts.CephHealth.Severity != "" {
+		alertCfg.CephHealth.Severity = cfg.Alerts.CephHealth.Severity
+	}
+	if cfg.Alerts.ClusterQuorum != nil && cfg.Alerts.ClusterQuorum.Severity != "" {
+		alertCfg.ClusterQuorum.Severity = cfg.Alerts.ClusterQuorum.Severity
+	}
+	if cfg.Alerts.HAResourceError != nil && cfg.Alerts.HAResourceError.Severity != "" {
+		alertCfg.HAResourceError.Severity = cfg.Alerts.HAResourceError.Severity
+	}
+	if cfg.Alerts.PVEBackupFailed != nil && cfg.Alerts.PVEBackupFailed.Severity != "" {
+		alertCfg.PVEBackupFailed.Severity = cfg.Alerts.PVEBackupFailed.Severity
+	}
+	if cfg.Alerts.PVETaskFailed != nil && cfg.Alerts.PVETaskFailed.Severity != "" {
+		alertCfg.PVETaskFailed.Severity = cfg.Alerts.PVETaskFailed.Severity
+	}
+	alertCfg.GuestNetHigh = throughputAlert(cfg.Alerts.GuestNetHigh)
+	alertCfg.GuestDiskIOHigh = throughputAlert(cfg.Alerts.GuestDiskIOHigh)
 
 	// Sync the backup-stale threshold to the UI so the dashboard chip matches
 	// the alerter: the chip shows "Stale" exactly when an alert would fire.
 	templates.BackupStaleHours = alertCfg.BackupStale.MaxAge.Hours()
+	templates.EnduranceLowMonths = alertCfg.DiskEndurance.Threshold
+	templates.DiskTempLimit = alertCfg.DiskTempHigh.Limit
 
 	a := alerter.NewAlerter(c, st, providers, alertCfg)
 	g.Go(func() error { return a.Run(ctx) })
@@ -327,6 +427,7 @@ func main() {
 		"pve_instances", len(cfg.PVE),
 		"pbs_instances", len(cfg.PBS),
 		"notifications", len(providers),
+		"metrics_sinks", len(cfg.MetricsSinks),
 	)
 
 	// Block until a shutdown signal arrives, then log it and unregister the
@@ -342,6 +443,90 @@ func main() {
 	slog.Info("glint stopped gracefully")
 }
 
+// retentionConfig builds the store retention from the config file. Node and
+// guest snapshots follow history_hours unless set explicitly; other unset
+// fields keep the store defaults.
+func retentionConfig(cfg *config.Config) store.RetentionConfig {
+	r := store.DefaultRetention()
+	r.NodeSnapshots = time.Duration(cfg.HistoryHours) * time.Hour
+	r.GuestSnapshots = r.NodeSnapshots
+
+	c := cfg.Retention
+	for _, f := range []struct {
+		dst *time.Duration
+		src config.Duration
+	}{
+		{&r.NodeSnapshots, c.NodeSnapshots},
+		{&r.GuestSnapshots, c.GuestSnapshots},
+		{&r.SMARTSnapshots, c.SMARTSnapshots},
+		{&r.BackupSnapshots, c.BackupSnapshots},
+		{&r.DatastoreSnapshots, c.DatastoreSnapshots},
+		{&r.AlertLog, c.AlertLog},
+		{&r.PVETasks, c.PVETasks},
+		{&r.Rollups5m, c.Rollups5m},
+		{&r.Rollups1h, c.Rollups1h},
+	} {
+		if f.src.Duration > 0 {
+			*f.dst = f.src.Duration
+		}
+	}
+	return r
+}
+
+// smartRules converts the SMART scoring overrides from the config file, or
+// returns nil when none are configured. Match patterns were checked by
+// config validation.
+func smartRules(c config.SMARTConfig) *smart.Rules {
+	if len(c.Attributes) == 0 && len(c.Models) == 0 {
+		return nil
+	}
+	r := &smart.Rules{Attributes: smartAttrRules(c.Attributes)}
+	for _, m := range c.Models {
+		r.Models = append(r.Models, smart.ModelRules{
+			Model:      regexp.MustCompile(m.Match),
+			Attributes: smartAttrRules(m.Attributes),
+		})
+	}
+	return r
+}
+
+func smartAttrRules(attrs map[int]config.SMARTAttrRule) map[int]smart.AttrRule {
+	rules := make(map[int]smart.AttrRule, len(attrs))
+	for id, a := range attrs {
+		rule := smart.AttrRule{Critical: a.Critical, Ignore: a.Ignore}
+		for _, b := range a.Buckets {
+			high := int64(1<<62 - 1)
+			if b.High != nil {
+				high = *b.High
+			}
+			rule.Buckets = append(rule.Buckets, smart.Bucket{Low: b.Low, High: high, AnnualFailureRate: b.Rate})
+		}
+		rules[id] = rule
+	}
+	return rules
+}
+
+// throughputAlert converts an opt-in guest throughput alert from the config
+// file, or returns nil when it is not configured.
+func throughputAlert(c *config.AlertThroughput) *alerter.ThresholdAlert {
+	if c == nil {
+		return nil
+	}
+	a := &alerter.ThresholdAlert{
+		Threshold: c.Threshold,
+		Duration:  c.Duration.Duration,
+		Severity:  c.Severity,
+		Cooldown:  1 * time.Hour,
+	}
+	if a.Duration == 0 {
+		a.Duration = 10 * time.Minute
+	}
+	if a.Severity == "" {
+		a.Severity = "warning"
+	}
+	return a
+}
+
 // printListenURLs prints the local and network URLs glint is reachable on.
 func printListenURLs(listenAddr string) {
 	host, port, err := net.SplitHostPort(listenAddr)
@@ -32,9 +32,244 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/disks": {
+            "get": {
+                "description": "Returns every disk with its SMART health: status bitfield, attributes with failure rates, trends, SSD endurance, self-test log and failure risk. Sorted by instance, node and device path.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Disks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Disk"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/disks/risk": {
+            "get": {
+                "description": "Returns every disk with a risk score, most likely to fail first. The score is the estimated annual failure probability combined from SMART attribute failure rates, age, error counter trends, health, self-tests and SSD wear, with a breakdown of the factors. replace_soon marks disks at or above 10%.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Disk failure risk ranking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Maximum number of disks (0 for all)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.diskRiskEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/disks/{wwn}": {
+            "get": {
+                "description": "Returns one disk with its SMART health, as listed by /api/disks",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Disk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Disk WWN identifier",
+                        "name": "wwn",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Disk"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/disks/{wwn}/history": {
+            "get": {
+                "description": "Returns the SMART snapshots recorded for a disk, oldest first: health, status bitfield, temperature, power-on hours, wearout and attributes. History is kept for the smart_snapshots retention.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Disk SMART history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Disk WWN identifier",
+                        "name": "wwn",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 168,
+                        "description": "Hours of history (1-8760)",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated attribute IDs to include (default all)",
+                        "name": "attr",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DiskSnapshot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid attr",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/export": {
+            "get": {
+                "description": "Downloads one database table as CSV with a header row. Tables with a ts column are limited to rows at or after since.",
+                "produces": [
+                    "text/csv"
+                ],
+                "summary": "Export a table as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table name (e.g. guest_snapshots, smart_snapshots)",
+                        "name": "table",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Unix timestamp; only rows with ts \u003e= since",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown table or invalid since",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sparkline/disk/{wwn}": {
+            "get": {
+                "description": "Returns JSON array of time-series data points for a disk SMART metric",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Disk sparkline data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Disk WWN identifier",
+                        "name": "wwn",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 168,
+                        "description": "Hours of history (1-8760)",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "temperature",
+                        "description": "Metric name (temperature, wearout, power_on_hours)",
+                        "name": "metric",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SparklinePoint"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/api/sparkline/guest/{instance}/{vmid}": {
             "get": {
-                "description": "Returns JSON array of CPU usage data points for a guest",
+                "description": "Returns JSON array of data points for a guest metric. Throughput metrics are in bytes/sec.",
                 "produces": [
                     "application/json"
                 ],
@@ -53,6 +288,13 @@ const docTemplate = `{
                         "name": "vmid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "cpu",
+                        "description": "Metric name (cpu, memory, netin, netout, diskread, diskwrite)",
+                        "name": "metric",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -105,14 +347,14 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "default": 24,
-                        "description": "Hours of history (1-168)",
+                        "description": "Hours of history (1-8760); spans over 48h read 5-minute or hourly rollups",
                         "name": "hours",
                         "in": "query"
                     },
                     {
                         "type": "string",
                         "default": "cpu",
-                        "description": "Metric name (cpu, memory)",
+                        "description": "Metric name (cpu, memory, netin, netout, psi_cpu, psi_io, psi_mem)",
                         "name": "metric",
                         "in": "query"
                     }
@@ -136,6 +378,23 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/widget": {
+            "get": {
+                "description": "Returns cluster summary statistics for homepage-style dashboard widgets (Homepage, Glance, Dashy, etc.)",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Dashboard widget data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.widgetResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/fragments/backups": {
             "get": {
                 "description": "Returns HTML fragment of PBS backup status for htmx",
@@ -153,6 +412,23 @@ const docTemplate = `{
                 }
             }
         },
+        "/fragments/ceph": {
+            "get": {
+                "description": "Returns HTML fragment of Ceph health, OSD, pool and PG status for htmx",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "Ceph health fragment",
+                "responses": {
+                    "200": {
+                        "description": "HTML fragment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/fragments/disk/{wwn}": {
             "get": {
                 "description": "Returns HTML fragment with SMART details for a specific disk",
@@ -202,30 +478,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/fragments/guests": {
+        "/fragments/events": {
             "get": {
-                "description": "Returns HTML fragment of guest (LXC/QEMU) table for htmx",
+                "description": "Returns HTML fragment of PBS and PVE task events (last 7 days) for htmx",
                 "produces": [
                     "text/html"
                 ],
-                "summary": "Guest table fragment",
-                "responses": {
-                    "200": {
-                        "description": "HTML fragment",
-                        "schema": {
-                            "type": "string"
-                        }
+                "summary": "Events fragment",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pbs",
+                            "pve"
+                        ],
+                        "type": "string",
+                        "description": "Filter by source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by task type (e.g. qmigrate)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "failed",
+                            "running"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
                     }
-                }
-            }
-        },
-        "/fragments/nodes": {
-            "get": {
-                "description": "Returns HTML fragment of node status cards for htmx",
-                "produces": [
-                    "text/html"
                 ],
-                "summary": "Node cards fragment",
                 "responses": {
                     "200": {
                         "description": "HTML fragment",
@@ -236,13 +523,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/fragments/sparkline/guest/{instance}/{vmid}": {
+        "/fragments/guest/{instance}/{vmid}": {
             "get": {
-                "description": "Returns HTML/SVG sparkline visualization for a guest's CPU usage",
+                "description": "Returns HTML fragment with history charts, configuration, backups and recent tasks for a guest",
                 "produces": [
                     "text/html"
                 ],
-                "summary": "Guest sparkline SVG fragment",
+                "summary": "Guest detail fragment",
                 "parameters": [
                     {
                         "type": "string",
@@ -257,7 +544,169 @@ const docTemplate = `{
                         "name": "vmid",
                         "in": "path",
                         "required": true
-                    }
+                    },
+                    {
+                        "type": "integer",
+                        "default": 24,
+                        "description": "Hours of history (1-8760); spans over 48h read 5-minute or hourly rollups",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML fragment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid VMID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Guest not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/fragments/guests": {
+            "get": {
+                "description": "Returns HTML fragment of guest (LXC/QEMU) table for htmx",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "Guest table fragment",
+                "responses": {
+                    "200": {
+                        "description": "HTML fragment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/fragments/inventory": {
+            "get": {
+                "description": "Returns HTML fragment of every disk seen, its lifecycle events and slot history for htmx",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "Disk inventory fragment",
+                "responses": {
+                    "200": {
+                        "description": "HTML fragment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/fragments/nodes": {
+            "get": {
+                "description": "Returns HTML fragment of node status cards for htmx",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "Node cards fragment",
+                "responses": {
+                    "200": {
+                        "description": "HTML fragment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/fragments/sparkline/disk/{wwn}": {
+            "get": {
+                "description": "Returns HTML/SVG sparkline visualization for a disk SMART metric",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "Disk sparkline SVG fragment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Disk WWN identifier",
+                        "name": "wwn",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 168,
+                        "description": "Hours of history (1-8760)",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "temperature",
+                        "description": "Metric name (temperature, wearout, power_on_hours)",
+                        "name": "metric",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SVG sparkline HTML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/fragments/sparkline/guest/{instance}/{vmid}": {
+            "get": {
+                "description": "Returns HTML/SVG sparkline visualization for a guest metric",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "Guest sparkline SVG fragment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PVE instance name",
+                        "name": "instance",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Guest VMID",
+                        "name": "vmid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "cpu",
+                        "description": "Metric name (cpu, memory, netin, netout, diskread, diskwrite)",
+                        "name": "metric",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -306,14 +755,14 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "default": 24,
-                        "description": "Hours of history (1-168)",
+                        "description": "Hours of history (1-8760); spans over 48h read 5-minute or hourly rollups",
                         "name": "hours",
                         "in": "query"
                     },
                     {
                         "type": "string",
                         "default": "cpu",
-                        "description": "Metric name (cpu, memory)",
+                        "description": "Metric name (cpu, memory, netin, netout, psi_cpu, psi_io, psi_mem)",
                         "name": "metric",
                         "in": "query"
                     }
@@ -336,7 +785,7 @@ const docTemplate = `{
         },
         "/healthz": {
             "get": {
-                "description": "Returns service health status and collector poll times",
+                "description": "Returns service health status, collector poll times and database size",
                 "produces": [
                     "application/json"
                 ],
@@ -354,6 +803,460 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "api.diskRiskEntry": {
+            "type": "object",
+            "properties": {
+                "dev_path": {
+                    "type": "string"
+                },
+                "disk_type": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "node": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "replace_soon": {
+                    "type": "boolean"
+                },
+                "risk": {
+                    "$ref": "#/definitions/model.DiskRisk"
+                },
+                "serial": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "wwn": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.widgetBackupStats": {
+            "type": "object",
+            "properties": {
+                "last_backup_time": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.widgetCPUStats": {
+            "type": "object",
+            "properties": {
+                "usage_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.widgetDiskStats": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "hot": {
+                    "type": "integer"
+                },
+                "hottest_temp": {
+                    "description": "Temperature: the hottest disk reading, and how many disks are at or\nabove the limit for their type.",
+                    "type": "integer"
+                },
+                "passed": {
+                    "type": "integer"
+                },
+                "replace_soon": {
+                    "description": "Disks whose annual failure probability is at or above the replace\nsoon level.",
+                    "type": "integer"
+                },
+                "shortest_life_months": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unknown": {
+                    "type": "integer"
+                },
+                "warning": {
+                    "type": "integer"
+                },
+                "write_tb_per_day": {
+                    "description": "SSD endurance: host writes summed across SSDs, and the shortest\nprojected life of any SSD (omitted when none has a projection).",
+                    "type": "number"
+                }
+            }
+        },
+        "api.widgetGuestStats": {
+            "type": "object",
+            "properties": {
+                "lxc": {
+                    "type": "integer"
+                },
+                "running": {
+                    "type": "integer"
+                },
+                "stopped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "vms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.widgetMemStats": {
+            "type": "object",
+            "properties": {
+                "total_bytes": {
+                    "type": "integer"
+                },
+                "usage_pct": {
+                    "type": "number"
+                },
+                "used_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.widgetNodeStats": {
+            "type": "object",
+            "properties": {
+                "offline": {
+                    "type": "integer"
+                },
+                "online": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.widgetResponse": {
+            "type": "object",
+            "properties": {
+                "backups": {
+                    "$ref": "#/definitions/api.widgetBackupStats"
+                },
+                "cpu": {
+                    "$ref": "#/definitions/api.widgetCPUStats"
+                },
+                "disks": {
+                    "$ref": "#/definitions/api.widgetDiskStats"
+                },
+                "guests": {
+                    "$ref": "#/definitions/api.widgetGuestStats"
+                },
+                "memory": {
+                    "$ref": "#/definitions/api.widgetMemStats"
+                },
+                "nodes": {
+                    "$ref": "#/definitions/api.widgetNodeStats"
+                },
+                "throughput": {
+                    "$ref": "#/definitions/api.widgetThroughputStats"
+                }
+            }
+        },
+        "api.widgetThroughputStats": {
+            "type": "object",
+            "properties": {
+                "disk_read_bytes_per_sec": {
+                    "type": "number"
+                },
+                "disk_write_bytes_per_sec": {
+                    "type": "number"
+                },
+                "net_in_bytes_per_sec": {
+                    "type": "number"
+                },
+                "net_out_bytes_per_sec": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.AttributeTrend": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "integer"
+                },
+                "delta_24h": {
+                    "type": "integer"
+                },
+                "delta_30d": {
+                    "type": "integer"
+                },
+                "delta_7d": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Disk": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SMARTAttribute"
+                    }
+                },
+                "dev_path": {
+                    "type": "string"
+                },
+                "disk_type": {
+                    "description": "\"hdd\", \"ssd\", \"nvme\"",
+                    "type": "string"
+                },
+                "endurance": {
+                    "description": "SSD/NVMe only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Endurance"
+                        }
+                    ]
+                },
+                "first_seen": {
+                    "type": "string"
+                },
+                "health": {
+                    "description": "\"PASSED\", \"FAILED\"",
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "node": {
+                    "type": "string"
+                },
+                "power_on_hours": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "description": "\"ata\", \"nvme\", \"scsi\"",
+                    "type": "string"
+                },
+                "risk": {
+                    "description": "nil when SMART data could not be read",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DiskRisk"
+                        }
+                    ]
+                },
+                "self_test_log": {
+                    "$ref": "#/definitions/model.SelfTestLog"
+                },
+                "serial": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "bitfield",
+                    "type": "integer"
+                },
+                "temperature": {
+                    "type": "integer"
+                },
+                "trends": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AttributeTrend"
+                    }
+                },
+                "wearout": {
+                    "type": "integer"
+                },
+                "wwn": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DiskRisk": {
+            "type": "object",
+            "properties": {
+                "annual_failure_pct": {
+                    "type": "number"
+                },
+                "factors": {
+                    "description": "largest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RiskFactor"
+                    }
+                }
+            }
+        },
+        "model.DiskSnapshot": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SMARTAttribute"
+                    }
+                },
+                "health": {
+                    "type": "string"
+                },
+                "power_on_hours": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "temperature": {
+                    "type": "integer"
+                },
+                "ts": {
+                    "type": "integer"
+                },
+                "wearout": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Endurance": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "type": "string"
+                },
+                "end_of_life": {
+                    "type": "string"
+                },
+                "wear_pct_per_day": {
+                    "type": "number"
+                },
+                "wear_used_pct": {
+                    "type": "integer"
+                },
+                "write_tb_per_day": {
+                    "description": "recent host write rate",
+                    "type": "number"
+                },
+                "written_tb": {
+                    "description": "lifetime host writes",
+                    "type": "number"
+                }
+            }
+        },
+        "model.RiskFactor": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pct": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SMARTAttribute": {
+            "type": "object",
+            "properties": {
+                "failure_rate": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "raw_string": {
+                    "type": "string"
+                },
+                "raw_value": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "threshold": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "integer"
+                },
+                "worst": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SelfTest": {
+            "type": "object",
+            "properties": {
+                "lba_first_error": {
+                    "type": "integer"
+                },
+                "lifetime_hours": {
+                    "type": "integer"
+                },
+                "result": {
+                    "description": "\"passed\", \"failed\", \"aborted\", \"in_progress\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "status text as reported by smartctl",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Time is when the test ran, estimated from the disk's power-on hours.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "\"short\", \"long\", \"conveyance\", \"selective\", \"offline\", \"vendor\"",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SelfTestLog": {
+            "type": "object",
+            "properties": {
+                "tests": {
+                    "description": "newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SelfTest"
+                    }
+                }
+            }
+        },
         "model.SparklinePoint": {
             "type": "object",
             "properties": {
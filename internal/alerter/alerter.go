@@ -16,12 +16,28 @@ import (
 
 // AlertConfig holds configuration for alert rules.
 type AlertConfig struct {
-	NodeCPUHigh     *ThresholdAlert `yaml:"node_cpu_high"`
-	NodeMemHigh     *ThresholdAlert `yaml:"node_mem_high"`
-	GuestDown       *GuestAlert     `yaml:"guest_down"`
-	BackupStale     *BackupAlert    `yaml:"backup_stale"`
-	DiskSmartFailed *SimpleAlert    `yaml:"disk_smart_failed"`
-	DatastoreFull   *ThresholdAlert `yaml:"datastore_full"`
+	NodeCPUHigh        *ThresholdAlert `yaml:"node_cpu_high"`
+	NodeMemHigh        *ThresholdAlert `yaml:"node_mem_high"`
+	GuestDown          *GuestAlert     `yaml:"guest_down"`
+	BackupStale        *BackupAlert    `yaml:"backup_stale"`
+	DiskSmartFailed    *SimpleAlert    `yaml:"disk_smart_failed"`
+	DiskDegrading      *SimpleAlert    `yaml:"disk_degrading"`
+	DiskEndurance      *ThresholdAlert `yaml:"disk_endurance_low"` // threshold in months
+	DiskTempHigh       *DiskTempAlert  `yaml:"disk_temp_high"`
+	DiskSelfTestFailed *SimpleAlert    `yaml:"disk_self_test_failed"`
+	DiskNoLongTest     *SimpleAlert    `yaml:"disk_no_long_self_test"`
+	DiskMissing        *SimpleAlert    `yaml:"disk_missing"`
+	DatastoreFull      *ThresholdAlert `yaml:"datastore_full"`
+	CephHealth         *SimpleAlert    `yaml:"ceph_health"`
+	ClusterQuorum      *SimpleAlert    `yaml:"cluster_quorum_lost"`
+	HAResourceError    *SimpleAlert    `yaml:"ha_resource_error"`
+	PVEBackupFailed    *SimpleAlert    `yaml:"pve_backup_failed"`
+	PVETaskFailed      *SimpleAlert    `yaml:"pve_task_failed"`
+
+	// Guest throughput alerts; thresholds are in MB/s. Disabled by default
+	// because sensible limits depend on the network and storage.
+	GuestNetHigh    *ThresholdAlert `yaml:"guest_net_high"`
+	GuestDiskIOHigh *ThresholdAlert `yaml:"guest_disk_io_high"`
 }
 
 // ThresholdAlert triggers when a value exceeds a threshold.
@@ -32,6 +48,31 @@ type ThresholdAlert struct {
 	Cooldown  time.Duration `yaml:"cooldown"`
 }
 
+// DiskTempAlert triggers when a disk stays at or above the temperature limit
+// (°C) for its type for Duration. Spinning disks tolerate less heat than
+// flash, so each type has its own limit.
+type DiskTempAlert struct {
+	HDD      float64       `yaml:"hdd"`
+	SSD      float64       `yaml:"ssd"`
+	NVMe     float64       `yaml:"nvme"`
+	Duration time.Duration `yaml:"duration"`
+	Severity string        `yaml:"severity"`
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+// Limit returns the temperature limit for a disk type. Disks of unknown type
+// get the HDD limit, the most conservative of the three.
+func (d *DiskTempAlert) Limit(diskType string) float64 {
+	switch diskType {
+	case "nvme":
+		return d.NVMe
+	case "ssd":
+		return d.SSD
+	default:
+		return d.HDD
+	}
+}
+
 // GuestAlert triggers when a guest is down for too long.
 type GuestAlert struct {
 	GracePeriod time.Duration `yaml:"grace_period"`
@@ -70,9 +111,42 @@ func DefaultAlertConfig() AlertConfig {
 		DiskSmartFailed: &SimpleAlert{
 			Severity: "critical", Cooldown: 6 * time.Hour,
 		},
+		DiskDegrading: &SimpleAlert{
+			Severity: "warning", Cooldown: 24 * time.Hour,
+		},
+		DiskEndurance: &ThresholdAlert{
+			Threshold: 6, Severity: "warning", Cooldown: 24 * time.Hour,
+		},
+		DiskTempHigh: &DiskTempAlert{
+			HDD: 50, SSD: 60, NVMe: 70, Duration: 1 * time.Hour, Severity: "warning", Cooldown: 6 * time.Hour,
+		},
+		DiskSelfTestFailed: &SimpleAlert{
+			Severity: "critical", Cooldown: 24 * time.Hour,
+		},
+		DiskNoLongTest: &SimpleAlert{
+			Severity: "warning", Cooldown: 24 * time.Hour,
+		},
+		DiskMissing: &SimpleAlert{
+			Severity: "critical", Cooldown: 24 * time.Hour,
+		},
 		DatastoreFull: &ThresholdAlert{
 			Threshold: 85, Severity: "warning", Cooldown: 6 * time.Hour,
 		},
+		CephHealth: &SimpleAlert{
+			Severity: "warning", Cooldown: 1 * time.Hour,
+		},
+		ClusterQuorum: &SimpleAlert{
+			Severity: "critical", Cooldown: 30 * time.Minute,
+		},
+		HAResourceError: &SimpleAlert{
+			Severity: "critical", Cooldown: 30 * time.Minute,
+		},
+		PVEBackupFailed: &SimpleAlert{
+			Severity: "warning", Cooldown: 6 * time.Hour,
+		},
+		PVETaskFailed: &SimpleAlert{
+			Severity: "warning", Cooldown: 24 * time.Hour,
+		},
 	}
 }
 
@@ -89,6 +163,9 @@ type Alerter struct {
 
 	// Track sustained conditions: maps alert key → first observed time
 	sustained map[string]time.Time
+
+	// Error counter values at the last disk_degrading alert: WWN → attribute ID → raw value
+	degradingAlerted map[string]map[int]int64
 }
 
 // NewAlerter creates a new alerter.
@@ -101,6 +178,8 @@ func NewAlerter(c *cache.Cache, s *store.Store, providers []notify.Provider, cfg
 		interval:  30 * time.Second,
 		lastFired: make(map[string]time.Time),
 		sustained: make(map[string]time.Time),
+
+		degradingAlerted: make(map[string]map[int]int64),
 	}
 }
 
@@ -216,6 +295,22 @@ func (a *Alerter) evaluate(ctx context.Context) {
 		}
 	}
 
+	// Guest throughput alerts
+	if a.config.GuestNetHigh != nil || a.config.GuestDiskIOHigh != nil {
+		for clusterID, guests := range snap.Guests {
+			for _, guest := range guests {
+				if a.config.GuestNetHigh != nil {
+					a.checkGuestRate(ctx, now, clusterID, guest, "net_in", "Net in", guest.NetInRate, a.config.GuestNetHigh)
+					a.checkGuestRate(ctx, now, clusterID, guest, "net_out", "Net out", guest.NetOutRate, a.config.GuestNetHigh)
+				}
+				if a.config.GuestDiskIOHigh != nil {
+					a.checkGuestRate(ctx, now, clusterID, guest, "disk_read", "Disk read", guest.DiskReadRate, a.config.GuestDiskIOHigh)
+					a.checkGuestRate(ctx, now, clusterID, guest, "disk_write", "Disk write", guest.DiskWriteRate, a.config.GuestDiskIOHigh)
+				}
+			}
+		}
+	}
+
 	// Backup stale alerts
 	if a.config.BackupStale != nil {
 		for pbsInstance, backups := range snap.Backups {
@@ -241,6 +336,101 @@ func (a *Alerter) evaluate(ctx context.Context) {
 		}
 	}
 
+	// PVE (vzdump) backup stale alerts: only guests covered by a job with at
+	// least one successful run in the task history are considered.
+	if a.config.BackupStale != nil {
+		for clusterID, backups := range snap.GuestBackups {
+			for vmid, gb := range backups {
+				if len(gb.Jobs) == 0 || gb.LastSuccess == 0 {
+					continue
+				}
+				age := now.Sub(time.Unix(gb.LastSuccess, 0))
+				if age > a.config.BackupStale.MaxAge {
+					name := guestName(snap, clusterID, vmid)
+					key := fmt.Sprintf("pve_backup_stale:%s/%d", clusterID, vmid)
+					a.fire(ctx, now, key, a.config.BackupStale.Cooldown, model.Notification{
+						AlertType: "backup_stale",
+						Severity:  a.config.BackupStale.Severity,
+						Title:     fmt.Sprintf("Backup Stale: %s (%d)", name, vmid),
+						Message:   fmt.Sprintf("[%s] %s (ID %d) last vzdump backup %.0fh ago", gb.Instance, name, vmid, age.Hours()),
+						Instance:  gb.Instance,
+						Subject:   name,
+						Timestamp: now,
+						Metadata: map[string]string{
+							"age":    fmt.Sprintf("%.0fh", age.Hours()),
+							"vmid":   fmt.Sprintf("%d", vmid),
+							"source": "vzdump",
+						},
+					})
+				}
+			}
+		}
+	}
+
+	// PVE (vzdump) backup failed alerts: the most recent run for the guest failed.
+	if a.config.PVEBackupFailed != nil {
+		for clusterID, backups := range snap.GuestBackups {
+			for vmid, gb := range backups {
+				if gb.LastFailure == 0 || gb.LastFailure < gb.LastSuccess {
+					continue
+				}
+				name := guestName(snap, clusterID, vmid)
+				key := fmt.Sprintf("pve_backup_failed:%s/%d", clusterID, vmid)
+				a.fire(ctx, now, key, a.config.PVEBackupFailed.Cooldown, model.Notification{
+					AlertType: "pve_backup_failed",
+					Severity:  a.config.PVEBackupFailed.Severity,
+					Title:     fmt.Sprintf("Backup Failed: %s (%d)", name, vmid),
+					Message:   fmt.Sprintf("[%s] vzdump backup of %s (ID %d) failed: %s", gb.Instance, name, vmid, gb.LastStatus),
+					Instance:  gb.Instance,
+					Subject:   name,
+					Timestamp: now,
+					Metadata: map[string]string{
+						"vmid":   fmt.Sprintf("%d", vmid),
+						"status": gb.LastStatus,
+					},
+				})
+			}
+		}
+	}
+
+	// PVE task failed alerts: one per failed task. Only tasks that finished
+	// within the cooldown window are considered so old failures still listed
+	// by /cluster/tasks do not re-fire. vzdump runs are reported by
+	// pve_backup_failed instead.
+	if a.config.PVETaskFailed != nil {
+		for _, tasks := range snap.PVETasks {
+			for _, t := range tasks {
+				if t.EndTime == nil || t.Type == "vzdump" || !pveTaskFailed(t.Status) {
+					continue
+				}
+				if now.Sub(time.Unix(*t.EndTime, 0)) > a.config.PVETaskFailed.Cooldown {
+					continue
+				}
+				subject := t.Type
+				if t.ID != "" {
+					subject = fmt.Sprintf("%s %s", t.Type, t.ID)
+				}
+				key := fmt.Sprintf("pve_task:%s/%s", t.Instance, t.UPID)
+				a.fire(ctx, now, key, a.config.PVETaskFailed.Cooldown, model.Notification{
+					AlertType: "pve_task_failed",
+					Severity:  a.config.PVETaskFailed.Severity,
+					Title:     fmt.Sprintf("Task Failed: %s on %s", subject, t.Node),
+					Message:   fmt.Sprintf("[%s] %s on %s by %s failed: %s", t.Instance, subject, t.Node, t.User, t.Status),
+					Instance:  t.Instance,
+					Subject:   subject,
+					Timestamp: now,
+					Metadata: map[string]string{
+						"node":   t.Node,
+						"type":   t.Type,
+						"user":   t.User,
+						"status": t.Status,
+						"upid":   t.UPID,
+					},
+				})
+			}
+		}
+	}
+
 	// Disk SMART alerts
 	if a.config.DiskSmartFailed != nil {
 		for wwn, disk := range snap.Disks {
@@ -276,6 +466,124 @@ func (a *Alerter) evaluate(ctx context.Context) {
 		}
 	}
 
+	// Disk degradation alerts: error counters growing over recent history.
+	// A counter stays in the 30d trend window long after it grew, so only
+	// growth past the value at the last alert fires again.
+	if a.config.DiskDegrading != nil {
+		for wwn := range a.degradingAlerted {
+			if disk, ok := snap.Disks[wwn]; !ok || disk.Status&model.StatusDegrading == 0 {
+				delete(a.degradingAlerted, wwn)
+			}
+		}
+		for wwn, disk := range snap.Disks {
+			if disk.Status&model.StatusDegrading == 0 {
+				continue
+			}
+			alerted := a.degradingAlerted[wwn]
+			grew := false
+			for _, t := range disk.Trends {
+				if last, ok := alerted[t.ID]; t.Growing() && (!ok || t.Current > last) {
+					grew = true
+				}
+			}
+			if !grew {
+				continue
+			}
+			key := fmt.Sprintf("disk_degrading:%s", wwn)
+			if !a.fire(ctx, now, key, a.config.DiskDegrading.Cooldown, model.Notification{
+				AlertType: "disk_degrading",
+				Severity:  a.config.DiskDegrading.Severity,
+				Title:     fmt.Sprintf("Disk Degrading: %s", disk.DevPath),
+				Message:   fmt.Sprintf("[%s/%s] %s (%s) error counters increasing: %s", disk.Instance, disk.Node, disk.DevPath, disk.Model, trendSummary(disk.Trends)),
+				Instance:  disk.Instance,
+				Subject:   disk.DevPath,
+				Timestamp: now,
+				Metadata:  map[string]string{"wwn": wwn, "model": disk.Model},
+			}) {
+				continue
+			}
+			alerted = make(map[int]int64, len(disk.Trends))
+			for _, t := range disk.Trends {
+				alerted[t.ID] = t.Current
+			}
+			a.degradingAlerted[wwn] = alerted
+		}
+	}
+
+	// SSD endurance alerts: projected end of rated write endurance
+	if a.config.DiskEndurance != nil {
+		for wwn, disk := range snap.Disks {
+			months, ok := disk.Endurance.MonthsLeft(now)
+			if !ok || months >= a.config.DiskEndurance.Threshold {
+				continue
+			}
+			e := disk.Endurance
+			msg := fmt.Sprintf("[%s/%s] %s (%s) projected to reach its rated endurance in %.1f months (%s), %d%% used",
+				disk.Instance, disk.Node, disk.DevPath, disk.Model, months, e.EndOfLife.Format("2006-01-02"), e.WearUsedPct)
+			if e.WriteTBPerDay != nil {
+				msg += fmt.Sprintf(", writing %.2f TB/day", *e.WriteTBPerDay)
+			}
+			key := fmt.Sprintf("disk_endurance:%s", wwn)
+			a.fire(ctx, now, key, a.config.DiskEndurance.Cooldown, model.Notification{
+				AlertType: "disk_endurance_low",
+				Severity:  a.config.DiskEndurance.Severity,
+				Title:     fmt.Sprintf("SSD Endurance Low: %s", disk.DevPath),
+				Message:   msg,
+				Instance:  disk.Instance,
+				Subject:   disk.DevPath,
+				Timestamp: now,
+				Metadata: map[string]string{
+					"wwn":         wwn,
+					"model":       disk.Model,
+					"months_left": fmt.Sprintf("%.1f", months),
+				},
+			})
+		}
+	}
+
+	// Disk temperature alerts: sustained heat across SMART polls
+	if a.config.DiskTempHigh != nil {
+		for wwn, disk := range snap.Disks {
+			a.checkDiskTemp(ctx, now, wwn, disk)
+		}
+	}
+
+	// Disk missing alerts: a known disk dropped out of its node's disk list
+	// and no new disk took its slot. Only removals within the cooldown window
+	// are considered, so a disk pulled on purpose does not alert forever.
+	if a.config.DiskMissing != nil {
+		for _, disks := range snap.Inventory {
+			for _, d := range disks {
+				if d.RemovedAt == nil || d.ReplacedBy != "" || now.Sub(*d.RemovedAt) > a.config.DiskMissing.Cooldown {
+					continue
+				}
+				key := fmt.Sprintf("disk_missing:%s", d.WWN)
+				a.fire(ctx, now, key, a.config.DiskMissing.Cooldown, model.Notification{
+					AlertType: "disk_missing",
+					Severity:  a.config.DiskMissing.Severity,
+					Title:     fmt.Sprintf("Disk Missing: %s on %s", d.DevPath, d.Node),
+					Message: fmt.Sprintf("[%s/%s] %s (%s, serial %s) has not been seen since %s",
+						d.Instance, d.Node, d.DevPath, d.Model, d.Serial, d.LastSeen.Format("2006-01-02 15:04")),
+					Instance:  d.Instance,
+					Subject:   d.DevPath,
+					Timestamp: now,
+					Metadata: map[string]string{
+						"wwn":    d.WWN,
+						"model":  d.Model,
+						"serial": d.Serial,
+					},
+				})
+			}
+		}
+	}
+
+	// Disk self-test alerts
+	if a.config.DiskSelfTestFailed != nil || a.config.DiskNoLongTest != nil {
+		for wwn, disk := range snap.Disks {
+			a.checkSelfTests(ctx, now, wwn, disk)
+		}
+	}
+
 	// Datastore full alerts
 	if a.config.DatastoreFull != nil {
 		for pbsInstance, datastores := range snap.Datastores {
@@ -311,6 +619,217 @@ func (a *Alerter) evaluate(ctx context.Context) {
 			}
 		}
 	}
+
+	// Ceph health alerts: one per active (unmuted) health check so that
+	// e.g. OSD_DOWN and POOL_NEARFULL are deduplicated independently.
+	if a.config.CephHealth != nil {
+		for instance, ceph := range snap.Ceph {
+			for _, check := range ceph.Checks {
+				if check.Muted {
+					continue
+				}
+				severity := a.config.CephHealth.Severity
+				if check.Severity == "HEALTH_ERR" {
+					severity = "critical"
+				}
+				key := fmt.Sprintf("ceph:%s/%s", instance, check.Code)
+				a.fire(ctx, now, key, a.config.CephHealth.Cooldown, model.Notification{
+					AlertType: "ceph_health",
+					Severity:  severity,
+					Title:     fmt.Sprintf("Ceph %s: %s", check.Severity, check.Code),
+					Message:   fmt.Sprintf("[%s] Ceph %s: %s", instance, check.Code, check.Summary),
+					Instance:  instance,
+					Subject:   check.Code,
+					Timestamp: now,
+					Metadata: map[string]string{
+						"health":       ceph.Health,
+						"check_health": check.Severity,
+					},
+				})
+			}
+		}
+	}
+	// Cluster quorum alerts
+	if a.config.ClusterQuorum != nil {
+		for instance, cl := range snap.Clusters {
+			if !cl.Quorate {
+				key := fmt.Sprintf("cluster_quorum:%s", instance)
+				a.fire(ctx, now, key, a.config.ClusterQuorum.Cooldown, model.Notification{
+					AlertType: "cluster_quorum_lost",
+					Severity:  a.config.ClusterQuorum.Severity,
+					Title:     fmt.Sprintf("Cluster Quorum Lost: %s", cl.Name),
+					Message:   fmt.Sprintf("[%s] Cluster %s is not quorate (%d/%d votes present)", instance, cl.Name, cl.PresentVotes, cl.ExpectedVotes),
+					Instance:  instance,
+					Subject:   cl.Name,
+					Timestamp: now,
+					Metadata: map[string]string{
+						"present_votes":  fmt.Sprintf("%d", cl.PresentVotes),
+						"expected_votes": fmt.Sprintf("%d", cl.ExpectedVotes),
+					},
+				})
+			}
+		}
+	}
+
+	// HA resource alerts
+	if a.config.HAResourceError != nil {
+		for instance, cl := range snap.Clusters {
+			for _, r := range cl.HAResources {
+				if r.State != "error" && r.State != "fence" {
+					continue
+				}
+				key := fmt.Sprintf("ha_resource:%s/%s", instance, r.SID)
+				a.fire(ctx, now, key, a.config.HAResourceError.Cooldown, model.Notification{
+					AlertType: "ha_resource_error",
+					Severity:  a.config.HAResourceError.Severity,
+					Title:     fmt.Sprintf("HA Resource %s: %s", r.State, r.SID),
+					Message:   fmt.Sprintf("[%s] HA resource %s is in state %s on %s", instance, r.SID, r.State, r.Node),
+					Instance:  instance,
+					Subject:   r.SID,
+					Timestamp: now,
+					Metadata: map[string]string{
+						"state": r.State,
+						"node":  r.Node,
+					},
+				})
+			}
+		}
+	}
+}
+
+// pveTaskFailed reports whether a finished PVE task status is a failure.
+// PVE reports "OK", "WARNINGS: n" or the error message.
+func pveTaskFailed(status string) bool {
+	return status != "" && status != "OK" && !strings.HasPrefix(status, "WARNINGS")
+}
+
+// guestName returns the guest's name from the snapshot, falling back to its VMID.
+func guestName(snap cache.CacheSnapshot, clusterID string, vmid int) string {
+	if g, ok := snap.Guests[clusterID][vmid]; ok && g.Name != "" {
+		return g.Name
+	}
+	return fmt.Sprintf("%d", vmid)
+}
+
+// checkGuestRate alerts when a guest throughput rate (bytes/sec) stays above
+// the configured MB/s threshold for the configured duration.
+func (a *Alerter) checkGuestRate(ctx context.Context, now time.Time, clusterID string, guest *model.Guest, direction, label string, rate float64, cfg *ThresholdAlert) {
+	alertType := "guest_net_high"
+	if strings.HasPrefix(direction, "disk") {
+		alertType = "guest_disk_io_high"
+	}
+	mbps := rate / (1 << 20)
+	a.checkSustainedThreshold(ctx, now,
+		fmt.Sprintf("guest_%s:%s/%d", direction, clusterID, guest.VMID),
+		mbps,
+		cfg,
+		model.Notification{
+			AlertType: alertType,
+			Severity:  cfg.Severity,
+			Title:     fmt.Sprintf("Guest %s High: %s (%d)", label, guest.Name, guest.VMID),
+			Message:   fmt.Sprintf("[%s] %s (ID %d) %s at %.1f MB/s (threshold %.0f MB/s)", guest.Instance, guest.Name, guest.VMID, strings.ToLower(label), mbps, cfg.Threshold),
+			Instance:  guest.Instance,
+			Subject:   guest.Name,
+			Timestamp: now,
+			Metadata: map[string]string{
+				"vmid":      fmt.Sprintf("%d", guest.VMID),
+				"direction": direction,
+				"value":     fmt.Sprintf("%.1f", mbps),
+			},
+		},
+	)
+}
+
+// checkDiskTemp fires disk_temp_high once a disk has read at or above the
+// limit for its type for the configured duration. Disks are polled far less
+// often than rules are evaluated, so the duration is measured between SMART
+// readings (disk.LastSeen) rather than evaluations: a single hot reading
+// never fires on its own, however long it stays in the cache.
+func (a *Alerter) checkDiskTemp(ctx context.Context, now time.Time, wwn string, disk *model.Disk) {
+	cfg := a.config.DiskTempHigh
+	key := fmt.Sprintf("disk_temp:%s", wwn)
+	limit := cfg.Limit(disk.DiskType)
+	if disk.Temperature == nil || float64(*disk.Temperature) < limit {
+		delete(a.sustained, key)
+		return
+	}
+	first, ok := a.sustained[key]
+	if !ok {
+		a.sustained[key] = disk.LastSeen
+		return
+	}
+	hotFor := disk.LastSeen.Sub(first)
+	if hotFor < cfg.Duration {
+		return
+	}
+	a.fire(ctx, now, key, cfg.Cooldown, model.Notification{
+		AlertType: "disk_temp_high",
+		Severity:  cfg.Severity,
+		Title:     fmt.Sprintf("Disk Temperature High: %s", disk.DevPath),
+		Message: fmt.Sprintf("[%s/%s] %s (%s) at %dC, at or above the %.0fC %s limit for %s",
+			disk.Instance, disk.Node, disk.DevPath, disk.Model, *disk.Temperature, limit, disk.DiskType, hotFor.Round(time.Minute)),
+		Instance:  disk.Instance,
+		Subject:   disk.DevPath,
+		Timestamp: now,
+		Metadata: map[string]string{
+			"wwn":       wwn,
+			"model":     disk.Model,
+			"disk_type": disk.DiskType,
+			"value":     fmt.Sprintf("%d", *disk.Temperature),
+			"threshold": fmt.Sprintf("%.0f", limit),
+		},
+	})
+}
+
+// checkSelfTests fires disk_self_test_failed when the disk's newest finished
+// self-test failed and disk_no_long_self_test when its log holds no completed
+// long test. Disks whose self-test log could not be read are skipped.
+func (a *Alerter) checkSelfTests(ctx context.Context, now time.Time, wwn string, disk *model.Disk) {
+	log := disk.SelfTestLog
+	if log == nil {
+		return
+	}
+
+	if cfg := a.config.DiskSelfTestFailed; cfg != nil {
+		if t := log.Latest(); t != nil && t.Result == "failed" {
+			msg := fmt.Sprintf("[%s/%s] %s (%s) %s self-test failed at %dh: %s",
+				disk.Instance, disk.Node, disk.DevPath, disk.Model, t.Type, t.LifetimeHours, t.Status)
+			meta := map[string]string{
+				"wwn":            wwn,
+				"model":          disk.Model,
+				"test_type":      t.Type,
+				"status":         t.Status,
+				"lifetime_hours": fmt.Sprintf("%d", t.LifetimeHours),
+			}
+			if t.LBAFirstError != nil {
+				msg += fmt.Sprintf(", first error at LBA %d", *t.LBAFirstError)
+				meta["lba"] = fmt.Sprintf("%d", *t.LBAFirstError)
+			}
+			a.fire(ctx, now, fmt.Sprintf("disk_self_test:%s", wwn), cfg.Cooldown, model.Notification{
+				AlertType: "disk_self_test_failed",
+				Severity:  cfg.Severity,
+				Title:     fmt.Sprintf("Disk Self-Test Failed: %s", disk.DevPath),
+				Message:   msg,
+				Instance:  disk.Instance,
+				Subject:   disk.DevPath,
+				Timestamp: now,
+				Metadata:  meta,
+			})
+		}
+	}
+
+	if cfg := a.config.DiskNoLongTest; cfg != nil && log.LatestLong() == nil {
+		a.fire(ctx, now, fmt.Sprintf("disk_no_long_test:%s", wwn), cfg.Cooldown, model.Notification{
+			AlertType: "disk_no_long_self_test",
+			Severity:  cfg.Severity,
+			Title:     fmt.Sprintf("Disk Never Long-Tested: %s", disk.DevPath),
+			Message:   fmt.Sprintf("[%s/%s] %s (%s) has no completed long self-test in its log", disk.Instance, disk.Node, disk.DevPath, disk.Model),
+			Instance:  disk.Instance,
+			Subject:   disk.DevPath,
+			Timestamp: now,
+			Metadata:  map[string]string{"wwn": wwn, "model": disk.Model},
+		})
+	}
 }
 
 func (a *Alerter) checkSustainedThreshold(ctx context.Context, now time.Time, key string, value float64, cfg *ThresholdAlert, notif model.Notification) {
@@ -327,9 +846,10 @@ func (a *Alerter) checkSustainedThreshold(ctx context.Context, now time.Time, ke
 	}
 }
 
-func (a *Alerter) fire(ctx context.Context, now time.Time, key string, cooldown time.Duration, notif model.Notification) {
+// fire sends notif unless key is still in cooldown, and reports whether it did.
+func (a *Alerter) fire(ctx context.Context, now time.Time, key string, cooldown time.Duration, notif model.Notification) bool {
 	if last, ok := a.lastFired[key]; ok && now.Sub(last) < cooldown {
-		return // still in cooldown
+		return false // still in cooldown
 	}
 	a.lastFired[key] = now
 
@@ -352,9 +872,28 @@ func (a *Alerter) fire(ctx context.Context, now time.Time, key string, cooldown
 		"subject", notif.Subject,
 		"title", notif.Title,
 	)
+	return true
 }
 
 // FormatSeverity returns an uppercase severity string for templates.
 func FormatSeverity(s string) string {
 	return strings.ToUpper(s)
 }
+
+// trendSummary describes each growing counter by its growth over the shortest
+// window in which it grew, e.g. "Reallocated Sectors Count +8 in 7d".
+func trendSummary(trends []model.AttributeTrend) string {
+	var parts []string
+	for _, t := range trends {
+		for _, w := range []struct {
+			label string
+			delta *int64
+		}{{"24h", t.Delta24h}, {"7d", t.Delta7d}, {"30d", t.Delta30d}} {
+			if w.delta != nil && *w.delta > 0 {
+				parts = append(parts, fmt.Sprintf("%s +%d in %s", t.Name, *w.delta, w.label))
+				break
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
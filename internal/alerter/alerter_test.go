@@ -58,6 +58,11 @@ func TestDefaultAlertConfig(t *testing.T) {
 	assert.NotNil(t, cfg.BackupStale)
 	assert.NotNil(t, cfg.DiskSmartFailed)
 	assert.NotNil(t, cfg.DatastoreFull)
+	assert.NotNil(t, cfg.CephHealth)
+	assert.NotNil(t, cfg.ClusterQuorum)
+	assert.NotNil(t, cfg.HAResourceError)
+	assert.NotNil(t, cfg.PVEBackupFailed)
+	assert.NotNil(t, cfg.PVETaskFailed)
 
 	assert.Equal(t, float64(90), cfg.NodeCPUHigh.Threshold)
 	assert.Equal(t, 5*time.Minute, cfg.NodeCPUHigh.Duration)
@@ -70,6 +75,12 @@ func TestDefaultAlertConfig(t *testing.T) {
 	assert.Equal(t, 36*time.Hour, cfg.BackupStale.MaxAge)
 	assert.Equal(t, "critical", cfg.DiskSmartFailed.Severity)
 	assert.Equal(t, float64(85), cfg.DatastoreFull.Threshold)
+	assert.Equal(t, "warning", cfg.CephHealth.Severity)
+	assert.Equal(t, 6*time.Hour, cfg.PVEBackupFailed.Cooldown)
+	assert.Equal(t, 24*time.Hour, cfg.PVETaskFailed.Cooldown)
+
+	assert.Nil(t, cfg.GuestNetHigh, "throughput alerts are opt-in")
+	assert.Nil(t, cfg.GuestDiskIOHigh)
 }
 
 func TestNewAlerter(t *testing.T) {
@@ -223,6 +234,44 @@ func TestEvaluate_GuestDown(t *testing.T) {
 	assert.Contains(t, p.sent[0].Message, "stopped")
 }
 
+func TestEvaluate_GuestThroughputHigh(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+	cfg.GuestNetHigh = &ThresholdAlert{Threshold: 50, Severity: "warning", Cooldown: time.Hour}
+	cfg.GuestDiskIOHigh = &ThresholdAlert{Threshold: 100, Severity: "critical", Cooldown: time.Hour}
+
+	a, p := newTestAlerter(t, c, cfg)
+
+	c.UpdateGuests("cluster1", map[int]*model.Guest{
+		100: {
+			Instance: "pve1", ClusterID: "cluster1", VMID: 100, Name: "seedbox", Status: "running",
+			NetInRate: 10 << 20, NetOutRate: 80 << 20, DiskReadRate: 20 << 20, DiskWriteRate: 150 << 20,
+		},
+	})
+
+	// First eval seeds sustained.
+	a.evaluate(context.Background())
+	assert.Empty(t, p.sent)
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 2)
+	byType := map[string]model.Notification{}
+	for _, n := range p.sent {
+		byType[n.AlertType] = n
+	}
+	assert.Contains(t, byType["guest_net_high"].Message, "net out at 80.0 MB/s")
+	assert.Equal(t, "net_out", byType["guest_net_high"].Metadata["direction"])
+	assert.Equal(t, "critical", byType["guest_disk_io_high"].Severity)
+	assert.Equal(t, "disk_write", byType["guest_disk_io_high"].Metadata["direction"])
+
+	// Dropping below the threshold clears the sustained state.
+	c.UpdateGuests("cluster1", map[int]*model.Guest{
+		100: {Instance: "pve1", ClusterID: "cluster1", VMID: 100, Name: "seedbox", Status: "running"},
+	})
+	a.evaluate(context.Background())
+	assert.NotContains(t, a.sustained, "guest_net_out:cluster1/100")
+}
+
 func TestEvaluate_GuestRunning(t *testing.T) {
 	c := cache.New()
 	cfg := DefaultAlertConfig()
@@ -410,6 +459,287 @@ func TestEvaluate_DiskSmartAndScrutiny(t *testing.T) {
 	assert.True(t, types["disk_scrutiny_warning"])
 }
 
+func TestEvaluate_DiskDegrading(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+
+	a, p := newTestAlerter(t, c, cfg)
+
+	day, week := int64(2), int64(8)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-deg": {
+			Instance: "pve1", Node: "node1", WWN: "wwn-deg",
+			DevPath: "/dev/sdf", Model: "WD Red", Health: "PASSED",
+			Status: model.StatusDegrading,
+			Trends: []model.AttributeTrend{
+				{ID: 5, Name: "Reallocated Sectors Count", Current: 8, Delta7d: &week, Delta30d: &week},
+				{ID: 199, Name: "UDMA CRC Error Count", Current: 3, Delta24h: &day},
+			},
+		},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "disk_degrading", p.sent[0].AlertType)
+	assert.Equal(t, "warning", p.sent[0].Severity)
+	assert.Contains(t, p.sent[0].Message, "Reallocated Sectors Count +8 in 7d, UDMA CRC Error Count +2 in 24h")
+}
+
+func TestEvaluate_DiskDegrading_OnlyOnNewGrowth(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	month := int64(1)
+	disk := func(current int64) *model.Disk {
+		return &model.Disk{
+			Instance: "pve1", Node: "node1", WWN: "wwn-deg", DevPath: "/dev/sdf", Model: "WD Red",
+			Status: model.StatusDegrading,
+			Trends: []model.AttributeTrend{{ID: 5, Name: "Reallocated Sectors Count", Current: current, Delta30d: &month}},
+		}
+	}
+	expireCooldown := func() { clear(a.lastFired) }
+
+	c.UpdateDisks(map[string]*model.Disk{"wwn-deg": disk(1)})
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+
+	// Growth during the cooldown fires once the cooldown ends
+	c.UpdateDisks(map[string]*model.Disk{"wwn-deg": disk(2)})
+	a.evaluate(context.Background())
+	assert.Len(t, p.sent, 1)
+	expireCooldown()
+	a.evaluate(context.Background())
+	assert.Len(t, p.sent, 2)
+
+	// The same increment is still inside the 30d window after the cooldown
+	expireCooldown()
+	a.evaluate(context.Background())
+	assert.Len(t, p.sent, 2)
+
+	// Once the window no longer shows growth, the next increment alerts again
+	c.UpdateDisks(map[string]*model.Disk{"wwn-deg": {WWN: "wwn-deg", DevPath: "/dev/sdf", Status: model.StatusPassed}})
+	a.evaluate(context.Background())
+	assert.Empty(t, a.degradingAlerted)
+}
+
+func TestEvaluate_DiskEnduranceLow(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+
+	a, p := newTestAlerter(t, c, cfg)
+
+	soon := time.Now().Add(60 * 24 * time.Hour)
+	later := time.Now().Add(365 * 24 * time.Hour)
+	rate := 0.42
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-worn": {
+			Instance: "pve1", Node: "node1", WWN: "wwn-worn", DevPath: "/dev/nvme0n1", Model: "Consumer NVMe",
+			Endurance: &model.Endurance{WearUsedPct: 91, WriteTBPerDay: &rate, EndOfLife: &soon},
+		},
+		"wwn-fine": {
+			Instance: "pve1", Node: "node1", WWN: "wwn-fine", DevPath: "/dev/nvme1n1",
+			Endurance: &model.Endurance{WearUsedPct: 20, EndOfLife: &later},
+		},
+		"wwn-new": {
+			Instance: "pve1", Node: "node1", WWN: "wwn-new", DevPath: "/dev/sdb",
+			Endurance: &model.Endurance{WearUsedPct: 0},
+		},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "disk_endurance_low", p.sent[0].AlertType)
+	assert.Equal(t, "warning", p.sent[0].Severity)
+	assert.Equal(t, "/dev/nvme0n1", p.sent[0].Subject)
+	assert.Contains(t, p.sent[0].Message, "91% used, writing 0.42 TB/day")
+	assert.Equal(t, "2.0", p.sent[0].Metadata["months_left"])
+}
+
+func TestDiskTempAlert_Limit(t *testing.T) {
+	cfg := DefaultAlertConfig().DiskTempHigh
+	assert.InDelta(t, 50.0, cfg.Limit("hdd"), 0)
+	assert.InDelta(t, 60.0, cfg.Limit("ssd"), 0)
+	assert.InDelta(t, 70.0, cfg.Limit("nvme"), 0)
+	assert.InDelta(t, 50.0, cfg.Limit(""), 0, "unknown type uses the HDD limit")
+}
+
+func TestEvaluate_DiskTempHigh_Sustained(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+
+	a, p := newTestAlerter(t, c, cfg)
+
+	poll := time.Now().Add(-2 * time.Hour)
+	disk := func(temp int, seen time.Time) map[string]*model.Disk {
+		return map[string]*model.Disk{
+			"wwn-hot": {
+				Instance: "pve1", Node: "node1", WWN: "wwn-hot", DevPath: "/dev/sdc", Model: "IronWolf",
+				DiskType: "hdd", Temperature: &temp, LastSeen: seen,
+			},
+		}
+	}
+
+	// One hot reading never fires, however often the rules run.
+	c.UpdateDisks(disk(55, poll))
+	a.evaluate(context.Background())
+	a.evaluate(context.Background())
+	assert.Empty(t, p.sent)
+
+	// Still hot on the next poll, but not yet for the full hour.
+	c.UpdateDisks(disk(56, poll.Add(30*time.Minute)))
+	a.evaluate(context.Background())
+	assert.Empty(t, p.sent)
+
+	c.UpdateDisks(disk(57, poll.Add(time.Hour)))
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "disk_temp_high", p.sent[0].AlertType)
+	assert.Equal(t, "warning", p.sent[0].Severity)
+	assert.Equal(t, "/dev/sdc", p.sent[0].Subject)
+	assert.Contains(t, p.sent[0].Message, "at 57C, at or above the 50C hdd limit for 1h0m0s")
+	assert.Equal(t, "57", p.sent[0].Metadata["value"])
+	assert.Equal(t, "50", p.sent[0].Metadata["threshold"])
+}
+
+func TestEvaluate_DiskTempHigh_CoolingResets(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+
+	a, p := newTestAlerter(t, c, cfg)
+
+	poll := time.Now().Add(-3 * time.Hour)
+	for i, temp := range []int{52, 45, 52, 52} {
+		c.UpdateDisks(map[string]*model.Disk{
+			"wwn-hdd": {
+				Instance: "pve1", Node: "node1", WWN: "wwn-hdd", DevPath: "/dev/sdd",
+				DiskType: "hdd", Temperature: &temp, LastSeen: poll.Add(time.Duration(i) * 40 * time.Minute),
+			},
+		})
+		a.evaluate(context.Background())
+	}
+	// The dip below the limit restarts the clock; the last two readings
+	// span only 40 minutes.
+	assert.Empty(t, p.sent)
+}
+
+func TestEvaluate_DiskTempHigh_PerType(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+	cfg.DiskTempHigh.Duration = 0
+
+	a, p := newTestAlerter(t, c, cfg)
+
+	warm := 65
+	now := time.Now()
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-hdd":  {Instance: "pve1", Node: "node1", WWN: "wwn-hdd", DevPath: "/dev/sda", DiskType: "hdd", Temperature: &warm, LastSeen: now},
+		"wwn-ssd":  {Instance: "pve1", Node: "node1", WWN: "wwn-ssd", DevPath: "/dev/sdb", DiskType: "ssd", Temperature: &warm, LastSeen: now},
+		"wwn-nvme": {Instance: "pve1", Node: "node1", WWN: "wwn-nvme", DevPath: "/dev/nvme0n1", DiskType: "nvme", Temperature: &warm, LastSeen: now},
+		"wwn-none": {Instance: "pve1", Node: "node1", WWN: "wwn-none", DevPath: "/dev/sdc", DiskType: "hdd", LastSeen: now},
+	})
+
+	a.evaluate(context.Background())
+	a.evaluate(context.Background())
+
+	subjects := make([]string, 0, len(p.sent))
+	for _, n := range p.sent {
+		subjects = append(subjects, n.Subject)
+	}
+	assert.ElementsMatch(t, []string{"/dev/sda", "/dev/sdb"}, subjects)
+}
+
+func TestEvaluate_DiskSelfTests(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+
+	a, p := newTestAlerter(t, c, cfg)
+
+	lba := int64(1234567890)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-failed": {
+			Instance: "pve1", Node: "node1", WWN: "wwn-failed", DevPath: "/dev/sda", Model: "IronWolf",
+			SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{
+				{Type: "long", Result: "failed", Status: "Completed: read failure", LifetimeHours: 23651, LBAFirstError: &lba},
+				{Type: "long", Result: "passed", LifetimeHours: 20000},
+			}},
+		},
+		"wwn-short-only": {
+			Instance: "pve1", Node: "node1", WWN: "wwn-short-only", DevPath: "/dev/sdb", Model: "870 EVO",
+			SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{
+				{Type: "long", Result: "aborted", LifetimeHours: 900},
+				{Type: "short", Result: "passed", LifetimeHours: 800},
+			}},
+		},
+		"wwn-healthy": {
+			Instance: "pve1", Node: "node1", WWN: "wwn-healthy", DevPath: "/dev/sdc",
+			SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{
+				{Type: "short", Result: "in_progress"},
+				{Type: "long", Result: "passed", LifetimeHours: 500},
+			}},
+		},
+		// No log available (PVE API only): nothing to judge.
+		"wwn-unknown": {Instance: "pve1", Node: "node1", WWN: "wwn-unknown", DevPath: "/dev/sdd"},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 2)
+	byType := make(map[string]model.Notification)
+	for _, n := range p.sent {
+		byType[n.AlertType] = n
+	}
+
+	failed := byType["disk_self_test_failed"]
+	assert.Equal(t, "critical", failed.Severity)
+	assert.Equal(t, "/dev/sda", failed.Subject)
+	assert.Contains(t, failed.Message, "long self-test failed at 23651h: Completed: read failure, first error at LBA 1234567890")
+	assert.Equal(t, "1234567890", failed.Metadata["lba"])
+
+	never := byType["disk_no_long_self_test"]
+	assert.Equal(t, "warning", never.Severity)
+	assert.Equal(t, "/dev/sdb", never.Subject, "an aborted long test does not count")
+}
+
+func TestEvaluate_DiskSelfTests_Disabled(t *testing.T) {
+	c := cache.New()
+	cfg := DefaultAlertConfig()
+	cfg.DiskSelfTestFailed = nil
+	cfg.DiskNoLongTest = nil
+
+	a, p := newTestAlerter(t, c, cfg)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn": {WWN: "wwn", DevPath: "/dev/sda", SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{{Type: "short", Result: "failed"}}}},
+	})
+
+	a.evaluate(context.Background())
+	assert.Empty(t, p.sent)
+}
+
+func TestEvaluate_DiskMissing(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	now := time.Now()
+	c.UpdateInventory("homelab", []*model.InventoryDisk{
+		{WWN: "present", Node: "pve", DevPath: "/dev/sda", LastSeen: now},
+		{WWN: "missing", Instance: "homelab", Node: "pve", DevPath: "/dev/sdb", Model: "WD Red", Serial: "WD-1",
+			LastSeen: now.Add(-2 * time.Hour), MissedPolls: 2, RemovedAt: new(now.Add(-time.Hour))},
+		{WWN: "replaced", Node: "pve", DevPath: "/dev/sdc", MissedPolls: 1, RemovedAt: new(now.Add(-time.Hour)), ReplacedBy: "new"},
+		{WWN: "long-gone", Node: "pve", DevPath: "/dev/sdd", MissedPolls: 2, RemovedAt: new(now.Add(-30 * 24 * time.Hour))},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "disk_missing", p.sent[0].AlertType)
+	assert.Equal(t, "critical", p.sent[0].Severity)
+	assert.Equal(t, "Disk Missing: /dev/sdb on pve", p.sent[0].Title)
+	assert.Contains(t, p.sent[0].Message, "serial WD-1")
+	assert.Equal(t, "missing", p.sent[0].Metadata["wwn"])
+
+	// Cooldown suppresses a repeat
+	a.evaluate(context.Background())
+	assert.Len(t, p.sent, 1)
+}
+
 func TestEvaluate_DatastoreFull(t *testing.T) {
 	c := cache.New()
 	cfg := DefaultAlertConfig()
@@ -489,6 +819,178 @@ func TestEvaluate_DatastoreNilBytes(t *testing.T) {
 	assert.Empty(t, p.sent)
 }
 
+func TestEvaluate_CephHealth(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	c.UpdateCeph("pve1", &model.CephStatus{
+		Instance: "pve1",
+		Health:   "HEALTH_ERR",
+		Checks: []model.CephHealthCheck{
+			{Code: "OSD_DOWN", Severity: "HEALTH_WARN", Summary: "1 osds down"},
+			{Code: "PG_DAMAGED", Severity: "HEALTH_ERR", Summary: "Possible data damage: 1 pg inconsistent"},
+			{Code: "MON_CLOCK_SKEW", Severity: "HEALTH_WARN", Summary: "clock skew", Muted: true},
+		},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 2)
+
+	bySubject := map[string]model.Notification{}
+	for _, n := range p.sent {
+		assert.Equal(t, "ceph_health", n.AlertType)
+		bySubject[n.Subject] = n
+	}
+	assert.Equal(t, "warning", bySubject["OSD_DOWN"].Severity)
+	assert.Contains(t, bySubject["OSD_DOWN"].Message, "1 osds down")
+	assert.Equal(t, "critical", bySubject["PG_DAMAGED"].Severity)
+	assert.Equal(t, "HEALTH_ERR", bySubject["PG_DAMAGED"].Metadata["health"])
+
+	// Same checks within cooldown do not re-fire.
+	a.evaluate(context.Background())
+	assert.Len(t, p.sent, 2)
+}
+
+func TestEvaluate_CephHealthOK(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	c.UpdateCeph("pve1", &model.CephStatus{Instance: "pve1", Health: "HEALTH_OK"})
+
+	a.evaluate(context.Background())
+	assert.Empty(t, p.sent)
+}
+
+func TestEvaluate_ClusterQuorumLost(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	c.UpdateCluster("pve1", &model.ClusterStatus{
+		Instance: "pve1", Name: "homelab", Quorate: false, ExpectedVotes: 3, PresentVotes: 1,
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "cluster_quorum_lost", p.sent[0].AlertType)
+	assert.Equal(t, "critical", p.sent[0].Severity)
+	assert.Contains(t, p.sent[0].Message, "1/3 votes")
+	assert.Equal(t, "homelab", p.sent[0].Subject)
+}
+
+func TestEvaluate_ClusterQuorate(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	c.UpdateCluster("pve1", &model.ClusterStatus{
+		Instance: "pve1", Name: "homelab", Quorate: true, ExpectedVotes: 3, PresentVotes: 3,
+		HAResources: []model.HAResource{
+			{SID: "vm:100", State: "started"},
+			{SID: "vm:101", State: "migrate"},
+		},
+	})
+
+	a.evaluate(context.Background())
+	assert.Empty(t, p.sent)
+}
+
+func TestEvaluate_HAResourceError(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	c.UpdateCluster("pve1", &model.ClusterStatus{
+		Instance: "pve1", Name: "homelab", Quorate: true,
+		HAResources: []model.HAResource{
+			{SID: "vm:100", State: "error", Node: "pve1"},
+			{SID: "ct:101", State: "fence", Node: "pve2"},
+			{SID: "vm:102", State: "started", Node: "pve1"},
+		},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 2)
+	subjects := []string{p.sent[0].Subject, p.sent[1].Subject}
+	assert.ElementsMatch(t, []string{"vm:100", "ct:101"}, subjects)
+	for _, n := range p.sent {
+		assert.Equal(t, "ha_resource_error", n.AlertType)
+		assert.Equal(t, "critical", n.Severity)
+	}
+}
+
+func TestEvaluate_PVEBackupFailed(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	now := time.Now().Unix()
+	c.UpdateGuests("cluster1", map[int]*model.Guest{
+		100: {Instance: "pve1", ClusterID: "cluster1", VMID: 100, Name: "web", Status: "running"},
+	})
+	c.UpdateGuestBackups("cluster1", map[int]*model.PVEGuestBackup{
+		// Latest run failed
+		100: {Instance: "pve1", ClusterID: "cluster1", VMID: 100, Jobs: []string{"nightly"}, LastSuccess: now - 86400, LastFailure: now - 60, LastStatus: "job errors"},
+		// Failure superseded by a newer success
+		101: {Instance: "pve1", ClusterID: "cluster1", VMID: 101, Jobs: []string{"nightly"}, LastSuccess: now - 60, LastFailure: now - 86400, LastStatus: "OK"},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "pve_backup_failed", p.sent[0].AlertType)
+	assert.Equal(t, "warning", p.sent[0].Severity)
+	assert.Equal(t, "web", p.sent[0].Subject)
+	assert.Contains(t, p.sent[0].Message, "job errors")
+}
+
+func TestEvaluate_PVEBackupStale(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	old := time.Now().Add(-72 * time.Hour).Unix()
+	c.UpdateGuestBackups("cluster1", map[int]*model.PVEGuestBackup{
+		// Covered, last success too old
+		100: {Instance: "pve1", ClusterID: "cluster1", VMID: 100, Jobs: []string{"nightly"}, LastSuccess: old, LastStatus: "OK"},
+		// Not covered by any job
+		101: {Instance: "pve1", ClusterID: "cluster1", VMID: 101, LastSuccess: old},
+		// Covered but no run in task history yet
+		102: {Instance: "pve1", ClusterID: "cluster1", VMID: 102, Jobs: []string{"nightly"}},
+		// Covered and fresh
+		103: {Instance: "pve1", ClusterID: "cluster1", VMID: 103, Jobs: []string{"nightly"}, LastSuccess: time.Now().Unix()},
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "backup_stale", p.sent[0].AlertType)
+	// No guest in the cache: name falls back to the VMID
+	assert.Equal(t, "100", p.sent[0].Subject)
+	assert.Equal(t, "vzdump", p.sent[0].Metadata["source"])
+}
+
+func TestEvaluate_PVETaskFailed(t *testing.T) {
+	c := cache.New()
+	a, p := newTestAlerter(t, c, DefaultAlertConfig())
+
+	recent := time.Now().Add(-10 * time.Minute).Unix()
+	old := time.Now().Add(-48 * time.Hour).Unix()
+	c.UpdatePVETasks("pve1", []*model.PVETask{
+		{Instance: "pve1", UPID: "UPID:1", Node: "pve", Type: "qmigrate", ID: "100", User: "alice@pve", EndTime: &recent, Status: "migration aborted"},
+		{Instance: "pve1", UPID: "UPID:2", Node: "pve", Type: "qmstart", ID: "101", EndTime: &recent, Status: "OK"},
+		{Instance: "pve1", UPID: "UPID:3", Node: "pve", Type: "vzsnapshot", ID: "102", EndTime: &recent, Status: "WARNINGS: 1"},
+		{Instance: "pve1", UPID: "UPID:4", Node: "pve", Type: "qmstart", ID: "103", Status: ""},                                  // running
+		{Instance: "pve1", UPID: "UPID:5", Node: "pve", Type: "vzdump", EndTime: &recent, Status: "job errors"},                  // pve_backup_failed
+		{Instance: "pve1", UPID: "UPID:6", Node: "pve", Type: "qmigrate", ID: "104", EndTime: &old, Status: "migration aborted"}, // outside window
+	})
+
+	a.evaluate(context.Background())
+	require.Len(t, p.sent, 1)
+	assert.Equal(t, "pve_task_failed", p.sent[0].AlertType)
+	assert.Equal(t, "warning", p.sent[0].Severity)
+	assert.Equal(t, "qmigrate 100", p.sent[0].Subject)
+	assert.Contains(t, p.sent[0].Message, "alice@pve")
+	assert.Contains(t, p.sent[0].Message, "migration aborted")
+
+	// Same task does not fire again
+	a.evaluate(context.Background())
+	assert.Len(t, p.sent, 1)
+}
+
 func TestCheckSustainedThreshold_SeededThenFires(t *testing.T) {
 	c := cache.New()
 	cfg := DefaultAlertConfig()
@@ -641,6 +1143,19 @@ func TestEvaluate_NilConfigFields(t *testing.T) {
 	c.UpdateBackups("pbs1", map[string]*model.Backup{
 		"ct/100": {BackupType: "ct", BackupID: "100", BackupTime: 0},
 	})
+	c.UpdateCeph("pve1", &model.CephStatus{
+		Health: "HEALTH_ERR",
+		Checks: []model.CephHealthCheck{{Code: "OSD_DOWN", Severity: "HEALTH_ERR"}},
+	})
+	c.UpdateCluster("pve1", &model.ClusterStatus{
+		Quorate:     false,
+		HAResources: []model.HAResource{{SID: "vm:100", State: "error"}},
+	})
+	c.UpdateGuestBackups("cluster1", map[int]*model.PVEGuestBackup{
+		100: {VMID: 100, Jobs: []string{"nightly"}, LastSuccess: 1, LastFailure: 2},
+	})
+	end := time.Now().Unix()
+	c.UpdatePVETasks("pve1", []*model.PVETask{{UPID: "UPID:1", Type: "qmigrate", EndTime: &end, Status: "migration aborted"}})
 
 	// Should not panic or fire any alerts.
 	a.evaluate(context.Background())
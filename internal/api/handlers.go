@@ -3,12 +3,15 @@ package api
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/a-h/templ"
@@ -22,6 +25,10 @@ import (
 	_ "github.com/darshan-rambhia/glint/docs/swagger"
 )
 
+// maxHistoryHours is the longest selectable history window: the default
+// retention of the hourly rollups.
+const maxHistoryHours = 365 * 24
+
 // Server is the HTTP server for Glint.
 type Server struct {
 	cache  *cache.Cache
@@ -84,18 +91,29 @@ func (s *Server) registerRoutes() {
 	// htmx fragment endpoints
 	s.mux.HandleFunc("GET /fragments/nodes", s.handleNodesFragment)
 	s.mux.HandleFunc("GET /fragments/guests", s.handleGuestsFragment)
+	s.mux.HandleFunc("GET /fragments/guest/{instance}/{vmid}", s.handleGuestDetailFragment)
 	s.mux.HandleFunc("GET /fragments/backups", s.handleBackupsFragment)
 	s.mux.HandleFunc("GET /fragments/events", s.handleEventsFragment)
+	s.mux.HandleFunc("GET /fragments/ceph", s.handleCephFragment)
 	s.mux.HandleFunc("GET /fragments/disks", s.handleDisksFragment)
 	s.mux.HandleFunc("GET /fragments/disk/{wwn}", s.handleDiskDetailFragment)
+	s.mux.HandleFunc("GET /fragments/inventory", s.handleInventoryFragment)
 
 	// SVG sparkline fragment endpoints (for htmx)
 	s.mux.HandleFunc("GET /fragments/sparkline/node/{instance}/{node}", s.handleNodeSparklineSVG)
 	s.mux.HandleFunc("GET /fragments/sparkline/guest/{instance}/{vmid}", s.handleGuestSparklineSVG)
+	s.mux.HandleFunc("GET /fragments/sparkline/disk/{wwn}", s.handleDiskSparklineSVG)
 
 	// API endpoints (JSON)
 	s.mux.HandleFunc("GET /api/sparkline/node/{instance}/{node}", s.handleNodeSparkline)
 	s.mux.HandleFunc("GET /api/sparkline/guest/{instance}/{vmid}", s.handleGuestSparkline)
+	s.mux.HandleFunc("GET /api/sparkline/disk/{wwn}", s.handleDiskSparkline)
+	s.mux.HandleFunc("GET /api/disks", s.handleDisks)
+	s.mux.HandleFunc("GET /api/disks/risk", s.handleDiskRisk)
+	s.mux.HandleFunc("GET /api/disks/{wwn}", s.handleDisk)
+	s.mux.HandleFunc("GET /api/disks/{wwn}/history", s.handleDiskHistory)
+
+	s.mux.HandleFunc("GET /api/export", s.handleExport)
 
 	// Health check
 	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
@@ -152,7 +170,7 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	snap := s.cache.Snapshot()
-	renderHTML(w, r, templates.Dashboard(snap))
+	renderHTML(w, r, templates.Dashboard(snap, s.diskEvents()))
 }
 
 // @Summary Node cards fragment
@@ -175,6 +193,63 @@ func (s *Server) handleGuestsFragment(w http.ResponseWriter, r *http.Request) {
 	renderHTML(w, r, templates.GuestsFragment(snap))
 }
 
+// @Summary Guest detail fragment
+// @Description Returns HTML fragment with history charts, configuration, backups and recent tasks for a guest
+// @Produce html
+// @Param instance path string true "PVE instance name"
+// @Param vmid path int true "Guest VMID"
+// @Param hours query int false "Hours of history (1-8760); spans over 48h read 5-minute or hourly rollups" default(24)
+// @Success 200 {string} string "HTML fragment"
+// @Failure 400 {string} string "Invalid VMID"
+// @Failure 404 {string} string "Guest not found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /fragments/guest/{instance}/{vmid} [get]
+func (s *Server) handleGuestDetailFragment(w http.ResponseWriter, r *http.Request) {
+	instance := r.PathValue("instance")
+	vmid, err := strconv.Atoi(r.PathValue("vmid"))
+	if err != nil {
+		http.Error(w, "Invalid VMID", http.StatusBadRequest)
+		return
+	}
+	hours := 24
+	if h := r.URL.Query().Get("hours"); h != "" {
+		if v, err := strconv.Atoi(h); err == nil && v > 0 && v <= maxHistoryHours {
+			hours = v
+		}
+	}
+
+	snap := s.cache.Snapshot()
+	guest := templates.FindGuest(snap.Guests, instance, vmid)
+	if guest == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	now := time.Now()
+	history, err := s.store.QueryGuestHistory(instance, vmid, now.Add(-time.Duration(hours)*time.Hour).Unix())
+	if err != nil {
+		slog.Error("querying guest history", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	pveTasks, err := s.store.QueryPVETasks(now.Add(-7 * 24 * time.Hour).Unix())
+	if err != nil {
+		slog.Error("querying PVE tasks", "error", err)
+		pveTasks = templates.AllPVETasksSorted(snap.PVETasks)
+	}
+
+	renderHTML(w, r, templates.GuestDetail(
+		guest,
+		templates.GuestConfigFor(snap.GuestConfigs, guest),
+		history,
+		templates.BackupsForGuest(snap.Backups, vmid),
+		templates.PVEBackupFor(snap.GuestBackups, guest),
+		templates.GuestTasks(pveTasks, instance, vmid, 20),
+		hours,
+	))
+}
+
 // @Summary Backup status fragment
 // @Description Returns HTML fragment of PBS backup status for htmx
 // @Produce html
@@ -186,13 +261,40 @@ func (s *Server) handleBackupsFragment(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Events fragment
-// @Description Returns HTML fragment of PBS server-side task events for htmx
+// @Description Returns HTML fragment of PBS and PVE task events (last 7 days) for htmx
 // @Produce html
+// @Param source query string false "Filter by source" Enums(pbs, pve)
+// @Param type query string false "Filter by task type (e.g. qmigrate)"
+// @Param status query string false "Filter by status" Enums(failed, running)
 // @Success 200 {string} string "HTML fragment"
 // @Router /fragments/events [get]
 func (s *Server) handleEventsFragment(w http.ResponseWriter, r *http.Request) {
 	snap := s.cache.Snapshot()
-	renderHTML(w, r, templates.EventsFragment(snap))
+	q := r.URL.Query()
+	filter := templates.EventsFilter{
+		Source: q.Get("source"),
+		Type:   q.Get("type"),
+		Status: q.Get("status"),
+	}
+
+	// PVE task history comes from the store so it outlives the short list
+	// returned by /cluster/tasks; fall back to the cache if the query fails.
+	pveTasks, err := s.store.QueryPVETasks(time.Now().Add(-7 * 24 * time.Hour).Unix())
+	if err != nil {
+		slog.Error("querying PVE tasks", "error", err)
+		pveTasks = templates.AllPVETasksSorted(snap.PVETasks)
+	}
+	renderHTML(w, r, templates.EventsFragment(snap, pveTasks, filter))
+}
+
+// @Summary Ceph health fragment
+// @Description Returns HTML fragment of Ceph health, OSD, pool and PG status for htmx
+// @Produce html
+// @Success 200 {string} string "HTML fragment"
+// @Router /fragments/ceph [get]
+func (s *Server) handleCephFragment(w http.ResponseWriter, r *http.Request) {
+	snap := s.cache.Snapshot()
+	renderHTML(w, r, templates.CephFragment(snap))
 }
 
 // @Summary Disk health fragment
@@ -223,13 +325,34 @@ func (s *Server) handleDiskDetailFragment(w http.ResponseWriter, r *http.Request
 	renderHTML(w, r, templates.DiskDetail(disk))
 }
 
+// @Summary Disk inventory fragment
+// @Description Returns HTML fragment of every disk seen, its lifecycle events and slot history for htmx
+// @Produce html
+// @Success 200 {string} string "HTML fragment"
+// @Router /fragments/inventory [get]
+func (s *Server) handleInventoryFragment(w http.ResponseWriter, r *http.Request) {
+	snap := s.cache.Snapshot()
+	renderHTML(w, r, templates.InventoryFragment(snap, s.diskEvents()))
+}
+
+// diskEvents returns every recorded disk lifecycle event, newest first. A
+// failed query is logged and leaves the inventory without its history.
+func (s *Server) diskEvents() []*model.DiskEvent {
+	events, err := s.store.QueryDiskEvents(0)
+	if err != nil {
+		slog.Error("querying disk events", "error", err)
+		return nil
+	}
+	return events
+}
+
 // @Summary Node sparkline data
 // @Description Returns JSON array of time-series data points for a node metric
 // @Produce json
 // @Param instance path string true "PVE instance name"
 // @Param node path string true "Node name"
-// @Param hours query int false "Hours of history (1-168)" default(24)
-// @Param metric query string false "Metric name (cpu, memory)" default(cpu)
+// @Param hours query int false "Hours of history (1-8760); spans over 48h read 5-minute or hourly rollups" default(24)
+// @Param metric query string false "Metric name (cpu, memory, netin, netout, psi_cpu, psi_io, psi_mem)" default(cpu)
 // @Success 200 {array} model.SparklinePoint
 // @Failure 500 {string} string "Internal Server Error"
 // @Router /api/sparkline/node/{instance}/{node} [get]
@@ -238,7 +361,7 @@ func (s *Server) handleNodeSparkline(w http.ResponseWriter, r *http.Request) {
 	node := r.PathValue("node")
 	hours := 24
 	if h := r.URL.Query().Get("hours"); h != "" {
-		if v, err := strconv.Atoi(h); err == nil && v > 0 && v <= 168 {
+		if v, err := strconv.Atoi(h); err == nil && v > 0 && v <= maxHistoryHours {
 			hours = v
 		}
 	}
@@ -260,10 +383,11 @@ func (s *Server) handleNodeSparkline(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Guest sparkline data
-// @Description Returns JSON array of CPU usage data points for a guest
+// @Description Returns JSON array of data points for a guest metric. Throughput metrics are in bytes/sec.
 // @Produce json
 // @Param instance path string true "PVE instance name"
 // @Param vmid path int true "Guest VMID"
+// @Param metric query string false "Metric name (cpu, memory, netin, netout, diskread, diskwrite)" default(cpu)
 // @Success 200 {array} model.SparklinePoint
 // @Failure 400 {string} string "Invalid VMID"
 // @Failure 500 {string} string "Internal Server Error"
@@ -277,8 +401,13 @@ func (s *Server) handleGuestSparkline(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "cpu"
+	}
+
 	since := time.Now().Add(-24 * time.Hour).Unix()
-	points, err := s.store.QueryGuestSparkline(instance, vmid, since)
+	points, err := s.store.QueryGuestSparkline(instance, vmid, metric, since)
 	if err != nil {
 		slog.Error("querying guest sparkline", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -293,8 +422,8 @@ func (s *Server) handleGuestSparkline(w http.ResponseWriter, r *http.Request) {
 // @Produce html
 // @Param instance path string true "PVE instance name"
 // @Param node path string true "Node name"
-// @Param hours query int false "Hours of history (1-168)" default(24)
-// @Param metric query string false "Metric name (cpu, memory)" default(cpu)
+// @Param hours query int false "Hours of history (1-8760); spans over 48h read 5-minute or hourly rollups" default(24)
+// @Param metric query string false "Metric name (cpu, memory, netin, netout, psi_cpu, psi_io, psi_mem)" default(cpu)
 // @Success 200 {string} string "SVG sparkline HTML"
 // @Failure 500 {string} string "Internal Server Error"
 // @Router /fragments/sparkline/node/{instance}/{node} [get]
@@ -303,7 +432,7 @@ func (s *Server) handleNodeSparklineSVG(w http.ResponseWriter, r *http.Request)
 	node := r.PathValue("node")
 	hours := 24
 	if h := r.URL.Query().Get("hours"); h != "" {
-		if v, err := strconv.Atoi(h); err == nil && v > 0 && v <= 168 {
+		if v, err := strconv.Atoi(h); err == nil && v > 0 && v <= maxHistoryHours {
 			hours = v
 		}
 	}
@@ -321,15 +450,16 @@ func (s *Server) handleNodeSparklineSVG(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	label := fmt.Sprintf("%s %dh", metric, hours)
+	label := metric + " " + templates.WindowLabel(hours)
 	renderHTML(w, r, components.SparklineSVG(points, label))
 }
 
 // @Summary Guest sparkline SVG fragment
-// @Description Returns HTML/SVG sparkline visualization for a guest's CPU usage
+// @Description Returns HTML/SVG sparkline visualization for a guest metric
 // @Produce html
 // @Param instance path string true "PVE instance name"
 // @Param vmid path int true "Guest VMID"
+// @Param metric query string false "Metric name (cpu, memory, netin, netout, diskread, diskwrite)" default(cpu)
 // @Success 200 {string} string "SVG sparkline HTML"
 // @Failure 400 {string} string "Invalid VMID"
 // @Failure 500 {string} string "Internal Server Error"
@@ -343,15 +473,230 @@ func (s *Server) handleGuestSparklineSVG(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "cpu"
+	}
+
 	since := time.Now().Add(-24 * time.Hour).Unix()
-	points, err := s.store.QueryGuestSparkline(instance, vmid, since)
+	points, err := s.store.QueryGuestSparkline(instance, vmid, metric, since)
 	if err != nil {
 		slog.Error("querying guest sparkline SVG", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	renderHTML(w, r, components.SparklineSVG(points, "cpu 24h"))
+	renderHTML(w, r, components.SparklineSVG(points, metric+" 24h"))
+}
+
+// diskSparklineHours is the default disk sparkline window. SMART data is
+// polled hourly, so a day holds too few points to show a trend.
+const diskSparklineHours = 7 * 24
+
+// @Summary Disk sparkline data
+// @Description Returns JSON array of time-series data points for a disk SMART metric
+// @Produce json
+// @Param wwn path string true "Disk WWN identifier"
+// @Param hours query int false "Hours of history (1-8760)" default(168)
+// @Param metric query string false "Metric name (temperature, wearout, power_on_hours)" default(temperature)
+// @Success 200 {array} model.SparklinePoint
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/sparkline/disk/{wwn} [get]
+func (s *Server) handleDiskSparkline(w http.ResponseWriter, r *http.Request) {
+	wwn := r.PathValue("wwn")
+	hours, metric := diskSparklineParams(r)
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour).Unix()
+	points, err := s.store.QueryDiskSparkline(wwn, metric, since)
+	if err != nil {
+		slog.Error("querying disk sparkline", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, r, points)
+}
+
+// @Summary Disk sparkline SVG fragment
+// @Description Returns HTML/SVG sparkline visualization for a disk SMART metric
+// @Produce html
+// @Param wwn path string true "Disk WWN identifier"
+// @Param hours query int false "Hours of history (1-8760)" default(168)
+// @Param metric query string false "Metric name (temperature, wearout, power_on_hours)" default(temperature)
+// @Success 200 {string} string "SVG sparkline HTML"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /fragments/sparkline/disk/{wwn} [get]
+func (s *Server) handleDiskSparklineSVG(w http.ResponseWriter, r *http.Request) {
+	wwn := r.PathValue("wwn")
+	hours, metric := diskSparklineParams(r)
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour).Unix()
+	points, err := s.store.QueryDiskSparkline(wwn, metric, since)
+	if err != nil {
+		slog.Error("querying disk sparkline SVG", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	label := metric + " " + templates.WindowLabel(hours)
+	renderHTML(w, r, components.SparklineSVG(points, label))
+}
+
+// diskSparklineParams reads the hours and metric query parameters of the disk
+// sparkline endpoints.
+func diskSparklineParams(r *http.Request) (int, string) {
+	hours := diskSparklineHours
+	if h := r.URL.Query().Get("hours"); h != "" {
+		if v, err := strconv.Atoi(h); err == nil && v > 0 && v <= maxHistoryHours {
+			hours = v
+		}
+	}
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "temperature"
+	}
+	return hours, metric
+}
+
+// diskRiskEntry is one disk of the GET /api/disks/risk ranking.
+type diskRiskEntry struct {
+	Rank        int             `json:"rank"`
+	Instance    string          `json:"instance"`
+	Node        string          `json:"node"`
+	WWN         string          `json:"wwn"`
+	DevPath     string          `json:"dev_path"`
+	Model       string          `json:"model"`
+	Serial      string          `json:"serial"`
+	DiskType    string          `json:"disk_type"`
+	SizeBytes   int64           `json:"size_bytes"`
+	ReplaceSoon bool            `json:"replace_soon"`
+	Risk        *model.DiskRisk `json:"risk"`
+}
+
+// @Summary Disk failure risk ranking
+// @Description Returns every disk with a risk score, most likely to fail first. The score is the estimated annual failure probability combined from SMART attribute failure rates, age, error counter trends, health, self-tests and SSD wear, with a breakdown of the factors. replace_soon marks disks at or above 10%.
+// @Produce json
+// @Param limit query int false "Maximum number of disks (0 for all)" default(0)
+// @Success 200 {array} diskRiskEntry
+// @Router /api/disks/risk [get]
+func (s *Server) handleDiskRisk(w http.ResponseWriter, r *http.Request) {
+	ranking := templates.RiskRanking(s.cache.Snapshot().Disks)
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v < len(ranking) {
+		ranking = ranking[:v]
+	}
+
+	entries := make([]diskRiskEntry, 0, len(ranking))
+	for i, d := range ranking {
+		entries = append(entries, diskRiskEntry{
+			Rank:        i + 1,
+			Instance:    d.Instance,
+			Node:        d.Node,
+			WWN:         d.WWN,
+			DevPath:     d.DevPath,
+			Model:       d.Model,
+			Serial:      d.Serial,
+			DiskType:    d.DiskType,
+			SizeBytes:   d.SizeBytes,
+			ReplaceSoon: d.Risk.AnnualFailurePct >= templates.ReplaceSoonPct,
+			Risk:        d.Risk,
+		})
+	}
+	writeJSON(w, r, entries)
+}
+
+// @Summary Disks
+// @Description Returns every disk with its SMART health: status bitfield, attributes with failure rates, trends, SSD endurance, self-test log and failure risk. Sorted by instance, node and device path.
+// @Produce json
+// @Success 200 {array} model.Disk
+// @Router /api/disks [get]
+func (s *Server) handleDisks(w http.ResponseWriter, r *http.Request) {
+	disks := make([]*model.Disk, 0)
+	for _, d := range s.cache.Snapshot().Disks {
+		disks = append(disks, d)
+	}
+	slices.SortFunc(disks, func(a, b *model.Disk) int {
+		return cmp.Or(
+			cmp.Compare(a.Instance, b.Instance),
+			cmp.Compare(a.Node, b.Node),
+			cmp.Compare(a.DevPath, b.DevPath),
+		)
+	})
+	writeJSON(w, r, disks)
+}
+
+// @Summary Disk
+// @Description Returns one disk with its SMART health, as listed by /api/disks
+// @Produce json
+// @Param wwn path string true "Disk WWN identifier"
+// @Success 200 {object} model.Disk
+// @Failure 404 {string} string "Not Found"
+// @Router /api/disks/{wwn} [get]
+func (s *Server) handleDisk(w http.ResponseWriter, r *http.Request) {
+	disk, ok := s.cache.Snapshot().Disks[r.PathValue("wwn")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, r, disk)
+}
+
+// @Summary Disk SMART history
+// @Description Returns the SMART snapshots recorded for a disk, oldest first: health, status bitfield, temperature, power-on hours, wearout and attributes. History is kept for the smart_snapshots retention.
+// @Produce json
+// @Param wwn path string true "Disk WWN identifier"
+// @Param hours query int false "Hours of history (1-8760)" default(168)
+// @Param attr query string false "Comma-separated attribute IDs to include (default all)"
+// @Success 200 {array} model.DiskSnapshot
+// @Failure 400 {string} string "Invalid attr"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/disks/{wwn}/history [get]
+func (s *Server) handleDiskHistory(w http.ResponseWriter, r *http.Request) {
+	hours := diskSparklineHours
+	if h := r.URL.Query().Get("hours"); h != "" {
+		if v, err := strconv.Atoi(h); err == nil && v > 0 && v <= maxHistoryHours {
+			hours = v
+		}
+	}
+	var ids []int
+	if a := r.URL.Query().Get("attr"); a != "" {
+		for f := range strings.SplitSeq(a, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(f))
+			if err != nil {
+				http.Error(w, "Invalid attr", http.StatusBadRequest)
+				return
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	// A disk that is no longer polled still has history while it is in the
+	// inventory; only a WWN never seen is unknown.
+	wwn := r.PathValue("wwn")
+	if _, ok := s.cache.Snapshot().Disks[wwn]; !ok {
+		known, err := s.store.HasDisk(wwn)
+		if err != nil {
+			slog.Error("querying disk history", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if !known {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour).Unix()
+	snaps, err := s.store.QueryDiskHistory(wwn, since, ids)
+	if err != nil {
+		slog.Error("querying disk history", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if snaps == nil {
+		snaps = []model.DiskSnapshot{}
+	}
+	writeJSON(w, r, snaps)
 }
 
 // widgetResponse is the response body for GET /api/widget.
@@ -362,6 +707,8 @@ type widgetResponse struct {
 	Memory  widgetMemStats    `json:"memory"`
 	Disks   widgetDiskStats   `json:"disks"`
 	Backups widgetBackupStats `json:"backups"`
+
+	Throughput widgetThroughputStats `json:"throughput"`
 }
 
 type widgetNodeStats struct {
@@ -394,6 +741,28 @@ type widgetDiskStats struct {
 	Failed  int `json:"failed"`
 	Warning int `json:"warning"`
 	Unknown int `json:"unknown"`
+
+	// SSD endurance: host writes summed across SSDs, and the shortest
+	// projected life of any SSD (omitted when none has a projection).
+	WriteTBPerDay      float64  `json:"write_tb_per_day"`
+	ShortestLifeMonths *float64 `json:"shortest_life_months,omitempty"`
+
+	// Temperature: the hottest disk reading, and how many disks are at or
+	// above the limit for their type.
+	HottestTemp *int `json:"hottest_temp,omitempty"`
+	Hot         int  `json:"hot"`
+
+	// Disks whose annual failure probability is at or above the replace
+	// soon level.
+	ReplaceSoon int `json:"replace_soon"`
+}
+
+// widgetThroughputStats sums guest throughput across all running guests, in bytes/sec.
+type widgetThroughputStats struct {
+	NetIn     float64 `json:"net_in_bytes_per_sec"`
+	NetOut    float64 `json:"net_out_bytes_per_sec"`
+	DiskRead  float64 `json:"disk_read_bytes_per_sec"`
+	DiskWrite float64 `json:"disk_write_bytes_per_sec"`
 }
 
 type widgetBackupStats struct {
@@ -441,6 +810,10 @@ func (s *Server) handleWidget(w http.ResponseWriter, r *http.Request) {
 			switch g.Status {
 			case "running":
 				resp.Guests.Running++
+				resp.Throughput.NetIn += g.NetInRate
+				resp.Throughput.NetOut += g.NetOutRate
+				resp.Throughput.DiskRead += g.DiskReadRate
+				resp.Throughput.DiskWrite += g.DiskWriteRate
 			case "stopped":
 				resp.Guests.Stopped++
 			}
@@ -454,6 +827,7 @@ func (s *Server) handleWidget(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Disks — categorize by SMART status bitfield.
+	now := time.Now()
 	for _, d := range snap.Disks {
 		resp.Disks.Total++
 		switch {
@@ -461,11 +835,30 @@ func (s *Server) handleWidget(w http.ResponseWriter, r *http.Request) {
 			resp.Disks.Passed++
 		case d.Status&(model.StatusFailedSmart|model.StatusFailedScrutiny) != 0:
 			resp.Disks.Failed++
-		case d.Status&model.StatusWarnScrutiny != 0:
+		case d.Status&(model.StatusWarnScrutiny|model.StatusDegrading) != 0:
 			resp.Disks.Warning++
 		default:
 			resp.Disks.Unknown++
 		}
+		if e := d.Endurance; e != nil {
+			if e.WriteTBPerDay != nil {
+				resp.Disks.WriteTBPerDay += *e.WriteTBPerDay
+			}
+			if months, ok := e.MonthsLeft(now); ok && (resp.Disks.ShortestLifeMonths == nil || months < *resp.Disks.ShortestLifeMonths) {
+				resp.Disks.ShortestLifeMonths = &months
+			}
+		}
+		if d.Temperature != nil {
+			if t := *d.Temperature; resp.Disks.HottestTemp == nil || t > *resp.Disks.HottestTemp {
+				resp.Disks.HottestTemp = &t
+			}
+			if float64(*d.Temperature) >= templates.DiskTempLimit(d.DiskType) {
+				resp.Disks.Hot++
+			}
+		}
+		if d.Risk != nil && d.Risk.AnnualFailurePct >= templates.ReplaceSoonPct {
+			resp.Disks.ReplaceSoon++
+		}
 	}
 
 	// Backups — total count and most recent timestamp.
@@ -481,8 +874,48 @@ func (s *Server) handleWidget(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, r, resp)
 }
 
+// @Summary Export a table as CSV
+// @Description Downloads one database table as CSV with a header row. Tables with a ts column are limited to rows at or after since.
+// @Produce text/csv
+// @Param table query string true "Table name (e.g. guest_snapshots, smart_snapshots)"
+// @Param since query int false "Unix timestamp; only rows with ts >= since" default(0)
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {string} string "Unknown table or invalid since"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/export [get]
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	table := r.URL.Query().Get("table")
+	var since int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			http.Error(w, "Invalid since", http.StatusBadRequest)
+			return
+		}
+		since = n
+	}
+
+	tables, err := s.store.Tables()
+	if err != nil {
+		slog.Error("listing tables", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if !slices.Contains(tables, table) {
+		http.Error(w, "Unknown table; expected one of: "+strings.Join(tables, ", "), http.StatusBadRequest)
+		return
+	}
+
+	// Rows are streamed, so a failure after the first write can only be logged.
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, table))
+	if err := s.store.ExportCSV(w, table, since); err != nil {
+		slog.Error("exporting table", "table", table, "error", err)
+	}
+}
+
 // @Summary Health check
-// @Description Returns service health status and collector poll times
+// @Description Returns service health status, collector poll times and database size
 // @Produce json
 // @Success 200 {object} map[string]interface{} "Health status"
 // @Router /healthz [get]
@@ -499,9 +932,25 @@ func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
 	for k, v := range snap.LastPoll {
 		collectors[k] = fmt.Sprintf("%ds ago", int(time.Since(v).Seconds()))
 	}
-	writeJSON(w, r, map[string]any{
+	resp := map[string]any{
 		"status":     status,
 		"timestamp":  time.Now().Unix(),
 		"collectors": collectors,
-	})
+	}
+	// The database size is informational; a failure does not fail the check.
+	if size, free, err := s.store.Size(); err != nil {
+		slog.Warn("reading database size", "error", err)
+	} else {
+		resp["database"] = map[string]int64{
+			"size_bytes": size,
+			"free_bytes": free,
+		}
+	}
+	if queued, dropped, ok := s.store.SinkStats(); ok {
+		resp["metrics_sinks"] = map[string]int64{
+			"queued":  int64(queued),
+			"dropped": dropped,
+		}
+	}
+	writeJSON(w, r, resp)
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -152,6 +154,65 @@ func TestHandleNodesFragment_Populated(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "node1")
 }
 
+func TestHandleNodesFragment_RRD(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateNodes("pve1", map[string]*model.Node{
+		"node1": {
+			Instance: "pve1", Name: "node1", Status: "online",
+			RRD: &model.NodeRRD{
+				NetIn: 117_000_000, NetOut: 2048,
+				Pressure: &model.NodePressure{CPUSome: 0.5, IOSome: 12.5},
+			},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/nodes", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "metric=netin")
+	assert.Contains(t, body, "metric=netout")
+	assert.Contains(t, body, "Pressure")
+	assert.Contains(t, body, "io 12.5%")
+}
+
+func TestHandleNodesFragment_NoRRD(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	populateCache(c)
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/nodes", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "metric=netin")
+	assert.NotContains(t, w.Body.String(), "Pressure")
+}
+
+func TestHandleNodesFragment_ClusterStatus(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	populateCache(c)
+	c.UpdateCluster("main", &model.ClusterStatus{
+		Instance: "main", Name: "homelab-cluster", Quorate: false, ExpectedVotes: 3, PresentVotes: 1,
+		HAMaster:    "pve",
+		HAResources: []model.HAResource{{SID: "vm:100", State: "fence", Node: "pve2", RequestedState: "started"}},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/nodes", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "homelab-cluster")
+	assert.Contains(t, body, "No quorum")
+	assert.Contains(t, body, "1/3")
+	assert.Contains(t, body, "vm:100")
+	assert.Contains(t, body, "fence")
+}
+
 // --- handleGuestsFragment ---
 
 func TestHandleGuestsFragment_Empty(t *testing.T) {
@@ -174,6 +235,7 @@ func TestHandleGuestsFragment_Populated(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "network-services")
+	assert.Contains(t, w.Body.String(), `hx-get="/fragments/guest/pve1/101"`)
 }
 
 // --- handleBackupsFragment ---
@@ -199,6 +261,39 @@ func TestHandleBackupsFragment_Populated(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestHandleBackupsFragment_PVEBackupJobs(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	populateCache(c)
+	c.UpdateGuests("pve1", map[int]*model.Guest{
+		101: {Instance: "pve1", Node: "node1", ClusterID: "pve1", VMID: 101, Name: "network-services", Type: "lxc", Status: "running"},
+		102: {Instance: "pve1", Node: "node1", ClusterID: "pve1", VMID: 102, Name: "scratch", Type: "lxc", Status: "running"},
+	})
+	c.UpdateBackupJobs("pve1", []*model.PVEBackupJob{
+		{Instance: "pve1", ID: "backup-nightly", Enabled: true, Schedule: "21:00", Storage: "nfs", VMIDs: []int{101}},
+	})
+	c.UpdateGuestBackups("pve1", map[int]*model.PVEGuestBackup{
+		101: {Instance: "pve1", ClusterID: "pve1", VMID: 101, Jobs: []string{"backup-nightly"}, LastSuccess: time.Now().Unix(), LastStatus: "OK"},
+		102: {Instance: "pve1", ClusterID: "pve1", VMID: 102},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/backups", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "tbl-backup-jobs")
+	assert.Contains(t, body, "backup-nightly")
+	assert.Contains(t, body, "tbl-uncovered")
+	assert.Contains(t, body, "scratch")
+
+	req = httptest.NewRequest(http.MethodGet, "/fragments/guests", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "vzdump")
+}
+
 // --- handleEventsFragment ---
 
 func TestHandleEventsFragment_Empty(t *testing.T) {
@@ -222,6 +317,189 @@ func TestHandleEventsFragment_Populated(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestHandleEventsFragment_PVETasks(t *testing.T) {
+	srv, c, st := newTestServer(t)
+	populateCache(c)
+
+	now := time.Now().Unix()
+	end := now - 60
+	require.NoError(t, st.UpsertPVETask(&model.PVETask{
+		Instance: "pve1", UPID: "UPID:1", Node: "node1", Type: "qmigrate", ID: "101",
+		User: "alice@pve", StartTime: now - 120, EndTime: &end, Status: "migration aborted",
+	}))
+	require.NoError(t, st.UpsertPVETask(&model.PVETask{
+		Instance: "pve1", UPID: "UPID:2", Node: "node1", Type: "vzsnapshot", ID: "102",
+		User: "root@pam", StartTime: now - 100, EndTime: &end, Status: "OK",
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/events", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "events-filter")
+	assert.Contains(t, body, "alice@pve")
+	assert.Contains(t, body, "vzsnapshot")
+
+	req = httptest.NewRequest(http.MethodGet, "/fragments/events?source=pve&status=failed", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	body = w.Body.String()
+	assert.Contains(t, body, "migration aborted")
+	assert.NotContains(t, body, "root@pam")
+}
+
+func TestHandleEventsFragment_StoreErrorFallsBackToCache(t *testing.T) {
+	srv, c, st := newTestServer(t)
+	c.UpdatePVETasks("pve1", []*model.PVETask{
+		{Instance: "pve1", UPID: "UPID:1", Node: "node1", Type: "qmigrate", ID: "101", User: "alice@pve", StartTime: time.Now().Unix()},
+	})
+	st.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/events", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "alice@pve")
+}
+
+// --- handleGuestDetailFragment ---
+
+func TestHandleGuestDetailFragment(t *testing.T) {
+	srv, c, st := newTestServer(t)
+	populateCache(c)
+	c.UpdateGuestConfigs("pve1", map[int]*model.GuestConfig{
+		101: {
+			Instance: "pve1", ClusterID: "pve1", VMID: 101, Cores: 2, Memory: 2 << 30,
+			Disks: []model.GuestDisk{{Key: "rootfs", Volume: "local-lvm:subvol-101-disk-0", Size: 8 << 30}},
+			NICs:  []model.GuestNIC{{Key: "net0", Model: "veth", Bridge: "vmbr0", MAC: "BC:24:11:11:22:33"}},
+		},
+	})
+
+	now := time.Now().Unix()
+	for i := range 3 {
+		require.NoError(t, st.InsertGuestSnapshot(model.GuestSnapshot{
+			Timestamp: now - int64((2-i)*60), Instance: "pve1", VMID: 101, Node: "node1", ClusterID: "pve1",
+			GuestType: "lxc", Name: "network-services", Status: "running", CPUPct: float64(5 + i),
+			MemUsed: 512 << 20, MemTotal: 2 << 30, NetIn: int64(i * 60_000), NetOut: int64(i * 6_000),
+		}))
+	}
+	end := now - 30
+	require.NoError(t, st.UpsertPVETask(&model.PVETask{
+		Instance: "pve1", UPID: "UPID:1", Node: "node1", Type: "vzsnapshot", ID: "101",
+		User: "alice@pve", StartTime: now - 60, EndTime: &end, Status: "OK",
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/guest/pve1/101", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "network-services")
+	assert.Contains(t, body, "gd-chart-svg")
+	assert.Contains(t, body, "tbl-guest-disks")
+	assert.Contains(t, body, "BC:24:11:11:22:33")
+	assert.Contains(t, body, "alice@pve")
+}
+
+func TestHandleGuestDetailFragment_NoConfig(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	populateCache(c)
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/guest/pve1/101?hours=6", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Configuration not collected yet")
+	assert.Contains(t, w.Body.String(), `class="btn-details active" hx-get="/fragments/guest/pve1/101?hours=6"`)
+}
+
+func TestHandleGuestDetailFragment_LongWindow(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	populateCache(c)
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/guest/pve1/101?hours=720", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `class="btn-details active" hx-get="/fragments/guest/pve1/101?hours=720"`)
+	assert.Contains(t, w.Body.String(), ">30d<")
+}
+
+func TestHandleGuestDetailFragment_Errors(t *testing.T) {
+	srv, c, st := newTestServer(t)
+	populateCache(c)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/fragments/guest/pve1/abc", http.StatusBadRequest},
+		{"/fragments/guest/pve1/999", http.StatusNotFound},
+		{"/fragments/guest/other/101", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+
+	st.Close()
+	req := httptest.NewRequest(http.MethodGet, "/fragments/guest/pve1/101", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+// --- handleCephFragment ---
+
+func TestHandleCephFragment_Empty(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/fragments/ceph", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "Ceph")
+}
+
+func TestHandleCephFragment_Populated(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateCeph("main", &model.CephStatus{
+		Instance:   "main",
+		Health:     "HEALTH_WARN",
+		Checks:     []model.CephHealthCheck{{Code: "OSD_DOWN", Severity: "HEALTH_WARN", Summary: "1 osds down"}},
+		Monitors:   []model.CephMonitor{{Name: "pve", InQuorum: true}, {Name: "pve2"}},
+		NumOSDs:    3,
+		NumUpOSDs:  2,
+		NumInOSDs:  3,
+		OSDs:       []model.CephOSD{{ID: 0, Name: "osd.0", Host: "pve", Status: "up", In: true}, {ID: 1, Name: "osd.1", Host: "pve2", Status: "down"}},
+		Pools:      []model.CephPool{{Name: "vm-pool", Size: 3, MinSize: 2, PGNum: 128, UsedPct: 42}},
+		NumPGs:     128,
+		PGStates:   []model.CephPGState{{State: "active+clean", Count: 128}},
+		BytesUsed:  1 << 40,
+		BytesTotal: 4 << 40,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/ceph", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "OSD_DOWN")
+	assert.Contains(t, body, "1 osds down")
+	assert.Contains(t, body, "vm-pool")
+	assert.Contains(t, body, "osd.1")
+	assert.Contains(t, body, "active+clean")
+	assert.Contains(t, body, "1/2")
+}
+
 // --- handleDisksFragment ---
 
 func TestHandleDisksFragment_Empty(t *testing.T) {
@@ -246,6 +524,228 @@ func TestHandleDisksFragment_Populated(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Samsung 870 EVO")
 }
 
+func TestHandleDisksFragment_Hottest(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-a": {WWN: "wwn-a", DevPath: "/dev/sda", Node: "node1", DiskType: "hdd", Temperature: new(53)},
+		"wwn-b": {WWN: "wwn-b", DevPath: "/dev/sdb", Node: "node2", DiskType: "ssd", Temperature: new(45)},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/disks", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "Hottest")
+	assert.Contains(t, body, "/dev/sda 53C")
+	assert.Less(t, strings.Index(body, "/dev/sda 53C"), strings.Index(body, "/dev/sdb 45C"))
+	assert.Contains(t, body, `class="text-warn">53C</td>`)
+}
+
+func TestHandleDisksFragment_ReplaceSoon(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-a": {WWN: "wwn-a", DevPath: "/dev/sda", Node: "node1", Risk: &model.DiskRisk{AnnualFailurePct: 1.5}},
+		"wwn-b": {WWN: "wwn-b", DevPath: "/dev/sdb", Node: "node1", Risk: &model.DiskRisk{AnnualFailurePct: 31.2}},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/disks", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "Replace soon")
+	assert.Less(t, strings.Index(body, "Replace soon"), strings.Index(body, "31.2%"))
+
+	srv, c, _ = newTestServer(t)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-a": {WWN: "wwn-a", DevPath: "/dev/sda", Node: "node1", Risk: &model.DiskRisk{AnnualFailurePct: 1.5}},
+	})
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.NotContains(t, w.Body.String(), "Replace soon")
+}
+
+// --- handleDisks / handleDisk / handleDiskHistory ---
+
+func TestHandleDisks(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-b": {WWN: "wwn-b", Instance: "pve1", Node: "node2", DevPath: "/dev/sda"},
+		"wwn-a": {WWN: "wwn-a", Instance: "pve1", Node: "node1", DevPath: "/dev/sdb", Health: "PASSED",
+			Attributes: []model.SMARTAttribute{{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: 3}}},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/disks", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	var resp []model.Disk
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, "wwn-a", resp[0].WWN)
+	require.Len(t, resp[0].Attributes, 1)
+	assert.Equal(t, int64(3), resp[0].Attributes[0].RawValue)
+	assert.Equal(t, "wwn-b", resp[1].WWN)
+}
+
+func TestHandleDisks_Empty(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/disks", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
+func TestHandleDisk(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	populateCache(c)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/disks/wwn-test-001", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp model.Disk
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "wwn-test-001", resp.WWN)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/disks/wwn-nonexistent", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleDiskHistory(t *testing.T) {
+	srv, _, s := newTestServer(t)
+	now := time.Now().Unix()
+	// Removed from the cache but still in the inventory
+	require.NoError(t, s.UpsertDisk(&model.Disk{WWN: "wwn-test-001", Instance: "pve1", Node: "node1", DevPath: "/dev/sda", DiskType: "hdd", Protocol: "ata"}))
+	for i, realloc := range []int64{0, 2} {
+		require.NoError(t, s.InsertSMARTSnapshot(now-int64((1-i)*3600), &model.Disk{
+			WWN:    "wwn-test-001",
+			Health: "PASSED",
+			Attributes: []model.SMARTAttribute{
+				{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: realloc},
+				{ID: 9, Name: "Power_On_Hours", RawValue: 1000},
+			},
+		}))
+	}
+	require.NoError(t, s.InsertSMARTSnapshot(now-10*24*3600, &model.Disk{WWN: "wwn-test-001", Health: "PASSED"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/disks/wwn-test-001/history", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []model.DiskSnapshot
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 2) // default window is 7 days
+	assert.Len(t, resp[0].Attributes, 2)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/disks/wwn-test-001/history?attr=5&hours=720", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	resp = nil
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 3)
+	require.Len(t, resp[2].Attributes, 1)
+	assert.Equal(t, 5, resp[2].Attributes[0].ID)
+	assert.Equal(t, int64(2), resp[2].Attributes[0].RawValue)
+}
+
+func TestHandleDiskHistory_EmptyAndUnknown(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	populateCache(c)
+
+	// In the cache but with no history yet
+	req := httptest.NewRequest(http.MethodGet, "/api/disks/wwn-test-001/history", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/disks/wwn-nonexistent/history", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleDiskHistory_InvalidAttr(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/disks/wwn-test-001/history?attr=5,abc", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandleDiskHistory_StoreError(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.New(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+	srv := NewServer(":0", cache.New(), s)
+	s.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/disks/wwn-test-001/history", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+// --- handleDiskRisk ---
+
+func TestHandleDiskRisk(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-a": {WWN: "wwn-a", DevPath: "/dev/sda", Node: "node1", Risk: &model.DiskRisk{AnnualFailurePct: 1.5}},
+		"wwn-b": {WWN: "wwn-b", DevPath: "/dev/sdb", Node: "node1", Risk: &model.DiskRisk{
+			AnnualFailurePct: 25,
+			Factors:          []model.RiskFactor{{Source: model.RiskAttribute, Name: "Reallocated_Sector_Ct", Pct: 23.5}},
+		}},
+		"wwn-c": {WWN: "wwn-c", DevPath: "/dev/sdc", Node: "node1"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/disks/risk", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []diskRiskEntry
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, 1, resp[0].Rank)
+	assert.Equal(t, "wwn-b", resp[0].WWN)
+	assert.True(t, resp[0].ReplaceSoon)
+	require.Len(t, resp[0].Risk.Factors, 1)
+	assert.Equal(t, model.RiskAttribute, resp[0].Risk.Factors[0].Source)
+	assert.Equal(t, 2, resp[1].Rank)
+	assert.False(t, resp[1].ReplaceSoon)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/disks/risk?limit=1", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, "wwn-b", resp[0].WWN)
+}
+
+func TestHandleDiskRisk_Empty(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/disks/risk", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
 // --- handleDiskDetailFragment ---
 
 func TestHandleDiskDetailFragment_Found(t *testing.T) {
@@ -261,6 +761,87 @@ func TestHandleDiskDetailFragment_Found(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "wwn-test-001")
 }
 
+func TestHandleDiskDetailFragment_Trends(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	week := int64(8)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-trend": {
+			WWN: "wwn-trend", DevPath: "/dev/sdb", Status: model.StatusDegrading,
+			Attributes: []model.SMARTAttribute{{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: 8}},
+			Trends:     []model.AttributeTrend{{ID: 5, Name: "Reallocated_Sector_Ct", Current: 8, Delta7d: &week}},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/disk/wwn-trend", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "Error counter trends")
+	assert.Contains(t, body, `<td class="text-warn">+8</td>`)
+	assert.Contains(t, body, `/fragments/sparkline/disk/wwn-trend?metric=temperature`)
+}
+
+func TestHandleDiskDetailFragment_SelfTests(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	ran := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	lba := int64(1234567890)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-st": {
+			WWN: "wwn-st", DevPath: "/dev/sda",
+			Attributes: []model.SMARTAttribute{{ID: 5, Name: "Reallocated_Sector_Ct"}},
+			SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{
+				{Type: "long", Result: "failed", Status: "Completed: read failure", LifetimeHours: 23651, LBAFirstError: &lba, Time: &ran},
+			}},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/disk/wwn-st", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "Self-tests")
+	assert.Contains(t, body, `<td class="text-crit">failed</td>`)
+	assert.Contains(t, body, "Completed: read failure")
+	assert.Contains(t, body, "2026-03-01 12:00")
+	assert.Contains(t, body, "1234567890")
+
+	// The table row summarizes the newest test.
+	req = httptest.NewRequest(http.MethodGet, "/fragments/disks", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), `class="text-crit">long failed, `)
+}
+
+func TestHandleInventoryFragment(t *testing.T) {
+	srv, c, st := newTestServer(t)
+	removed := time.Now()
+	c.UpdateInventory("main", []*model.InventoryDisk{
+		{WWN: "wwn-a", Instance: "main", Node: "pve", DevPath: "/dev/sda", Model: "WD Red", Serial: "WD-1", RemovedAt: &removed, ReplacedBy: "wwn-b"},
+		{WWN: "wwn-b", Instance: "main", Node: "pve", DevPath: "/dev/sda", Model: "WD Red Plus", Serial: "WD-2"},
+	})
+	var b store.Batch
+	b.AddDiskEvent(&model.DiskEvent{Timestamp: 100, Type: model.DiskEventAdded, WWN: "wwn-a", Instance: "main", Node: "pve", DevPath: "/dev/sda", Model: "WD Red", Serial: "WD-1"})
+	b.AddDiskEvent(&model.DiskEvent{Timestamp: 200, Type: model.DiskEventReplaced, WWN: "wwn-b", Instance: "main", Node: "pve", DevPath: "/dev/sda",
+		Model: "WD Red Plus", Serial: "WD-2", PrevWWN: "wwn-a", PrevSerial: "WD-1"})
+	require.NoError(t, st.WriteBatch(&b))
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/inventory", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "2 disks seen")
+	assert.Contains(t, body, ">replaced</span>")
+	assert.Contains(t, body, "Slot history")
+	assert.Contains(t, body, "replaced S/N WD-1")
+	assert.Less(t, strings.Index(body, "WD Red S/N WD-1"), strings.Index(body, "WD Red Plus S/N WD-2"))
+}
+
 func TestHandleDiskDetailFragment_NotFound(t *testing.T) {
 	srv, _, _ := newTestServer(t)
 	req := httptest.NewRequest(http.MethodGet, "/fragments/disk/wwn-nonexistent", nil)
@@ -357,6 +938,28 @@ func TestHandleNodeSparkline_MemoryMetric(t *testing.T) {
 	assert.InDelta(t, 50.0, points[0].Value, 0.1)
 }
 
+func TestHandleNodeSparkline_NetInMetric(t *testing.T) {
+	srv, _, s := newTestServer(t)
+
+	netIn := 117_000_000.0
+	require.NoError(t, s.InsertNodeSnapshot(model.NodeSnapshot{
+		Timestamp: time.Now().Add(-1 * time.Hour).Unix(),
+		Instance:  "pve1",
+		Node:      "node1",
+		NetIn:     &netIn,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sparkline/node/pve1/node1?metric=netin", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var points []model.SparklinePoint
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&points))
+	require.Len(t, points, 1)
+	assert.Equal(t, netIn, points[0].Value)
+}
+
 func TestHandleNodeSparkline_InvalidHoursIgnored(t *testing.T) {
 	srv, _, _ := newTestServer(t)
 
@@ -371,7 +974,7 @@ func TestHandleNodeSparkline_InvalidHoursIgnored(t *testing.T) {
 func TestHandleNodeSparkline_HoursOutOfRange(t *testing.T) {
 	srv, _, _ := newTestServer(t)
 
-	// hours=0 is out of range (must be >0 and <=168), should fallback to 24
+	// hours=0 is out of range (must be >0 and <=8760), should fallback to 24
 	req := httptest.NewRequest(http.MethodGet, "/api/sparkline/node/pve1/node1?hours=0", nil)
 	w := httptest.NewRecorder()
 	srv.mux.ServeHTTP(w, req)
@@ -400,6 +1003,77 @@ func TestHandleNodeSparkline_UnknownMetric(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+// --- handleDiskSparkline ---
+
+func TestHandleDiskSparkline_DefaultsToTemperatureWeek(t *testing.T) {
+	srv, _, s := newTestServer(t)
+
+	now := time.Now()
+	for i, temp := range []int{44, 46, 49} {
+		require.NoError(t, s.InsertSMARTSnapshot(now.Add(-time.Duration(i)*48*time.Hour).Unix(),
+			&model.Disk{WWN: "wwn-hot", Health: "PASSED", Temperature: &temp}))
+	}
+	old := 30
+	require.NoError(t, s.InsertSMARTSnapshot(now.Add(-10*24*time.Hour).Unix(),
+		&model.Disk{WWN: "wwn-hot", Health: "PASSED", Temperature: &old}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sparkline/disk/wwn-hot", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var points []model.SparklinePoint
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&points))
+	require.Len(t, points, 3)
+	assert.InDelta(t, 49.0, points[0].Value, 0)
+	assert.InDelta(t, 44.0, points[2].Value, 0)
+}
+
+func TestHandleDiskSparkline_Hours(t *testing.T) {
+	srv, _, s := newTestServer(t)
+
+	now := time.Now()
+	temp := 40
+	require.NoError(t, s.InsertSMARTSnapshot(now.Add(-2*time.Hour).Unix(), &model.Disk{WWN: "wwn-1", Health: "PASSED", Temperature: &temp}))
+	require.NoError(t, s.InsertSMARTSnapshot(now.Unix(), &model.Disk{WWN: "wwn-1", Health: "PASSED", Temperature: &temp}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sparkline/disk/wwn-1?hours=1", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var points []model.SparklinePoint
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&points))
+	assert.Len(t, points, 1)
+}
+
+func TestHandleDiskSparkline_UnknownMetric(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sparkline/disk/wwn-1?metric=bogus", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestHandleDiskSparklineSVG(t *testing.T) {
+	srv, _, s := newTestServer(t)
+
+	now := time.Now()
+	for i, temp := range []int{41, 43} {
+		require.NoError(t, s.InsertSMARTSnapshot(now.Add(-time.Duration(i)*time.Hour).Unix(),
+			&model.Disk{WWN: "wwn-1", Health: "PASSED", Temperature: &temp}))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/fragments/sparkline/disk/wwn-1", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<polyline")
+	assert.Contains(t, w.Body.String(), "temperature 7d")
+}
+
 // --- handleGuestSparkline ---
 
 func TestHandleGuestSparkline_Valid(t *testing.T) {
@@ -433,6 +1107,41 @@ func TestHandleGuestSparkline_Valid(t *testing.T) {
 	assert.Len(t, points, 1)
 }
 
+func TestHandleGuestSparkline_RateMetric(t *testing.T) {
+	srv, _, s := newTestServer(t)
+
+	now := time.Now()
+	rate := 2048.0
+	for i, r := range []*float64{nil, &rate} {
+		require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+			Timestamp: now.Add(time.Duration(i-2) * time.Minute).Unix(),
+			Instance:  "pve1", VMID: 101, Node: "node1", GuestType: "lxc", Name: "network-services", Status: "running",
+			NetOutRate: r,
+		}))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sparkline/guest/pve1/101?metric=netout", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var points []model.SparklinePoint
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&points))
+	require.Len(t, points, 1, "samples without a rate are omitted")
+	assert.Equal(t, rate, points[0].Value)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/sparkline/guest/pve1/101?metric=bogus", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/fragments/sparkline/guest/pve1/101?metric=netout", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "netout 24h")
+}
+
 func TestHandleGuestSparkline_InvalidVMID(t *testing.T) {
 	srv, _, _ := newTestServer(t)
 
@@ -497,6 +1206,27 @@ func TestHandleNodeSparklineSVG_WithData(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "polyline")
 }
 
+func TestHandleNodeSparkline_LongWindowIncludesRawTail(t *testing.T) {
+	srv, _, s := newTestServer(t)
+	now := time.Now().Unix()
+	require.NoError(t, s.InsertNodeSnapshot(model.NodeSnapshot{
+		Timestamp: now, Instance: "pve1", Node: "node1", CPUPct: 10,
+	}))
+
+	// Samples the pruner has not rolled up yet are read from the raw table.
+	req := httptest.NewRequest(http.MethodGet, "/api/sparkline/node/pve1/node1?hours=720", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`[{"ts": %d, "value": 10}]`, now), w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/fragments/sparkline/node/pve1/node1?hours=720", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "polyline")
+}
+
 func TestHandleNodeSparklineSVG_Empty(t *testing.T) {
 	srv, _, _ := newTestServer(t)
 
@@ -585,6 +1315,56 @@ func TestHandleHealthz_WithData(t *testing.T) {
 	assert.Contains(t, collectors, "pve1")
 }
 
+type nopSink struct{}
+
+func (nopSink) Name() string                                  { return "nop" }
+func (nopSink) Write(context.Context, *store.Snapshots) error { return nil }
+
+func TestHandleHealthz_MetricsSinks(t *testing.T) {
+	srv, _, st := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	var resp map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.NotContains(t, resp, "metrics_sinks")
+
+	st.AddSink(nopSink{})
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	resp = nil
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	sinks, ok := resp["metrics_sinks"].(map[string]any)
+	require.True(t, ok)
+	assert.InDelta(t, 0, sinks["dropped"], 0)
+	assert.Contains(t, sinks, "queued")
+}
+
+func TestHandleHealthz_DatabaseSize(t *testing.T) {
+	srv, _, st := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	db, ok := resp["database"].(map[string]any)
+	require.True(t, ok)
+	assert.Positive(t, db["size_bytes"])
+	assert.Contains(t, db, "free_bytes")
+
+	// A store error drops the database section but keeps the check healthy.
+	require.NoError(t, st.Close())
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	resp = nil
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.NotContains(t, resp, "database")
+}
+
 // --- Server.Run ---
 
 func TestServerRun_GracefulShutdown(t *testing.T) {
@@ -741,7 +1521,7 @@ func TestRenderHTML_WriteBodyFail(t *testing.T) {
 	r := httptest.NewRequest(http.MethodGet, "/", nil)
 	// The component renders fine; the subsequent Write to w returns an error.
 	// This exercises the slog.Debug path — must not panic.
-	renderHTML(w, r, templates.Dashboard(cache.CacheSnapshot{}))
+	renderHTML(w, r, templates.Dashboard(cache.CacheSnapshot{}, nil))
 }
 
 func TestWriteJSON_MarshalError(t *testing.T) {
@@ -878,6 +1658,66 @@ func TestHandleWidget_WithData(t *testing.T) {
 	assert.Equal(t, int64(2000), resp.Backups.LastBackupTime)
 }
 
+func TestHandleWidget_SSDEndurance(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	soon, later := time.Now().Add(90*24*time.Hour), time.Now().Add(900*24*time.Hour)
+	rateA, rateB := 0.25, 0.5
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-a":   {Endurance: &model.Endurance{WriteTBPerDay: &rateA, EndOfLife: &later}},
+		"wwn-b":   {Endurance: &model.Endurance{WriteTBPerDay: &rateB, EndOfLife: &soon}},
+		"wwn-new": {Endurance: &model.Endurance{}},
+		"wwn-hdd": {},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/widget", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp widgetResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.InDelta(t, 0.75, resp.Disks.WriteTBPerDay, 1e-9)
+	require.NotNil(t, resp.Disks.ShortestLifeMonths)
+	assert.InDelta(t, 90/30.44, *resp.Disks.ShortestLifeMonths, 0.01)
+}
+
+func TestHandleWidget_DiskTemperature(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-hdd":  {DiskType: "hdd", Temperature: new(52)},
+		"wwn-ssd":  {DiskType: "ssd", Temperature: new(55)},
+		"wwn-nvme": {DiskType: "nvme", Temperature: new(71)},
+		"wwn-none": {DiskType: "hdd"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/widget", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp widgetResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotNil(t, resp.Disks.HottestTemp)
+	assert.Equal(t, 71, *resp.Disks.HottestTemp)
+	assert.Equal(t, 2, resp.Disks.Hot)
+}
+
+func TestHandleWidget_ReplaceSoon(t *testing.T) {
+	srv, c, _ := newTestServer(t)
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-a": {Risk: &model.DiskRisk{AnnualFailurePct: 1.5}},
+		"wwn-b": {Risk: &model.DiskRisk{AnnualFailurePct: 10}},
+		"wwn-c": {Risk: &model.DiskRisk{AnnualFailurePct: 42}},
+		"wwn-d": {},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/widget", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp widgetResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 2, resp.Disks.ReplaceSoon)
+}
+
 func TestHandleWidget_CPUAveragedAcrossNodes(t *testing.T) {
 	// CPU must be averaged over online node count, not summed.
 	srv, c, _ := newTestServer(t)
@@ -985,3 +1825,76 @@ func TestHandleWidget_GuestPausedStatus(t *testing.T) {
 	assert.Equal(t, 1, resp.Guests.Running)
 	assert.Equal(t, 0, resp.Guests.Stopped)
 }
+
+func TestHandleWidget_Throughput(t *testing.T) {
+	// Throughput sums rates over running guests only.
+	srv, c, _ := newTestServer(t)
+	c.UpdateGuests("pve1", map[int]*model.Guest{
+		100: {Type: "qemu", Status: "running", NetInRate: 1000, NetOutRate: 500, DiskReadRate: 4096, DiskWriteRate: 2048},
+		101: {Type: "lxc", Status: "running", NetInRate: 24, NetOutRate: 12},
+		102: {Type: "lxc", Status: "stopped", NetInRate: 9999},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/widget", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp widgetResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 1024.0, resp.Throughput.NetIn)
+	assert.Equal(t, 512.0, resp.Throughput.NetOut)
+	assert.Equal(t, 4096.0, resp.Throughput.DiskRead)
+	assert.Equal(t, 2048.0, resp.Throughput.DiskWrite)
+}
+
+// --- /api/export ---
+
+func TestHandleExport_CSV(t *testing.T) {
+	srv, _, st := newTestServer(t)
+	now := time.Now().Unix()
+	require.NoError(t, st.InsertAlert(now-7200, "guest_down", "pve1", "101", "old", "critical"))
+	require.NoError(t, st.InsertAlert(now, "guest_down", "pve1", "101", "new", "critical"))
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/export?table=alert_log&since=%d", now-60), nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="alert_log.csv"`, w.Header().Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "id,ts,alert_type,instance,subject,message,severity", lines[0])
+	assert.Contains(t, lines[1], ",new,")
+}
+
+func TestHandleExport_BadRequest(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	for _, url := range []string{
+		"/api/export",
+		"/api/export?table=schema_version",
+		"/api/export?table=alert_log&since=yesterday",
+		"/api/export?table=alert_log&since=-1",
+	} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?table=nope", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), "guest_snapshots")
+}
+
+func TestHandleExport_StoreError(t *testing.T) {
+	srv, _, st := newTestServer(t)
+	require.NoError(t, st.Close())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?table=alert_log", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
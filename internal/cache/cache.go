@@ -2,6 +2,7 @@ package cache
 
 import (
 	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,36 +13,57 @@ import (
 type Cache struct {
 	mu sync.RWMutex
 
-	Nodes      map[string]map[string]*model.Node
-	Guests     map[string]map[int]*model.Guest
-	Disks      map[string]*model.Disk
-	Datastores map[string]map[string]*model.DatastoreStatus
-	Backups    map[string]map[string]*model.Backup
-	Tasks      map[string][]*model.PBSTask
-	LastPoll   map[string]time.Time
+	Nodes        map[string]map[string]*model.Node
+	Guests       map[string]map[int]*model.Guest
+	Disks        map[string]*model.Disk
+	Inventory    map[string][]*model.InventoryDisk
+	Datastores   map[string]map[string]*model.DatastoreStatus
+	Backups      map[string]map[string]*model.Backup
+	Tasks        map[string][]*model.PBSTask
+	PVETasks     map[string][]*model.PVETask
+	Ceph         map[string]*model.CephStatus
+	Clusters     map[string]*model.ClusterStatus
+	BackupJobs   map[string][]*model.PVEBackupJob
+	GuestBackups map[string]map[int]*model.PVEGuestBackup
+	GuestConfigs map[string]map[int]*model.GuestConfig
+	LastPoll     map[string]time.Time
 }
 
 // CacheSnapshot is a read-only deep copy of the cache state.
 type CacheSnapshot struct {
-	Nodes      map[string]map[string]*model.Node
-	Guests     map[string]map[int]*model.Guest
-	Disks      map[string]*model.Disk
-	Datastores map[string]map[string]*model.DatastoreStatus
-	Backups    map[string]map[string]*model.Backup
-	Tasks      map[string][]*model.PBSTask
-	LastPoll   map[string]time.Time
+	Nodes        map[string]map[string]*model.Node
+	Guests       map[string]map[int]*model.Guest
+	Disks        map[string]*model.Disk
+	Inventory    map[string][]*model.InventoryDisk
+	Datastores   map[string]map[string]*model.DatastoreStatus
+	Backups      map[string]map[string]*model.Backup
+	Tasks        map[string][]*model.PBSTask
+	PVETasks     map[string][]*model.PVETask
+	Ceph         map[string]*model.CephStatus
+	Clusters     map[string]*model.ClusterStatus
+	BackupJobs   map[string][]*model.PVEBackupJob
+	GuestBackups map[string]map[int]*model.PVEGuestBackup
+	GuestConfigs map[string]map[int]*model.GuestConfig
+	LastPoll     map[string]time.Time
 }
 
 // New returns an initialized Cache.
 func New() *Cache {
 	return &Cache{
-		Nodes:      make(map[string]map[string]*model.Node),
-		Guests:     make(map[string]map[int]*model.Guest),
-		Disks:      make(map[string]*model.Disk),
-		Datastores: make(map[string]map[string]*model.DatastoreStatus),
-		Backups:    make(map[string]map[string]*model.Backup),
-		Tasks:      make(map[string][]*model.PBSTask),
-		LastPoll:   make(map[string]time.Time),
+		Nodes:        make(map[string]map[string]*model.Node),
+		Guests:       make(map[string]map[int]*model.Guest),
+		Disks:        make(map[string]*model.Disk),
+		Inventory:    make(map[string][]*model.InventoryDisk),
+		Datastores:   make(map[string]map[string]*model.DatastoreStatus),
+		Backups:      make(map[string]map[string]*model.Backup),
+		Tasks:        make(map[string][]*model.PBSTask),
+		PVETasks:     make(map[string][]*model.PVETask),
+		Ceph:         make(map[string]*model.CephStatus),
+		Clusters:     make(map[string]*model.ClusterStatus),
+		BackupJobs:   make(map[string][]*model.PVEBackupJob),
+		GuestBackups: make(map[string]map[int]*model.PVEGuestBackup),
+		GuestConfigs: make(map[string]map[int]*model.GuestConfig),
+		LastPoll:     make(map[string]time.Time),
 	}
 }
 
@@ -51,13 +73,20 @@ func (c *Cache) Snapshot() CacheSnapshot {
 	defer c.mu.RUnlock()
 
 	snap := CacheSnapshot{
-		Nodes:      make(map[string]map[string]*model.Node, len(c.Nodes)),
-		Guests:     make(map[string]map[int]*model.Guest, len(c.Guests)),
-		Disks:      make(map[string]*model.Disk, len(c.Disks)),
-		Datastores: make(map[string]map[string]*model.DatastoreStatus, len(c.Datastores)),
-		Backups:    make(map[string]map[string]*model.Backup, len(c.Backups)),
-		Tasks:      make(map[string][]*model.PBSTask, len(c.Tasks)),
-		LastPoll:   make(map[string]time.Time, len(c.LastPoll)),
+		Nodes:        make(map[string]map[string]*model.Node, len(c.Nodes)),
+		Guests:       make(map[string]map[int]*model.Guest, len(c.Guests)),
+		Disks:        make(map[string]*model.Disk, len(c.Disks)),
+		Inventory:    make(map[string][]*model.InventoryDisk, len(c.Inventory)),
+		Datastores:   make(map[string]map[string]*model.DatastoreStatus, len(c.Datastores)),
+		Backups:      make(map[string]map[string]*model.Backup, len(c.Backups)),
+		Tasks:        make(map[string][]*model.PBSTask, len(c.Tasks)),
+		PVETasks:     make(map[string][]*model.PVETask, len(c.PVETasks)),
+		Ceph:         make(map[string]*model.CephStatus, len(c.Ceph)),
+		Clusters:     make(map[string]*model.ClusterStatus, len(c.Clusters)),
+		BackupJobs:   make(map[string][]*model.PVEBackupJob, len(c.BackupJobs)),
+		GuestBackups: make(map[string]map[int]*model.PVEGuestBackup, len(c.GuestBackups)),
+		GuestConfigs: make(map[string]map[int]*model.GuestConfig, len(c.GuestConfigs)),
+		LastPoll:     make(map[string]time.Time, len(c.LastPoll)),
 	}
 
 	for inst, nodes := range c.Nodes {
@@ -87,6 +116,15 @@ func (c *Cache) Snapshot() CacheSnapshot {
 		snap.Disks[wwn] = &cp
 	}
 
+	for inst, disks := range c.Inventory {
+		sl := make([]*model.InventoryDisk, len(disks))
+		for i, d := range disks {
+			cp := *d
+			sl[i] = &cp
+		}
+		snap.Inventory[inst] = sl
+	}
+
 	for inst, stores := range c.Datastores {
 		m := make(map[string]*model.DatastoreStatus, len(stores))
 		for k, v := range stores {
@@ -114,6 +152,65 @@ func (c *Cache) Snapshot() CacheSnapshot {
 		snap.Tasks[inst] = sl
 	}
 
+	for inst, tasks := range c.PVETasks {
+		sl := make([]*model.PVETask, len(tasks))
+		for i, t := range tasks {
+			cp := *t
+			sl[i] = &cp
+		}
+		snap.PVETasks[inst] = sl
+	}
+
+	for inst, ceph := range c.Ceph {
+		cp := *ceph
+		cp.Checks = slices.Clone(ceph.Checks)
+		cp.Monitors = slices.Clone(ceph.Monitors)
+		cp.OSDs = slices.Clone(ceph.OSDs)
+		cp.Pools = slices.Clone(ceph.Pools)
+		cp.PGStates = slices.Clone(ceph.PGStates)
+		snap.Ceph[inst] = &cp
+	}
+
+	for inst, cl := range c.Clusters {
+		cp := *cl
+		cp.Nodes = slices.Clone(cl.Nodes)
+		cp.HAResources = slices.Clone(cl.HAResources)
+		snap.Clusters[inst] = &cp
+	}
+
+	for inst, jobs := range c.BackupJobs {
+		sl := make([]*model.PVEBackupJob, len(jobs))
+		for i, j := range jobs {
+			cp := *j
+			cp.VMIDs = slices.Clone(j.VMIDs)
+			cp.Exclude = slices.Clone(j.Exclude)
+			cp.PoolMembers = slices.Clone(j.PoolMembers)
+			sl[i] = &cp
+		}
+		snap.BackupJobs[inst] = sl
+	}
+
+	for cid, backups := range c.GuestBackups {
+		m := make(map[int]*model.PVEGuestBackup, len(backups))
+		for k, v := range backups {
+			cp := *v
+			cp.Jobs = slices.Clone(v.Jobs)
+			m[k] = &cp
+		}
+		snap.GuestBackups[cid] = m
+	}
+
+	for cid, configs := range c.GuestConfigs {
+		m := make(map[int]*model.GuestConfig, len(configs))
+		for k, v := range configs {
+			cp := *v
+			cp.Disks = slices.Clone(v.Disks)
+			cp.NICs = slices.Clone(v.NICs)
+			m[k] = &cp
+		}
+		snap.GuestConfigs[cid] = m
+	}
+
 	maps.Copy(snap.LastPoll, c.LastPoll)
 
 	return snap
@@ -141,6 +238,23 @@ func (c *Cache) UpdateDisks(disks map[string]*model.Disk) {
 	maps.Copy(c.Disks, disks)
 }
 
+// RemoveDisks deletes the given disks, e.g. ones that have been removed from
+// their node, from the disk table.
+func (c *Cache) RemoveDisks(wwns []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, wwn := range wwns {
+		delete(c.Disks, wwn)
+	}
+}
+
+// UpdateInventory replaces the disk inventory for the given PVE instance.
+func (c *Cache) UpdateInventory(instance string, disks []*model.InventoryDisk) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Inventory[instance] = disks
+}
+
 // UpdateDatastores replaces all datastores for the given PBS instance.
 func (c *Cache) UpdateDatastores(pbsInstance string, datastores map[string]*model.DatastoreStatus) {
 	c.mu.Lock()
@@ -162,6 +276,58 @@ func (c *Cache) UpdateTasks(pbsInstance string, tasks []*model.PBSTask) {
 	c.Tasks[pbsInstance] = tasks
 }
 
+// UpdatePVETasks replaces the recent cluster task list for the given PVE instance.
+func (c *Cache) UpdatePVETasks(instance string, tasks []*model.PVETask) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.PVETasks[instance] = tasks
+}
+
+// UpdateCeph replaces the Ceph status for the given PVE instance.
+// A nil status removes the entry (Ceph not installed or unreachable).
+func (c *Cache) UpdateCeph(instance string, status *model.CephStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status == nil {
+		delete(c.Ceph, instance)
+		return
+	}
+	c.Ceph[instance] = status
+}
+
+// UpdateCluster replaces the cluster quorum/HA status for the given PVE instance.
+// A nil status removes the entry (standalone node or status unavailable).
+func (c *Cache) UpdateCluster(instance string, status *model.ClusterStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status == nil {
+		delete(c.Clusters, instance)
+		return
+	}
+	c.Clusters[instance] = status
+}
+
+// UpdateBackupJobs replaces all vzdump backup jobs for the given PVE instance.
+func (c *Cache) UpdateBackupJobs(instance string, jobs []*model.PVEBackupJob) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.BackupJobs[instance] = jobs
+}
+
+// UpdateGuestBackups replaces all per-guest vzdump state for the given cluster ID.
+func (c *Cache) UpdateGuestBackups(clusterID string, backups map[int]*model.PVEGuestBackup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.GuestBackups[clusterID] = backups
+}
+
+// UpdateGuestConfigs replaces all guest configurations for the given cluster ID.
+func (c *Cache) UpdateGuestConfigs(clusterID string, configs map[int]*model.GuestConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.GuestConfigs[clusterID] = configs
+}
+
 // UpdateNodeTemperature updates the temperature for a specific node.
 func (c *Cache) UpdateNodeTemperature(instance, node string, temp float64) {
 	c.mu.Lock()
@@ -18,6 +18,12 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, c.Datastores)
 	assert.NotNil(t, c.Backups)
 	assert.NotNil(t, c.Tasks)
+	assert.NotNil(t, c.PVETasks)
+	assert.NotNil(t, c.Ceph)
+	assert.NotNil(t, c.Clusters)
+	assert.NotNil(t, c.BackupJobs)
+	assert.NotNil(t, c.GuestBackups)
+	assert.NotNil(t, c.GuestConfigs)
 	assert.NotNil(t, c.LastPoll)
 }
 
@@ -63,6 +69,33 @@ func TestUpdateDisksMerge(t *testing.T) {
 	assert.Equal(t, "WD Red", snap.Disks["wwn-2"].Model)
 }
 
+func TestRemoveDisks(t *testing.T) {
+	c := New()
+	c.UpdateDisks(map[string]*model.Disk{
+		"wwn-1": {WWN: "wwn-1"},
+		"wwn-2": {WWN: "wwn-2"},
+	})
+	c.RemoveDisks([]string{"wwn-1", "wwn-unknown"})
+
+	snap := c.Snapshot()
+	assert.Len(t, snap.Disks, 1)
+	assert.Contains(t, snap.Disks, "wwn-2")
+}
+
+func TestUpdateInventory(t *testing.T) {
+	c := New()
+	c.UpdateInventory("homelab", []*model.InventoryDisk{{WWN: "wwn-1"}, {WWN: "wwn-2"}})
+	c.UpdateInventory("homelab", []*model.InventoryDisk{{WWN: "wwn-1", MissedPolls: 1}})
+
+	snap := c.Snapshot()
+	require.Len(t, snap.Inventory["homelab"], 1)
+	assert.Equal(t, 1, snap.Inventory["homelab"][0].MissedPolls)
+
+	// Snapshot entries are copies
+	snap.Inventory["homelab"][0].MissedPolls = 5
+	assert.Equal(t, 1, c.Snapshot().Inventory["homelab"][0].MissedPolls)
+}
+
 func TestUpdateDatastores(t *testing.T) {
 	c := New()
 	total := int64(1000000)
@@ -100,6 +133,136 @@ func TestUpdateTasks(t *testing.T) {
 	assert.Len(t, snap.Tasks["pbs1"], 2)
 }
 
+func TestUpdatePVETasks(t *testing.T) {
+	c := New()
+	c.UpdatePVETasks("main", []*model.PVETask{
+		{Instance: "main", UPID: "UPID:1", Type: "qmigrate", Status: "OK"},
+	})
+
+	snap := c.Snapshot()
+	require.Len(t, snap.PVETasks["main"], 1)
+
+	c.mu.Lock()
+	c.PVETasks["main"][0].Status = "MUTATED"
+	c.mu.Unlock()
+	assert.Equal(t, "OK", snap.PVETasks["main"][0].Status)
+}
+
+func TestUpdateGuestConfigs(t *testing.T) {
+	c := New()
+	c.UpdateGuestConfigs("main", map[int]*model.GuestConfig{
+		100: {
+			Instance: "main", VMID: 100, Cores: 4,
+			Disks: []model.GuestDisk{{Key: "scsi0", Size: 32 << 30}},
+			NICs:  []model.GuestNIC{{Key: "net0", Bridge: "vmbr0"}},
+		},
+	})
+
+	snap := c.Snapshot()
+	require.Contains(t, snap.GuestConfigs["main"], 100)
+	assert.Equal(t, 4, snap.GuestConfigs["main"][100].Cores)
+
+	c.mu.Lock()
+	c.GuestConfigs["main"][100].Disks[0].Key = "MUTATED"
+	c.GuestConfigs["main"][100].NICs[0].Bridge = "MUTATED"
+	c.mu.Unlock()
+	assert.Equal(t, "scsi0", snap.GuestConfigs["main"][100].Disks[0].Key)
+	assert.Equal(t, "vmbr0", snap.GuestConfigs["main"][100].NICs[0].Bridge)
+}
+
+func TestUpdateCeph(t *testing.T) {
+	c := New()
+	c.UpdateCeph("main", &model.CephStatus{Instance: "main", Health: "HEALTH_OK"})
+
+	snap := c.Snapshot()
+	require.Contains(t, snap.Ceph, "main")
+	assert.Equal(t, "HEALTH_OK", snap.Ceph["main"].Health)
+
+	// nil removes the entry
+	c.UpdateCeph("main", nil)
+	assert.NotContains(t, c.Snapshot().Ceph, "main")
+}
+
+func TestSnapshotDeepCopyCeph(t *testing.T) {
+	c := New()
+	c.UpdateCeph("main", &model.CephStatus{
+		Instance: "main",
+		Health:   "HEALTH_WARN",
+		Checks:   []model.CephHealthCheck{{Code: "OSD_DOWN", Severity: "HEALTH_WARN"}},
+		OSDs:     []model.CephOSD{{ID: 0, Status: "up"}},
+	})
+
+	snap := c.Snapshot()
+
+	c.mu.Lock()
+	c.Ceph["main"].Checks[0].Code = "MUTATED"
+	c.Ceph["main"].OSDs[0].Status = "down"
+	c.mu.Unlock()
+
+	assert.Equal(t, "OSD_DOWN", snap.Ceph["main"].Checks[0].Code)
+	assert.Equal(t, "up", snap.Ceph["main"].OSDs[0].Status)
+}
+
+func TestUpdateCluster(t *testing.T) {
+	c := New()
+	c.UpdateCluster("main", &model.ClusterStatus{
+		Instance:    "main",
+		Name:        "homelab",
+		Quorate:     true,
+		Nodes:       []model.ClusterNode{{Name: "pve1", Online: true}},
+		HAResources: []model.HAResource{{SID: "vm:100", State: "started"}},
+	})
+
+	snap := c.Snapshot()
+	require.Contains(t, snap.Clusters, "main")
+	assert.True(t, snap.Clusters["main"].Quorate)
+
+	// Snapshot slices are independent of the cache
+	c.mu.Lock()
+	c.Clusters["main"].HAResources[0].State = "error"
+	c.Clusters["main"].Nodes[0].Online = false
+	c.mu.Unlock()
+	assert.Equal(t, "started", snap.Clusters["main"].HAResources[0].State)
+	assert.True(t, snap.Clusters["main"].Nodes[0].Online)
+
+	c.UpdateCluster("main", nil)
+	assert.NotContains(t, c.Snapshot().Clusters, "main")
+}
+
+func TestUpdateBackupJobs(t *testing.T) {
+	c := New()
+	c.UpdateBackupJobs("main", []*model.PVEBackupJob{
+		{Instance: "main", ID: "nightly", All: true, Exclude: []int{999}},
+		{Instance: "main", ID: "prod", Pool: "prod", PoolMembers: []int{100}},
+	})
+
+	snap := c.Snapshot()
+	require.Len(t, snap.BackupJobs["main"], 2)
+
+	c.mu.Lock()
+	c.BackupJobs["main"][0].Exclude[0] = 1
+	c.BackupJobs["main"][1].PoolMembers[0] = 1
+	c.mu.Unlock()
+	assert.Equal(t, []int{999}, snap.BackupJobs["main"][0].Exclude)
+	assert.Equal(t, []int{100}, snap.BackupJobs["main"][1].PoolMembers)
+}
+
+func TestUpdateGuestBackups(t *testing.T) {
+	c := New()
+	c.UpdateGuestBackups("main", map[int]*model.PVEGuestBackup{
+		100: {ClusterID: "main", VMID: 100, Jobs: []string{"nightly"}, LastSuccess: 1000},
+	})
+
+	snap := c.Snapshot()
+	require.Contains(t, snap.GuestBackups["main"], 100)
+	assert.Equal(t, int64(1000), snap.GuestBackups["main"][100].LastSuccess)
+
+	c.mu.Lock()
+	c.GuestBackups["main"][100].Jobs[0] = "MUTATED"
+	c.mu.Unlock()
+	assert.Equal(t, []string{"nightly"}, snap.GuestBackups["main"][100].Jobs)
+}
+
 func TestSetLastPoll(t *testing.T) {
 	c := New()
 	now := time.Now()
@@ -0,0 +1,195 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/darshan-rambhia/glint/internal/store"
+)
+
+// backfillTimeframes are the PVE RRD timeframes used to seed history, finest
+// first. PVE keeps about 70 rows per timeframe: 1-minute averages for "hour"
+// and 30-minute averages for "day".
+var backfillTimeframes = []string{"hour", "day"}
+
+// backfill seeds node_snapshots and guest_snapshots from PVE's RRD data so
+// that a fresh install or a reset database shows history immediately. It
+// only runs when the store holds no snapshots for this instance, covers at
+// most the configured history window and ends just before the current poll.
+func (p *PVECollector) backfill(ctx context.Context, nodes map[string]*model.Node, guests map[int]*model.Guest, now time.Time) {
+	has, err := p.store.HasSnapshots(p.config.Name)
+	if err != nil {
+		slog.Error("checking history for backfill", "instance", p.config.Name, "error", err)
+		return
+	}
+	if has {
+		return
+	}
+
+	since := now.Add(-p.config.HistoryWindow).Unix()
+	until := now.Unix()
+
+	byNode := make(map[string][]*model.Guest)
+	for _, g := range guests {
+		byNode[g.Node] = append(byNode[g.Node], g)
+	}
+
+	var wg sync.WaitGroup
+	var nodeRows, guestRows atomic.Int64
+
+	for name, node := range nodes {
+		wg.Add(1)
+		if err := p.pool.Submit(ctx, func() {
+			defer wg.Done()
+
+			// One transaction per node covers the node and all its guests.
+			var batch store.Batch
+			var guestCount int
+			rows, err := p.fetchRRDHistory(ctx, fmt.Sprintf("/api2/json/nodes/%s/rrddata", name), since, until)
+			if err != nil {
+				slog.Warn("backfilling node history", "instance", p.config.Name, "node", name, "error", err)
+			}
+			for _, r := range rows {
+				batch.AddNodeSnapshot(p.nodeSnapshotFromRRD(node, r, until))
+			}
+			nodeCount := batch.Len()
+
+			for _, g := range byNode[name] {
+				path := fmt.Sprintf("/api2/json/nodes/%s/%s/%d/rrddata", name, g.Type, g.VMID)
+				rows, err := p.fetchRRDHistory(ctx, path, since, until)
+				if err != nil {
+					slog.Warn("backfilling guest history", "instance", p.config.Name, "vmid", g.VMID, "error", err)
+					continue
+				}
+				for _, r := range rows {
+					batch.AddGuestSnapshot(p.guestSnapshotFromRRD(g, r))
+				}
+				guestCount += len(rows)
+			}
+
+			if err := p.store.WriteBatch(&batch); err != nil {
+				slog.Error("storing backfilled history", "instance", p.config.Name, "node", name, "error", err)
+				return
+			}
+			nodeRows.Add(int64(nodeCount))
+			guestRows.Add(int64(guestCount))
+		}); err != nil {
+			wg.Done()
+			slog.Warn("submitting history backfill", "instance", p.config.Name, "node", name, "error", err)
+		}
+	}
+
+	wg.Wait()
+	slog.Info("backfilled history from PVE RRD", "instance", p.config.Name,
+		"node_rows", nodeRows.Load(), "guest_rows", guestRows.Load())
+
+	// Other instances may already have advanced the rollups past this window.
+	if err := p.store.RewindRollups(since); err != nil {
+		slog.Error("rewinding rollups after backfill", "instance", p.config.Name, "error", err)
+	}
+}
+
+// fetchRRDHistory fetches each backfill timeframe for an rrddata path and
+// merges the rows into one ascending series within [since, until).
+func (p *PVECollector) fetchRRDHistory(ctx context.Context, path string, since, until int64) ([]rrdRow, error) {
+	series := make([][]rrdRow, 0, len(backfillTimeframes))
+	for _, tf := range backfillTimeframes {
+		rows, err := p.fetchRRD(ctx, "backfill", path, tf)
+		if err != nil {
+			return mergeRRD(series, since, until), err
+		}
+		series = append(series, rows)
+		// The finer timeframe already covers the whole window.
+		if len(rows) > 0 && rows[0].Time <= since {
+			break
+		}
+	}
+	return mergeRRD(series, since, until), nil
+}
+
+// mergeRRD combines rows from several timeframes, finest first, into one
+// ascending series within [since, until). Coarser rows only fill the span
+// before the finer timeframe begins. Rows without data are dropped.
+func mergeRRD(series [][]rrdRow, since, until int64) []rrdRow {
+	var out []rrdRow
+	end := until
+	for _, rows := range series {
+		start := end
+		var kept []rrdRow
+		for _, r := range rows {
+			if r.CPU == nil || r.Time < since || r.Time >= end {
+				continue
+			}
+			kept = append(kept, r)
+			start = min(start, r.Time)
+		}
+		out = append(kept, out...)
+		end = start
+	}
+	return out
+}
+
+// nodeSnapshotFromRRD converts a node RRD row to a snapshot. RRD only has the
+// 1-minute load average and no temperature; uptime is derived from the
+// node's current uptime.
+func (p *PVECollector) nodeSnapshotFromRRD(node *model.Node, r rrdRow, until int64) model.NodeSnapshot {
+	snap := model.NodeSnapshot{
+		Timestamp:  r.Time,
+		Instance:   p.config.Name,
+		Node:       node.Name,
+		CPUPct:     deref(r.CPU) * 100,
+		MemUsed:    int64(deref(r.MemUsed)),
+		MemTotal:   int64(deref(r.MemTotal)),
+		SwapUsed:   int64(deref(r.SwapUsed)),
+		SwapTotal:  int64(deref(r.SwapTotal)),
+		RootUsed:   int64(deref(r.RootUsed)),
+		RootTotal:  int64(deref(r.RootTotal)),
+		Load1m:     deref(r.LoadAvg),
+		IOWait:     deref(r.IOWait),
+		UptimeSecs: max(node.Uptime-(until-r.Time), 0),
+	}
+	if rrd := nodeRRDFromRow(r); rrd != nil {
+		snap.NetIn, snap.NetOut = &rrd.NetIn, &rrd.NetOut
+		if ps := rrd.Pressure; ps != nil {
+			snap.PSICPU, snap.PSIIO, snap.PSIIOFull = &ps.CPUSome, &ps.IOSome, &ps.IOFull
+			snap.PSIMem, snap.PSIMemFull = &ps.MemorySome, &ps.MemoryFull
+		}
+	}
+	return snap
+}
+
+// guestSnapshotFromRRD converts a guest RRD row to a snapshot. PVE's RRD
+// stores network and disk I/O as bytes/sec averages, so they fill the rate
+// columns; the cumulative counters are unknown and left at zero.
+func (p *PVECollector) guestSnapshotFromRRD(g *model.Guest, r rrdRow) model.GuestSnapshot {
+	cpuPct := float64(0)
+	if g.CPUs > 0 {
+		cpuPct = deref(r.CPU) / float64(g.CPUs) * 100
+	}
+	return model.GuestSnapshot{
+		Timestamp: r.Time,
+		Instance:  p.config.Name,
+		VMID:      g.VMID,
+		Node:      g.Node,
+		ClusterID: g.ClusterID,
+		GuestType: g.Type,
+		Name:      g.Name,
+		Status:    g.Status,
+		CPUPct:    cpuPct,
+		CPUs:      g.CPUs,
+		MemUsed:   int64(deref(r.Mem)),
+		MemTotal:  int64(deref(r.MaxMem)),
+		DiskUsed:  int64(deref(r.Disk)),
+		DiskTotal: int64(deref(r.MaxDisk)),
+
+		NetInRate:     r.NetIn,
+		NetOutRate:    r.NetOut,
+		DiskReadRate:  r.DiskRead,
+		DiskWriteRate: r.DiskWrite,
+	}
+}
@@ -0,0 +1,184 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRRD(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+	row := func(ts int64) rrdRow { return rrdRow{Time: ts, CPU: v(float64(ts))} }
+
+	hour := []rrdRow{row(900), row(960), row(1020), {Time: 1080}}
+	day := []rrdRow{row(0), row(300), row(600), row(900), row(1200)}
+
+	got := mergeRRD([][]rrdRow{hour, day}, 300, 1080)
+	var times []int64
+	for _, r := range got {
+		times = append(times, r.Time)
+	}
+	// Day rows fill only the span before the hour rows; the row without
+	// data, rows outside the window and rows at or after until are dropped.
+	assert.Equal(t, []int64{300, 600, 900, 960, 1020}, times)
+
+	assert.Empty(t, mergeRRD(nil, 0, 1000))
+}
+
+// rrdHistory renders an rrddata response with one row per step ending
+// just before now, plus a trailing row without data.
+func rrdHistory(now time.Time, step time.Duration, n int, fields string) string {
+	var rows []string
+	for i := n; i >= 1; i-- {
+		ts := now.Add(-time.Duration(i) * step).Unix()
+		rows = append(rows, fmt.Sprintf(`{"time": %d, %s}`, ts, fields))
+	}
+	rows = append(rows, fmt.Sprintf(`{"time": %d}`, now.Unix()))
+	return `{"data": [` + strings.Join(rows, ",") + `]}`
+}
+
+func TestPVE_Collect_Backfill(t *testing.T) {
+	now := time.Now()
+	nodeFields := `"cpu": 0.25, "memused": 4096, "memtotal": 8192, "loadavg": 1.5, "iowait": 0.01, "netin": 1000, "netout": 2000, "pressureiosome": 3.5`
+	guestFields := `"cpu": 0.5, "mem": 512, "maxmem": 1024, "netin": 100, "netout": 200, "diskread": 300, "diskwrite": 400`
+
+	var dayCalls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tf := r.URL.Query().Get("timeframe")
+		if tf == "day" {
+			dayCalls.Add(1)
+		}
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc":
+			fmt.Fprint(w, lxcListJSON)
+		case "/api2/json/nodes/pve/qemu":
+			fmt.Fprint(w, `{"data": [{"vmid": 200, "name": "vm", "status": "running", "cpus": 2}]}`)
+		case "/api2/json/nodes/pve/rrddata":
+			if tf == "day" {
+				fmt.Fprint(w, rrdHistory(now, 30*time.Minute, 70, nodeFields))
+				return
+			}
+			fmt.Fprint(w, rrdHistory(now, time.Minute, 70, nodeFields))
+		case "/api2/json/nodes/pve/lxc/101/rrddata":
+			if tf == "day" {
+				fmt.Fprint(w, rrdHistory(now, 30*time.Minute, 70, guestFields))
+				return
+			}
+			fmt.Fprint(w, rrdHistory(now, time.Minute, 70, guestFields))
+		case "/api2/json/nodes/pve/qemu/200/rrddata":
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, _, st, _ := newTestPVECollector(t, handler)
+	coll.config.HistoryWindow = 24 * time.Hour
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Equal(t, int32(2), dayCalls.Load(), "node and container day timeframes")
+
+	// 70 one-minute rows plus the 30-minute rows between 24h ago and the
+	// start of the hour timeframe, plus the live snapshot.
+	since := now.Add(-24 * time.Hour).Unix()
+	cpu, err := st.QueryNodeSparkline("test-pve", "pve", "cpu", since)
+	require.NoError(t, err)
+	assert.Greater(t, len(cpu), 70+40)
+	assert.Equal(t, float64(25), cpu[0].Value)
+	assert.GreaterOrEqual(t, cpu[0].Timestamp, since)
+
+	psi, err := st.QueryNodeSparkline("test-pve", "pve", "psi_io", since)
+	require.NoError(t, err)
+	assert.Equal(t, 3.5, psi[0].Value)
+
+	guest, err := st.QueryGuestHistory("test-pve", 101, since)
+	require.NoError(t, err)
+	require.Greater(t, len(guest), 70)
+	first := guest[0]
+	assert.Equal(t, "lxc", first.GuestType)
+	assert.Equal(t, int64(512), first.MemUsed)
+	require.NotNil(t, first.DiskWriteRate)
+	assert.Equal(t, float64(400), *first.DiskWriteRate)
+	assert.Zero(t, first.NetIn, "cumulative counters are unknown")
+
+	vm, err := st.QueryGuestHistory("test-pve", 200, since)
+	require.NoError(t, err)
+	assert.Len(t, vm, 1, "a guest without rrddata only has the live snapshot")
+
+	// Backfill runs once per collector.
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Equal(t, int32(2), dayCalls.Load())
+}
+
+func TestPVE_Collect_BackfillSkippedWithHistory(t *testing.T) {
+	var historyCalls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc":
+			fmt.Fprint(w, lxcListJSON)
+		case "/api2/json/nodes/pve/lxc/101/rrddata":
+			historyCalls.Add(1)
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, _, st, _ := newTestPVECollector(t, handler)
+	coll.config.HistoryWindow = 24 * time.Hour
+	require.NoError(t, st.InsertNodeSnapshot(model.NodeSnapshot{
+		Timestamp: time.Now().Add(-time.Hour).Unix(), Instance: "test-pve", Node: "pve",
+	}))
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Zero(t, historyCalls.Load())
+}
+
+func TestPVE_Collect_BackfillDisabled(t *testing.T) {
+	var historyCalls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc":
+			fmt.Fprint(w, lxcListJSON)
+		case "/api2/json/nodes/pve/lxc/101/rrddata":
+			historyCalls.Add(1)
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Zero(t, historyCalls.Load())
+}
+
+func TestPVE_Backfill_StoreError(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data": []}`)
+	})
+	coll, _, st, _ := newTestPVECollector(t, handler)
+	coll.config.HistoryWindow = time.Hour
+	require.NoError(t, st.Close())
+
+	// A closed store is logged and leaves backfill as a no-op.
+	coll.backfill(context.Background(), map[string]*model.Node{"pve": {Name: "pve"}}, nil, time.Now())
+}
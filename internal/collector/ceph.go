@@ -0,0 +1,214 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// collectCeph fetches Ceph health, OSD tree and pool usage for the instance.
+// The OSD tree and pools are per-node endpoints in PVE but describe the whole
+// Ceph cluster, so they are queried once via the given node.
+//
+// PVE answers /cluster/ceph/status with an error when Ceph is not installed,
+// so callers treat an error here as "no Ceph on this instance".
+func (p *PVECollector) collectCeph(ctx context.Context, nodeName string) (*model.CephStatus, error) {
+	body, err := p.apiGet(ctx, "collectCeph", "/api2/json/cluster/ceph/status")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing ceph status response: %w", err)
+	}
+
+	status, err := parseCephStatus(p.config.Name, resp.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// OSD tree and pools are best-effort: health alone is still useful.
+	if body, err := p.apiGet(ctx, "collectCephOSDs", fmt.Sprintf("/api2/json/nodes/%s/ceph/osd", nodeName)); err == nil {
+		if err := json.Unmarshal(body, &resp); err == nil {
+			status.OSDs = parseCephOSDTree(resp.Data)
+		}
+	}
+	if body, err := p.apiGet(ctx, "collectCephPools", fmt.Sprintf("/api2/json/nodes/%s/ceph/pool", nodeName)); err == nil {
+		if err := json.Unmarshal(body, &resp); err == nil {
+			status.Pools = parseCephPools(resp.Data)
+		}
+	}
+
+	return status, nil
+}
+
+// cephOSDMap holds OSD counters. Ceph before Quincy nests them one level
+// deeper as osdmap.osdmap, so both layouts are decoded.
+type cephOSDMap struct {
+	NumOSDs   int         `json:"num_osds"`
+	NumUpOSDs int         `json:"num_up_osds"`
+	NumInOSDs int         `json:"num_in_osds"`
+	OSDMap    *cephOSDMap `json:"osdmap"`
+}
+
+func parseCephStatus(instance string, data json.RawMessage) (*model.CephStatus, error) {
+	var raw struct {
+		FSID   string `json:"fsid"`
+		Health struct {
+			Status string `json:"status"`
+			Checks map[string]struct {
+				Severity string `json:"severity"`
+				Summary  struct {
+					Message string `json:"message"`
+				} `json:"summary"`
+				Muted bool `json:"muted"`
+			} `json:"checks"`
+		} `json:"health"`
+		QuorumNames []string `json:"quorum_names"`
+		MonMap      struct {
+			Mons []struct {
+				Name string `json:"name"`
+			} `json:"mons"`
+		} `json:"monmap"`
+		OSDMap cephOSDMap `json:"osdmap"`
+		PGMap  struct {
+			PGsByState []struct {
+				StateName string `json:"state_name"`
+				Count     int    `json:"count"`
+			} `json:"pgs_by_state"`
+			NumPGs     int   `json:"num_pgs"`
+			BytesUsed  int64 `json:"bytes_used"`
+			BytesTotal int64 `json:"bytes_total"`
+		} `json:"pgmap"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing ceph status: %w", err)
+	}
+	if raw.Health.Status == "" {
+		return nil, fmt.Errorf("ceph status has no health field")
+	}
+
+	status := &model.CephStatus{
+		Instance:   instance,
+		FSID:       raw.FSID,
+		Health:     raw.Health.Status,
+		NumPGs:     raw.PGMap.NumPGs,
+		BytesUsed:  raw.PGMap.BytesUsed,
+		BytesTotal: raw.PGMap.BytesTotal,
+	}
+
+	for code, c := range raw.Health.Checks {
+		status.Checks = append(status.Checks, model.CephHealthCheck{
+			Code:     code,
+			Severity: c.Severity,
+			Summary:  c.Summary.Message,
+			Muted:    c.Muted,
+		})
+	}
+	sort.Slice(status.Checks, func(i, j int) bool { return status.Checks[i].Code < status.Checks[j].Code })
+
+	inQuorum := make(map[string]bool, len(raw.QuorumNames))
+	for _, name := range raw.QuorumNames {
+		inQuorum[name] = true
+	}
+	for _, m := range raw.MonMap.Mons {
+		status.Monitors = append(status.Monitors, model.CephMonitor{Name: m.Name, InQuorum: inQuorum[m.Name]})
+	}
+
+	osdMap := raw.OSDMap
+	if osdMap.OSDMap != nil {
+		osdMap = *osdMap.OSDMap
+	}
+	status.NumOSDs = osdMap.NumOSDs
+	status.NumUpOSDs = osdMap.NumUpOSDs
+	status.NumInOSDs = osdMap.NumInOSDs
+
+	for _, s := range raw.PGMap.PGsByState {
+		status.PGStates = append(status.PGStates, model.CephPGState{State: s.StateName, Count: s.Count})
+	}
+
+	return status, nil
+}
+
+// cephTreeNode is an entry in the PVE OSD CRUSH tree (root → host → osd).
+type cephTreeNode struct {
+	Type        string         `json:"type"`
+	Name        string         `json:"name"`
+	ID          int            `json:"id"`
+	Host        string         `json:"host"`
+	Status      string         `json:"status"`
+	In          int            `json:"in"`
+	DeviceClass string         `json:"device_class"`
+	PercentUsed float64        `json:"percent_used"`
+	Children    []cephTreeNode `json:"children"`
+}
+
+func parseCephOSDTree(data json.RawMessage) []model.CephOSD {
+	var raw struct {
+		Root cephTreeNode `json:"root"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	var osds []model.CephOSD
+	var walk func(n cephTreeNode, host string)
+	walk = func(n cephTreeNode, host string) {
+		switch n.Type {
+		case "host":
+			host = n.Name
+		case "osd":
+			if n.Host != "" {
+				host = n.Host
+			}
+			osds = append(osds, model.CephOSD{
+				ID:          n.ID,
+				Name:        n.Name,
+				Host:        host,
+				Status:      n.Status,
+				In:          n.In == 1,
+				DeviceClass: n.DeviceClass,
+				UsedPct:     n.PercentUsed,
+			})
+		}
+		for _, c := range n.Children {
+			walk(c, host)
+		}
+	}
+	walk(raw.Root, "")
+
+	sort.Slice(osds, func(i, j int) bool { return osds[i].ID < osds[j].ID })
+	return osds
+}
+
+func parseCephPools(data json.RawMessage) []model.CephPool {
+	var raw []struct {
+		PoolName    string  `json:"pool_name"`
+		Size        int     `json:"size"`
+		MinSize     int     `json:"min_size"`
+		PGNum       int     `json:"pg_num"`
+		BytesUsed   int64   `json:"bytes_used"`
+		PercentUsed float64 `json:"percent_used"` // fraction 0.0-1.0
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	pools := make([]model.CephPool, 0, len(raw))
+	for _, r := range raw {
+		pools = append(pools, model.CephPool{
+			Name:      r.PoolName,
+			Size:      r.Size,
+			MinSize:   r.MinSize,
+			PGNum:     r.PGNum,
+			UsedBytes: r.BytesUsed,
+			UsedPct:   r.PercentUsed * 100,
+		})
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools
+}
@@ -0,0 +1,300 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Test fixtures — realistic PVE Ceph API response JSON
+// ---------------------------------------------------------------------------
+
+const cephStatusJSON = `{
+	"data": {
+		"fsid": "5f3c7a1e-8d2b-4c6a-9e1f-0a2b3c4d5e6f",
+		"health": {
+			"status": "HEALTH_WARN",
+			"checks": {
+				"OSD_DOWN": {
+					"severity": "HEALTH_WARN",
+					"summary": {"message": "1 osds down", "count": 1},
+					"muted": false
+				},
+				"MON_CLOCK_SKEW": {
+					"severity": "HEALTH_WARN",
+					"summary": {"message": "clock skew detected on mon.pve2"},
+					"muted": true
+				}
+			}
+		},
+		"quorum_names": ["pve", "pve2"],
+		"monmap": {"mons": [{"name": "pve"}, {"name": "pve2"}, {"name": "pve3"}]},
+		"osdmap": {"num_osds": 6, "num_up_osds": 5, "num_in_osds": 6},
+		"pgmap": {
+			"pgs_by_state": [
+				{"state_name": "active+clean", "count": 120},
+				{"state_name": "active+undersized+degraded", "count": 8}
+			],
+			"num_pgs": 128,
+			"bytes_used": 1099511627776,
+			"bytes_total": 6597069766656
+		}
+	}
+}`
+
+const cephStatusNestedOSDMapJSON = `{
+	"data": {
+		"health": {"status": "HEALTH_OK", "checks": {}},
+		"osdmap": {"osdmap": {"num_osds": 3, "num_up_osds": 3, "num_in_osds": 3}}
+	}
+}`
+
+const cephOSDTreeJSON = `{
+	"data": {
+		"root": {
+			"type": "root", "name": "default", "id": -1,
+			"children": [
+				{
+					"type": "host", "name": "pve", "id": -3,
+					"children": [
+						{"type": "osd", "name": "osd.1", "id": 1, "status": "down", "in": 1, "device_class": "hdd", "percent_used": 0},
+						{"type": "osd", "name": "osd.0", "id": 0, "status": "up", "in": 1, "device_class": "ssd", "percent_used": 17.2}
+					]
+				},
+				{
+					"type": "host", "name": "pve2", "id": -5,
+					"children": [
+						{"type": "osd", "name": "osd.2", "id": 2, "host": "pve2", "status": "up", "in": 0, "device_class": "nvme", "percent_used": 21.5}
+					]
+				}
+			]
+		}
+	}
+}`
+
+const cephPoolsJSON = `{
+	"data": [
+		{"pool_name": "vm-pool", "size": 3, "min_size": 2, "pg_num": 128, "bytes_used": 549755813888, "percent_used": 0.25},
+		{"pool_name": ".mgr", "size": 3, "min_size": 2, "pg_num": 1, "bytes_used": 1048576, "percent_used": 0.0001}
+	]
+}`
+
+func cephHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/cluster/ceph/status":
+			fmt.Fprint(w, cephStatusJSON)
+		case "/api2/json/nodes/pve/ceph/osd":
+			fmt.Fprint(w, cephOSDTreeJSON)
+		case "/api2/json/nodes/pve/ceph/pool":
+			fmt.Fprint(w, cephPoolsJSON)
+		default:
+			http.Error(w, "not found", 404)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Parsers
+// ---------------------------------------------------------------------------
+
+func TestParseCephStatus(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(cephStatusJSON), &resp))
+
+	status, err := parseCephStatus("test-pve", resp.Data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-pve", status.Instance)
+	assert.Equal(t, "HEALTH_WARN", status.Health)
+	assert.Equal(t, "5f3c7a1e-8d2b-4c6a-9e1f-0a2b3c4d5e6f", status.FSID)
+
+	// Checks are sorted by code
+	require.Len(t, status.Checks, 2)
+	assert.Equal(t, "MON_CLOCK_SKEW", status.Checks[0].Code)
+	assert.True(t, status.Checks[0].Muted)
+	assert.Equal(t, "OSD_DOWN", status.Checks[1].Code)
+	assert.Equal(t, "1 osds down", status.Checks[1].Summary)
+	assert.Equal(t, "HEALTH_WARN", status.Checks[1].Severity)
+
+	require.Len(t, status.Monitors, 3)
+	assert.True(t, status.Monitors[0].InQuorum)
+	assert.True(t, status.Monitors[1].InQuorum)
+	assert.False(t, status.Monitors[2].InQuorum)
+
+	assert.Equal(t, 6, status.NumOSDs)
+	assert.Equal(t, 5, status.NumUpOSDs)
+	assert.Equal(t, 6, status.NumInOSDs)
+
+	assert.Equal(t, 128, status.NumPGs)
+	require.Len(t, status.PGStates, 2)
+	assert.Equal(t, "active+clean", status.PGStates[0].State)
+	assert.Equal(t, 120, status.PGStates[0].Count)
+	assert.Equal(t, int64(1099511627776), status.BytesUsed)
+	assert.Equal(t, int64(6597069766656), status.BytesTotal)
+}
+
+func TestParseCephStatus_NestedOSDMap(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(cephStatusNestedOSDMapJSON), &resp))
+
+	status, err := parseCephStatus("test-pve", resp.Data)
+	require.NoError(t, err)
+	assert.Equal(t, "HEALTH_OK", status.Health)
+	assert.Empty(t, status.Checks)
+	assert.Equal(t, 3, status.NumOSDs)
+	assert.Equal(t, 3, status.NumUpOSDs)
+	assert.Equal(t, 3, status.NumInOSDs)
+}
+
+func TestParseCephStatus_Errors(t *testing.T) {
+	_, err := parseCephStatus("test-pve", json.RawMessage(`not json`))
+	assert.Error(t, err)
+
+	_, err = parseCephStatus("test-pve", json.RawMessage(`{"fsid": "abc"}`))
+	assert.ErrorContains(t, err, "no health field")
+}
+
+func TestParseCephOSDTree(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(cephOSDTreeJSON), &resp))
+
+	osds := parseCephOSDTree(resp.Data)
+	require.Len(t, osds, 3)
+
+	// Sorted by OSD ID; host inherited from the parent bucket
+	assert.Equal(t, "osd.0", osds[0].Name)
+	assert.Equal(t, "pve", osds[0].Host)
+	assert.Equal(t, "up", osds[0].Status)
+	assert.True(t, osds[0].In)
+	assert.Equal(t, "ssd", osds[0].DeviceClass)
+	assert.InDelta(t, 17.2, osds[0].UsedPct, 0.001)
+
+	assert.Equal(t, "down", osds[1].Status)
+
+	assert.Equal(t, "pve2", osds[2].Host)
+	assert.False(t, osds[2].In)
+}
+
+func TestParseCephOSDTree_InvalidJSON(t *testing.T) {
+	assert.Nil(t, parseCephOSDTree(json.RawMessage(`[1,2,3]`)))
+}
+
+func TestParseCephPools(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(cephPoolsJSON), &resp))
+
+	pools := parseCephPools(resp.Data)
+	require.Len(t, pools, 2)
+	assert.Equal(t, ".mgr", pools[0].Name)
+	assert.Equal(t, "vm-pool", pools[1].Name)
+	assert.Equal(t, 3, pools[1].Size)
+	assert.Equal(t, 2, pools[1].MinSize)
+	assert.Equal(t, 128, pools[1].PGNum)
+	assert.Equal(t, int64(549755813888), pools[1].UsedBytes)
+	assert.InDelta(t, 25.0, pools[1].UsedPct, 0.001)
+}
+
+func TestParseCephPools_InvalidJSON(t *testing.T) {
+	assert.Nil(t, parseCephPools(json.RawMessage(`{"pool_name": "x"}`)))
+}
+
+// ---------------------------------------------------------------------------
+// collectCeph
+// ---------------------------------------------------------------------------
+
+func TestPVE_collectCeph(t *testing.T) {
+	coll, _, _, _ := newTestPVECollector(t, cephHandler())
+
+	status, err := coll.collectCeph(context.Background(), "pve")
+	require.NoError(t, err)
+	assert.Equal(t, "HEALTH_WARN", status.Health)
+	assert.Len(t, status.OSDs, 3)
+	assert.Len(t, status.Pools, 2)
+}
+
+func TestPVE_collectCeph_NotInstalled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "binary not installed: /usr/bin/ceph-mon", 500)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	_, err := coll.collectCeph(context.Background(), "pve")
+	assert.Error(t, err)
+}
+
+func TestPVE_collectCeph_InvalidJSON(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `not json`)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	_, err := coll.collectCeph(context.Background(), "pve")
+	assert.ErrorContains(t, err, "parsing ceph status response")
+}
+
+func TestPVE_collectCeph_OSDAndPoolsBestEffort(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/cluster/ceph/status" {
+			fmt.Fprint(w, cephStatusJSON)
+			return
+		}
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	status, err := coll.collectCeph(context.Background(), "pve")
+	require.NoError(t, err)
+	assert.Equal(t, "HEALTH_WARN", status.Health)
+	assert.Empty(t, status.OSDs)
+	assert.Empty(t, status.Pools)
+}
+
+func TestPVE_Collect_WithCeph(t *testing.T) {
+	ceph := cephHandler()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc", "/api2/json/nodes/pve/qemu", "/api2/json/nodes/pve/disks/list":
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			ceph(w, r)
+		}
+	})
+	coll, ch, _, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+
+	snap := ch.Snapshot()
+	require.Contains(t, snap.Ceph, "test-pve")
+	assert.Equal(t, "HEALTH_WARN", snap.Ceph["test-pve"].Health)
+	assert.Len(t, snap.Ceph["test-pve"].OSDs, 3)
+}
+
+func TestPVE_Collect_WithoutCeph(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc", "/api2/json/nodes/pve/qemu", "/api2/json/nodes/pve/disks/list":
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			http.Error(w, "not found", 404)
+		}
+	})
+	coll, ch, _, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.NotContains(t, ch.Snapshot().Ceph, "test-pve")
+}
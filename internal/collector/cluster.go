@@ -0,0 +1,226 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// collectClusterStatus fetches quorum state and node membership from
+// /cluster/status, then vote weights, HA manager and resource state. Those
+// endpoints are best-effort: a cluster without HA or a QDevice configured
+// still reports quorum, with one vote per node if the config is unreadable.
+func (p *PVECollector) collectClusterStatus(ctx context.Context) (*model.ClusterStatus, error) {
+	body, err := p.apiGet(ctx, "collectClusterStatus", "/api2/json/cluster/status")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing cluster status response: %w", err)
+	}
+
+	status, err := parseClusterStatus(p.config.Name, resp.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodesConfig, qdevice pveResponse
+	if body, err := p.apiGet(ctx, "collectClusterNodesConfig", "/api2/json/cluster/config/nodes"); err == nil {
+		_ = json.Unmarshal(body, &nodesConfig)
+	}
+	if body, err := p.apiGet(ctx, "collectQDeviceStatus", "/api2/json/cluster/config/qdevice"); err == nil {
+		_ = json.Unmarshal(body, &qdevice)
+	}
+	applyClusterVotes(status, nodesConfig.Data, qdevice.Data)
+
+	var current, resources pveResponse
+	if body, err := p.apiGet(ctx, "collectHAStatus", "/api2/json/cluster/ha/status/current"); err == nil {
+		_ = json.Unmarshal(body, &current)
+	}
+	if body, err := p.apiGet(ctx, "collectHAResources", "/api2/json/cluster/ha/resources"); err == nil {
+		_ = json.Unmarshal(body, &resources)
+	}
+	parseHAStatus(status, current.Data, resources.Data)
+
+	return status, nil
+}
+
+func parseClusterStatus(instance string, data json.RawMessage) (*model.ClusterStatus, error) {
+	var entries []struct {
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Nodes   int    `json:"nodes"`
+		Quorate int    `json:"quorate"`
+		NodeID  int    `json:"nodeid"`
+		IP      string `json:"ip"`
+		Online  int    `json:"online"`
+		Local   int    `json:"local"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing cluster status: %w", err)
+	}
+
+	var status *model.ClusterStatus
+	var nodes []model.ClusterNode
+	for _, e := range entries {
+		switch e.Type {
+		case "cluster":
+			status = &model.ClusterStatus{
+				Instance:      instance,
+				Name:          e.Name,
+				Quorate:       e.Quorate == 1,
+				ExpectedVotes: e.Nodes,
+			}
+		case "node":
+			nodes = append(nodes, model.ClusterNode{
+				Name:   e.Name,
+				NodeID: e.NodeID,
+				IP:     e.IP,
+				Online: e.Online == 1,
+				Local:  e.Local == 1,
+				Votes:  1,
+			})
+		}
+	}
+	if status == nil {
+		return nil, fmt.Errorf("no cluster entry found")
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	status.Nodes = nodes
+	if len(nodes) > 0 {
+		countVotes(status, 0, false)
+	}
+	return status, nil
+}
+
+// applyClusterVotes sets each node's quorum_votes from /cluster/config/nodes
+// and adds the QDevice vote from /cluster/config/qdevice, then recounts the
+// votes. A QDevice with the "last man standing" algorithm holds one vote less
+// than the node count; with fifty-fifty split it holds one.
+func applyClusterVotes(status *model.ClusterStatus, nodesConfig, qdevice json.RawMessage) {
+	var config []struct {
+		Node        string          `json:"node"`
+		QuorumVotes json.RawMessage `json:"quorum_votes"`
+	}
+	if nodesConfig != nil {
+		_ = json.Unmarshal(nodesConfig, &config)
+	}
+	for _, c := range config {
+		votes, err := strconv.Atoi(strings.Trim(string(c.QuorumVotes), `"`))
+		if err != nil || votes < 0 {
+			continue
+		}
+		for i := range status.Nodes {
+			if status.Nodes[i].Name == c.Node {
+				status.Nodes[i].Votes = votes
+			}
+		}
+	}
+
+	var qd map[string]any
+	if qdevice != nil {
+		_ = json.Unmarshal(qdevice, &qd)
+	}
+	qdVotes := 0
+	if len(qd) > 0 {
+		qdVotes = 1
+		if alg := fmt.Sprint(qd["Algorithm"]); strings.Contains(alg, "LMS") || strings.Contains(strings.ToLower(alg), "last man") {
+			qdVotes = max(len(status.Nodes)-1, 1)
+		}
+	}
+	connected := fmt.Sprint(qd["State"]) == "Connected"
+	if len(status.Nodes) > 0 {
+		countVotes(status, qdVotes, connected)
+	}
+}
+
+// countVotes sums the expected and present votes of the cluster's nodes and
+// its QDevice.
+func countVotes(status *model.ClusterStatus, qdeviceVotes int, qdeviceConnected bool) {
+	status.ExpectedVotes, status.PresentVotes = qdeviceVotes, 0
+	if qdeviceConnected {
+		status.PresentVotes = qdeviceVotes
+	}
+	for _, n := range status.Nodes {
+		status.ExpectedVotes += n.Votes
+		if n.Online {
+			status.PresentVotes += n.Votes
+		}
+	}
+}
+
+// parseHAStatus merges the CRM view (/cluster/ha/status/current) with the
+// configured resources (/cluster/ha/resources). Resources that the CRM has not
+// picked up yet are still listed, with an empty State.
+func parseHAStatus(status *model.ClusterStatus, current, resources json.RawMessage) {
+	var entries []struct {
+		Type     string `json:"type"`
+		Node     string `json:"node"`
+		Status   string `json:"status"`
+		SID      string `json:"sid"`
+		State    string `json:"state"`
+		CRMState string `json:"crm_state"`
+	}
+	if current != nil {
+		_ = json.Unmarshal(current, &entries)
+	}
+
+	var configured []struct {
+		SID   string `json:"sid"`
+		Type  string `json:"type"`
+		State string `json:"state"`
+		Group string `json:"group"`
+	}
+	if resources != nil {
+		_ = json.Unmarshal(resources, &configured)
+	}
+
+	bySID := make(map[string]*model.HAResource)
+	for _, c := range configured {
+		bySID[c.SID] = &model.HAResource{
+			SID:            c.SID,
+			Type:           c.Type,
+			RequestedState: c.State,
+			Group:          c.Group,
+		}
+	}
+
+	for _, e := range entries {
+		switch e.Type {
+		case "master":
+			status.HAMaster = e.Node
+			status.HAStatus = e.Status
+		case "service":
+			r, ok := bySID[e.SID]
+			if !ok {
+				r = &model.HAResource{SID: e.SID}
+				bySID[e.SID] = r
+			}
+			r.Node = e.Node
+			r.State = e.State
+			if r.State == "" {
+				r.State = e.CRMState
+			}
+		}
+	}
+
+	for _, r := range bySID {
+		if r.Type == "" {
+			if typ, _, ok := strings.Cut(r.SID, ":"); ok {
+				r.Type = typ
+			}
+		}
+		status.HAResources = append(status.HAResources, *r)
+	}
+	sort.Slice(status.HAResources, func(i, j int) bool {
+		return status.HAResources[i].SID < status.HAResources[j].SID
+	})
+}
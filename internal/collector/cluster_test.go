@@ -0,0 +1,289 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Test fixtures — realistic PVE cluster/HA API response JSON
+// ---------------------------------------------------------------------------
+
+const clusterStatusFullJSON = `{
+	"data": [
+		{"type": "cluster", "id": "cluster", "name": "homelab-cluster", "nodes": 3, "quorate": 1, "version": 7},
+		{"type": "node", "id": "node/pve2", "name": "pve2", "nodeid": 2, "ip": "10.0.0.12", "online": 0, "local": 0, "level": ""},
+		{"type": "node", "id": "node/pve", "name": "pve", "nodeid": 1, "ip": "10.0.0.11", "online": 1, "local": 1, "level": ""},
+		{"type": "node", "id": "node/pve3", "name": "pve3", "nodeid": 3, "ip": "10.0.0.13", "online": 1, "local": 0, "level": ""}
+	]
+}`
+
+const clusterNodesConfigJSON = `{
+	"data": [
+		{"name": "pve", "node": "pve", "nodeid": "1", "quorum_votes": "1", "ring0_addr": "10.0.0.11"},
+		{"name": "pve2", "node": "pve2", "nodeid": "2", "quorum_votes": "1", "ring0_addr": "10.0.0.12"},
+		{"name": "pve3", "node": "pve3", "nodeid": "3", "quorum_votes": "2", "ring0_addr": "10.0.0.13"}
+	]
+}`
+
+const haStatusCurrentJSON = `{
+	"data": [
+		{"id": "quorum", "type": "quorum", "node": "pve", "status": "OK", "quorate": 1},
+		{"id": "master", "type": "master", "node": "pve", "status": "pve (active, Sat Oct 17 10:00:00 2026)"},
+		{"id": "lrm:pve", "type": "lrm", "node": "pve", "status": "pve (active, Sat Oct 17 10:00:01 2026)"},
+		{"id": "service:vm:100", "type": "service", "sid": "vm:100", "node": "pve", "state": "started", "crm_state": "started", "request_state": "started"},
+		{"id": "service:ct:101", "type": "service", "sid": "ct:101", "node": "pve2", "state": "fence", "crm_state": "fence", "request_state": "started"},
+		{"id": "service:vm:102", "type": "service", "sid": "vm:102", "node": "pve3", "crm_state": "error"}
+	]
+}`
+
+const haResourcesJSON = `{
+	"data": [
+		{"sid": "vm:100", "type": "vm", "state": "started", "group": "prefer-pve"},
+		{"sid": "ct:101", "type": "ct", "state": "started"},
+		{"sid": "vm:103", "type": "vm", "state": "stopped"}
+	]
+}`
+
+// ---------------------------------------------------------------------------
+// Parsers
+// ---------------------------------------------------------------------------
+
+func TestParseClusterStatus(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(clusterStatusFullJSON), &resp))
+
+	status, err := parseClusterStatus("test-pve", resp.Data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-pve", status.Instance)
+	assert.Equal(t, "homelab-cluster", status.Name)
+	assert.True(t, status.Quorate)
+	assert.Equal(t, 3, status.ExpectedVotes)
+	assert.Equal(t, 2, status.PresentVotes)
+
+	require.Len(t, status.Nodes, 3)
+	assert.Equal(t, "pve", status.Nodes[0].Name)
+	assert.True(t, status.Nodes[0].Online)
+	assert.True(t, status.Nodes[0].Local)
+	assert.Equal(t, 1, status.Nodes[0].NodeID)
+	assert.Equal(t, "10.0.0.11", status.Nodes[0].IP)
+	assert.Equal(t, "pve2", status.Nodes[1].Name)
+	assert.False(t, status.Nodes[1].Online)
+}
+
+func TestParseClusterStatus_NotQuorate(t *testing.T) {
+	data := json.RawMessage(`[
+		{"type": "cluster", "name": "c", "quorate": 0},
+		{"type": "node", "name": "a", "online": 1},
+		{"type": "node", "name": "b", "online": 0}
+	]`)
+
+	status, err := parseClusterStatus("test-pve", data)
+	require.NoError(t, err)
+	assert.False(t, status.Quorate)
+	// "nodes" missing: fall back to member count
+	assert.Equal(t, 2, status.ExpectedVotes)
+	assert.Equal(t, 1, status.PresentVotes)
+}
+
+func TestApplyClusterVotes(t *testing.T) {
+	// Two nodes and a QDevice, one node down: still quorate with 2 of 3 votes.
+	status, err := parseClusterStatus("test-pve", json.RawMessage(`[
+		{"type": "cluster", "name": "c", "nodes": 2, "quorate": 1},
+		{"type": "node", "name": "a", "online": 1},
+		{"type": "node", "name": "b", "online": 0}
+	]`))
+	require.NoError(t, err)
+	applyClusterVotes(status,
+		json.RawMessage(`[{"node": "a", "quorum_votes": "1"}, {"node": "b", "quorum_votes": 1}]`),
+		json.RawMessage(`{"Algorithm": "Fifty-Fifty split", "Model": "Net", "Node ID": "1", "QNetd host": "10.0.0.5:5403", "State": "Connected", "Tie-breaker": "Node with lowest node id"}`))
+	assert.Equal(t, 3, status.ExpectedVotes)
+	assert.Equal(t, 2, status.PresentVotes)
+
+	// A disconnected QDevice is expected but not present
+	applyClusterVotes(status, nil, json.RawMessage(`{"Algorithm": "Fifty-Fifty split", "State": "Connect failed"}`))
+	assert.Equal(t, 3, status.ExpectedVotes)
+	assert.Equal(t, 1, status.PresentVotes)
+
+	// Last man standing gives the QDevice one vote less than the node count
+	status.Nodes = append(status.Nodes, model.ClusterNode{Name: "c", Online: true, Votes: 1})
+	applyClusterVotes(status, nil, json.RawMessage(`{"Algorithm": "LMS", "State": "Connected"}`))
+	assert.Equal(t, 5, status.ExpectedVotes)
+	assert.Equal(t, 4, status.PresentVotes)
+
+	// No config: one vote per node
+	applyClusterVotes(status, nil, nil)
+	assert.Equal(t, 3, status.ExpectedVotes)
+	assert.Equal(t, 2, status.PresentVotes)
+}
+
+func TestParseClusterStatus_Errors(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(clusterStatusStandaloneJSON), &resp))
+	_, err := parseClusterStatus("test-pve", resp.Data)
+	assert.ErrorContains(t, err, "no cluster entry")
+
+	_, err = parseClusterStatus("test-pve", json.RawMessage(`{"type": "cluster"}`))
+	assert.Error(t, err)
+}
+
+func TestParseHAStatus(t *testing.T) {
+	var current, resources pveResponse
+	require.NoError(t, json.Unmarshal([]byte(haStatusCurrentJSON), &current))
+	require.NoError(t, json.Unmarshal([]byte(haResourcesJSON), &resources))
+
+	status, err := parseClusterStatus("test-pve", json.RawMessage(`[{"type": "cluster", "name": "c", "quorate": 1}]`))
+	require.NoError(t, err)
+	parseHAStatus(status, current.Data, resources.Data)
+
+	assert.Equal(t, "pve", status.HAMaster)
+	assert.Contains(t, status.HAStatus, "active")
+
+	// Sorted by SID; configured-only and CRM-only resources are both listed
+	require.Len(t, status.HAResources, 4)
+	assert.Equal(t, "ct:101", status.HAResources[0].SID)
+	assert.Equal(t, "fence", status.HAResources[0].State)
+	assert.Equal(t, "pve2", status.HAResources[0].Node)
+	assert.Equal(t, "started", status.HAResources[0].RequestedState)
+
+	assert.Equal(t, "vm:100", status.HAResources[1].SID)
+	assert.Equal(t, "started", status.HAResources[1].State)
+	assert.Equal(t, "prefer-pve", status.HAResources[1].Group)
+
+	// crm_state fallback and type derived from SID
+	assert.Equal(t, "vm:102", status.HAResources[2].SID)
+	assert.Equal(t, "error", status.HAResources[2].State)
+	assert.Equal(t, "vm", status.HAResources[2].Type)
+
+	// Configured but not yet seen by the CRM
+	assert.Equal(t, "vm:103", status.HAResources[3].SID)
+	assert.Empty(t, status.HAResources[3].State)
+}
+
+func TestParseHAStatus_NoHA(t *testing.T) {
+	status, err := parseClusterStatus("test-pve", json.RawMessage(`[{"type": "cluster", "name": "c", "quorate": 1}]`))
+	require.NoError(t, err)
+	parseHAStatus(status, nil, nil)
+	assert.Empty(t, status.HAMaster)
+	assert.Empty(t, status.HAResources)
+}
+
+// ---------------------------------------------------------------------------
+// collectClusterStatus
+// ---------------------------------------------------------------------------
+
+func clusterHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/cluster/status":
+			fmt.Fprint(w, clusterStatusFullJSON)
+		case "/api2/json/cluster/config/nodes":
+			fmt.Fprint(w, clusterNodesConfigJSON)
+		case "/api2/json/cluster/ha/status/current":
+			fmt.Fprint(w, haStatusCurrentJSON)
+		case "/api2/json/cluster/ha/resources":
+			fmt.Fprint(w, haResourcesJSON)
+		default:
+			http.Error(w, "not found", 404)
+		}
+	}
+}
+
+func TestPVE_collectClusterStatus(t *testing.T) {
+	coll, _, _, _ := newTestPVECollector(t, clusterHandler())
+
+	status, err := coll.collectClusterStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "homelab-cluster", status.Name)
+	assert.Len(t, status.HAResources, 4)
+	// pve3 holds two votes; pve2 is offline
+	assert.Equal(t, 4, status.ExpectedVotes)
+	assert.Equal(t, 3, status.PresentVotes)
+}
+
+func TestPVE_collectClusterStatus_APIError(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	_, err := coll.collectClusterStatus(context.Background())
+	assert.Error(t, err)
+}
+
+func TestPVE_collectClusterStatus_InvalidJSON(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `not json`)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	_, err := coll.collectClusterStatus(context.Background())
+	assert.ErrorContains(t, err, "parsing cluster status response")
+}
+
+func TestPVE_collectClusterStatus_HAUnavailable(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/cluster/status" {
+			fmt.Fprint(w, clusterStatusFullJSON)
+			return
+		}
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	status, err := coll.collectClusterStatus(context.Background())
+	require.NoError(t, err)
+	assert.True(t, status.Quorate)
+	assert.Empty(t, status.HAResources)
+}
+
+func TestPVE_Collect_ClusterStatusCached(t *testing.T) {
+	cluster := clusterHandler()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc", "/api2/json/nodes/pve/qemu", "/api2/json/nodes/pve/disks/list":
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			cluster(w, r)
+		}
+	})
+	coll, ch, _, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+
+	snap := ch.Snapshot()
+	require.Contains(t, snap.Clusters, "test-pve")
+	assert.Equal(t, "homelab-cluster", snap.Clusters["test-pve"].Name)
+	assert.Equal(t, 3, snap.Clusters["test-pve"].PresentVotes)
+}
+
+func TestPVE_Collect_StandaloneHasNoClusterStatus(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/cluster/status":
+			fmt.Fprint(w, clusterStatusStandaloneJSON)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, ch, _, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Empty(t, ch.Snapshot().Clusters)
+}
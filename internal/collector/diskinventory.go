@@ -0,0 +1,197 @@
+package collector
+
+import (
+	"cmp"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/darshan-rambhia/glint/internal/store"
+)
+
+// diskInventory tracks every disk seen on a PVE instance across disk polls
+// and turns the differences between polls into lifecycle events.
+type diskInventory struct {
+	missingPolls int                             // disk polls a disk may miss before it is removed
+	disks        map[string]*model.InventoryDisk // keyed by WWN; nil until loaded
+}
+
+// inventoryChanges is the outcome of one disk poll.
+type inventoryChanges struct {
+	events  []*model.DiskEvent
+	absent  []*model.InventoryDisk // known disks whose absence state changed
+	removed []string               // WWNs removed by this poll
+}
+
+func (inv *diskInventory) load(disks []*model.InventoryDisk) {
+	inv.disks = make(map[string]*model.InventoryDisk, len(disks))
+	for _, d := range disks {
+		inv.disks[d.WWN] = d
+	}
+}
+
+// list returns the inventory ordered by node, device path and WWN.
+func (inv *diskInventory) list() []*model.InventoryDisk {
+	disks := slices.Collect(maps.Values(inv.disks))
+	slices.SortFunc(disks, func(a, b *model.InventoryDisk) int {
+		return cmp.Or(cmp.Compare(a.Node, b.Node), cmp.Compare(a.DevPath, b.DevPath), cmp.Compare(a.WWN, b.WWN))
+	})
+	return disks
+}
+
+// update folds one disk poll into the inventory. polled holds the nodes whose
+// disk list was read; disks on other nodes keep their state, so a node that
+// is down does not make its disks look removed.
+func (inv *diskInventory) update(now time.Time, polled map[string]bool, seen []*model.Disk) inventoryChanges {
+	if inv.disks == nil {
+		inv.disks = make(map[string]*model.InventoryDisk)
+	}
+	ts := now.Unix()
+	var ch inventoryChanges
+	changed := make(map[string]*model.InventoryDisk)
+
+	// A disk shared between nodes (e.g. a SAS enclosure) is listed by each
+	// of them; it has only moved if none of them is its known slot.
+	sightings := make(map[string][]*model.Disk, len(seen))
+	for _, d := range seen {
+		sightings[d.WWN] = append(sightings[d.WWN], d)
+	}
+
+	// Absences first, so a new disk below can find the slot it took over.
+	for _, wwn := range slices.Sorted(maps.Keys(inv.disks)) {
+		known := inv.disks[wwn]
+		if sightings[wwn] != nil || known.RemovedAt != nil || !polled[known.Node] {
+			continue
+		}
+		known.MissedPolls++
+		if known.MissedPolls >= max(inv.missingPolls, 1) {
+			known.RemovedAt = &now
+			ch.events = append(ch.events, inventoryEvent(ts, model.DiskEventRemoved, known))
+			ch.removed = append(ch.removed, wwn)
+		}
+		changed[wwn] = known
+	}
+
+	for _, wwn := range slices.Sorted(maps.Keys(sightings)) {
+		disks := sightings[wwn]
+		d := disks[0]
+		known, ok := inv.disks[wwn]
+		switch {
+		case !ok || known.RemovedAt != nil:
+			e := diskEvent(ts, model.DiskEventAdded, d)
+			if old := inv.vacated(d, sightings); old != nil {
+				e.Type = model.DiskEventReplaced
+				e.PrevWWN, e.PrevSerial = old.WWN, old.Serial
+				if old.RemovedAt == nil {
+					old.RemovedAt = &now
+					ch.events = append(ch.events, inventoryEvent(ts, model.DiskEventRemoved, old))
+					ch.removed = append(ch.removed, old.WWN)
+				}
+				old.ReplacedBy = wwn
+				changed[old.WWN] = old
+			}
+			ch.events = append(ch.events, e)
+		default:
+			if i := slices.IndexFunc(disks, func(s *model.Disk) bool {
+				return s.Node == known.Node && s.DevPath == known.DevPath
+			}); i >= 0 {
+				d = disks[i] // still in its slot
+			} else {
+				e := diskEvent(ts, model.DiskEventMoved, d)
+				e.PrevNode, e.PrevDevPath = known.Node, known.DevPath
+				ch.events = append(ch.events, e)
+			}
+		}
+
+		firstSeen := now
+		if ok {
+			firstSeen = known.FirstSeen
+		}
+		inv.disks[wwn] = &model.InventoryDisk{
+			WWN:       wwn,
+			Instance:  d.Instance,
+			Node:      d.Node,
+			DevPath:   d.DevPath,
+			Model:     d.Model,
+			Serial:    d.Serial,
+			DiskType:  d.DiskType,
+			SizeBytes: d.SizeBytes,
+			FirstSeen: firstSeen,
+			LastSeen:  now,
+		}
+	}
+
+	for _, wwn := range slices.Sorted(maps.Keys(changed)) {
+		ch.absent = append(ch.absent, changed[wwn])
+	}
+	return ch
+}
+
+// vacated returns the missing disk that last occupied d's slot, if any.
+func (inv *diskInventory) vacated(d *model.Disk, sightings map[string][]*model.Disk) *model.InventoryDisk {
+	var slot *model.InventoryDisk
+	for wwn, known := range inv.disks {
+		if wwn == d.WWN || sightings[wwn] != nil || known.ReplacedBy != "" ||
+			known.Node != d.Node || known.DevPath != d.DevPath || known.Present() {
+			continue
+		}
+		if slot == nil || known.LastSeen.After(slot.LastSeen) {
+			slot = known
+		}
+	}
+	return slot
+}
+
+func diskEvent(ts int64, typ string, d *model.Disk) *model.DiskEvent {
+	return &model.DiskEvent{
+		Timestamp: ts,
+		Type:      typ,
+		WWN:       d.WWN,
+		Instance:  d.Instance,
+		Node:      d.Node,
+		DevPath:   d.DevPath,
+		Model:     d.Model,
+		Serial:    d.Serial,
+	}
+}
+
+func inventoryEvent(ts int64, typ string, d *model.InventoryDisk) *model.DiskEvent {
+	return &model.DiskEvent{
+		Timestamp: ts,
+		Type:      typ,
+		WWN:       d.WWN,
+		Instance:  d.Instance,
+		Node:      d.Node,
+		DevPath:   d.DevPath,
+		Model:     d.Model,
+		Serial:    d.Serial,
+	}
+}
+
+// trackInventory records the lifecycle events of a disk poll in the batch and
+// drops removed disks from the disk table. polled holds the nodes whose disk
+// list was read.
+func (p *PVECollector) trackInventory(now time.Time, polled map[string]bool, seen []*model.Disk, batch *store.Batch) {
+	if p.inventory.disks == nil {
+		known, err := p.store.QueryDiskInventory(p.config.Name)
+		if err != nil {
+			slog.Warn("loading disk inventory", "instance", p.config.Name, "error", err)
+			return
+		}
+		p.inventory.load(known)
+	}
+
+	ch := p.inventory.update(now, polled, seen)
+	for _, e := range ch.events {
+		slog.Info("disk "+e.Type, "instance", e.Instance, "node", e.Node, "disk", e.DevPath,
+			"wwn", e.WWN, "serial", e.Serial, "model", e.Model)
+		batch.AddDiskEvent(e)
+	}
+	for _, d := range ch.absent {
+		batch.AddDiskAbsence(d)
+	}
+	p.cache.RemoveDisks(ch.removed)
+	p.cache.UpdateInventory(p.config.Name, p.inventory.list())
+}
@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invDisk(wwn, node, devPath, serial string) *model.Disk {
+	return &model.Disk{WWN: wwn, Instance: "homelab", Node: node, DevPath: devPath, Serial: serial, Model: "WD Red", DiskType: "hdd"}
+}
+
+func eventTypes(events []*model.DiskEvent) []string {
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type+" "+e.WWN)
+	}
+	return types
+}
+
+func TestDiskInventory_AddedAndMoved(t *testing.T) {
+	inv := diskInventory{missingPolls: 2}
+	t0 := time.Unix(1_700_000_000, 0)
+	polled := map[string]bool{"pve": true, "pve2": true}
+
+	ch := inv.update(t0, polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A"), invDisk("b", "pve", "/dev/sdb", "B")})
+	assert.Equal(t, []string{"added a", "added b"}, eventTypes(ch.events))
+	assert.Equal(t, t0.Unix(), ch.events[0].Timestamp)
+	assert.Empty(t, ch.absent)
+
+	// Unchanged poll: no events
+	t1 := t0.Add(time.Hour)
+	ch = inv.update(t1, polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A"), invDisk("b", "pve", "/dev/sdb", "B")})
+	assert.Empty(t, ch.events)
+	assert.Equal(t, t0, inv.disks["a"].FirstSeen)
+	assert.Equal(t, t1, inv.disks["a"].LastSeen)
+
+	// b moves to another node, a to another device path
+	ch = inv.update(t1.Add(time.Hour), polled, []*model.Disk{invDisk("a", "pve", "/dev/sdc", "A"), invDisk("b", "pve2", "/dev/sdb", "B")})
+	require.Equal(t, []string{"moved a", "moved b"}, eventTypes(ch.events))
+	assert.Equal(t, "/dev/sda", ch.events[0].PrevDevPath)
+	assert.Equal(t, "pve", ch.events[0].PrevNode)
+	assert.Equal(t, "pve", ch.events[1].PrevNode)
+	assert.Equal(t, "pve2", ch.events[1].Node)
+	assert.Equal(t, "pve2", inv.disks["b"].Node)
+}
+
+func TestDiskInventory_Removed(t *testing.T) {
+	inv := diskInventory{missingPolls: 2}
+	t0 := time.Unix(1_700_000_000, 0)
+	polled := map[string]bool{"pve": true}
+	inv.update(t0, polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A"), invDisk("b", "pve", "/dev/sdb", "B")})
+
+	// First miss is only counted
+	ch := inv.update(t0.Add(time.Hour), polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A")})
+	assert.Empty(t, ch.events)
+	require.Len(t, ch.absent, 1)
+	assert.Equal(t, 1, ch.absent[0].MissedPolls)
+	assert.Nil(t, ch.absent[0].RemovedAt)
+
+	// Node down: its disks keep their state
+	ch = inv.update(t0.Add(2*time.Hour), map[string]bool{}, nil)
+	assert.Empty(t, ch.events)
+	assert.Empty(t, ch.absent)
+	assert.Equal(t, 0, inv.disks["a"].MissedPolls)
+
+	// Second miss removes it
+	t3 := t0.Add(3 * time.Hour)
+	ch = inv.update(t3, polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A")})
+	require.Equal(t, []string{"removed b"}, eventTypes(ch.events))
+	assert.Equal(t, "B", ch.events[0].Serial)
+	assert.Equal(t, []string{"b"}, ch.removed)
+	require.NotNil(t, inv.disks["b"].RemovedAt)
+	assert.Equal(t, t3, *inv.disks["b"].RemovedAt)
+	assert.Equal(t, t0, inv.disks["b"].LastSeen)
+
+	// Removed disks are not counted again
+	ch = inv.update(t0.Add(4*time.Hour), polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A")})
+	assert.Empty(t, ch.events)
+	assert.Empty(t, ch.absent)
+
+	// It comes back
+	ch = inv.update(t0.Add(5*time.Hour), polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A"), invDisk("b", "pve", "/dev/sdb", "B")})
+	assert.Equal(t, []string{"added b"}, eventTypes(ch.events))
+	assert.True(t, inv.disks["b"].Present())
+	assert.Equal(t, t0, inv.disks["b"].FirstSeen)
+}
+
+func TestDiskInventory_Replaced(t *testing.T) {
+	inv := diskInventory{missingPolls: 3}
+	t0 := time.Unix(1_700_000_000, 0)
+	polled := map[string]bool{"pve": true}
+	inv.update(t0, polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A"), invDisk("b", "pve", "/dev/sdb", "B")})
+
+	// b is swapped for c between polls: b is removed at once and c takes its slot
+	ch := inv.update(t0.Add(time.Hour), polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A"), invDisk("c", "pve", "/dev/sdb", "C")})
+	require.Equal(t, []string{"removed b", "replaced c"}, eventTypes(ch.events))
+	assert.Equal(t, "b", ch.events[1].PrevWWN)
+	assert.Equal(t, "B", ch.events[1].PrevSerial)
+	assert.Equal(t, "C", ch.events[1].Serial)
+	assert.Equal(t, []string{"b"}, ch.removed)
+	require.Len(t, ch.absent, 1)
+	assert.Equal(t, "c", ch.absent[0].ReplacedBy)
+
+	// A new disk in a slot whose disk is still present is just added
+	ch = inv.update(t0.Add(2*time.Hour), polled, []*model.Disk{
+		invDisk("a", "pve", "/dev/sda", "A"), invDisk("c", "pve", "/dev/sdb", "C"), invDisk("d", "pve", "/dev/sdb", "D"),
+	})
+	assert.Equal(t, []string{"added d"}, eventTypes(ch.events))
+}
+
+func TestDiskInventory_SharedDisk(t *testing.T) {
+	inv := diskInventory{missingPolls: 2}
+	t0 := time.Unix(1_700_000_000, 0)
+	polled := map[string]bool{"pve": true, "pve2": true}
+	inv.update(t0, polled, []*model.Disk{invDisk("a", "pve", "/dev/sda", "A")})
+
+	// Listed by both nodes, in either order: not a move
+	for _, seen := range [][]*model.Disk{
+		{invDisk("a", "pve2", "/dev/sdb", "A"), invDisk("a", "pve", "/dev/sda", "A")},
+		{invDisk("a", "pve", "/dev/sda", "A"), invDisk("a", "pve2", "/dev/sdb", "A")},
+	} {
+		ch := inv.update(t0.Add(time.Hour), polled, seen)
+		assert.Empty(t, ch.events)
+		assert.Equal(t, "pve", inv.disks["a"].Node)
+	}
+}
+
+func TestDiskInventory_List(t *testing.T) {
+	var inv diskInventory
+	inv.load([]*model.InventoryDisk{
+		{WWN: "c", Node: "pve2", DevPath: "/dev/sda"},
+		{WWN: "b", Node: "pve", DevPath: "/dev/sdb"},
+		{WWN: "a", Node: "pve", DevPath: "/dev/sdb"},
+		{WWN: "d", Node: "pve", DevPath: "/dev/sda"},
+	})
+	var wwns []string
+	for _, d := range inv.list() {
+		wwns = append(wwns, d.WWN)
+	}
+	assert.Equal(t, []string{"d", "a", "b", "c"}, wwns)
+}
@@ -0,0 +1,168 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// guestConfigPollInterval is how often guest hardware configuration is
+// refreshed. Configuration only changes on user action.
+const guestConfigPollInterval = 5 * time.Minute
+
+var (
+	qemuDiskKey = regexp.MustCompile(`^(scsi|virtio|sata|ide|efidisk|tpmstate)\d+$`)
+	lxcDiskKey  = regexp.MustCompile(`^(rootfs|mp\d+)$`)
+	nicKey      = regexp.MustCompile(`^net\d+$`)
+)
+
+// collectGuestConfig fetches the configuration of a single guest.
+func (p *PVECollector) collectGuestConfig(ctx context.Context, g *model.Guest) (*model.GuestConfig, error) {
+	body, err := p.apiGet(ctx, "collectGuestConfig", fmt.Sprintf("/api2/json/nodes/%s/%s/%d/config", g.Node, g.Type, g.VMID))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing guest config response: %w", err)
+	}
+
+	cfg, err := parseGuestConfig(g.Type, resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Instance = p.config.Name
+	cfg.ClusterID = g.ClusterID
+	cfg.VMID = g.VMID
+	return cfg, nil
+}
+
+func parseGuestConfig(guestType string, data json.RawMessage) (*model.GuestConfig, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing guest config: %w", err)
+	}
+
+	get := func(key string) string {
+		v, ok := raw[key]
+		if !ok {
+			return ""
+		}
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			return s
+		}
+		return string(v) // bare number
+	}
+	getInt := func(key string, def int) int {
+		if n, err := strconv.Atoi(get(key)); err == nil {
+			return n
+		}
+		return def
+	}
+
+	cfg := &model.GuestConfig{
+		Cores:  getInt("cores", 1),
+		Memory: int64(getInt("memory", 512)) << 20, // MiB
+		OSType: get("ostype"),
+	}
+	diskKey := lxcDiskKey
+	if guestType == "qemu" {
+		cfg.Sockets = getInt("sockets", 1)
+		diskKey = qemuDiskKey
+	} else {
+		cfg.Swap = int64(getInt("swap", 0)) << 20
+	}
+
+	for key := range raw {
+		switch {
+		case diskKey.MatchString(key):
+			if d, ok := parseGuestDisk(key, get(key)); ok {
+				cfg.Disks = append(cfg.Disks, d)
+			}
+		case nicKey.MatchString(key):
+			cfg.NICs = append(cfg.NICs, parseGuestNIC(key, get(key)))
+		}
+	}
+	sort.Slice(cfg.Disks, func(i, j int) bool { return cfg.Disks[i].Key < cfg.Disks[j].Key })
+	sort.Slice(cfg.NICs, func(i, j int) bool { return cfg.NICs[i].Key < cfg.NICs[j].Key })
+	return cfg, nil
+}
+
+// parseGuestDisk parses a disk property such as
+// "local-lvm:vm-100-disk-0,iothread=1,size=32G". CD-ROM drives are skipped.
+func parseGuestDisk(key, value string) (model.GuestDisk, bool) {
+	volume, opts := splitPVEProperty(value)
+	if volume == "" || volume == "none" || opts["media"] == "cdrom" {
+		return model.GuestDisk{}, false
+	}
+	return model.GuestDisk{Key: key, Volume: volume, Size: parsePVESize(opts["size"])}, true
+}
+
+// parseGuestNIC parses a network property. QEMU uses
+// "virtio=BC:24:11:AA:BB:CC,bridge=vmbr0,tag=20"; LXC uses
+// "name=eth0,bridge=vmbr0,hwaddr=BC:24:11:AA:BB:CC,ip=dhcp,type=veth".
+func parseGuestNIC(key, value string) model.GuestNIC {
+	first, opts := splitPVEProperty(value)
+	nic := model.GuestNIC{
+		Key:      key,
+		Bridge:   opts["bridge"],
+		Firewall: opts["firewall"] == "1",
+		IP:       opts["ip"],
+	}
+	nic.VLAN, _ = strconv.Atoi(opts["tag"])
+	if m, mac, ok := strings.Cut(first, "="); ok && m != "name" {
+		nic.Model, nic.MAC = m, mac
+	} else {
+		nic.Model, nic.MAC = opts["type"], opts["hwaddr"]
+	}
+	return nic
+}
+
+// splitPVEProperty splits a PVE property string into its leading value and
+// key=value options. The leading value is returned verbatim, including a
+// "model=MAC" pair for QEMU NICs; it is also added to the options map.
+func splitPVEProperty(value string) (string, map[string]string) {
+	parts := strings.Split(value, ",")
+	opts := make(map[string]string, len(parts))
+	for _, part := range parts {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			opts[k] = v
+		}
+	}
+	return parts[0], opts
+}
+
+// parsePVESize parses a PVE size string ("32G", "512M", "1T", "4096") into bytes.
+func parsePVESize(s string) int64 {
+	if s == "" {
+		return 0
+	}
+	shift := 0
+	switch s[len(s)-1] {
+	case 'K':
+		shift = 10
+	case 'M':
+		shift = 20
+	case 'G':
+		shift = 30
+	case 'T':
+		shift = 40
+	}
+	if shift > 0 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(n * float64(int64(1)<<shift))
+}
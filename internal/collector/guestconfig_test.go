@@ -0,0 +1,223 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Test fixtures — realistic PVE guest config JSON
+// ---------------------------------------------------------------------------
+
+const qemuConfigJSON = `{
+	"data": {
+		"cores": 4,
+		"sockets": 2,
+		"memory": "8192",
+		"ostype": "l26",
+		"scsi0": "local-lvm:vm-100-disk-0,iothread=1,size=32G",
+		"scsi1": "tank:vm-100-disk-1,size=1T",
+		"efidisk0": "local-lvm:vm-100-disk-2,efitype=4m,size=4M",
+		"ide2": "local:iso/debian-12.iso,media=cdrom,size=628M",
+		"ide0": "none,media=cdrom",
+		"net0": "virtio=BC:24:11:AA:BB:CC,bridge=vmbr0,firewall=1,tag=20",
+		"net1": "e1000=BC:24:11:DD:EE:FF,bridge=vmbr1",
+		"digest": "0123456789abcdef"
+	}
+}`
+
+const lxcConfigJSON = `{
+	"data": {
+		"arch": "amd64",
+		"cores": 2,
+		"memory": 1024,
+		"swap": 512,
+		"hostname": "network-services",
+		"ostype": "debian",
+		"rootfs": "local-lvm:subvol-101-disk-0,size=8G",
+		"mp0": "tank:subvol-101-disk-1,mp=/data,size=100G",
+		"net0": "name=eth0,bridge=vmbr0,firewall=1,gw=10.0.0.1,hwaddr=BC:24:11:11:22:33,ip=10.0.0.5/24,type=veth"
+	}
+}`
+
+// ---------------------------------------------------------------------------
+// Parsers
+// ---------------------------------------------------------------------------
+
+func TestParseGuestConfig_QEMU(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(qemuConfigJSON), &resp))
+
+	cfg, err := parseGuestConfig("qemu", resp.Data)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, cfg.Cores)
+	assert.Equal(t, 2, cfg.Sockets)
+	assert.Equal(t, int64(8192)<<20, cfg.Memory)
+	assert.Equal(t, "l26", cfg.OSType)
+	assert.Zero(t, cfg.Swap)
+
+	// CD-ROM drives are skipped; sorted by key
+	require.Len(t, cfg.Disks, 3)
+	assert.Equal(t, "efidisk0", cfg.Disks[0].Key)
+	assert.Equal(t, "scsi0", cfg.Disks[1].Key)
+	assert.Equal(t, "local-lvm:vm-100-disk-0", cfg.Disks[1].Volume)
+	assert.Equal(t, int64(32)<<30, cfg.Disks[1].Size)
+	assert.Equal(t, int64(1)<<40, cfg.Disks[2].Size)
+
+	require.Len(t, cfg.NICs, 2)
+	assert.Equal(t, "virtio", cfg.NICs[0].Model)
+	assert.Equal(t, "BC:24:11:AA:BB:CC", cfg.NICs[0].MAC)
+	assert.Equal(t, "vmbr0", cfg.NICs[0].Bridge)
+	assert.Equal(t, 20, cfg.NICs[0].VLAN)
+	assert.True(t, cfg.NICs[0].Firewall)
+	assert.Equal(t, "e1000", cfg.NICs[1].Model)
+	assert.False(t, cfg.NICs[1].Firewall)
+}
+
+func TestParseGuestConfig_LXC(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(lxcConfigJSON), &resp))
+
+	cfg, err := parseGuestConfig("lxc", resp.Data)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, cfg.Cores)
+	assert.Zero(t, cfg.Sockets)
+	assert.Equal(t, int64(1024)<<20, cfg.Memory)
+	assert.Equal(t, int64(512)<<20, cfg.Swap)
+
+	require.Len(t, cfg.Disks, 2)
+	assert.Equal(t, "mp0", cfg.Disks[0].Key)
+	assert.Equal(t, int64(100)<<30, cfg.Disks[0].Size)
+	assert.Equal(t, "rootfs", cfg.Disks[1].Key)
+
+	require.Len(t, cfg.NICs, 1)
+	assert.Equal(t, "veth", cfg.NICs[0].Model)
+	assert.Equal(t, "BC:24:11:11:22:33", cfg.NICs[0].MAC)
+	assert.Equal(t, "10.0.0.5/24", cfg.NICs[0].IP)
+}
+
+func TestParseGuestConfig_Defaults(t *testing.T) {
+	cfg, err := parseGuestConfig("qemu", json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.Cores)
+	assert.Equal(t, 1, cfg.Sockets)
+	assert.Equal(t, int64(512)<<20, cfg.Memory)
+	assert.Empty(t, cfg.Disks)
+}
+
+func TestParseGuestConfig_InvalidJSON(t *testing.T) {
+	_, err := parseGuestConfig("qemu", json.RawMessage(`[]`))
+	assert.ErrorContains(t, err, "parsing guest config")
+}
+
+func TestParsePVESize(t *testing.T) {
+	assert.Equal(t, int64(528)<<10, parsePVESize("528K"))
+	assert.Equal(t, int64(4)<<20, parsePVESize("4M"))
+	assert.Equal(t, int64(32)<<30, parsePVESize("32G"))
+	assert.Equal(t, int64(1536)<<30, parsePVESize("1.5T"))
+	assert.Equal(t, int64(4096), parsePVESize("4096"))
+	assert.Zero(t, parsePVESize(""))
+	assert.Zero(t, parsePVESize("abcG"))
+}
+
+// ---------------------------------------------------------------------------
+// collectGuestConfig
+// ---------------------------------------------------------------------------
+
+func TestPVE_collectGuestConfig(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/nodes/pve/qemu/100/config" {
+			fmt.Fprint(w, qemuConfigJSON)
+			return
+		}
+		http.Error(w, "not found", 404)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	cfg, err := coll.collectGuestConfig(context.Background(), &model.Guest{VMID: 100, Node: "pve", Type: "qemu", ClusterID: "c1"})
+	require.NoError(t, err)
+	assert.Equal(t, "test-pve", cfg.Instance)
+	assert.Equal(t, "c1", cfg.ClusterID)
+	assert.Equal(t, 100, cfg.VMID)
+	assert.Equal(t, 4, cfg.Cores)
+}
+
+func TestPVE_collectGuestConfig_Errors(t *testing.T) {
+	g := &model.Guest{VMID: 100, Node: "pve", Type: "qemu"}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+	_, err := coll.collectGuestConfig(context.Background(), g)
+	assert.Error(t, err)
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `not json`)
+	})
+	coll, _, _, _ = newTestPVECollector(t, handler)
+	_, err = coll.collectGuestConfig(context.Background(), g)
+	assert.ErrorContains(t, err, "parsing guest config response")
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data": []}`)
+	})
+	coll, _, _, _ = newTestPVECollector(t, handler)
+	_, err = coll.collectGuestConfig(context.Background(), g)
+	assert.ErrorContains(t, err, "parsing guest config")
+}
+
+func guestConfigCollectHandler(configCalls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc":
+			fmt.Fprint(w, lxcListJSON)
+		case "/api2/json/nodes/pve/lxc/101/config":
+			configCalls.Add(1)
+			fmt.Fprint(w, lxcConfigJSON)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	}
+}
+
+func TestPVE_Collect_GuestConfigs(t *testing.T) {
+	var calls atomic.Int32
+	coll, ch, _, _ := newTestPVECollector(t, guestConfigCollectHandler(&calls))
+
+	require.NoError(t, coll.Collect(context.Background()))
+
+	snap := ch.Snapshot()
+	require.Contains(t, snap.GuestConfigs, "test-pve")
+	cfg := snap.GuestConfigs["test-pve"][101]
+	require.NotNil(t, cfg)
+	assert.Equal(t, "test-pve", cfg.Instance)
+	assert.Equal(t, 2, cfg.Cores)
+	assert.Positive(t, calls.Load())
+	assert.False(t, coll.lastConfigPoll.IsZero())
+}
+
+func TestPVE_Collect_SkipsGuestConfigsWhenNotDue(t *testing.T) {
+	var calls atomic.Int32
+	coll, ch, _, _ := newTestPVECollector(t, guestConfigCollectHandler(&calls))
+	coll.lastConfigPoll = time.Now()
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Zero(t, calls.Load())
+	assert.Empty(t, ch.Snapshot().GuestConfigs)
+}
@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// rrdRow is one row of a node or guest rrddata response. PVE omits values
+// for intervals without data, so every metric is a pointer. Guest rows carry
+// mem/maxmem and disk counters; node rows carry the memused/rootused family.
+type rrdRow struct {
+	Time   int64    `json:"time"`
+	CPU    *float64 `json:"cpu"`
+	NetIn  *float64 `json:"netin"`
+	NetOut *float64 `json:"netout"`
+
+	// Node fields
+	MemUsed    *float64 `json:"memused"`
+	MemTotal   *float64 `json:"memtotal"`
+	SwapUsed   *float64 `json:"swapused"`
+	SwapTotal  *float64 `json:"swaptotal"`
+	RootUsed   *float64 `json:"rootused"`
+	RootTotal  *float64 `json:"roottotal"`
+	LoadAvg    *float64 `json:"loadavg"`
+	IOWait     *float64 `json:"iowait"`
+	CPUSome    *float64 `json:"pressurecpusome"`
+	IOSome     *float64 `json:"pressureiosome"`
+	IOFull     *float64 `json:"pressureiofull"`
+	MemorySome *float64 `json:"pressurememorysome"`
+	MemoryFull *float64 `json:"pressurememoryfull"`
+
+	// Guest fields
+	Mem       *float64 `json:"mem"`
+	MaxMem    *float64 `json:"maxmem"`
+	Disk      *float64 `json:"disk"`
+	MaxDisk   *float64 `json:"maxdisk"`
+	DiskRead  *float64 `json:"diskread"`
+	DiskWrite *float64 `json:"diskwrite"`
+}
+
+// fetchRRD returns the averaged RRD rows for an API path such as
+// /api2/json/nodes/pve/rrddata over the given timeframe (hour, day, ...).
+func (p *PVECollector) fetchRRD(ctx context.Context, op, path, timeframe string) ([]rrdRow, error) {
+	body, err := p.apiGet(ctx, op, fmt.Sprintf("%s?timeframe=%s&cf=AVERAGE", path, timeframe))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing rrddata response: %w", err)
+	}
+
+	var rows []rrdRow
+	if err := json.Unmarshal(resp.Data, &rows); err != nil {
+		return nil, fmt.Errorf("parsing rrddata: %w", err)
+	}
+	return rows, nil
+}
+
+// collectNodeRRD fetches the latest node averages from the hourly RRD data.
+func (p *PVECollector) collectNodeRRD(ctx context.Context, nodeName string) (*model.NodeRRD, error) {
+	rows, err := p.fetchRRD(ctx, "collectNodeRRD", fmt.Sprintf("/api2/json/nodes/%s/rrddata", nodeName), "hour")
+	if err != nil {
+		return nil, err
+	}
+	return latestNodeRRD(rows)
+}
+
+// latestNodeRRD returns the newest complete RRD row. The most recent row is
+// often still being filled and carries only a timestamp, so rows without
+// network data are skipped.
+func latestNodeRRD(rows []rrdRow) (*model.NodeRRD, error) {
+	for i := len(rows) - 1; i >= 0; i-- {
+		if rrd := nodeRRDFromRow(rows[i]); rrd != nil {
+			return rrd, nil
+		}
+	}
+	return nil, fmt.Errorf("no rrddata rows with network data")
+}
+
+// nodeRRDFromRow converts a row to NodeRRD, or returns nil when the row has
+// no network data. Pressure is only set when the node reports PSI.
+func nodeRRDFromRow(r rrdRow) *model.NodeRRD {
+	if r.NetIn == nil || r.NetOut == nil {
+		return nil
+	}
+	rrd := &model.NodeRRD{NetIn: *r.NetIn, NetOut: *r.NetOut}
+	if r.CPUSome != nil || r.IOSome != nil || r.MemorySome != nil {
+		rrd.Pressure = &model.NodePressure{
+			CPUSome:    deref(r.CPUSome),
+			IOSome:     deref(r.IOSome),
+			IOFull:     deref(r.IOFull),
+			MemorySome: deref(r.MemorySome),
+			MemoryFull: deref(r.MemoryFull),
+		}
+	}
+	return rrd
+}
+
+func deref(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
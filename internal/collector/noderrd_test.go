@@ -0,0 +1,180 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Test fixtures — realistic PVE /nodes/{node}/rrddata JSON
+// ---------------------------------------------------------------------------
+
+const nodeRRDDataJSON = `{
+	"data": [
+		{"time": 1700000000, "cpu": 0.05, "iowait": 0.001, "netin": 1000.5, "netout": 2000.25, "pressurecpusome": 0.4, "pressureiosome": 2.5, "pressureiofull": 1.25, "pressurememorysome": 0, "pressurememoryfull": 0},
+		{"time": 1700000060, "cpu": 0.07, "iowait": 0.002, "netin": 117000000, "netout": 3500, "pressurecpusome": 0.5, "pressureiosome": 12.5, "pressureiofull": 8.75, "pressurememorysome": 0.1, "pressurememoryfull": 0.05},
+		{"time": 1700000120}
+	]
+}`
+
+const nodeRRDDataNoPSIJSON = `{
+	"data": [
+		{"time": 1700000000, "cpu": 0.05, "netin": 1000, "netout": 2000}
+	]
+}`
+
+// ---------------------------------------------------------------------------
+// Parsers
+// ---------------------------------------------------------------------------
+
+// decodeRRD unmarshals a rrddata fixture into rows.
+func decodeRRD(t *testing.T, raw string) []rrdRow {
+	t.Helper()
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(raw), &resp))
+	var rows []rrdRow
+	require.NoError(t, json.Unmarshal(resp.Data, &rows))
+	return rows
+}
+
+func TestLatestNodeRRD(t *testing.T) {
+	rrd, err := latestNodeRRD(decodeRRD(t, nodeRRDDataJSON))
+	require.NoError(t, err)
+	// The trailing timestamp-only row is skipped
+	assert.Equal(t, float64(117000000), rrd.NetIn)
+	assert.Equal(t, float64(3500), rrd.NetOut)
+	require.NotNil(t, rrd.Pressure)
+	assert.Equal(t, 0.5, rrd.Pressure.CPUSome)
+	assert.Equal(t, 12.5, rrd.Pressure.IOSome)
+	assert.Equal(t, 8.75, rrd.Pressure.IOFull)
+	assert.Equal(t, 0.1, rrd.Pressure.MemorySome)
+	assert.Equal(t, 0.05, rrd.Pressure.MemoryFull)
+}
+
+func TestLatestNodeRRD_NoPressure(t *testing.T) {
+	rrd, err := latestNodeRRD(decodeRRD(t, nodeRRDDataNoPSIJSON))
+	require.NoError(t, err)
+	assert.Equal(t, float64(1000), rrd.NetIn)
+	assert.Nil(t, rrd.Pressure, "PVE versions without PSI report no pressure")
+}
+
+func TestLatestNodeRRD_NoRows(t *testing.T) {
+	_, err := latestNodeRRD([]rrdRow{{Time: 1700000000}})
+	assert.ErrorContains(t, err, "no rrddata rows")
+
+	_, err = latestNodeRRD(nil)
+	assert.Error(t, err)
+}
+
+// ---------------------------------------------------------------------------
+// collectNodeRRD
+// ---------------------------------------------------------------------------
+
+func TestPVE_collectNodeRRD(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/nodes/pve/rrddata" && r.URL.Query().Get("timeframe") == "hour" {
+			fmt.Fprint(w, nodeRRDDataJSON)
+			return
+		}
+		http.Error(w, "not found", 404)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	rrd, err := coll.collectNodeRRD(context.Background(), "pve")
+	require.NoError(t, err)
+	assert.Equal(t, float64(3500), rrd.NetOut)
+}
+
+func TestPVE_collectNodeRRD_Errors(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+	_, err := coll.collectNodeRRD(context.Background(), "pve")
+	assert.Error(t, err)
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `not json`)
+	})
+	coll, _, _, _ = newTestPVECollector(t, handler)
+	_, err = coll.collectNodeRRD(context.Background(), "pve")
+	assert.ErrorContains(t, err, "parsing rrddata response")
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data": {}}`)
+	})
+	coll, _, _, _ = newTestPVECollector(t, handler)
+	_, err = coll.collectNodeRRD(context.Background(), "pve")
+	assert.ErrorContains(t, err, "parsing rrddata")
+}
+
+func TestPVE_Collect_NodeRRD(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/rrddata":
+			fmt.Fprint(w, nodeRRDDataJSON)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, ch, st, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+	recent := time.Now().Add(-time.Hour).Unix()
+
+	node := ch.Snapshot().Nodes["test-pve"]["pve"]
+	require.NotNil(t, node)
+	require.NotNil(t, node.RRD)
+	assert.Equal(t, float64(117000000), node.RRD.NetIn)
+
+	points, err := st.QueryNodeSparkline("test-pve", "pve", "netin", recent)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	assert.Equal(t, float64(117000000), points[0].Value)
+
+	points, err = st.QueryNodeSparkline("test-pve", "pve", "psi_io", recent)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	assert.Equal(t, 12.5, points[0].Value)
+}
+
+func TestPVE_Collect_NodeRRDUnavailable(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/rrddata":
+			http.Error(w, "forbidden", 403)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, ch, st, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+	recent := time.Now().Add(-time.Hour).Unix()
+
+	node := ch.Snapshot().Nodes["test-pve"]["pve"]
+	require.NotNil(t, node)
+	assert.Nil(t, node.RRD)
+
+	points, err := st.QueryNodeSparkline("test-pve", "pve", "netin", recent)
+	require.NoError(t, err)
+	assert.Empty(t, points)
+	points, err = st.QueryNodeSparkline("test-pve", "pve", "cpu", recent)
+	require.NoError(t, err)
+	assert.Len(t, points, 1)
+}
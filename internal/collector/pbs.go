@@ -145,16 +145,16 @@ func (c *PBSCollector) Collect(ctx context.Context) error {
 		c.cache.UpdateTasks(c.config.Name, tasks)
 	}
 
-	// Write to store
+	// Write to store in one transaction
+	var batch store.Batch
 	for _, ds := range datastores {
-		if err := c.store.InsertDatastoreSnapshot(ts, ds); err != nil {
-			slog.Error("storing datastore snapshot", "pbs", c.config.Name, "store", ds.Name, "error", err)
-		}
+		batch.AddDatastoreSnapshot(ts, ds)
 	}
 	for _, b := range backups {
-		if err := c.store.InsertBackupSnapshot(ts, b); err != nil {
-			slog.Error("storing backup snapshot", "pbs", c.config.Name, "id", b.BackupID, "error", err)
-		}
+		batch.AddBackupSnapshot(ts, b)
+	}
+	if err := c.store.WriteBatch(&batch); err != nil {
+		slog.Error("storing PBS snapshots", "pbs", c.config.Name, "rows", batch.Len(), "error", err)
 	}
 
 	c.cache.SetLastPoll(c.Name(), now)
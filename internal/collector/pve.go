@@ -32,18 +32,28 @@ type PVEConfig struct {
 	IsCluster        bool
 	PollInterval     time.Duration
 	DiskPollInterval time.Duration
+	HistoryWindow    time.Duration // history seeded from RRD on first poll; 0 disables
+	DiskMissingPolls int           // disk polls a disk may miss before it is reported removed
+	SMARTRules       *smart.Rules  // attribute scoring overrides; nil uses the built-in thresholds
 }
 
 // PVECollector polls a single Proxmox VE instance.
 type PVECollector struct {
-	config       PVEConfig
-	client       *http.Client
-	pool         *WorkerPool
-	cache        *cache.Cache
-	store        *store.Store
-	nodes        []string
-	clusterID    string
-	lastDiskPoll time.Time
+	config         PVEConfig
+	client         *http.Client
+	pool           *WorkerPool
+	cache          *cache.Cache
+	store          *store.Store
+	nodes          []string
+	clusterID      string
+	lastDiskPoll   time.Time
+	lastBackupPoll time.Time
+	lastConfigPoll time.Time
+	counters       map[int]guestCounters   // previous guest counter samples, keyed by VMID
+	vzdumpGuests   map[string]map[int]bool // per-guest results of multi-guest vzdump runs, keyed by UPID
+	backfilled     bool
+	smartShell     smartReportReader // nil unless SSH is configured
+	inventory      diskInventory
 }
 
 // NewPVECollector creates a new PVE collector.
@@ -61,9 +71,18 @@ func NewPVECollector(cfg PVEConfig, pool *WorkerPool, c *cache.Cache, s *store.S
 			Transport: transport,
 			Timeout:   30 * time.Second,
 		},
-		pool:  pool,
-		cache: c,
-		store: s,
+		pool:      pool,
+		cache:     c,
+		store:     s,
+		inventory: diskInventory{missingPolls: cfg.DiskMissingPolls},
+	}
+}
+
+// SetSMARTShell enables reading self-test logs over SSH for disks whose PVE
+// SMART output does not include them. Call before the collector runs.
+func (p *PVECollector) SetSMARTShell(s *SMARTShell) {
+	if s != nil {
+		p.smartShell = s
 	}
 }
 
@@ -87,6 +106,8 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 
 	now := time.Now()
 	pollDisks := now.Sub(p.lastDiskPoll) >= p.config.DiskPollInterval
+	pollBackups := now.Sub(p.lastBackupPoll) >= pveBackupPollInterval
+	pollConfigs := now.Sub(p.lastConfigPoll) >= guestConfigPollInterval
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -94,6 +115,9 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 	nodeMap := make(map[string]*model.Node)
 	guestMap := make(map[int]*model.Guest)
 	var diskList []*model.Disk
+	diskNodes := make(map[string]bool) // nodes whose disk list was read
+	var vzdumpTasks []vzdumpTask
+	configMap := make(map[int]*model.GuestConfig)
 
 	for _, nodeName := range p.nodes {
 		wg.Add(1)
@@ -107,6 +131,11 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 				slog.Error("collecting node status", "instance", p.config.Name, "node", nodeName, "error", err)
 				return
 			}
+			if rrd, err := p.collectNodeRRD(ctx, nodeName); err != nil {
+				slog.Debug("collecting node rrddata", "instance", p.config.Name, "node", nodeName, "error", err)
+			} else {
+				node.RRD = rrd
+			}
 			mu.Lock()
 			nodeMap[nodeName] = node
 			mu.Unlock()
@@ -121,6 +150,20 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 					guestMap[g.VMID] = g
 				}
 				mu.Unlock()
+
+				// Collect guest configuration if due
+				if pollConfigs {
+					for _, g := range guests {
+						cfg, err := p.collectGuestConfig(ctx, g)
+						if err != nil {
+							slog.Debug("collecting guest config", "instance", p.config.Name, "vmid", g.VMID, "error", err)
+							continue
+						}
+						mu.Lock()
+						configMap[g.VMID] = cfg
+						mu.Unlock()
+					}
+				}
 			}
 
 			// Collect disks if due
@@ -131,6 +174,19 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 				} else {
 					mu.Lock()
 					diskList = append(diskList, disks...)
+					diskNodes[nodeName] = true
+					mu.Unlock()
+				}
+			}
+
+			// Collect vzdump task history if due
+			if pollBackups {
+				tasks, err := p.collectVzdumpTasks(ctx, nodeName)
+				if err != nil {
+					slog.Debug("collecting vzdump tasks", "instance", p.config.Name, "node", nodeName, "error", err)
+				} else {
+					mu.Lock()
+					vzdumpTasks = append(vzdumpTasks, tasks...)
 					mu.Unlock()
 				}
 			}
@@ -142,9 +198,63 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 
 	wg.Wait()
 
+	// Ceph is cluster-wide; query it through the first node that answered.
+	var ceph *model.CephStatus
+	for _, nodeName := range p.nodes {
+		if _, ok := nodeMap[nodeName]; !ok {
+			continue
+		}
+		status, err := p.collectCeph(ctx, nodeName)
+		if err != nil {
+			slog.Debug("ceph not available", "instance", p.config.Name, "error", err)
+		} else {
+			ceph = status
+		}
+		break
+	}
+
+	// Standalone nodes have no cluster entry in /cluster/status; that is
+	// reported as an error and simply leaves the cluster status empty.
+	var cluster *model.ClusterStatus
+	if status, err := p.collectClusterStatus(ctx); err != nil {
+		slog.Debug("cluster status not available", "instance", p.config.Name, "error", err)
+	} else {
+		cluster = status
+	}
+
+	tasks, err := p.collectTasks(ctx)
+	if err != nil {
+		slog.Debug("collecting cluster tasks", "instance", p.config.Name, "error", err)
+	}
+
+	rates := p.applyGuestRates(guestMap, now)
+
 	// Update cache
 	p.cache.UpdateNodes(p.config.Name, nodeMap)
 	p.cache.UpdateGuests(p.clusterID, guestMap)
+	p.cache.UpdateCeph(p.config.Name, ceph)
+	p.cache.UpdateCluster(p.config.Name, cluster)
+
+	if tasks != nil {
+		p.cache.UpdatePVETasks(p.config.Name, tasks)
+	}
+
+	if pollConfigs {
+		p.cache.UpdateGuestConfigs(p.clusterID, configMap)
+		p.lastConfigPoll = now
+	}
+
+	if pollBackups {
+		jobs, err := p.collectBackupJobs(ctx)
+		if err != nil {
+			slog.Debug("collecting backup jobs", "instance", p.config.Name, "error", err)
+		} else {
+			p.resolveVzdumpGuests(ctx, vzdumpTasks, now)
+			p.cache.UpdateBackupJobs(p.config.Name, jobs)
+			p.cache.UpdateGuestBackups(p.clusterID, buildGuestBackups(p.config.Name, p.clusterID, guestMap, jobs, vzdumpTasks))
+		}
+		p.lastBackupPoll = now
+	}
 
 	if pollDisks {
 		diskMap := make(map[string]*model.Disk, len(diskList))
@@ -155,8 +265,15 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 		p.lastDiskPoll = now
 	}
 
-	// Write snapshots to store
+	// Seed history before the first snapshot is written.
+	if !p.backfilled && p.config.HistoryWindow > 0 && len(nodeMap) > 0 {
+		p.backfill(ctx, nodeMap, guestMap, now)
+		p.backfilled = true
+	}
+
+	// Write snapshots to store in one transaction
 	ts := now.Unix()
+	var batch store.Batch
 	for _, node := range nodeMap {
 		snap := model.NodeSnapshot{
 			Timestamp:  ts,
@@ -176,9 +293,14 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 			UptimeSecs: node.Uptime,
 			CPUTemp:    node.Temperature,
 		}
-		if err := p.store.InsertNodeSnapshot(snap); err != nil {
-			slog.Error("storing node snapshot", "instance", p.config.Name, "node", node.Name, "error", err)
+		if rrd := node.RRD; rrd != nil {
+			snap.NetIn, snap.NetOut = &rrd.NetIn, &rrd.NetOut
+			if ps := rrd.Pressure; ps != nil {
+				snap.PSICPU, snap.PSIIO, snap.PSIIOFull = &ps.CPUSome, &ps.IOSome, &ps.IOFull
+				snap.PSIMem, snap.PSIMemFull = &ps.MemorySome, &ps.MemoryFull
+			}
 		}
+		batch.AddNodeSnapshot(snap)
 	}
 
 	for _, guest := range guestMap {
@@ -203,18 +325,35 @@ func (p *PVECollector) Collect(ctx context.Context) error {
 			DiskTotal: guest.MaxDisk,
 			NetIn:     guest.NetIn,
 			NetOut:    guest.NetOut,
+			DiskRead:  guest.DiskRead,
+			DiskWrite: guest.DiskWrite,
+
+			NetInRate:     rates[guest.VMID].netIn,
+			NetOutRate:    rates[guest.VMID].netOut,
+			DiskReadRate:  rates[guest.VMID].diskRead,
+			DiskWriteRate: rates[guest.VMID].diskWrite,
 		}
-		if err := p.store.InsertGuestSnapshot(snap); err != nil {
-			slog.Error("storing guest snapshot", "instance", p.config.Name, "vmid", guest.VMID, "error", err)
-		}
+		batch.AddGuestSnapshot(snap)
 	}
 
 	for _, disk := range diskList {
-		if err := p.store.UpsertDisk(disk); err != nil {
-			slog.Error("storing disk", "wwn", disk.WWN, "error", err)
-		}
-		if err := p.store.InsertSMARTSnapshot(ts, disk); err != nil {
-			slog.Error("storing SMART snapshot", "wwn", disk.WWN, "error", err)
+		batch.AddDisk(disk)
+		batch.AddSMARTSnapshot(ts, disk)
+	}
+	if pollDisks {
+		p.trackInventory(now, diskNodes, diskList, &batch)
+	}
+
+	for _, task := range tasks {
+		batch.AddPVETask(task)
+	}
+
+	if err := p.store.WriteBatch(&batch); err != nil {
+		slog.Error("storing PVE snapshots", "instance", p.config.Name, "rows", batch.Len(), "error", err)
+		if pollDisks {
+			// The in-memory inventory already holds this poll's changes.
+			// Reload it from the store so the next poll finds them again.
+			p.inventory.disks = nil
 		}
 	}
 
@@ -453,18 +592,20 @@ func (p *PVECollector) collectGuestType(ctx context.Context, nodeName, guestType
 	}
 
 	var rawGuests []struct {
-		VMID    int     `json:"vmid"`
-		Name    string  `json:"name"`
-		Status  string  `json:"status"`
-		CPU     float64 `json:"cpu"`
-		CPUs    int     `json:"cpus"`
-		Mem     int64   `json:"mem"`
-		MaxMem  int64   `json:"maxmem"`
-		Disk    int64   `json:"disk"`
-		MaxDisk int64   `json:"maxdisk"`
-		NetIn   int64   `json:"netin"`
-		NetOut  int64   `json:"netout"`
-		Uptime  int64   `json:"uptime"`
+		VMID      int     `json:"vmid"`
+		Name      string  `json:"name"`
+		Status    string  `json:"status"`
+		CPU       float64 `json:"cpu"`
+		CPUs      int     `json:"cpus"`
+		Mem       int64   `json:"mem"`
+		MaxMem    int64   `json:"maxmem"`
+		Disk      int64   `json:"disk"`
+		MaxDisk   int64   `json:"maxdisk"`
+		NetIn     int64   `json:"netin"`
+		NetOut    int64   `json:"netout"`
+		DiskRead  int64   `json:"diskread"`
+		DiskWrite int64   `json:"diskwrite"`
+		Uptime    int64   `json:"uptime"`
 	}
 	if err := json.Unmarshal(resp.Data, &rawGuests); err != nil {
 		return nil, fmt.Errorf("parsing %s data: %w", guestType, err)
@@ -488,6 +629,8 @@ func (p *PVECollector) collectGuestType(ctx context.Context, nodeName, guestType
 			MaxDisk:   rg.MaxDisk,
 			NetIn:     rg.NetIn,
 			NetOut:    rg.NetOut,
+			DiskRead:  rg.DiskRead,
+			DiskWrite: rg.DiskWrite,
 			Uptime:    rg.Uptime,
 		})
 	}
@@ -578,16 +721,100 @@ func (p *PVECollector) collectDisks(ctx context.Context, nodeName string) ([]*mo
 		if err := p.collectSMART(ctx, nodeName, disk); err != nil {
 			slog.Warn("collecting SMART data", "disk", rd.DevPath, "node", nodeName, "error", err)
 			disk.Status = model.StatusInternalError
+		} else {
+			p.evaluateTrends(disk, now)
+			p.projectEndurance(disk, now)
 		}
 
 		disks = append(disks, disk)
 	}
 
+	if p.smartShell != nil {
+		p.readSelfTestLogs(ctx, nodeName, disks)
+	}
+	for _, disk := range disks {
+		smart.DateSelfTests(disk)
+		disk.Risk = p.config.SMARTRules.ScoreRisk(disk)
+	}
+
 	slog.Debug("PVE disks collected", "instance", p.config.Name, "node", nodeName,
 		"listed", len(rawDisks), "collected", len(disks))
 	return disks, nil
 }
 
+// readSelfTestLogs fills in self-test logs the PVE API did not return by
+// running smartctl over SSH. The SSH host is a single node of the cluster,
+// so only that node's disks are read; a report is also only used when its
+// serial number matches the disk.
+func (p *PVECollector) readSelfTestLogs(ctx context.Context, nodeName string, disks []*model.Disk) {
+	sshNode, err := p.smartShell.Node(ctx)
+	if err != nil {
+		slog.Warn("reading SSH node name", "instance", p.config.Name, "error", err)
+		return
+	}
+	if !strings.EqualFold(sshNode, nodeName) {
+		return
+	}
+
+	var paths []string
+	for _, disk := range disks {
+		if disk.SelfTestLog == nil && disk.Serial != "" && disk.Status != model.StatusInternalError {
+			paths = append(paths, disk.DevPath)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+	reports, err := p.smartShell.Reports(ctx, paths)
+	if err != nil {
+		slog.Warn("reading SMART over SSH", "instance", p.config.Name, "node", nodeName, "error", err)
+	}
+	for _, disk := range disks {
+		r := reports[disk.DevPath]
+		if r == nil || disk.SelfTestLog != nil || !strings.EqualFold(strings.TrimSpace(r.Serial), strings.TrimSpace(disk.Serial)) {
+			continue
+		}
+		disk.SelfTestLog = r.SelfTestLog
+	}
+}
+
+// evaluateTrends compares the disk's error counters against its recorded
+// SMART history. A failed query leaves the disk without trends.
+func (p *PVECollector) evaluateTrends(disk *model.Disk, now time.Time) {
+	ids := smart.TrendAttributeIDs(disk.Protocol)
+	if len(ids) == 0 {
+		return
+	}
+	history, err := p.store.QuerySMARTAttributeHistory(disk.WWN, now.Add(-smart.TrendSpan).Unix(), ids)
+	if err != nil {
+		slog.Warn("querying SMART history", "disk", disk.DevPath, "error", err)
+		return
+	}
+	p.config.SMARTRules.EvaluateTrends(disk, history, now)
+}
+
+// projectEndurance estimates an SSD's remaining write endurance from its
+// recorded wear and host writes.
+func (p *PVECollector) projectEndurance(disk *model.Disk, now time.Time) {
+	if disk.Wearout == nil || disk.DiskType == "hdd" {
+		return
+	}
+	wear, err := p.store.QueryFirstDiskPoint(disk.WWN, "wearout")
+	if err != nil {
+		slog.Warn("querying SSD wear history", "disk", disk.DevPath, "error", err)
+		return
+	}
+	var written []model.AttributeSample
+	if id, _, ok := smart.HostWritesAttribute(disk); ok {
+		written, err = p.store.QuerySMARTAttributeHistory(disk.WWN, now.Add(-smart.TrendSpan).Unix(), []int{id})
+		if err != nil {
+			slog.Warn("querying SSD write history", "disk", disk.DevPath, "error", err)
+			return
+		}
+	}
+	disk.Endurance = smart.ProjectEndurance(disk, wear, written, now)
+}
+
 func (p *PVECollector) collectSMART(ctx context.Context, nodeName string, disk *model.Disk) error {
 	return p.collectSMARTWithType(ctx, nodeName, disk, "")
 }
@@ -680,7 +907,10 @@ func (p *PVECollector) collectSMARTWithType(ctx context.Context, nodeName string
 	}
 
 	disk.Attributes = attrs
-	smart.EvaluateDisk(disk)
+	p.config.SMARTRules.EvaluateDisk(disk)
+	if smartData.Text != "" {
+		disk.SelfTestLog = smart.ParseSelfTestText(smartData.Text)
+	}
 
 	// Extract scalar metrics from parsed attributes.
 	for i := range disk.Attributes {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/darshan-rambhia/glint/internal/cache"
 	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/darshan-rambhia/glint/internal/smart"
 	"github.com/darshan-rambhia/glint/internal/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -485,6 +486,8 @@ func TestPVE_collectDisks(t *testing.T) {
 	assert.Equal(t, 32, *ssd.Temperature)
 	require.NotNil(t, ssd.PowerOnHours)
 	assert.Equal(t, 12345, *ssd.PowerOnHours)
+	require.NotNil(t, ssd.Risk)
+	assert.GreaterOrEqual(t, ssd.Risk.AnnualFailurePct, 1.5)
 
 	// NVMe disk
 	nvme := disks[1]
@@ -516,6 +519,67 @@ func TestPVE_collectDisks_DevPathFallback(t *testing.T) {
 	assert.Equal(t, "/dev/sdb", disks[0].WWN) // DevPath used as identity
 }
 
+func TestPVE_collectDisks_Trends(t *testing.T) {
+	resp := `{"data": [{"devpath": "/dev/sdb", "model": "Test", "serial": "SER123", "wwn": "0x5000aaaa", "size": 100, "type": "hdd"}]}`
+	smartResp := `{"data": {"health": "PASSED", "type": "ata", "attributes": [
+		{"id": 5, "name": "Reallocated_Sector_Ct", "value": 100, "worst": 100, "threshold": 10, "raw": "8"}
+	]}}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/nodes/pve/disks/list" {
+			fmt.Fprint(w, resp)
+		} else {
+			fmt.Fprint(w, smartResp)
+		}
+	})
+	coll, _, s, _ := newTestPVECollector(t, handler)
+
+	// Two days ago the disk had no reallocated sectors.
+	require.NoError(t, s.InsertSMARTSnapshot(time.Now().Add(-48*time.Hour).Unix(), &model.Disk{
+		WWN: "0x5000aaaa", Health: "PASSED",
+		Attributes: []model.SMARTAttribute{{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: 0}},
+	}))
+
+	disks, err := coll.collectDisks(context.Background(), "pve")
+	require.NoError(t, err)
+	require.Len(t, disks, 1)
+	disk := disks[0]
+	assert.NotZero(t, disk.Status&model.StatusDegrading)
+	require.Len(t, disk.Trends, 1)
+	assert.Nil(t, disk.Trends[0].Delta24h)
+	require.NotNil(t, disk.Trends[0].Delta7d)
+	assert.Equal(t, int64(8), *disk.Trends[0].Delta7d)
+}
+
+func TestPVE_collectDisks_Endurance(t *testing.T) {
+	resp := `{"data": [{"devpath": "/dev/nvme0n1", "model": "Consumer NVMe", "serial": "S1", "wwn": "eui.0001", "size": 100, "type": "nvme"}]}`
+	smartResp := `{"data": {"health": "PASSED", "type": "nvme", "wearout": "80", "text": "Percentage Used: 20%\nData Units Written: 400,000,000 [204 TB]\nPower On Hours: 8,000"}}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/nodes/pve/disks/list" {
+			fmt.Fprint(w, resp)
+		} else {
+			fmt.Fprint(w, smartResp)
+		}
+	})
+	coll, _, s, _ := newTestPVECollector(t, handler)
+
+	// Ten days ago: 2 TB less written.
+	require.NoError(t, s.InsertSMARTSnapshot(time.Now().Add(-10*24*time.Hour).Unix(), &model.Disk{
+		WWN: "eui.0001", Health: "PASSED", Wearout: new(80),
+		Attributes: []model.SMARTAttribute{{ID: smart.NVMeDataUnitsWritten, RawValue: 396_093_750}},
+	}))
+
+	disks, err := coll.collectDisks(context.Background(), "pve")
+	require.NoError(t, err)
+	require.Len(t, disks, 1)
+	e := disks[0].Endurance
+	require.NotNil(t, e)
+	assert.Equal(t, 20, e.WearUsedPct)
+	assert.Equal(t, "writes", e.Basis)
+	require.NotNil(t, e.WriteTBPerDay)
+	assert.InDelta(t, 0.2, *e.WriteTBPerDay, 0.001)
+	assert.NotNil(t, e.EndOfLife)
+}
+
 func TestPVE_collectDisks_FallsBackToSerial(t *testing.T) {
 	resp := `{"data": [{"devpath": "/dev/sdb", "model": "Test", "serial": "SER123", "wwn": "", "size": 100, "type": "hdd"}]}`
 	smartResp := `{"data": {"health": "PASSED", "type": "ata", "attributes": []}}`
@@ -534,6 +598,84 @@ func TestPVE_collectDisks_FallsBackToSerial(t *testing.T) {
 	assert.Equal(t, "SER123", disks[0].WWN)
 }
 
+func TestPVE_collectDisks_SelfTestLogFromText(t *testing.T) {
+	resp := `{"data": [{"devpath": "/dev/nvme0n1", "model": "NVMe", "serial": "S1", "wwn": "eui.0001", "size": 100, "type": "nvme"}]}`
+	smartResp := `{"data": {"health": "PASSED", "type": "nvme", "text": "Power On Hours: 1,000\n\n` +
+		`Self-test Log (NVMe Log 0x06)\nSelf-test status: No self-test in progress\n` +
+		`Num  Test_Description  Result                       Power_on_Hours  Failing_LBA  NSID Seg SCT Code\n` +
+		` 0   Extended          Completed without error                 976             -     -   -   -    -\n"}}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/nodes/pve/disks/list" {
+			fmt.Fprint(w, resp)
+		} else {
+			fmt.Fprint(w, smartResp)
+		}
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	disks, err := coll.collectDisks(context.Background(), "pve")
+	require.NoError(t, err)
+	require.Len(t, disks, 1)
+	log := disks[0].SelfTestLog
+	require.NotNil(t, log)
+	require.Len(t, log.Tests, 1)
+	assert.Equal(t, "long", log.Tests[0].Type)
+	require.NotNil(t, log.Tests[0].Time)
+	assert.Equal(t, disks[0].LastSeen.Add(-24*time.Hour), *log.Tests[0].Time)
+}
+
+type fakeSMARTReader struct {
+	node    string
+	reports map[string]*smart.Report
+	asked   []string
+}
+
+func (f *fakeSMARTReader) Node(context.Context) (string, error) {
+	return f.node, nil
+}
+
+func (f *fakeSMARTReader) Reports(_ context.Context, devPaths []string) (map[string]*smart.Report, error) {
+	f.asked = devPaths
+	return f.reports, nil
+}
+
+func TestPVE_collectDisks_SelfTestLogOverSSH(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api2/json/nodes/pve/disks/list":
+			fmt.Fprint(w, diskListJSON)
+		case r.URL.Query().Get("disk") == "/dev/sda":
+			fmt.Fprint(w, smartSDAJSON)
+		default:
+			fmt.Fprint(w, smartNVMeJSON)
+		}
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+	failed := &model.SelfTestLog{Tests: []model.SelfTest{{Type: "long", Result: "failed", LifetimeHours: 12300}}}
+	fake := &fakeSMARTReader{node: "pve", reports: map[string]*smart.Report{
+		"/dev/sda": {Serial: "S6PPNX0T123456", SelfTestLog: failed},
+		// Same path on the SSH host, but a different disk.
+		"/dev/nvme0n1": {Serial: "OTHER", SelfTestLog: &model.SelfTestLog{}},
+	}}
+	coll.smartShell = fake
+
+	disks, err := coll.collectDisks(context.Background(), "pve")
+	require.NoError(t, err)
+	require.Len(t, disks, 2)
+	assert.Equal(t, []string{"/dev/sda", "/dev/nvme0n1"}, fake.asked)
+	require.NotNil(t, disks[0].SelfTestLog)
+	assert.Equal(t, "failed", disks[0].SelfTestLog.Latest().Result)
+	assert.NotNil(t, disks[0].SelfTestLog.Tests[0].Time, "dated from power-on hours")
+	assert.Nil(t, disks[1].SelfTestLog, "serial mismatch")
+
+	// Disks of other nodes are not read from the SSH host
+	fake.node, fake.asked = "pve2", nil
+	disks, err = coll.collectDisks(context.Background(), "pve")
+	require.NoError(t, err)
+	assert.Nil(t, fake.asked)
+	assert.Nil(t, disks[0].SelfTestLog)
+}
+
 // ---------------------------------------------------------------------------
 // Full Collect cycle
 // ---------------------------------------------------------------------------
@@ -653,6 +795,89 @@ func TestPVE_Collect_SkipsDiskPollWhenNotDue(t *testing.T) {
 	assert.False(t, diskListCalled, "disk list should not be called when not due")
 }
 
+func TestPVE_Collect_DiskInventory(t *testing.T) {
+	diskList := diskListJSON
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc", "/api2/json/nodes/pve/qemu":
+			fmt.Fprint(w, qemuListJSON)
+		case "/api2/json/nodes/pve/disks/list":
+			fmt.Fprint(w, diskList)
+		case "/api2/json/nodes/pve/disks/smart":
+			fmt.Fprint(w, smartSDAJSON)
+		default:
+			http.Error(w, "not found", 404)
+		}
+	})
+	coll, ch, s, _ := newTestPVECollector(t, handler)
+	coll.inventory.missingPolls = 1
+
+	require.NoError(t, coll.Collect(context.Background()))
+	events, err := s.QueryDiskEvents(0)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+	require.Len(t, ch.Snapshot().Inventory["test-pve"], 2)
+
+	// The NVMe drive drops out of the disk list
+	diskList = `{"data": [{"devpath": "/dev/sda", "model": "Samsung SSD 870 EVO", "serial": "S6PPNX0T123456",
+		"wwn": "0x5002538f4321abcd", "size": 1000204886016, "type": "ssd"}]}`
+	coll.lastDiskPoll = time.Time{}
+	require.NoError(t, coll.Collect(context.Background()))
+
+	events, err = s.QueryDiskEvents(0)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, model.DiskEventRemoved, events[0].Type)
+	assert.Equal(t, "eui.002538b331234567", events[0].WWN)
+
+	snap := ch.Snapshot()
+	assert.NotContains(t, snap.Disks, "eui.002538b331234567")
+	assert.Contains(t, snap.Disks, "0x5002538f4321abcd")
+
+	// A restarted collector loads the removal from the store
+	fresh := NewPVECollector(coll.config, coll.pool, cache.New(), s)
+	require.NoError(t, fresh.Collect(context.Background()))
+	events, err = s.QueryDiskEvents(0)
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+	inv, err := s.QueryDiskInventory("test-pve")
+	require.NoError(t, err)
+	require.Len(t, inv, 2)
+	assert.Equal(t, "/dev/nvme0n1", inv[0].DevPath)
+	require.NotNil(t, inv[0].RemovedAt)
+	assert.True(t, inv[1].Present())
+}
+
+func TestPVE_Collect_DiskInventoryReloadsAfterFailedWrite(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc", "/api2/json/nodes/pve/qemu":
+			fmt.Fprint(w, `{"data": []}`)
+		case "/api2/json/nodes/pve/disks/list":
+			fmt.Fprint(w, diskListJSON)
+		default:
+			http.Error(w, "not found", 404)
+		}
+	})
+	coll, _, s, _ := newTestPVECollector(t, handler)
+	require.NoError(t, coll.Collect(context.Background()))
+	require.NotNil(t, coll.inventory.disks)
+
+	// The batch carrying this poll's inventory changes is not stored.
+	require.NoError(t, s.Close())
+	coll.lastDiskPoll = time.Time{}
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Nil(t, coll.inventory.disks, "inventory is reloaded from the store next poll")
+}
+
 // ---------------------------------------------------------------------------
 // Name and Interval
 // ---------------------------------------------------------------------------
@@ -797,6 +1022,7 @@ func TestPVE_collectDisks_SMARTFails_SetsInternalError(t *testing.T) {
 	require.NoError(t, err) // collectDisks itself doesn't fail
 	require.Len(t, disks, 1)
 	assert.Equal(t, 16, disks[0].Status) // StatusInternalError
+	assert.Nil(t, disks[0].Risk)
 }
 
 func TestPVE_detectCluster_APIError(t *testing.T) {
@@ -927,6 +1153,25 @@ func TestPVE_collectSMART_NullWearout(t *testing.T) {
 	assert.Nil(t, disk.Wearout)
 }
 
+func TestPVE_collectSMART_Rules(t *testing.T) {
+	resp := `{"data": {"health": "PASSED", "type": "ata", "attributes": [
+		{"id": 1, "name": "Raw_Read_Error_Rate", "value": 83, "worst": 64, "thresh": 44, "raw": "215863568"},
+		{"id": 5, "name": "Reallocated_Sector_Ct", "value": 100, "worst": 100, "thresh": 10, "raw": "24"}
+	]}}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, resp)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	disk := &model.Disk{DevPath: "/dev/sda", Protocol: "ata", Model: "ST8000VN004-2M2101"}
+	require.NoError(t, coll.collectSMART(context.Background(), "pve", disk))
+	assert.Equal(t, model.StatusFailedScrutiny, disk.Status, "packed read errors do not warn")
+
+	coll.config.SMARTRules = &smart.Rules{Attributes: map[int]smart.AttrRule{5: {Ignore: new(true)}}}
+	require.NoError(t, coll.collectSMART(context.Background(), "pve", disk))
+	assert.Equal(t, model.StatusPassed, disk.Status)
+}
+
 func TestPVE_collectGuests_BothFail(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
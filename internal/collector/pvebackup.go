@@ -0,0 +1,371 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// pveBackupPollInterval is how often backup jobs and vzdump task history are
+// refreshed. Jobs rarely change and tasks run at most a few times per day.
+const pveBackupPollInterval = 5 * time.Minute
+
+// vzdumpLogMaxAge bounds which multi-guest runs have their task log read.
+// Older runs are skipped; any guest backed up since has a newer result anyway.
+const vzdumpLogMaxAge = 31 * 24 * time.Hour
+
+// vzdumpLogLimit is the number of task log lines requested. PVE returns only
+// 50 by default, which truncates the log of a job covering many guests.
+const vzdumpLogLimit = 100000
+
+// vzdumpTask is a single vzdump run from the node task list. ID is the guest
+// VMID when the run backed up a single guest, and empty for multi-guest job
+// runs. Guests holds the per-guest outcome of a multi-guest run, read from its
+// task log; it is nil when the log has not been resolved.
+type vzdumpTask struct {
+	UPID      string `json:"upid"`
+	Node      string `json:"node"`
+	ID        string `json:"id"`
+	StartTime int64  `json:"starttime"`
+	EndTime   int64  `json:"endtime"`
+	Status    string `json:"status"`
+
+	Guests map[int]bool `json:"-"`
+}
+
+var (
+	vzdumpStartedRe  = regexp.MustCompile(`Starting Backup of VM (\d+)\b`)
+	vzdumpFinishedRe = regexp.MustCompile(`Finished Backup of VM (\d+)\b`)
+	vzdumpFailedRe   = regexp.MustCompile(`Backup of VM (\d+) failed\b`)
+)
+
+// collectBackupJobs fetches vzdump jobs from /cluster/backup and resolves pool
+// members for pool-based jobs. Pool lookups are best-effort.
+func (p *PVECollector) collectBackupJobs(ctx context.Context) ([]*model.PVEBackupJob, error) {
+	body, err := p.apiGet(ctx, "collectBackupJobs", "/api2/json/cluster/backup")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing backup jobs response: %w", err)
+	}
+
+	jobs, err := parseBackupJobs(p.config.Name, resp.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		if job.Pool == "" {
+			continue
+		}
+		members, err := p.collectPoolMembers(ctx, job.Pool)
+		if err != nil {
+			slog.Warn("resolving backup job pool", "instance", p.config.Name, "job", job.ID, "pool", job.Pool, "error", err)
+			continue
+		}
+		job.PoolMembers = members
+	}
+	return jobs, nil
+}
+
+func (p *PVECollector) collectPoolMembers(ctx context.Context, pool string) ([]int, error) {
+	body, err := p.apiGet(ctx, "collectPoolMembers", "/api2/json/pools/"+url.PathEscape(pool))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing pool response: %w", err)
+	}
+
+	var raw struct {
+		Members []struct {
+			VMID int `json:"vmid"`
+		} `json:"members"`
+	}
+	if err := json.Unmarshal(resp.Data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing pool members: %w", err)
+	}
+
+	var members []int
+	for _, m := range raw.Members {
+		if m.VMID > 0 { // storage members have no vmid
+			members = append(members, m.VMID)
+		}
+	}
+	return members, nil
+}
+
+// collectVzdumpTasks fetches recent vzdump task history for a node.
+func (p *PVECollector) collectVzdumpTasks(ctx context.Context, nodeName string) ([]vzdumpTask, error) {
+	body, err := p.apiGet(ctx, "collectVzdumpTasks", fmt.Sprintf("/api2/json/nodes/%s/tasks?typefilter=vzdump&limit=500", nodeName))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing vzdump tasks response: %w", err)
+	}
+
+	var tasks []vzdumpTask
+	if err := json.Unmarshal(resp.Data, &tasks); err != nil {
+		return nil, fmt.Errorf("parsing vzdump tasks: %w", err)
+	}
+	for i := range tasks {
+		if tasks[i].Node == "" {
+			tasks[i].Node = nodeName
+		}
+	}
+	return tasks, nil
+}
+
+// resolveVzdumpGuests fills in the per-guest outcome of finished multi-guest
+// runs from their task logs. Logs of finished tasks never change, so parsed
+// results are cached by UPID until the task drops out of the task list.
+// Runs whose log cannot be read are left unresolved and ignored.
+func (p *PVECollector) resolveVzdumpGuests(ctx context.Context, tasks []vzdumpTask, now time.Time) {
+	cutoff := now.Add(-vzdumpLogMaxAge).Unix()
+	seen := make(map[string]bool, len(tasks))
+	for i := range tasks {
+		t := &tasks[i]
+		if t.ID != "" || t.Status == "" || t.EndTime == 0 {
+			continue
+		}
+		seen[t.UPID] = true
+		if guests, ok := p.vzdumpGuests[t.UPID]; ok {
+			t.Guests = guests
+			continue
+		}
+		if t.EndTime < cutoff {
+			continue
+		}
+		guests, err := p.collectVzdumpLog(ctx, t.Node, t.UPID)
+		if err != nil {
+			slog.Debug("reading vzdump task log", "instance", p.config.Name, "node", t.Node, "upid", t.UPID, "error", err)
+			continue
+		}
+		if p.vzdumpGuests == nil {
+			p.vzdumpGuests = make(map[string]map[int]bool)
+		}
+		p.vzdumpGuests[t.UPID] = guests
+		t.Guests = guests
+	}
+	for upid := range p.vzdumpGuests {
+		if !seen[upid] {
+			delete(p.vzdumpGuests, upid)
+		}
+	}
+}
+
+// collectVzdumpLog fetches a vzdump task log and parses the guests it covered.
+func (p *PVECollector) collectVzdumpLog(ctx context.Context, nodeName, upid string) (map[int]bool, error) {
+	path := fmt.Sprintf("/api2/json/nodes/%s/tasks/%s/log?limit=%d", nodeName, url.PathEscape(upid), vzdumpLogLimit)
+	body, err := p.apiGet(ctx, "collectVzdumpLog", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing task log response: %w", err)
+	}
+
+	var lines []struct {
+		T string `json:"t"`
+	}
+	if err := json.Unmarshal(resp.Data, &lines); err != nil {
+		return nil, fmt.Errorf("parsing task log: %w", err)
+	}
+	text := make([]string, len(lines))
+	for i, l := range lines {
+		text[i] = l.T
+	}
+	return parseVzdumpLog(text), nil
+}
+
+// parseVzdumpLog maps each guest named in a vzdump log to whether its backup
+// finished. A guest that was started but never finished counts as failed,
+// which covers runs that were aborted midway.
+func parseVzdumpLog(lines []string) map[int]bool {
+	guests := make(map[int]bool)
+	mark := func(re *regexp.Regexp, line string, ok bool) bool {
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			return false
+		}
+		vmid, err := strconv.Atoi(m[1])
+		if err != nil {
+			return false
+		}
+		guests[vmid] = ok
+		return true
+	}
+	for _, line := range lines {
+		_ = mark(vzdumpStartedRe, line, false) ||
+			mark(vzdumpFinishedRe, line, true) ||
+			mark(vzdumpFailedRe, line, false)
+	}
+	return guests
+}
+
+func parseBackupJobs(instance string, data json.RawMessage) ([]*model.PVEBackupJob, error) {
+	var raw []struct {
+		ID       string          `json:"id"`
+		Type     string          `json:"type"`
+		Enabled  *int            `json:"enabled"`
+		Schedule string          `json:"schedule"`
+		Storage  string          `json:"storage"`
+		Mode     string          `json:"mode"`
+		All      int             `json:"all"`
+		VMID     json.RawMessage `json:"vmid"`
+		Exclude  json.RawMessage `json:"exclude"`
+		Pool     string          `json:"pool"`
+		Node     string          `json:"node"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing backup jobs: %w", err)
+	}
+
+	jobs := make([]*model.PVEBackupJob, 0, len(raw))
+	for _, r := range raw {
+		if r.Type != "" && r.Type != "vzdump" {
+			continue
+		}
+		jobs = append(jobs, &model.PVEBackupJob{
+			Instance: instance,
+			ID:       r.ID,
+			Enabled:  r.Enabled == nil || *r.Enabled != 0, // PVE omits "enabled" when true
+			Schedule: r.Schedule,
+			Storage:  r.Storage,
+			Mode:     r.Mode,
+			All:      r.All == 1,
+			VMIDs:    parseVMIDList(r.VMID),
+			Exclude:  parseVMIDList(r.Exclude),
+			Pool:     r.Pool,
+			Node:     r.Node,
+		})
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs, nil
+}
+
+// parseVMIDList parses a PVE VMID list, which is a comma-separated string
+// ("100,101") but may also arrive as a bare number.
+func parseVMIDList(raw json.RawMessage) []int {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var n int
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil
+		}
+		return []int{n}
+	}
+	var ids []int
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		if id, err := strconv.Atoi(part); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// jobCovers reports whether an enabled job includes the given guest.
+func jobCovers(job *model.PVEBackupJob, g *model.Guest) bool {
+	if !job.Enabled {
+		return false
+	}
+	if job.Node != "" && job.Node != g.Node {
+		return false
+	}
+	switch {
+	case job.All:
+		return !slices.Contains(job.Exclude, g.VMID)
+	case job.Pool != "":
+		return slices.Contains(job.PoolMembers, g.VMID)
+	default:
+		return slices.Contains(job.VMIDs, g.VMID)
+	}
+}
+
+// vzdumpTaskSucceeded treats warnings as success: the archive was written.
+func vzdumpTaskSucceeded(status string) bool {
+	return status == "OK" || strings.HasPrefix(status, "WARNINGS")
+}
+
+// buildGuestBackups combines jobs and vzdump task history into a per-guest
+// backup state. Tasks that name a VMID map directly to that guest; job runs
+// without an ID are attributed to the guests named in their task log, and
+// skipped when the log was not resolved.
+func buildGuestBackups(instance, clusterID string, guests map[int]*model.Guest, jobs []*model.PVEBackupJob, tasks []vzdumpTask) map[int]*model.PVEGuestBackup {
+	result := make(map[int]*model.PVEGuestBackup, len(guests))
+	for vmid, g := range guests {
+		gb := &model.PVEGuestBackup{Instance: instance, ClusterID: clusterID, VMID: vmid}
+		for _, job := range jobs {
+			if jobCovers(job, g) {
+				gb.Jobs = append(gb.Jobs, job.ID)
+			}
+		}
+		result[vmid] = gb
+	}
+
+	// Oldest first so that LastStatus ends up as the most recent result.
+	sorted := slices.Clone(tasks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime < sorted[j].StartTime })
+
+	apply := func(gb *model.PVEGuestBackup, endTime int64, ok bool, status string) {
+		if ok {
+			gb.LastSuccess = max(gb.LastSuccess, endTime)
+		} else {
+			gb.LastFailure = max(gb.LastFailure, endTime)
+		}
+		gb.LastStatus = status
+	}
+
+	for _, t := range sorted {
+		if t.Status == "" || t.EndTime == 0 {
+			continue // still running
+		}
+		if t.ID != "" {
+			if vmid, err := strconv.Atoi(t.ID); err == nil {
+				if gb, ok := result[vmid]; ok {
+					apply(gb, t.EndTime, vzdumpTaskSucceeded(t.Status), t.Status)
+				}
+			}
+			continue
+		}
+		for vmid, ok := range t.Guests {
+			gb, found := result[vmid]
+			if !found {
+				continue
+			}
+			switch {
+			case ok:
+				apply(gb, t.EndTime, true, "OK")
+			case vzdumpTaskSucceeded(t.Status):
+				// The run as a whole succeeded but this guest did not finish.
+				apply(gb, t.EndTime, false, "ERROR")
+			default:
+				apply(gb, t.EndTime, false, t.Status)
+			}
+		}
+	}
+	return result
+}
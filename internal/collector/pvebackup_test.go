@@ -0,0 +1,409 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Test fixtures — realistic PVE backup job / vzdump task JSON
+// ---------------------------------------------------------------------------
+
+const backupJobsJSON = `{
+	"data": [
+		{"id": "backup-nightly", "type": "vzdump", "schedule": "21:00", "storage": "nfs-backup", "mode": "snapshot", "all": 1, "exclude": "999"},
+		{"id": "backup-db", "type": "vzdump", "enabled": 1, "schedule": "*/6:00", "storage": "local", "mode": "snapshot", "vmid": "101,104"},
+		{"id": "backup-prod", "type": "vzdump", "enabled": 1, "schedule": "sun 02:00", "storage": "local", "pool": "prod"},
+		{"id": "backup-old", "type": "vzdump", "enabled": 0, "schedule": "mon 01:00", "storage": "local", "vmid": "102"},
+		{"id": "backup-pve2", "type": "vzdump", "schedule": "22:00", "storage": "local", "all": 1, "node": "pve2"}
+	]
+}`
+
+const poolProdJSON = `{
+	"data": {
+		"members": [
+			{"id": "qemu/100", "type": "qemu", "vmid": 100, "node": "pve"},
+			{"id": "storage/pve/local", "type": "storage", "node": "pve"}
+		]
+	}
+}`
+
+const vzdumpTasksJSON = `{
+	"data": [
+		{"upid": "UPID:pve:0001:vzdump::root@pam:", "node": "pve", "type": "vzdump", "id": "", "starttime": 1700000000, "endtime": 1700000600, "status": "OK", "user": "root@pam"},
+		{"upid": "UPID:pve:0002:vzdump:101:root@pam:", "node": "pve", "type": "vzdump", "id": "101", "starttime": 1700100000, "endtime": 1700100100, "status": "job errors", "user": "root@pam"},
+		{"upid": "UPID:pve:0003:vzdump::root@pam:", "node": "pve", "type": "vzdump", "id": "", "starttime": 1700200000, "status": "", "user": "root@pam"}
+	]
+}`
+
+// vzdumpLogJSON is the task log of the multi-guest run UPID:pve:0001: 100
+// finished and 101 failed, while the task itself still reports OK.
+const vzdumpLogJSON = `{
+	"total": 9,
+	"data": [
+		{"n": 1, "t": "INFO: starting new backup job: vzdump --all 1 --exclude 999 --mode snapshot --storage nfs-backup"},
+		{"n": 2, "t": "INFO: Starting Backup of VM 100 (lxc)"},
+		{"n": 3, "t": "INFO: Backup started at 2023-11-14 22:13:20"},
+		{"n": 4, "t": "INFO: creating vzdump archive '/mnt/pve/nfs-backup/dump/vzdump-lxc-100-2023_11_14-22_13_20.tar.zst'"},
+		{"n": 5, "t": "INFO: Finished Backup of VM 100 (00:04:10)"},
+		{"n": 6, "t": "INFO: Starting Backup of VM 101 (qemu)"},
+		{"n": 7, "t": "ERROR: Backup of VM 101 failed - unable to create temporary directory '/mnt/pve/nfs-backup/dump/vzdump-qemu-101.tmp'"},
+		{"n": 8, "t": "INFO: Failed at 2023-11-14 22:23:20"},
+		{"n": 9, "t": "INFO: Backup job finished with errors"}
+	]
+}`
+
+func testGuests() map[int]*model.Guest {
+	return map[int]*model.Guest{
+		100: {VMID: 100, Node: "pve", Name: "web"},
+		101: {VMID: 101, Node: "pve", Name: "db"},
+		102: {VMID: 102, Node: "pve", Name: "scratch"},
+		999: {VMID: 999, Node: "pve", Name: "template"},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Parsers
+// ---------------------------------------------------------------------------
+
+func TestParseBackupJobs(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(backupJobsJSON), &resp))
+
+	jobs, err := parseBackupJobs("test-pve", resp.Data)
+	require.NoError(t, err)
+	require.Len(t, jobs, 5)
+
+	// Sorted by ID
+	assert.Equal(t, "backup-db", jobs[0].ID)
+	assert.Equal(t, []int{101, 104}, jobs[0].VMIDs)
+	assert.True(t, jobs[0].Enabled)
+
+	assert.Equal(t, "backup-nightly", jobs[1].ID)
+	assert.True(t, jobs[1].All)
+	assert.True(t, jobs[1].Enabled, "enabled defaults to true when omitted")
+	assert.Equal(t, []int{999}, jobs[1].Exclude)
+	assert.Equal(t, "nfs-backup", jobs[1].Storage)
+	assert.Equal(t, "test-pve", jobs[1].Instance)
+
+	assert.Equal(t, "backup-old", jobs[2].ID)
+	assert.False(t, jobs[2].Enabled)
+
+	assert.Equal(t, "backup-prod", jobs[3].ID)
+	assert.Equal(t, "prod", jobs[3].Pool)
+
+	assert.Equal(t, "pve2", jobs[4].Node)
+}
+
+func TestParseBackupJobs_InvalidJSON(t *testing.T) {
+	_, err := parseBackupJobs("test-pve", json.RawMessage(`{}`))
+	assert.Error(t, err)
+}
+
+func TestParseVMIDList(t *testing.T) {
+	assert.Equal(t, []int{100, 101}, parseVMIDList(json.RawMessage(`"100,101"`)))
+	assert.Equal(t, []int{100, 101}, parseVMIDList(json.RawMessage(`"100, 101"`)))
+	assert.Equal(t, []int{100}, parseVMIDList(json.RawMessage(`100`)))
+	assert.Nil(t, parseVMIDList(nil))
+	assert.Nil(t, parseVMIDList(json.RawMessage(`""`)))
+	assert.Nil(t, parseVMIDList(json.RawMessage(`[1]`)))
+}
+
+func TestJobCovers(t *testing.T) {
+	g := &model.Guest{VMID: 100, Node: "pve"}
+
+	assert.True(t, jobCovers(&model.PVEBackupJob{Enabled: true, All: true}, g))
+	assert.False(t, jobCovers(&model.PVEBackupJob{Enabled: true, All: true, Exclude: []int{100}}, g))
+	assert.False(t, jobCovers(&model.PVEBackupJob{Enabled: false, All: true}, g))
+	assert.False(t, jobCovers(&model.PVEBackupJob{Enabled: true, All: true, Node: "pve2"}, g))
+	assert.True(t, jobCovers(&model.PVEBackupJob{Enabled: true, VMIDs: []int{100}}, g))
+	assert.False(t, jobCovers(&model.PVEBackupJob{Enabled: true, VMIDs: []int{101}}, g))
+	assert.True(t, jobCovers(&model.PVEBackupJob{Enabled: true, Pool: "prod", PoolMembers: []int{100}}, g))
+	assert.False(t, jobCovers(&model.PVEBackupJob{Enabled: true, Pool: "prod"}, g))
+}
+
+func TestVzdumpTaskSucceeded(t *testing.T) {
+	assert.True(t, vzdumpTaskSucceeded("OK"))
+	assert.True(t, vzdumpTaskSucceeded("WARNINGS: 1"))
+	assert.False(t, vzdumpTaskSucceeded("job errors"))
+	assert.False(t, vzdumpTaskSucceeded("unexpected status"))
+}
+
+func TestBuildGuestBackups(t *testing.T) {
+	jobs := []*model.PVEBackupJob{
+		{ID: "nightly", Enabled: true, All: true, Exclude: []int{999}},
+		{ID: "db", Enabled: true, VMIDs: []int{101}},
+		{ID: "old", Enabled: false, VMIDs: []int{102}},
+	}
+	// 102 is excluded only because "old" is disabled; cover nightly excludes 999.
+	jobs[0].Exclude = []int{102, 999}
+
+	tasks := []vzdumpTask{
+		// Job run without ID → only the guests named in its log (100, 101)
+		{Node: "pve", ID: "", StartTime: 1000, EndTime: 1100, Status: "OK", Guests: map[int]bool{100: true, 101: true}},
+		// Job run whose log was not resolved is skipped
+		{Node: "pve", ID: "", StartTime: 4000, EndTime: 4100, Status: "OK"},
+		// Job run in which 100 failed although the run reported OK
+		{Node: "pve", ID: "", StartTime: 5000, EndTime: 5100, Status: "OK", Guests: map[int]bool{100: false}},
+		// Single-guest failure after the job run
+		{Node: "pve", ID: "101", StartTime: 2000, EndTime: 2100, Status: "job errors"},
+		// Running task is ignored
+		{Node: "pve", ID: "100", StartTime: 3000, Status: ""},
+		// Unknown guest is ignored
+		{Node: "pve", ID: "555", StartTime: 1500, EndTime: 1600, Status: "OK"},
+	}
+
+	result := buildGuestBackups("test-pve", "test-pve", testGuests(), jobs, tasks)
+	require.Len(t, result, 4)
+
+	assert.Equal(t, []string{"nightly"}, result[100].Jobs)
+	assert.Equal(t, int64(1100), result[100].LastSuccess)
+	assert.Equal(t, int64(5100), result[100].LastFailure)
+	assert.Equal(t, "ERROR", result[100].LastStatus)
+
+	assert.Equal(t, []string{"nightly", "db"}, result[101].Jobs)
+	assert.Equal(t, int64(1100), result[101].LastSuccess)
+	assert.Equal(t, int64(2100), result[101].LastFailure)
+	assert.Equal(t, "job errors", result[101].LastStatus)
+
+	// Guests on the node that a job run did not name receive nothing from it
+	assert.Empty(t, result[102].Jobs)
+	assert.Zero(t, result[102].LastSuccess)
+	assert.Zero(t, result[102].LastFailure)
+	assert.Empty(t, result[999].Jobs)
+	assert.Equal(t, "test-pve", result[999].ClusterID)
+}
+
+func TestParseVzdumpLog(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(vzdumpLogJSON), &resp))
+	var lines []struct {
+		T string `json:"t"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &lines))
+	text := make([]string, len(lines))
+	for i, l := range lines {
+		text[i] = l.T
+	}
+	assert.Equal(t, map[int]bool{100: true, 101: false}, parseVzdumpLog(text))
+
+	// A guest started but never finished (aborted run) counts as failed
+	assert.Equal(t, map[int]bool{102: false}, parseVzdumpLog([]string{
+		"INFO: Starting Backup of VM 102 (qemu)",
+		"INFO: received signal - terminate process",
+	}))
+	assert.Empty(t, parseVzdumpLog([]string{"INFO: starting new backup job"}))
+}
+
+// ---------------------------------------------------------------------------
+// API calls
+// ---------------------------------------------------------------------------
+
+func pveBackupHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/cluster/backup":
+			fmt.Fprint(w, backupJobsJSON)
+		case "/api2/json/pools/prod":
+			fmt.Fprint(w, poolProdJSON)
+		case "/api2/json/nodes/pve/tasks":
+			if r.URL.Query().Get("typefilter") != "vzdump" {
+				http.Error(w, "missing typefilter", 400)
+				return
+			}
+			fmt.Fprint(w, vzdumpTasksJSON)
+		case "/api2/json/nodes/pve/tasks/UPID:pve:0001:vzdump::root@pam:/log":
+			fmt.Fprint(w, vzdumpLogJSON)
+		default:
+			http.Error(w, "not found", 404)
+		}
+	}
+}
+
+func TestPVE_collectBackupJobs_ResolvesPools(t *testing.T) {
+	coll, _, _, _ := newTestPVECollector(t, pveBackupHandler())
+
+	jobs, err := coll.collectBackupJobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 5)
+	assert.Equal(t, []int{100}, jobs[3].PoolMembers)
+}
+
+func TestPVE_collectBackupJobs_PoolLookupFails(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/cluster/backup" {
+			fmt.Fprint(w, backupJobsJSON)
+			return
+		}
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	jobs, err := coll.collectBackupJobs(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, jobs[3].PoolMembers)
+}
+
+func TestPVE_collectBackupJobs_Errors(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+	_, err := coll.collectBackupJobs(context.Background())
+	assert.Error(t, err)
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `not json`)
+	})
+	coll, _, _, _ = newTestPVECollector(t, handler)
+	_, err = coll.collectBackupJobs(context.Background())
+	assert.ErrorContains(t, err, "parsing backup jobs response")
+}
+
+func TestPVE_collectPoolMembers_InvalidData(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data": []}`)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	_, err := coll.collectPoolMembers(context.Background(), "prod")
+	assert.ErrorContains(t, err, "parsing pool members")
+}
+
+func TestPVE_collectVzdumpTasks(t *testing.T) {
+	coll, _, _, _ := newTestPVECollector(t, pveBackupHandler())
+
+	tasks, err := coll.collectVzdumpTasks(context.Background(), "pve")
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "101", tasks[1].ID)
+	assert.Equal(t, "job errors", tasks[1].Status)
+	assert.Equal(t, int64(1700000600), tasks[0].EndTime)
+}
+
+func TestPVE_collectVzdumpTasks_Errors(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data": {}}`)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+	_, err := coll.collectVzdumpTasks(context.Background(), "pve")
+	assert.ErrorContains(t, err, "parsing vzdump tasks")
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `not json`)
+	})
+	coll, _, _, _ = newTestPVECollector(t, handler)
+	_, err = coll.collectVzdumpTasks(context.Background(), "pve")
+	assert.ErrorContains(t, err, "parsing vzdump tasks response")
+}
+
+func TestPVE_resolveVzdumpGuests(t *testing.T) {
+	var logReads int
+	backups := pveBackupHandler()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/log") {
+			logReads++
+			assert.NotEmpty(t, r.URL.Query().Get("limit"))
+		}
+		backups(w, r)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+	now := time.Unix(1700300000, 0)
+
+	tasks, err := coll.collectVzdumpTasks(context.Background(), "pve")
+	require.NoError(t, err)
+	coll.resolveVzdumpGuests(context.Background(), tasks, now)
+	assert.Equal(t, map[int]bool{100: true, 101: false}, tasks[0].Guests)
+	assert.Nil(t, tasks[1].Guests, "single-guest task needs no log")
+	assert.Nil(t, tasks[2].Guests, "running task is not resolved")
+	assert.Equal(t, 1, logReads)
+
+	// Finished logs are cached by UPID
+	tasks, err = coll.collectVzdumpTasks(context.Background(), "pve")
+	require.NoError(t, err)
+	coll.resolveVzdumpGuests(context.Background(), tasks, now)
+	assert.Equal(t, map[int]bool{100: true, 101: false}, tasks[0].Guests)
+	assert.Equal(t, 1, logReads)
+
+	// Tasks that left the task list are evicted
+	coll.resolveVzdumpGuests(context.Background(), nil, now)
+	assert.Empty(t, coll.vzdumpGuests)
+}
+
+func TestPVE_resolveVzdumpGuests_Unresolved(t *testing.T) {
+	coll, _, _, _ := newTestPVECollector(t, pveBackupHandler())
+
+	// Runs older than vzdumpLogMaxAge are not read
+	tasks := []vzdumpTask{{UPID: "UPID:pve:0001:vzdump::root@pam:", Node: "pve", EndTime: 1700000600, Status: "OK"}}
+	coll.resolveVzdumpGuests(context.Background(), tasks, time.Unix(1700000600, 0).Add(vzdumpLogMaxAge+time.Hour))
+	assert.Nil(t, tasks[0].Guests)
+
+	// A log that cannot be read leaves the run unresolved
+	tasks = []vzdumpTask{{UPID: "UPID:pve:0009:vzdump::root@pam:", Node: "pve", EndTime: 1700000600, Status: "OK"}}
+	coll.resolveVzdumpGuests(context.Background(), tasks, time.Unix(1700000600, 0))
+	assert.Nil(t, tasks[0].Guests)
+	assert.Empty(t, coll.vzdumpGuests)
+}
+
+func TestPVE_Collect_PVEBackups(t *testing.T) {
+	backups := pveBackupHandler()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc":
+			fmt.Fprint(w, lxcListJSON)
+		case "/api2/json/nodes/pve/qemu", "/api2/json/nodes/pve/disks/list":
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			backups(w, r)
+		}
+	})
+	coll, ch, _, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+
+	snap := ch.Snapshot()
+	require.Len(t, snap.BackupJobs["test-pve"], 5)
+	require.Contains(t, snap.GuestBackups, "test-pve")
+	gb := snap.GuestBackups["test-pve"][101]
+	require.NotNil(t, gb)
+	assert.Contains(t, gb.Jobs, "backup-nightly")
+	assert.Contains(t, gb.Jobs, "backup-db")
+	assert.Equal(t, int64(1700100100), gb.LastFailure)
+	assert.False(t, coll.lastBackupPoll.IsZero())
+}
+
+func TestPVE_Collect_SkipsBackupPollWhenNotDue(t *testing.T) {
+	var jobsCalled bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/cluster/backup":
+			jobsCalled = true
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, ch, _, _ := newTestPVECollector(t, handler)
+	coll.lastBackupPoll = time.Now()
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.False(t, jobsCalled)
+	assert.Empty(t, ch.Snapshot().BackupJobs)
+}
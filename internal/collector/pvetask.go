@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// collectTasks fetches the recent cluster-wide task list from /cluster/tasks.
+// It covers migrations, snapshots, replication, guest start/stop and backups.
+func (p *PVECollector) collectTasks(ctx context.Context) ([]*model.PVETask, error) {
+	body, err := p.apiGet(ctx, "collectTasks", "/api2/json/cluster/tasks")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing tasks response: %w", err)
+	}
+	return parsePVETasks(p.config.Name, resp.Data)
+}
+
+func parsePVETasks(instance string, data json.RawMessage) ([]*model.PVETask, error) {
+	var raw []struct {
+		UPID      string `json:"upid"`
+		Node      string `json:"node"`
+		Type      string `json:"type"`
+		ID        string `json:"id"`
+		User      string `json:"user"`
+		StartTime int64  `json:"starttime"`
+		EndTime   *int64 `json:"endtime"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing tasks: %w", err)
+	}
+
+	tasks := make([]*model.PVETask, 0, len(raw))
+	for _, r := range raw {
+		if r.UPID == "" {
+			continue
+		}
+		t := &model.PVETask{
+			Instance:  instance,
+			UPID:      r.UPID,
+			Node:      r.Node,
+			Type:      r.Type,
+			ID:        r.ID,
+			User:      r.User,
+			StartTime: r.StartTime,
+			Status:    r.Status,
+		}
+		// Running tasks have no status; PVE may still report an endtime of 0.
+		if r.EndTime != nil && *r.EndTime > 0 && r.Status != "" {
+			t.EndTime = r.EndTime
+		} else {
+			t.Status = ""
+		}
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].StartTime > tasks[j].StartTime })
+	return tasks, nil
+}
@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Test fixtures — realistic PVE /cluster/tasks JSON
+// ---------------------------------------------------------------------------
+
+const clusterTasksJSON = `{
+	"data": [
+		{"upid": "UPID:pve:00001234:0A1B2C3D:6530A000:qmigrate:100:alice@pve:", "node": "pve", "pid": 4660, "pstart": 169553213, "starttime": 1700000000, "endtime": 1700000120, "type": "qmigrate", "id": "100", "user": "alice@pve", "status": "OK"},
+		{"upid": "UPID:pve2:00005678:0A1B2C3E:6530B000:vzsnapshot:101:root@pam:", "node": "pve2", "starttime": 1700001000, "endtime": 1700001005, "type": "vzsnapshot", "id": "101", "user": "root@pam", "status": "snapshot feature is not available"},
+		{"upid": "UPID:pve:00009ABC:0A1B2C3F:6530C000:qmstart:102:root@pam:", "node": "pve", "starttime": 1700002000, "type": "qmstart", "id": "102", "user": "root@pam"},
+		{"upid": "UPID:pve:0000DEF0:0A1B2C40:6530D000:vzdump::root@pam:", "node": "pve", "starttime": 1700003000, "endtime": 0, "type": "vzdump", "id": "", "user": "root@pam", "status": ""},
+		{"node": "pve", "starttime": 1700004000, "type": "broken"}
+	]
+}`
+
+// ---------------------------------------------------------------------------
+// Parsers
+// ---------------------------------------------------------------------------
+
+func TestParsePVETasks(t *testing.T) {
+	var resp pveResponse
+	require.NoError(t, json.Unmarshal([]byte(clusterTasksJSON), &resp))
+
+	tasks, err := parsePVETasks("test-pve", resp.Data)
+	require.NoError(t, err)
+	require.Len(t, tasks, 4, "entries without a UPID are skipped")
+
+	// Newest first
+	assert.Equal(t, "vzdump", tasks[0].Type)
+	assert.Nil(t, tasks[0].EndTime, "endtime 0 means still running")
+	assert.Empty(t, tasks[0].Status)
+
+	assert.Equal(t, "qmstart", tasks[1].Type)
+	assert.Nil(t, tasks[1].EndTime)
+
+	assert.Equal(t, "vzsnapshot", tasks[2].Type)
+	assert.Equal(t, "pve2", tasks[2].Node)
+	require.NotNil(t, tasks[2].EndTime)
+	assert.Equal(t, "snapshot feature is not available", tasks[2].Status)
+
+	assert.Equal(t, "qmigrate", tasks[3].Type)
+	assert.Equal(t, "100", tasks[3].ID)
+	assert.Equal(t, "alice@pve", tasks[3].User)
+	assert.Equal(t, "test-pve", tasks[3].Instance)
+	assert.Equal(t, int64(1700000120), *tasks[3].EndTime)
+}
+
+func TestParsePVETasks_InvalidJSON(t *testing.T) {
+	_, err := parsePVETasks("test-pve", json.RawMessage(`{}`))
+	assert.ErrorContains(t, err, "parsing tasks")
+}
+
+// ---------------------------------------------------------------------------
+// collectTasks
+// ---------------------------------------------------------------------------
+
+func TestPVE_collectTasks(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/cluster/tasks" {
+			fmt.Fprint(w, clusterTasksJSON)
+			return
+		}
+		http.Error(w, "not found", 404)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+
+	tasks, err := coll.collectTasks(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tasks, 4)
+}
+
+func TestPVE_collectTasks_Errors(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", 403)
+	})
+	coll, _, _, _ := newTestPVECollector(t, handler)
+	_, err := coll.collectTasks(context.Background())
+	assert.Error(t, err)
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `not json`)
+	})
+	coll, _, _, _ = newTestPVECollector(t, handler)
+	_, err = coll.collectTasks(context.Background())
+	assert.ErrorContains(t, err, "parsing tasks response")
+}
+
+func TestPVE_Collect_Tasks(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/cluster/tasks":
+			fmt.Fprint(w, clusterTasksJSON)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, ch, st, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+
+	assert.Len(t, ch.Snapshot().PVETasks["test-pve"], 4)
+
+	stored, err := st.QueryPVETasks(0)
+	require.NoError(t, err)
+	assert.Len(t, stored, 4)
+}
+
+func TestPVE_Collect_TasksUnavailable(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/cluster/tasks":
+			http.Error(w, "forbidden", 403)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, ch, _, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+	assert.Empty(t, ch.Snapshot().PVETasks)
+}
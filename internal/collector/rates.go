@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// guestCounters is the previous sample of a guest's cumulative counters.
+type guestCounters struct {
+	ts        time.Time
+	uptime    int64
+	netIn     int64
+	netOut    int64
+	diskRead  int64
+	diskWrite int64
+}
+
+// guestRates holds the per-interval rates derived for one guest. A nil field
+// means no rate could be derived for this interval.
+type guestRates struct {
+	netIn, netOut, diskRead, diskWrite *float64
+}
+
+// counterRate returns the per-second rate between two samples of a
+// cumulative counter. A counter that went backwards has been reset (reboot,
+// migration, stop/start) and yields no rate for this interval.
+func counterRate(prev, cur int64, dt float64) (float64, bool) {
+	if dt <= 0 || cur < prev {
+		return 0, false
+	}
+	return float64(cur-prev) / dt, true
+}
+
+// applyGuestRates derives throughput rates for each guest from the counters
+// seen on the previous poll, sets the Rate fields on the guests and returns
+// the rates keyed by VMID for the snapshot writer. The stored counters are
+// replaced by the current samples; guests that disappeared are dropped.
+func (p *PVECollector) applyGuestRates(guests map[int]*model.Guest, now time.Time) map[int]guestRates {
+	rates := make(map[int]guestRates, len(guests))
+	counters := make(map[int]guestCounters, len(guests))
+
+	for vmid, g := range guests {
+		cur := guestCounters{
+			ts:        now,
+			uptime:    g.Uptime,
+			netIn:     g.NetIn,
+			netOut:    g.NetOut,
+			diskRead:  g.DiskRead,
+			diskWrite: g.DiskWrite,
+		}
+		counters[vmid] = cur
+
+		prev, ok := p.counters[vmid]
+		// An uptime that went backwards means the guest restarted, even if
+		// the counters have already climbed past their previous values.
+		if !ok || g.Status != "running" || cur.uptime < prev.uptime {
+			rates[vmid] = guestRates{}
+			continue
+		}
+
+		dt := now.Sub(prev.ts).Seconds()
+		var r guestRates
+		r.netIn = rateField(prev.netIn, cur.netIn, dt, &g.NetInRate)
+		r.netOut = rateField(prev.netOut, cur.netOut, dt, &g.NetOutRate)
+		r.diskRead = rateField(prev.diskRead, cur.diskRead, dt, &g.DiskReadRate)
+		r.diskWrite = rateField(prev.diskWrite, cur.diskWrite, dt, &g.DiskWriteRate)
+		rates[vmid] = r
+	}
+
+	p.counters = counters
+	return rates
+}
+
+// rateField computes a counter rate, stores it in dst and returns a pointer
+// to it, or nil when the rate is not available.
+func rateField(prev, cur int64, dt float64, dst *float64) *float64 {
+	v, ok := counterRate(prev, cur, dt)
+	if !ok {
+		return nil
+	}
+	*dst = v
+	return &v
+}
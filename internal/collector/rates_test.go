@@ -0,0 +1,121 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterRate(t *testing.T) {
+	v, ok := counterRate(1000, 7000, 60)
+	assert.True(t, ok)
+	assert.Equal(t, float64(100), v)
+
+	v, ok = counterRate(1000, 1000, 60)
+	assert.True(t, ok)
+	assert.Zero(t, v)
+
+	_, ok = counterRate(7000, 1000, 60)
+	assert.False(t, ok, "counter reset")
+
+	_, ok = counterRate(1000, 7000, 0)
+	assert.False(t, ok, "zero interval")
+}
+
+func TestApplyGuestRates(t *testing.T) {
+	p := &PVECollector{}
+	t0 := time.Unix(1_700_000_000, 0)
+
+	guest := func(uptime, netIn, netOut, diskRead, diskWrite int64) *model.Guest {
+		return &model.Guest{VMID: 100, Status: "running", Uptime: uptime,
+			NetIn: netIn, NetOut: netOut, DiskRead: diskRead, DiskWrite: diskWrite}
+	}
+
+	// First poll: no previous sample, no rates.
+	g := guest(1000, 1000, 2000, 3000, 4000)
+	rates := p.applyGuestRates(map[int]*model.Guest{100: g}, t0)
+	assert.Nil(t, rates[100].netIn)
+	assert.Zero(t, g.NetInRate)
+
+	// Second poll 10s later.
+	g = guest(1010, 11000, 2000, 13240, 4000)
+	rates = p.applyGuestRates(map[int]*model.Guest{100: g}, t0.Add(10*time.Second))
+	require.NotNil(t, rates[100].netIn)
+	assert.Equal(t, float64(1000), *rates[100].netIn)
+	assert.Equal(t, float64(1000), g.NetInRate)
+	assert.Zero(t, g.NetOutRate)
+	require.NotNil(t, rates[100].netOut, "an idle counter has a zero rate")
+	assert.Equal(t, float64(1024), g.DiskReadRate)
+
+	// Net counter reset (migration): that rate is skipped, others still derived.
+	g = guest(1020, 500, 2100, 13240, 4000)
+	rates = p.applyGuestRates(map[int]*model.Guest{100: g}, t0.Add(20*time.Second))
+	assert.Nil(t, rates[100].netIn)
+	assert.Zero(t, g.NetInRate)
+	require.NotNil(t, rates[100].netOut)
+	assert.Equal(t, float64(10), g.NetOutRate)
+
+	// Reboot: uptime went backwards, counters already past previous values.
+	g = guest(5, 900_000, 900_000, 900_000, 900_000)
+	rates = p.applyGuestRates(map[int]*model.Guest{100: g}, t0.Add(30*time.Second))
+	assert.Equal(t, guestRates{}, rates[100])
+	assert.Zero(t, g.NetInRate)
+
+	// Stopped guests have no rate.
+	g = guest(0, 0, 0, 0, 0)
+	g.Status = "stopped"
+	rates = p.applyGuestRates(map[int]*model.Guest{100: g}, t0.Add(40*time.Second))
+	assert.Equal(t, guestRates{}, rates[100])
+
+	// Guests that disappear are dropped from the stored samples.
+	p.applyGuestRates(map[int]*model.Guest{}, t0.Add(50*time.Second))
+	assert.Empty(t, p.counters)
+}
+
+func TestPVE_Collect_GuestRates(t *testing.T) {
+	var polls atomic.Int64
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			fmt.Fprint(w, `{"data": [{"node": "pve", "status": "online"}]}`)
+		case "/api2/json/nodes/pve/status":
+			fmt.Fprint(w, nodeStatusJSON)
+		case "/api2/json/nodes/pve/lxc":
+			n := polls.Add(1)
+			fmt.Fprintf(w, `{"data": [{"vmid": 101, "name": "ct", "status": "running", "cpus": 1, "uptime": %d, "netin": %d, "netout": 0, "diskread": %d, "diskwrite": 0}]}`,
+				1000+n, n*1_000_000, n*2_000_000)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	})
+	coll, ch, st, _ := newTestPVECollector(t, handler)
+
+	require.NoError(t, coll.Collect(context.Background()))
+	// Backdate the previous sample so the second poll sees a 10s interval.
+	prev := coll.counters[101]
+	prev.ts = prev.ts.Add(-10 * time.Second)
+	coll.counters[101] = prev
+	time.Sleep(1100 * time.Millisecond) // distinct snapshot timestamp
+	require.NoError(t, coll.Collect(context.Background()))
+
+	g := ch.Snapshot().Guests["test-pve"][101]
+	require.NotNil(t, g)
+	assert.Equal(t, int64(4_000_000), g.DiskRead)
+	assert.InDelta(t, 100_000, g.NetInRate, 15_000)
+	assert.InDelta(t, 200_000, g.DiskReadRate, 30_000)
+
+	history, err := st.QueryGuestHistory("test-pve", 101, time.Now().Add(-time.Hour).Unix())
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Nil(t, history[0].NetInRate, "first sample has no rate")
+	require.NotNil(t, history[1].NetInRate)
+	assert.Equal(t, g.NetInRate, *history[1].NetInRate)
+	assert.Equal(t, int64(4_000_000), history[1].DiskRead)
+}
@@ -0,0 +1,146 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/darshan-rambhia/glint/internal/smart"
+	"golang.org/x/crypto/ssh"
+)
+
+// devPathPattern restricts the device paths passed to the remote shell.
+var devPathPattern = regexp.MustCompile(`^/dev/[A-Za-z0-9/_.-]+$`)
+
+// smartReportReader reads smartctl reports for the devices of the node it
+// connects to. SMARTShell implements it; tests substitute a fake.
+type smartReportReader interface {
+	Node(ctx context.Context) (string, error)
+	Reports(ctx context.Context, devPaths []string) (map[string]*smart.Report, error)
+}
+
+// SMARTShell reads the SMART data the PVE API leaves out, such as the
+// self-test log, by running `smartctl -a -j` on a node over SSH. The SSH
+// user must be allowed to run smartctl.
+type SMARTShell struct {
+	instance string
+	sshCfg   SSHConfig
+	signer   ssh.Signer // cached at startup
+
+	mu   sync.Mutex
+	node string // node name of the SSH host, once known
+}
+
+// NewSMARTShell creates a SMART reader for the node at cfg.Host. The SSH key
+// is parsed once at startup rather than on every poll.
+func NewSMARTShell(instance string, cfg SSHConfig) (*SMARTShell, error) {
+	signer, err := loadSSHSigner(cfg.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+	return &SMARTShell{instance: instance, sshCfg: cfg, signer: signer}, nil
+}
+
+// Node returns the name of the node the SSH host is, as reported by
+// `uname -n` without the domain. The name is read once and cached.
+func (s *SMARTShell) Node(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.node != "" {
+		return s.node, nil
+	}
+
+	client, err := dialSSH(ctx, s.instance, s.sshCfg, s.signer)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	out, err := runCommand(ctx, client, "uname -n")
+	if err != nil {
+		return "", fmt.Errorf("reading node name: %w", err)
+	}
+	name, _, _ := strings.Cut(strings.TrimSpace(string(out)), ".")
+	if name == "" {
+		return "", errors.New("reading node name: empty output")
+	}
+	s.node = name
+	return name, nil
+}
+
+// Reports runs smartctl for each device over one SSH connection and returns
+// the parsed reports keyed by device path. Devices smartctl cannot read are
+// logged and left out.
+func (s *SMARTShell) Reports(ctx context.Context, devPaths []string) (map[string]*smart.Report, error) {
+	client, err := dialSSH(ctx, s.instance, s.sshCfg, s.signer)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	reports := make(map[string]*smart.Report, len(devPaths))
+	for _, path := range devPaths {
+		if err := ctx.Err(); err != nil {
+			return reports, err
+		}
+		out, err := runSmartctl(ctx, client, path)
+		if err != nil {
+			slog.Debug("running smartctl over SSH", "instance", s.instance, "host", s.sshCfg.Host, "disk", path, "error", err)
+			continue
+		}
+		r, err := smart.ParseSmartctlJSON(out)
+		if err != nil {
+			slog.Debug("parsing smartctl output", "instance", s.instance, "host", s.sshCfg.Host, "disk", path, "error", err)
+			continue
+		}
+		reports[path] = r
+	}
+	return reports, nil
+}
+
+func runSmartctl(ctx context.Context, client *ssh.Client, devPath string) ([]byte, error) {
+	if !devPathPattern.MatchString(devPath) {
+		return nil, fmt.Errorf("invalid device path %q", devPath)
+	}
+	// smartctl's exit status is a bitmask that is also set for failing disks
+	// and logged errors; ParseSmartctlJSON reads it from the output instead.
+	out, err := runCommand(ctx, client, "smartctl -a -j "+devPath)
+	var exitErr *ssh.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("running smartctl: %w", err)
+	}
+	return out, nil
+}
+
+// runCommand runs cmd in a new session and returns its stdout. The session
+// is closed when ctx is cancelled, so a hung command does not stall the poll.
+func runCommand(ctx context.Context, client *ssh.Client, cmd string) ([]byte, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("creating SSH session: %w", err)
+	}
+	defer session.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			session.Close()
+		case <-done:
+		}
+	}()
+
+	var stdout bytes.Buffer
+	session.Stdout = &stdout
+	err = session.Run(cmd)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	return stdout.Bytes(), err
+}
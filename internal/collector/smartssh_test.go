@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestNewSMARTShell(t *testing.T) {
+	keyPath := testSSHKeyFile(t)
+	s, err := NewSMARTShell("homelab", SSHConfig{Host: "192.168.1.215", User: "root", KeyPath: keyPath})
+	require.NoError(t, err)
+	assert.Equal(t, "homelab", s.instance)
+	assert.NotNil(t, s.signer)
+
+	_, err = NewSMARTShell("homelab", SSHConfig{Host: "127.0.0.1", User: "root", KeyPath: "/nonexistent/key"})
+	assert.ErrorContains(t, err, "reading SSH key")
+}
+
+func TestSMARTShell_Reports_ConnectionFailure(t *testing.T) {
+	keyPath := testSSHKeyFile(t)
+	// Use an unreachable address to force a connection error
+	s, err := NewSMARTShell("homelab", SSHConfig{Host: "192.0.2.1", User: "root", KeyPath: keyPath})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = s.Reports(ctx, []string{"/dev/sda"})
+	assert.Error(t, err)
+}
+
+func TestDevPathPattern(t *testing.T) {
+	for path, valid := range map[string]bool{
+		"/dev/sda":                  true,
+		"/dev/nvme0n1":              true,
+		"/dev/disk/by-id/ata-WDC_1": true,
+		"/dev/sda; reboot":          false,
+		"/dev/$(id)":                false,
+		"sda":                       false,
+		"":                          false,
+	} {
+		assert.Equal(t, valid, devPathPattern.MatchString(path), path)
+	}
+}
+
+// hangingSSHClient returns a client connected to an in-process SSH server
+// that accepts every command and never completes it.
+func hangingSSHClient(t *testing.T) *ssh.Client {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostKey, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	serverCfg := &ssh.ServerConfig{NoClientAuth: true}
+	serverCfg.AddHostKey(hostKey)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		serverConn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, chans, reqs, err := ssh.NewServerConn(serverConn, serverCfg)
+		if err != nil {
+			return
+		}
+		go ssh.DiscardRequests(reqs)
+		for nc := range chans {
+			ch, requests, err := nc.Accept()
+			if err != nil {
+				continue
+			}
+			go func() {
+				defer ch.Close()
+				for req := range requests {
+					_ = req.Reply(req.Type == "exec", nil)
+				}
+			}()
+		}
+	}()
+
+	client, err := ssh.Dial("tcp", ln.Addr().String(), &ssh.ClientConfig{
+		User:            "root",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // test server
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRunSmartctl_ContextCancelled(t *testing.T) {
+	client := hangingSSHClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := runSmartctl(ctx, client, "/dev/sda")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
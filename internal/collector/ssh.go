@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHConfig holds SSH connection settings for a PVE node.
+type SSHConfig struct {
+	Host           string
+	User           string
+	KeyPath        string
+	KnownHostsFile string // path to known_hosts file; empty = insecure (warn at startup)
+}
+
+// loadSSHSigner reads and parses the private key at path.
+func loadSSHSigner(path string) (ssh.Signer, error) {
+	keyBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading SSH key %s: %w", path, err)
+	}
+	signer, err := ssh.ParsePrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing SSH key %s: %w", path, err)
+	}
+	return signer, nil
+}
+
+func sshHostKeyCallback(instance string, cfg SSHConfig) (ssh.HostKeyCallback, error) {
+	if cfg.KnownHostsFile == "" {
+		slog.Warn("SSH host key verification disabled — set known_hosts_file to enable it",
+			"instance", instance, "host", cfg.Host)
+		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // user opted out; warned above
+	}
+	cb, err := knownhosts.New(cfg.KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading known_hosts %s: %w", cfg.KnownHostsFile, err)
+	}
+	return cb, nil
+}
+
+// dialSSH connects and authenticates to cfg.Host. The caller closes the client.
+func dialSSH(ctx context.Context, instance string, cfg SSHConfig, signer ssh.Signer) (*ssh.Client, error) {
+	hkCb, err := sshHostKeyCallback(instance, cfg)
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ClientConfig{
+		User:            cfg.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hkCb,
+		Timeout:         10 * time.Second,
+	}
+
+	addr := cfg.Host + ":22"
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH handshake with %s: %w", addr, err)
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
@@ -6,23 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"net"
-	"os"
 	"time"
 
 	"github.com/darshan-rambhia/glint/internal/cache"
 	"golang.org/x/crypto/ssh"
-	"golang.org/x/crypto/ssh/knownhosts"
 )
 
-// SSHConfig holds SSH connection settings for temperature polling.
-type SSHConfig struct {
-	Host           string
-	User           string
-	KeyPath        string
-	KnownHostsFile string // path to known_hosts file; empty = insecure (warn at startup)
-}
-
 // TempCollector polls CPU temperatures via SSH.
 type TempCollector struct {
 	instance string
@@ -36,13 +25,9 @@ type TempCollector struct {
 // NewTempCollector creates a temperature collector for a specific node.
 // The SSH key is parsed once at startup rather than on every poll.
 func NewTempCollector(instance, node string, cfg SSHConfig, c *cache.Cache) (*TempCollector, error) {
-	keyBytes, err := os.ReadFile(cfg.KeyPath)
-	if err != nil {
-		return nil, fmt.Errorf("reading SSH key %s: %w", cfg.KeyPath, err)
-	}
-	signer, err := ssh.ParsePrivateKey(keyBytes)
+	signer, err := loadSSHSigner(cfg.KeyPath)
 	if err != nil {
-		return nil, fmt.Errorf("parsing SSH key %s: %w", cfg.KeyPath, err)
+		return nil, err
 	}
 
 	return &TempCollector{
@@ -72,44 +57,11 @@ func (t *TempCollector) Collect(ctx context.Context) error {
 	return nil
 }
 
-func (t *TempCollector) hostKeyCallback() (ssh.HostKeyCallback, error) {
-	if t.sshCfg.KnownHostsFile == "" {
-		slog.Warn("SSH host key verification disabled — set known_hosts_file to enable it",
-			"instance", t.instance, "host", t.sshCfg.Host)
-		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // user opted out; warned above
-	}
-	cb, err := knownhosts.New(t.sshCfg.KnownHostsFile)
-	if err != nil {
-		return nil, fmt.Errorf("loading known_hosts %s: %w", t.sshCfg.KnownHostsFile, err)
-	}
-	return cb, nil
-}
-
 func (t *TempCollector) pollTemperature(ctx context.Context) (float64, error) {
-	hkCb, err := t.hostKeyCallback()
+	client, err := dialSSH(ctx, t.instance, t.sshCfg, t.signer)
 	if err != nil {
 		return 0, err
 	}
-	config := &ssh.ClientConfig{
-		User:            t.sshCfg.User,
-		Auth:            []ssh.AuthMethod{ssh.PublicKeys(t.signer)},
-		HostKeyCallback: hkCb,
-		Timeout:         10 * time.Second,
-	}
-
-	addr := t.sshCfg.Host + ":22"
-	dialer := net.Dialer{Timeout: 10 * time.Second}
-	conn, err := dialer.DialContext(ctx, "tcp", addr)
-	if err != nil {
-		return 0, fmt.Errorf("connecting to %s: %w", addr, err)
-	}
-
-	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
-	if err != nil {
-		conn.Close()
-		return 0, fmt.Errorf("SSH handshake with %s: %w", addr, err)
-	}
-	client := ssh.NewClient(sshConn, chans, reqs)
 	defer client.Close()
 
 	session, err := client.NewSession()
@@ -31,7 +31,11 @@ type Config struct {
 	PVE            []PVEConfig          `yaml:"pve"`
 	PBS            []PBSConfig          `yaml:"pbs"`
 	Notifications  []NotificationConfig `yaml:"notifications"`
+	MetricsSinks   []MetricsSinkConfig  `yaml:"metrics_sinks"`
 	Alerts         AlertsConfig         `yaml:"alerts"`
+	Retention      RetentionConfig      `yaml:"retention"`
+	SMART          SMARTConfig          `yaml:"smart"`
+	DBBackup       *DBBackupConfig      `yaml:"db_backup,omitempty"`
 }
 
 // PVEConfig describes a single Proxmox VE instance.
@@ -43,6 +47,7 @@ type PVEConfig struct {
 	Insecure         bool       `yaml:"insecure"`
 	PollInterval     Duration   `yaml:"poll_interval"`
 	DiskPollInterval Duration   `yaml:"disk_poll_interval"`
+	DiskMissingPolls int        `yaml:"disk_missing_polls"` // disk polls a disk may miss before it is reported removed
 	SSH              *SSHConfig `yaml:"ssh,omitempty"`
 }
 
@@ -74,13 +79,85 @@ type NotificationConfig struct {
 	Headers map[string]string `yaml:"headers,omitempty"` // webhook only
 }
 
+// MetricsSinkConfig describes an external time-series database that receives
+// every snapshot in addition to SQLite.
+type MetricsSinkConfig struct {
+	Type    string            `yaml:"type"` // "influx" or "remote_write"
+	URL     string            `yaml:"url"`  // full write endpoint
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// RetentionConfig sets how long each table is kept. Unset fields keep the
+// built-in defaults; node and guest snapshots default to history_hours.
+type RetentionConfig struct {
+	NodeSnapshots      Duration `yaml:"node_snapshots"`
+	GuestSnapshots     Duration `yaml:"guest_snapshots"`
+	SMARTSnapshots     Duration `yaml:"smart_snapshots"`
+	BackupSnapshots    Duration `yaml:"backup_snapshots"`
+	DatastoreSnapshots Duration `yaml:"datastore_snapshots"`
+	AlertLog           Duration `yaml:"alert_log"`
+	PVETasks           Duration `yaml:"pve_tasks"`
+	Rollups5m          Duration `yaml:"rollups_5m"`
+	Rollups1h          Duration `yaml:"rollups_1h"`
+}
+
+// SMARTConfig overrides how ATA SMART attributes are scored. Attributes are
+// keyed by attribute ID; model rules apply to disks whose model matches the
+// regular expression and take precedence, later entries over earlier ones.
+type SMARTConfig struct {
+	Attributes map[int]SMARTAttrRule `yaml:"attributes,omitempty"`
+	Models     []SMARTModelRule      `yaml:"models,omitempty"`
+}
+
+// SMARTModelRule overrides attributes for the disk models matching Match.
+type SMARTModelRule struct {
+	Match      string                `yaml:"match"`
+	Attributes map[int]SMARTAttrRule `yaml:"attributes"`
+}
+
+// SMARTAttrRule overrides one attribute. Unset fields keep the built-in
+// behaviour.
+type SMARTAttrRule struct {
+	Critical *bool         `yaml:"critical,omitempty"`
+	Ignore   *bool         `yaml:"ignore,omitempty"`
+	Buckets  []SMARTBucket `yaml:"buckets,omitempty"`
+}
+
+// SMARTBucket maps a raw value range to an annual failure rate (0-1). High
+// is inclusive; unset means no upper bound.
+type SMARTBucket struct {
+	Low  int64   `yaml:"low"`
+	High *int64  `yaml:"high,omitempty"`
+	Rate float64 `yaml:"rate"`
+}
+
+// DBBackupConfig enables scheduled online backups of the SQLite database.
+type DBBackupConfig struct {
+	Dir      string   `yaml:"dir"`
+	Interval Duration `yaml:"interval"` // default 24h
+	Keep     int      `yaml:"keep"`     // default 7
+}
+
 // AlertsConfig holds thresholds for each alert type.
 type AlertsConfig struct {
-	NodeCPUHigh     *AlertNodeCPUHigh     `yaml:"node_cpu_high,omitempty"`
-	GuestDown       *AlertGuestDown       `yaml:"guest_down,omitempty"`
-	BackupStale     *AlertBackupStale     `yaml:"backup_stale,omitempty"`
-	DiskSmartFailed *AlertDiskSmartFailed `yaml:"disk_smart_failed,omitempty"`
-	DatastoreFull   *AlertDatastoreFull   `yaml:"datastore_full,omitempty"`
+	NodeCPUHigh        *AlertNodeCPUHigh     `yaml:"node_cpu_high,omitempty"`
+	GuestDown          *AlertGuestDown       `yaml:"guest_down,omitempty"`
+	BackupStale        *AlertBackupStale     `yaml:"backup_stale,omitempty"`
+	DiskSmartFailed    *AlertDiskSmartFailed `yaml:"disk_smart_failed,omitempty"`
+	DiskDegrading      *AlertSeverity        `yaml:"disk_degrading,omitempty"`
+	DiskEndurance      *AlertDiskEndurance   `yaml:"disk_endurance_low,omitempty"`
+	DiskTempHigh       *AlertDiskTempHigh    `yaml:"disk_temp_high,omitempty"`
+	DiskSelfTestFailed *AlertSeverity        `yaml:"disk_self_test_failed,omitempty"`
+	DiskNoLongTest     *AlertSeverity        `yaml:"disk_no_long_self_test,omitempty"`
+	DiskMissing        *AlertSeverity        `yaml:"disk_missing,omitempty"`
+	DatastoreFull      *AlertDatastoreFull   `yaml:"datastore_full,omitempty"`
+	CephHealth         *AlertCephHealth      `yaml:"ceph_health,omitempty"`
+	ClusterQuorum      *AlertSeverity        `yaml:"cluster_quorum_lost,omitempty"`
+	HAResourceError    *AlertSeverity        `yaml:"ha_resource_error,omitempty"`
+	PVEBackupFailed    *AlertSeverity        `yaml:"pve_backup_failed,omitempty"`
+	PVETaskFailed      *AlertSeverity        `yaml:"pve_task_failed,omitempty"`
+	GuestNetHigh       *AlertThroughput      `yaml:"guest_net_high,omitempty"`
+	GuestDiskIOHigh    *AlertThroughput      `yaml:"guest_disk_io_high,omitempty"`
 }
 
 type AlertNodeCPUHigh struct {
@@ -103,11 +180,47 @@ type AlertDiskSmartFailed struct {
 	Severity string `yaml:"severity"`
 }
 
+// AlertDiskEndurance fires when an SSD's projected end of rated write
+// endurance is less than Months away.
+type AlertDiskEndurance struct {
+	Months   float64 `yaml:"months"`
+	Severity string  `yaml:"severity"`
+}
+
+// AlertDiskTempHigh sets per-type disk temperature limits in °C. Unset
+// limits keep their defaults (HDD 50, SSD 60, NVMe 70).
+type AlertDiskTempHigh struct {
+	HDD      float64  `yaml:"hdd"`
+	SSD      float64  `yaml:"ssd"`
+	NVMe     float64  `yaml:"nvme"`
+	Duration Duration `yaml:"duration"`
+	Severity string   `yaml:"severity"`
+}
+
 type AlertDatastoreFull struct {
 	Threshold float64 `yaml:"threshold"`
 	Severity  string  `yaml:"severity"`
 }
 
+// AlertCephHealth sets the severity for Ceph HEALTH_WARN checks.
+// HEALTH_ERR checks are always reported as critical.
+type AlertCephHealth struct {
+	Severity string `yaml:"severity"`
+}
+
+// AlertThroughput enables a guest throughput alert. Threshold is in MB/s and
+// applies to each direction (in/out, read/write) separately.
+type AlertThroughput struct {
+	Threshold float64  `yaml:"threshold"`
+	Duration  Duration `yaml:"duration"`
+	Severity  string   `yaml:"severity"`
+}
+
+// AlertSeverity configures an alert that only takes a severity override.
+type AlertSeverity struct {
+	Severity string `yaml:"severity"`
+}
+
 // Duration wraps time.Duration with YAML string parsing support.
 type Duration struct {
 	time.Duration
@@ -118,7 +231,7 @@ func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
 	if err := value.Decode(&s); err != nil {
 		return err
 	}
-	parsed, err := time.ParseDuration(s)
+	parsed, err := parseDuration(s)
 	if err != nil {
 		return fmt.Errorf("invalid duration %q: %w", s, err)
 	}
@@ -126,6 +239,19 @@ func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// parseDuration extends time.ParseDuration with a whole-day suffix ("30d"),
+// which reads better for retention periods.
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid day count %q", days)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
 func (d Duration) MarshalYAML() (any, error) {
 	return d.String(), nil
 }
@@ -180,6 +306,9 @@ func (c *Config) Validate() error {
 		if pve.Name == "" {
 			return fmt.Errorf("pve[%d]: name is required", i)
 		}
+		if pve.DiskMissingPolls < 0 {
+			return fmt.Errorf("pve[%d]: disk_missing_polls must not be negative", i)
+		}
 	}
 	for i, pbs := range c.PBS {
 		if pbs.Host == "" {
@@ -212,6 +341,19 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("notifications[%d]: unknown type %q (expected ntfy or webhook)", i, n.Type)
 		}
 	}
+	for i, m := range c.MetricsSinks {
+		switch m.Type {
+		case "influx", "remote_write":
+		default:
+			return fmt.Errorf("metrics_sinks[%d]: unknown type %q (expected influx or remote_write)", i, m.Type)
+		}
+		if m.URL == "" {
+			return fmt.Errorf("metrics_sinks[%d]: url is required", i)
+		}
+		if _, err := url.Parse(m.URL); err != nil {
+			return fmt.Errorf("metrics_sinks[%d]: invalid url: %w", i, err)
+		}
+	}
 	validLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
 	if !validLevels[c.LogLevel] {
 		return fmt.Errorf("log_level must be one of: debug, info, warn, error")
@@ -227,6 +369,43 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("worker_pool_size must be >= 1")
 	}
 
+	// Validate retention periods (zero keeps the default)
+	r := c.Retention
+	for _, f := range []struct {
+		name string
+		d    Duration
+	}{
+		{"node_snapshots", r.NodeSnapshots},
+		{"guest_snapshots", r.GuestSnapshots},
+		{"smart_snapshots", r.SMARTSnapshots},
+		{"backup_snapshots", r.BackupSnapshots},
+		{"datastore_snapshots", r.DatastoreSnapshots},
+		{"alert_log", r.AlertLog},
+		{"pve_tasks", r.PVETasks},
+		{"rollups_5m", r.Rollups5m},
+		{"rollups_1h", r.Rollups1h},
+	} {
+		if f.d.Duration < 0 {
+			return fmt.Errorf("retention.%s must be >= 0 (0 = default)", f.name)
+		}
+	}
+
+	if b := c.DBBackup; b != nil {
+		if b.Dir == "" {
+			return fmt.Errorf("db_backup: dir is required")
+		}
+		if b.Interval.Duration < 0 {
+			return fmt.Errorf("db_backup: interval must be >= 0 (0 = default)")
+		}
+		if b.Keep < 0 {
+			return fmt.Errorf("db_backup: keep must be >= 0 (0 = default)")
+		}
+	}
+
+	if err := c.SMART.validate(); err != nil {
+		return err
+	}
+
 	// Validate alert thresholds
 	if a := c.Alerts.NodeCPUHigh; a != nil {
 		if a.Threshold <= 0 {
@@ -251,7 +430,79 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("alerts.datastore_full: threshold must be > 0")
 		}
 	}
+	if a := c.Alerts.DiskEndurance; a != nil {
+		if a.Months <= 0 {
+			return fmt.Errorf("alerts.disk_endurance_low: months must be > 0")
+		}
+	}
+	if a := c.Alerts.DiskTempHigh; a != nil {
+		for _, f := range []struct {
+			name  string
+			limit float64
+		}{{"hdd", a.HDD}, {"ssd", a.SSD}, {"nvme", a.NVMe}} {
+			if f.limit < 0 {
+				return fmt.Errorf("alerts.disk_temp_high: %s must be >= 0 (0 = default)", f.name)
+			}
+		}
+		if a.Duration.Duration < 0 {
+			return fmt.Errorf("alerts.disk_temp_high: duration must be >= 0 (0 = default)")
+		}
+	}
+	if a := c.Alerts.GuestNetHigh; a != nil {
+		if a.Threshold <= 0 {
+			return fmt.Errorf("alerts.guest_net_high: threshold must be > 0")
+		}
+	}
+	if a := c.Alerts.GuestDiskIOHigh; a != nil {
+		if a.Threshold <= 0 {
+			return fmt.Errorf("alerts.guest_disk_io_high: threshold must be > 0")
+		}
+	}
+
+	return nil
+}
+
+func (c SMARTConfig) validate() error {
+	if err := validateSMARTAttrs("smart.attributes", c.Attributes); err != nil {
+		return err
+	}
+	for i, m := range c.Models {
+		if m.Match == "" {
+			return fmt.Errorf("smart.models[%d]: match is required", i)
+		}
+		if _, err := regexp.Compile(m.Match); err != nil {
+			return fmt.Errorf("smart.models[%d]: invalid match: %w", i, err)
+		}
+		if err := validateSMARTAttrs(fmt.Sprintf("smart.models[%d].attributes", i), m.Attributes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func validateSMARTAttrs(path string, attrs map[int]SMARTAttrRule) error {
+	for id, rule := range attrs {
+		if id < 1 || id > 255 {
+			return fmt.Errorf("%s: attribute ID %d must be 1-255", path, id)
+		}
+		prev := int64(-1)
+		for j, b := range rule.Buckets {
+			if b.Low <= prev {
+				return fmt.Errorf("%s.%d.buckets[%d]: low must be above the previous bucket", path, id, j)
+			}
+			if b.High != nil {
+				if *b.High < b.Low {
+					return fmt.Errorf("%s.%d.buckets[%d]: high must be >= low", path, id, j)
+				}
+				prev = *b.High
+			} else if j < len(rule.Buckets)-1 {
+				return fmt.Errorf("%s.%d.buckets[%d]: only the last bucket may omit high", path, id, j)
+			}
+			if b.Rate < 0 || b.Rate > 1 {
+				return fmt.Errorf("%s.%d.buckets[%d]: rate must be between 0 and 1", path, id, j)
+			}
+		}
+	}
 	return nil
 }
 
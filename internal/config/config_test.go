@@ -57,6 +57,7 @@ pve:
     insecure: true
     poll_interval: "30s"
     disk_poll_interval: "2h"
+    disk_missing_polls: 3
     ssh:
       host: "192.168.1.215"
       user: "root"
@@ -94,9 +95,60 @@ alerts:
     severity: "warning"
   disk_smart_failed:
     severity: "critical"
+  disk_degrading:
+    severity: "critical"
+  disk_endurance_low:
+    months: 12
+    severity: "critical"
+  disk_temp_high:
+    hdd: 45
+    nvme: 75
+    duration: "2h"
+  disk_self_test_failed:
+    severity: "warning"
+  disk_no_long_self_test:
+    severity: "info"
+  disk_missing:
+    severity: "warning"
   datastore_full:
     threshold: 85
     severity: "warning"
+  ceph_health:
+    severity: "critical"
+  cluster_quorum_lost:
+    severity: "warning"
+  ha_resource_error:
+    severity: "warning"
+  pve_backup_failed:
+    severity: "critical"
+  pve_task_failed:
+    severity: "critical"
+  guest_net_high:
+    threshold: 50
+    duration: "10m"
+    severity: "warning"
+  guest_disk_io_high:
+    threshold: 200
+
+retention:
+  smart_snapshots: 365d
+  guest_snapshots: 72h
+  rollups_1h: 730d
+
+smart:
+  attributes:
+    188:
+      ignore: true
+    5:
+      critical: false
+      buckets:
+        - {low: 0, high: 10, rate: 0.02}
+        - {low: 11, rate: 0.3}
+  models:
+    - match: "^WDC WD40"
+      attributes:
+        188:
+          ignore: false
 `
 
 func TestLoad_FromYAML(t *testing.T) {
@@ -121,6 +173,7 @@ func TestLoad_FromYAML(t *testing.T) {
 	assert.True(t, cfg.PVE[0].Insecure)
 	assert.Equal(t, 30*time.Second, cfg.PVE[0].PollInterval.Duration)
 	assert.Equal(t, 2*time.Hour, cfg.PVE[0].DiskPollInterval.Duration)
+	assert.Equal(t, 3, cfg.PVE[0].DiskMissingPolls)
 	require.NotNil(t, cfg.PVE[0].SSH)
 	assert.Equal(t, "192.168.1.215", cfg.PVE[0].SSH.Host)
 	assert.Equal(t, "root", cfg.PVE[0].SSH.User)
@@ -155,9 +208,56 @@ func TestLoad_FromYAML(t *testing.T) {
 
 	require.NotNil(t, cfg.Alerts.DiskSmartFailed)
 	assert.Equal(t, "critical", cfg.Alerts.DiskSmartFailed.Severity)
+	require.NotNil(t, cfg.Alerts.DiskDegrading)
+	assert.Equal(t, "critical", cfg.Alerts.DiskDegrading.Severity)
+	require.NotNil(t, cfg.Alerts.DiskEndurance)
+	assert.Equal(t, 12.0, cfg.Alerts.DiskEndurance.Months)
+	require.NotNil(t, cfg.Alerts.DiskTempHigh)
+	assert.Equal(t, 45.0, cfg.Alerts.DiskTempHigh.HDD)
+	assert.Zero(t, cfg.Alerts.DiskTempHigh.SSD)
+	assert.Equal(t, 75.0, cfg.Alerts.DiskTempHigh.NVMe)
+	require.NotNil(t, cfg.Alerts.DiskSelfTestFailed)
+	assert.Equal(t, "warning", cfg.Alerts.DiskSelfTestFailed.Severity)
+	require.NotNil(t, cfg.Alerts.DiskNoLongTest)
+	assert.Equal(t, "info", cfg.Alerts.DiskNoLongTest.Severity)
+	require.NotNil(t, cfg.Alerts.DiskMissing)
+	assert.Equal(t, "warning", cfg.Alerts.DiskMissing.Severity)
+	assert.Equal(t, 2*time.Hour, cfg.Alerts.DiskTempHigh.Duration.Duration)
 
 	require.NotNil(t, cfg.Alerts.DatastoreFull)
 	assert.Equal(t, 85.0, cfg.Alerts.DatastoreFull.Threshold)
+
+	require.NotNil(t, cfg.Alerts.CephHealth)
+	assert.Equal(t, "critical", cfg.Alerts.CephHealth.Severity)
+
+	require.NotNil(t, cfg.Alerts.ClusterQuorum)
+	assert.Equal(t, "warning", cfg.Alerts.ClusterQuorum.Severity)
+	require.NotNil(t, cfg.Alerts.HAResourceError)
+	assert.Equal(t, "warning", cfg.Alerts.HAResourceError.Severity)
+	require.NotNil(t, cfg.Alerts.PVEBackupFailed)
+	assert.Equal(t, "critical", cfg.Alerts.PVEBackupFailed.Severity)
+	require.NotNil(t, cfg.Alerts.PVETaskFailed)
+	assert.Equal(t, "critical", cfg.Alerts.PVETaskFailed.Severity)
+	require.NotNil(t, cfg.Alerts.GuestNetHigh)
+	assert.Equal(t, float64(50), cfg.Alerts.GuestNetHigh.Threshold)
+	assert.Equal(t, 10*time.Minute, cfg.Alerts.GuestNetHigh.Duration.Duration)
+	require.NotNil(t, cfg.Alerts.GuestDiskIOHigh)
+	assert.Equal(t, float64(200), cfg.Alerts.GuestDiskIOHigh.Threshold)
+
+	// Retention
+	assert.Equal(t, 365*24*time.Hour, cfg.Retention.SMARTSnapshots.Duration)
+	assert.Equal(t, 72*time.Hour, cfg.Retention.GuestSnapshots.Duration)
+	assert.Equal(t, 730*24*time.Hour, cfg.Retention.Rollups1h.Duration)
+	assert.Zero(t, cfg.Retention.NodeSnapshots.Duration, "unset keeps the default")
+
+	// SMART
+	require.Len(t, cfg.SMART.Attributes, 2)
+	assert.Equal(t, new(true), cfg.SMART.Attributes[188].Ignore)
+	assert.Equal(t, new(false), cfg.SMART.Attributes[5].Critical)
+	assert.Equal(t, []SMARTBucket{{Low: 0, High: new(int64(10)), Rate: 0.02}, {Low: 11, Rate: 0.3}}, cfg.SMART.Attributes[5].Buckets)
+	require.Len(t, cfg.SMART.Models, 1)
+	assert.Equal(t, "^WDC WD40", cfg.SMART.Models[0].Match)
+	assert.Equal(t, new(false), cfg.SMART.Models[0].Attributes[188].Ignore)
 }
 
 func TestLoad_FileNotFound(t *testing.T) {
@@ -323,6 +423,11 @@ func TestValidate_Errors(t *testing.T) {
 			mutate:  func(c *Config) { c.PVE[0].Name = "" },
 			wantErr: "pve[0]: name is required",
 		},
+		{
+			name:    "PVE negative disk_missing_polls",
+			mutate:  func(c *Config) { c.PVE[0].DiskMissingPolls = -1 },
+			wantErr: "pve[0]: disk_missing_polls must not be negative",
+		},
 		{
 			name: "PBS missing host",
 			mutate: func(c *Config) {
@@ -358,6 +463,45 @@ func TestValidate_Errors(t *testing.T) {
 			},
 			wantErr: "url is required for webhook",
 		},
+		{
+			name:    "disk endurance zero months",
+			mutate:  func(c *Config) { c.Alerts.DiskEndurance = &AlertDiskEndurance{} },
+			wantErr: "alerts.disk_endurance_low: months must be > 0",
+		},
+		{
+			name:    "disk temp negative limit",
+			mutate:  func(c *Config) { c.Alerts.DiskTempHigh = &AlertDiskTempHigh{HDD: 50, SSD: -1} },
+			wantErr: "alerts.disk_temp_high: ssd must be >= 0 (0 = default)",
+		},
+		{
+			name:    "disk temp negative duration",
+			mutate:  func(c *Config) { c.Alerts.DiskTempHigh = &AlertDiskTempHigh{Duration: Duration{-time.Minute}} },
+			wantErr: "alerts.disk_temp_high: duration must be >= 0 (0 = default)",
+		},
+		{
+			name:    "db backup missing dir",
+			mutate:  func(c *Config) { c.DBBackup = &DBBackupConfig{Keep: 3} },
+			wantErr: "db_backup: dir is required",
+		},
+		{
+			name:    "db backup negative keep",
+			mutate:  func(c *Config) { c.DBBackup = &DBBackupConfig{Dir: "/backups", Keep: -1} },
+			wantErr: "db_backup: keep must be >= 0 (0 = default)",
+		},
+		{
+			name: "metrics sink unknown type",
+			mutate: func(c *Config) {
+				c.MetricsSinks = []MetricsSinkConfig{{Type: "graphite", URL: "http://x"}}
+			},
+			wantErr: "unknown type \"graphite\"",
+		},
+		{
+			name: "metrics sink missing url",
+			mutate: func(c *Config) {
+				c.MetricsSinks = []MetricsSinkConfig{{Type: "remote_write"}}
+			},
+			wantErr: "metrics_sinks[0]: url is required",
+		},
 		{
 			name:    "invalid log level",
 			mutate:  func(c *Config) { c.LogLevel = "verbose" },
@@ -373,6 +517,59 @@ func TestValidate_Errors(t *testing.T) {
 			mutate:  func(c *Config) { c.HistoryHours = 0 },
 			wantErr: "history_hours must be >= 1",
 		},
+		{
+			name:    "guest_net_high zero threshold",
+			mutate:  func(c *Config) { c.Alerts.GuestNetHigh = &AlertThroughput{Duration: Duration{time.Minute}} },
+			wantErr: "alerts.guest_net_high: threshold must be > 0",
+		},
+		{
+			name:    "negative retention",
+			mutate:  func(c *Config) { c.Retention.AlertLog = Duration{-time.Hour} },
+			wantErr: "retention.alert_log must be >= 0 (0 = default)",
+		},
+		{
+			name:    "guest_disk_io_high zero threshold",
+			mutate:  func(c *Config) { c.Alerts.GuestDiskIOHigh = &AlertThroughput{} },
+			wantErr: "alerts.guest_disk_io_high: threshold must be > 0",
+		},
+		{
+			name:    "smart attribute ID out of range",
+			mutate:  func(c *Config) { c.SMART.Attributes = map[int]SMARTAttrRule{256: {}} },
+			wantErr: "smart.attributes: attribute ID 256 must be 1-255",
+		},
+		{
+			name: "smart overlapping buckets",
+			mutate: func(c *Config) {
+				c.SMART.Attributes = map[int]SMARTAttrRule{5: {Buckets: []SMARTBucket{{Low: 0, High: new(int64(10))}, {Low: 10}}}}
+			},
+			wantErr: "smart.attributes.5.buckets[1]: low must be above the previous bucket",
+		},
+		{
+			name: "smart open bucket not last",
+			mutate: func(c *Config) {
+				c.SMART.Attributes = map[int]SMARTAttrRule{5: {Buckets: []SMARTBucket{{Low: 0}, {Low: 10}}}}
+			},
+			wantErr: "smart.attributes.5.buckets[0]: only the last bucket may omit high",
+		},
+		{
+			name: "smart bucket rate above 1",
+			mutate: func(c *Config) {
+				c.SMART.Attributes = map[int]SMARTAttrRule{5: {Buckets: []SMARTBucket{{Low: 0, Rate: 5}}}}
+			},
+			wantErr: "smart.attributes.5.buckets[0]: rate must be between 0 and 1",
+		},
+		{
+			name:    "smart model invalid match",
+			mutate:  func(c *Config) { c.SMART.Models = []SMARTModelRule{{Match: "(ST"}} },
+			wantErr: "smart.models[0]: invalid match",
+		},
+		{
+			name: "smart model attribute ID out of range",
+			mutate: func(c *Config) {
+				c.SMART.Models = []SMARTModelRule{{Match: "^ST", Attributes: map[int]SMARTAttrRule{0: {}}}}
+			},
+			wantErr: "smart.models[0].attributes: attribute ID 0 must be 1-255",
+		},
 		{
 			name:    "worker_pool_size zero",
 			mutate:  func(c *Config) { c.WorkerPoolSize = 0 },
@@ -419,6 +616,19 @@ pve:
 	assert.Contains(t, err.Error(), "invalid duration")
 }
 
+func TestParseDuration(t *testing.T) {
+	d, err := parseDuration("30d")
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, d)
+
+	d, err = parseDuration("90m")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	_, err = parseDuration("xd")
+	assert.ErrorContains(t, err, "invalid day count")
+}
+
 func TestDuration_MarshalYAML(t *testing.T) {
 	d := Duration{Duration: 5 * time.Minute}
 	v, err := d.MarshalYAML()
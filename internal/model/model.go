@@ -39,6 +39,24 @@ type Node struct {
 	PVEVersion  string     `json:"pveversion"`
 	KernelVer   string     `json:"kversion"`
 	Temperature *float64   `json:"temperature,omitempty"`
+	RRD         *NodeRRD   `json:"rrd,omitempty"` // nil when rrddata is unavailable
+}
+
+// NodeRRD holds the latest node averages from PVE's RRD data.
+type NodeRRD struct {
+	NetIn    float64       `json:"netin"`  // bytes/sec
+	NetOut   float64       `json:"netout"` // bytes/sec
+	Pressure *NodePressure `json:"pressure,omitempty"`
+}
+
+// NodePressure holds Linux pressure stall information (PSI) as the percentage
+// of time tasks were stalled. Reported by PVE 8.4+; nil on older versions.
+type NodePressure struct {
+	CPUSome    float64 `json:"cpu_some"`
+	IOSome     float64 `json:"io_some"`
+	IOFull     float64 `json:"io_full"`
+	MemorySome float64 `json:"memory_some"`
+	MemoryFull float64 `json:"memory_full"`
 }
 
 // Guest represents an LXC container or QEMU VM.
@@ -56,9 +74,18 @@ type Guest struct {
 	MaxMem    int64   `json:"maxmem"`
 	Disk      int64   `json:"disk"`
 	MaxDisk   int64   `json:"maxdisk"`
-	NetIn     int64   `json:"netin"`
-	NetOut    int64   `json:"netout"`
+	NetIn     int64   `json:"netin"`     // cumulative bytes
+	NetOut    int64   `json:"netout"`    // cumulative bytes
+	DiskRead  int64   `json:"diskread"`  // cumulative bytes
+	DiskWrite int64   `json:"diskwrite"` // cumulative bytes
 	Uptime    int64   `json:"uptime"`
+
+	// Throughput in bytes/sec over the last poll interval, derived from the
+	// cumulative counters. Zero on the first poll and after a counter reset.
+	NetInRate     float64 `json:"netin_rate"`
+	NetOutRate    float64 `json:"netout_rate"`
+	DiskReadRate  float64 `json:"diskread_rate"`
+	DiskWriteRate float64 `json:"diskwrite_rate"`
 }
 
 // SMART status bitfield values.
@@ -69,6 +96,7 @@ const (
 	StatusFailedScrutiny = 4
 	StatusUnknown        = 8
 	StatusInternalError  = 16
+	StatusDegrading      = 32 // error counters grew over recent history
 )
 
 // SMARTAttribute represents a single SMART attribute.
@@ -84,6 +112,139 @@ type SMARTAttribute struct {
 	FailureRate *float64 `json:"failure_rate,omitempty"`
 }
 
+// AttributeTrend is the change in a SMART error counter over recent windows.
+// A nil delta means there is no history that far back.
+type AttributeTrend struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Current  int64  `json:"current"`
+	Delta24h *int64 `json:"delta_24h,omitempty"`
+	Delta7d  *int64 `json:"delta_7d,omitempty"`
+	Delta30d *int64 `json:"delta_30d,omitempty"`
+}
+
+// Growing reports whether the counter increased in any window.
+func (t AttributeTrend) Growing() bool {
+	for _, d := range []*int64{t.Delta24h, t.Delta7d, t.Delta30d} {
+		if d != nil && *d > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SelfTest is one entry of a disk's SMART self-test log.
+type SelfTest struct {
+	Type          string `json:"type"`   // "short", "long", "conveyance", "selective", "offline", "vendor"
+	Result        string `json:"result"` // "passed", "failed", "aborted", "in_progress"
+	Status        string `json:"status"` // status text as reported by smartctl
+	LifetimeHours int    `json:"lifetime_hours"`
+	LBAFirstError *int64 `json:"lba_first_error,omitempty"`
+	// Time is when the test ran, estimated from the disk's power-on hours.
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// SelfTestLog is a disk's SMART self-test log. A disk with a nil log had none
+// available (the PVE API omits it); an empty log means no test has been run.
+type SelfTestLog struct {
+	Tests []SelfTest `json:"tests"` // newest first
+}
+
+// Latest returns the newest finished self-test, or nil if there is none.
+// A test still in progress has no outcome yet and is skipped.
+func (l *SelfTestLog) Latest() *SelfTest {
+	for i := range l.Tests {
+		if l.Tests[i].Result != "in_progress" {
+			return &l.Tests[i]
+		}
+	}
+	return nil
+}
+
+// LatestLong returns the newest long (extended) self-test that ran to
+// completion, passed or failed, or nil if none is logged.
+func (l *SelfTestLog) LatestLong() *SelfTest {
+	for i := range l.Tests {
+		if r := l.Tests[i].Result; l.Tests[i].Type == "long" && (r == "passed" || r == "failed") {
+			return &l.Tests[i]
+		}
+	}
+	return nil
+}
+
+// DiskRisk is a disk's estimated probability of failing within a year, in
+// percent, and the factors it combines. Each factor adds an independent
+// chance of failure, so AnnualFailurePct is 1 - Π(1 - factor) rather than
+// their sum.
+type DiskRisk struct {
+	AnnualFailurePct float64      `json:"annual_failure_pct"`
+	Factors          []RiskFactor `json:"factors"` // largest first
+}
+
+// Risk factor sources.
+const (
+	RiskBaseline  = "baseline"  // failure rate of a healthy disk
+	RiskAttribute = "attribute" // attribute failure rate above its healthy bucket
+	RiskAge       = "age"       // power-on hours
+	RiskTrend     = "trend"     // a growing error counter
+	RiskHealth    = "health"    // manufacturer SMART failure or NVMe critical warning
+	RiskSelfTest  = "self_test" // the newest self-test failed
+	RiskWear      = "wear"      // SSD rated endurance nearly or fully used
+)
+
+// RiskFactor is one contribution to a DiskRisk, in percent.
+type RiskFactor struct {
+	Source string  `json:"source"`
+	Name   string  `json:"name"`
+	Detail string  `json:"detail,omitempty"`
+	Pct    float64 `json:"pct"`
+}
+
+// Endurance is the projected write endurance of an SSD. WearPctPerDay is the
+// rate at which rated endurance is being consumed; Basis records how it was
+// derived: "writes" (recent host writes times lifetime wear per TB written),
+// "history" (change in wear over recorded snapshots) or "lifetime" (wear
+// averaged over power-on hours). EndOfLife is nil when wear is not
+// measurably increasing.
+type Endurance struct {
+	WearUsedPct   int        `json:"wear_used_pct"`
+	WrittenTB     *float64   `json:"written_tb,omitempty"`       // lifetime host writes
+	WriteTBPerDay *float64   `json:"write_tb_per_day,omitempty"` // recent host write rate
+	WearPctPerDay float64    `json:"wear_pct_per_day"`
+	Basis         string     `json:"basis,omitempty"`
+	EndOfLife     *time.Time `json:"end_of_life,omitempty"`
+}
+
+// MonthsLeft returns the months until the projected end of life, or false
+// when there is no projection.
+func (e *Endurance) MonthsLeft(now time.Time) (float64, bool) {
+	if e == nil || e.EndOfLife == nil {
+		return 0, false
+	}
+	return max(e.EndOfLife.Sub(now).Hours()/24/daysPerMonth, 0), true
+}
+
+// daysPerMonth is the average length of a Gregorian month.
+const daysPerMonth = 30.44
+
+// AttributeSample is one recorded raw value of a SMART attribute.
+type AttributeSample struct {
+	Timestamp int64 `json:"ts"`
+	ID        int   `json:"id"`
+	RawValue  int64 `json:"raw_value"`
+}
+
+// DiskSnapshot is a disk's SMART state as recorded by one poll.
+type DiskSnapshot struct {
+	Timestamp    int64            `json:"ts"`
+	Health       string           `json:"health"`
+	Status       int              `json:"status"`
+	Temperature  *int             `json:"temperature,omitempty"`
+	PowerOnHours *int             `json:"power_on_hours,omitempty"`
+	Wearout      *int             `json:"wearout,omitempty"`
+	Attributes   []SMARTAttribute `json:"attributes,omitempty"`
+}
+
 // Disk represents a physical disk with SMART data.
 type Disk struct {
 	Instance     string           `json:"instance"`
@@ -101,10 +262,64 @@ type Disk struct {
 	PowerOnHours *int             `json:"power_on_hours,omitempty"`
 	Wearout      *int             `json:"wearout,omitempty"`
 	Attributes   []SMARTAttribute `json:"attributes,omitempty"`
+	Trends       []AttributeTrend `json:"trends,omitempty"`
+	Endurance    *Endurance       `json:"endurance,omitempty"` // SSD/NVMe only
+	SelfTestLog  *SelfTestLog     `json:"self_test_log,omitempty"`
+	Risk         *DiskRisk        `json:"risk,omitempty"` // nil when SMART data could not be read
 	FirstSeen    time.Time        `json:"first_seen"`
 	LastSeen     time.Time        `json:"last_seen"`
 }
 
+// Disk lifecycle event types.
+const (
+	DiskEventAdded    = "added"    // first seen, or back after being removed
+	DiskEventRemoved  = "removed"  // missing from consecutive disk polls of its node
+	DiskEventMoved    = "moved"    // seen on another node or device path
+	DiskEventReplaced = "replaced" // a new disk took the slot of a missing one
+)
+
+// DiskEvent is a change in the disk inventory. A slot is a device path on a
+// node. For moved events the Prev fields hold the disk's old slot; for
+// replaced events they describe the disk that left the slot.
+type DiskEvent struct {
+	Timestamp   int64  `json:"ts"`
+	Type        string `json:"type"`
+	WWN         string `json:"wwn"`
+	Instance    string `json:"instance"`
+	Node        string `json:"node"`
+	DevPath     string `json:"dev_path"`
+	Model       string `json:"model"`
+	Serial      string `json:"serial"`
+	PrevWWN     string `json:"prev_wwn,omitempty"`
+	PrevSerial  string `json:"prev_serial,omitempty"`
+	PrevNode    string `json:"prev_node,omitempty"`
+	PrevDevPath string `json:"prev_dev_path,omitempty"`
+}
+
+// InventoryDisk is a disk glint has seen, whether or not it is still
+// present. Disks stay in the inventory after they disappear so removals and
+// replacements can be reported.
+type InventoryDisk struct {
+	WWN         string     `json:"wwn"`
+	Instance    string     `json:"instance"`
+	Node        string     `json:"node"`
+	DevPath     string     `json:"dev_path"`
+	Model       string     `json:"model"`
+	Serial      string     `json:"serial"`
+	DiskType    string     `json:"disk_type"`
+	SizeBytes   int64      `json:"size_bytes"`
+	FirstSeen   time.Time  `json:"first_seen"`
+	LastSeen    time.Time  `json:"last_seen"`
+	MissedPolls int        `json:"missed_polls"` // consecutive disk polls of its node without it
+	RemovedAt   *time.Time `json:"removed_at,omitempty"`
+	ReplacedBy  string     `json:"replaced_by,omitempty"` // WWN of the disk that took its slot
+}
+
+// Present reports whether the disk was listed in its node's latest disk poll.
+func (d *InventoryDisk) Present() bool {
+	return d.MissedPolls == 0 && d.RemovedAt == nil
+}
+
 // Backup represents a PBS backup snapshot.
 type Backup struct {
 	PBSInstance string `json:"pbs_instance"`
@@ -140,6 +355,176 @@ type DatastoreStatus struct {
 	Error       *string  `json:"error,omitempty"`
 }
 
+// PVEBackupJob is a scheduled vzdump job from /cluster/backup.
+type PVEBackupJob struct {
+	Instance string `json:"instance"`
+	ID       string `json:"id"`
+	Enabled  bool   `json:"enabled"`
+	Schedule string `json:"schedule"`
+	Storage  string `json:"storage"`
+	Mode     string `json:"mode"` // "snapshot", "suspend", "stop"
+	All      bool   `json:"all"`
+	VMIDs    []int  `json:"vmids,omitempty"`
+	Exclude  []int  `json:"exclude,omitempty"`
+	Pool     string `json:"pool,omitempty"`
+	Node     string `json:"node,omitempty"` // restricts the job to one node when set
+	// PoolMembers holds the resolved VMIDs of Pool at collection time.
+	PoolMembers []int `json:"pool_members,omitempty"`
+}
+
+// PVEGuestBackup is the vzdump backup state of a single guest, derived from
+// backup jobs and vzdump task history. It exists for every known guest so
+// that guests without any covering job can be reported.
+type PVEGuestBackup struct {
+	Instance    string   `json:"instance"`
+	ClusterID   string   `json:"cluster_id"`
+	VMID        int      `json:"vmid"`
+	Jobs        []string `json:"jobs,omitempty"`         // IDs of enabled jobs covering this guest
+	LastSuccess int64    `json:"last_success,omitempty"` // unix epoch, 0 if none seen
+	LastFailure int64    `json:"last_failure,omitempty"` // unix epoch, 0 if none seen
+	LastStatus  string   `json:"last_status,omitempty"`  // status of the most recent finished task
+}
+
+// GuestConfig is the configured hardware of a guest, from
+// /nodes/{node}/{type}/{vmid}/config.
+type GuestConfig struct {
+	Instance  string      `json:"instance"`
+	ClusterID string      `json:"cluster_id"`
+	VMID      int         `json:"vmid"`
+	Cores     int         `json:"cores"`
+	Sockets   int         `json:"sockets,omitempty"` // QEMU only
+	Memory    int64       `json:"memory"`            // bytes
+	Swap      int64       `json:"swap,omitempty"`    // bytes, LXC only
+	OSType    string      `json:"os_type,omitempty"`
+	Disks     []GuestDisk `json:"disks,omitempty"`
+	NICs      []GuestNIC  `json:"nics,omitempty"`
+}
+
+// GuestDisk is a configured guest disk or mount point (e.g. "scsi0", "rootfs", "mp0").
+type GuestDisk struct {
+	Key    string `json:"key"`
+	Volume string `json:"volume"` // "local-lvm:vm-100-disk-0"
+	Size   int64  `json:"size"`   // bytes, 0 if not reported
+}
+
+// GuestNIC is a configured guest network interface (e.g. "net0").
+type GuestNIC struct {
+	Key      string `json:"key"`
+	Model    string `json:"model"` // "virtio", "e1000", "veth"
+	MAC      string `json:"mac"`
+	Bridge   string `json:"bridge"`
+	VLAN     int    `json:"vlan,omitempty"`
+	Firewall bool   `json:"firewall"`
+	IP       string `json:"ip,omitempty"` // LXC only: "dhcp" or CIDR
+}
+
+// PVETask represents a PVE cluster task (migration, snapshot, start/stop, ...).
+type PVETask struct {
+	Instance  string `json:"instance"`
+	UPID      string `json:"upid"`
+	Node      string `json:"node"`
+	Type      string `json:"type"` // "qmigrate", "vzsnapshot", "qmstart", "vzdump", ...
+	ID        string `json:"id"`   // VMID, storage or empty
+	User      string `json:"user"`
+	StartTime int64  `json:"start_time"`
+	EndTime   *int64 `json:"end_time,omitempty"`
+	Status    string `json:"status"` // "OK", "WARNINGS: n", error message, "" while running
+}
+
+// CephStatus is the health and capacity summary of a PVE-managed Ceph cluster.
+type CephStatus struct {
+	Instance   string            `json:"instance"`
+	FSID       string            `json:"fsid"`
+	Health     string            `json:"health"` // "HEALTH_OK", "HEALTH_WARN", "HEALTH_ERR"
+	Checks     []CephHealthCheck `json:"checks,omitempty"`
+	Monitors   []CephMonitor     `json:"monitors,omitempty"`
+	NumOSDs    int               `json:"num_osds"`
+	NumUpOSDs  int               `json:"num_up_osds"`
+	NumInOSDs  int               `json:"num_in_osds"`
+	OSDs       []CephOSD         `json:"osds,omitempty"`
+	Pools      []CephPool        `json:"pools,omitempty"`
+	NumPGs     int               `json:"num_pgs"`
+	PGStates   []CephPGState     `json:"pg_states,omitempty"`
+	BytesUsed  int64             `json:"bytes_used"`
+	BytesTotal int64             `json:"bytes_total"`
+}
+
+// CephHealthCheck is a single active Ceph health check (e.g. OSD_DOWN).
+type CephHealthCheck struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"` // "HEALTH_WARN", "HEALTH_ERR"
+	Summary  string `json:"summary"`
+	Muted    bool   `json:"muted"`
+}
+
+// CephMonitor is a Ceph monitor and its quorum membership.
+type CephMonitor struct {
+	Name     string `json:"name"`
+	InQuorum bool   `json:"in_quorum"`
+}
+
+// CephOSD is a single OSD from the CRUSH tree.
+type CephOSD struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Host        string  `json:"host"`
+	Status      string  `json:"status"` // "up", "down"
+	In          bool    `json:"in"`
+	DeviceClass string  `json:"device_class"`
+	UsedPct     float64 `json:"used_pct"` // 0-100
+}
+
+// CephPool is the usage of a single Ceph pool.
+type CephPool struct {
+	Name      string  `json:"name"`
+	Size      int     `json:"size"`
+	MinSize   int     `json:"min_size"`
+	PGNum     int     `json:"pg_num"`
+	UsedBytes int64   `json:"used_bytes"`
+	UsedPct   float64 `json:"used_pct"` // 0-100
+}
+
+// CephPGState is the number of placement groups in a given state.
+type CephPGState struct {
+	State string `json:"state"` // e.g. "active+clean"
+	Count int    `json:"count"`
+}
+
+// ClusterStatus is the corosync quorum and HA manager state of a PVE cluster.
+// Votes are the configured quorum_votes of each node plus the QDevice vote;
+// present votes count online nodes and a connected QDevice.
+type ClusterStatus struct {
+	Instance      string        `json:"instance"`
+	Name          string        `json:"name"`
+	Quorate       bool          `json:"quorate"`
+	ExpectedVotes int           `json:"expected_votes"`
+	PresentVotes  int           `json:"present_votes"`
+	Nodes         []ClusterNode `json:"nodes,omitempty"`
+	HAMaster      string        `json:"ha_master,omitempty"` // node running the active CRM
+	HAStatus      string        `json:"ha_status,omitempty"` // CRM status line, e.g. "pve1 (active, Mon Jan 1 ...)"
+	HAResources   []HAResource  `json:"ha_resources,omitempty"`
+}
+
+// ClusterNode is a cluster member as reported by /cluster/status.
+type ClusterNode struct {
+	Name   string `json:"name"`
+	NodeID int    `json:"nodeid"`
+	IP     string `json:"ip"`
+	Online bool   `json:"online"`
+	Local  bool   `json:"local"`
+	Votes  int    `json:"votes"` // quorum_votes from the corosync config
+}
+
+// HAResource is an HA-managed guest with its configured and current state.
+type HAResource struct {
+	SID            string `json:"sid"`             // e.g. "vm:100", "ct:101"
+	Type           string `json:"type"`            // "vm", "ct"
+	RequestedState string `json:"requested_state"` // from resource config: "started", "stopped", "ignored", "disabled"
+	State          string `json:"state"`           // CRM state: "started", "error", "fence", "recovery", ...
+	Node           string `json:"node"`
+	Group          string `json:"group,omitempty"`
+}
+
 // NodeSnapshot is a time-series record of node metrics.
 type NodeSnapshot struct {
 	Timestamp  int64    `json:"ts"`
@@ -158,6 +543,15 @@ type NodeSnapshot struct {
 	IOWait     float64  `json:"io_wait"`
 	UptimeSecs int64    `json:"uptime_secs"`
 	CPUTemp    *float64 `json:"cpu_temp,omitempty"`
+
+	// From rrddata; nil when unavailable.
+	NetIn      *float64 `json:"net_in_rate,omitempty"`
+	NetOut     *float64 `json:"net_out_rate,omitempty"`
+	PSICPU     *float64 `json:"psi_cpu_some,omitempty"`
+	PSIIO      *float64 `json:"psi_io_some,omitempty"`
+	PSIIOFull  *float64 `json:"psi_io_full,omitempty"`
+	PSIMem     *float64 `json:"psi_mem_some,omitempty"`
+	PSIMemFull *float64 `json:"psi_mem_full,omitempty"`
 }
 
 // GuestSnapshot is a time-series record of guest metrics.
@@ -178,6 +572,15 @@ type GuestSnapshot struct {
 	DiskTotal int64   `json:"disk_total"`
 	NetIn     int64   `json:"net_in"`
 	NetOut    int64   `json:"net_out"`
+	DiskRead  int64   `json:"disk_read"`
+	DiskWrite int64   `json:"disk_write"`
+
+	// Rates in bytes/sec; nil when no rate could be derived (first sample
+	// or counter reset).
+	NetInRate     *float64 `json:"net_in_rate"`
+	NetOutRate    *float64 `json:"net_out_rate"`
+	DiskReadRate  *float64 `json:"disk_read_rate"`
+	DiskWriteRate *float64 `json:"disk_write_rate"`
 }
 
 // SparklinePoint is a single data point for sparkline rendering.
@@ -0,0 +1,86 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/darshan-rambhia/glint/internal/store"
+)
+
+// InfluxSink writes snapshots as InfluxDB line protocol. The URL is the full
+// write endpoint, e.g. http://influxdb:8086/api/v2/write?org=home&bucket=glint
+// or VictoriaMetrics' http://victoria:8428/write.
+type InfluxSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewInflux creates a sink that posts line protocol to url. Headers are sent
+// with every request, e.g. Authorization: Token xxx.
+func NewInflux(url string, headers map[string]string) *InfluxSink {
+	return &InfluxSink{url: url, headers: headers, client: newClient()}
+}
+
+func (s *InfluxSink) Name() string { return "influx" }
+
+func (s *InfluxSink) Write(ctx context.Context, snaps *store.Snapshots) error {
+	body := encodeLineProtocol(points(snaps))
+	if len(body) == 0 {
+		return nil
+	}
+	headers := map[string]string{"Content-Type": "text/plain; charset=utf-8"}
+	for k, v := range s.headers {
+		headers[k] = v
+	}
+	if err := send(ctx, s.client, s.url, body, headers); err != nil {
+		return fmt.Errorf("influx: %w", err)
+	}
+	return nil
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+// encodeLineProtocol renders one line per point with a nanosecond timestamp,
+// the default precision of both InfluxDB and VictoriaMetrics. Measurements
+// are prefixed with "glint_"; empty tags are omitted because line protocol
+// does not allow empty tag values.
+func encodeLineProtocol(pts []point) []byte {
+	var buf bytes.Buffer
+	for _, p := range pts {
+		if len(p.fields) == 0 {
+			continue
+		}
+		buf.WriteString(measurementEscaper.Replace("glint_" + p.measurement))
+		for _, t := range p.tags {
+			if t.value == "" {
+				continue
+			}
+			buf.WriteByte(',')
+			buf.WriteString(tagEscaper.Replace(t.key))
+			buf.WriteByte('=')
+			buf.WriteString(tagEscaper.Replace(t.value))
+		}
+		for i, f := range p.fields {
+			if i == 0 {
+				buf.WriteByte(' ')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(tagEscaper.Replace(f.name))
+			buf.WriteByte('=')
+			buf.WriteString(strconv.FormatFloat(f.value, 'f', -1, 64))
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatInt(p.ts*1e9, 10))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
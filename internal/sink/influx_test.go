@@ -0,0 +1,111 @@
+package sink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/darshan-rambhia/glint/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// testSnapshots returns one snapshot of each kind at ts 1700000000.
+func testSnapshots() *store.Snapshots {
+	const ts = 1700000000
+	return &store.Snapshots{
+		Nodes: []model.NodeSnapshot{{
+			Timestamp: ts, Instance: "main", Node: "pve1", CPUPct: 12.5,
+			MemUsed: 4096, MemTotal: 8192, NetIn: ptr(1500.0),
+		}},
+		Guests: []model.GuestSnapshot{{
+			Timestamp: ts, Instance: "main", VMID: 101, Node: "pve1", GuestType: "lxc",
+			Name: "web server", Status: "running", CPUPct: 3, NetInRate: ptr(250.0),
+		}},
+		SMART: []store.SMARTSnapshot{{Timestamp: ts, Disk: &model.Disk{
+			Instance: "main", Node: "pve1", WWN: "0x5000c500a1b2c3d4", DevPath: "/dev/sda",
+			Model: "ST4000VN008", Serial: "ZDH1", DiskType: "hdd", Health: "PASSED", Temperature: ptr(38),
+		}}},
+		Backups: []store.BackupSnapshot{{Timestamp: ts, Backup: &model.Backup{
+			PBSInstance: "pbs", Datastore: "store1", BackupType: "ct", BackupID: "101",
+			BackupTime: ts - 3600, SizeBytes: ptr(int64(2048)), Verified: ptr(true),
+		}}},
+		Datastores: []store.DatastoreSnapshot{{Timestamp: ts, Datastore: &model.DatastoreStatus{
+			PBSInstance: "pbs", Name: "store1", TotalBytes: ptr(int64(1000)), UsedBytes: ptr(int64(400)),
+		}}},
+	}
+}
+
+func TestInfluxName(t *testing.T) {
+	assert.Equal(t, "influx", NewInflux("http://localhost/write", nil).Name())
+}
+
+func TestInfluxWrite(t *testing.T) {
+	var body, contentType, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewInflux(srv.URL+"/write", map[string]string{"Authorization": "Token abc"})
+	require.NoError(t, s.Write(context.Background(), testSnapshots()))
+
+	assert.Equal(t, "text/plain; charset=utf-8", contentType)
+	assert.Equal(t, "Token abc", auth)
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	require.Len(t, lines, 7)
+	assert.Equal(t, "glint_node,instance=main,node=pve1 cpu_pct=12.5,mem_used=4096,mem_total=8192,"+
+		"swap_used=0,swap_total=0,rootfs_used=0,rootfs_total=0,load_1m=0,load_5m=0,load_15m=0,"+
+		"io_wait=0,uptime_secs=0,net_in_rate=1500 1700000000000000000", lines[0])
+	assert.Equal(t, `glint_guest,guest_type=lxc,instance=main,name=web\ server,node=pve1,vmid=101 info=1 1700000000000000000`, lines[1])
+	assert.Contains(t, lines[2], `glint_guest,guest_type=lxc,instance=main,node=pve1,vmid=101 running=1,cpu_pct=3,`)
+	assert.Contains(t, lines[2], "net_in_rate=250 ")
+	assert.Equal(t, "glint_disk,dev_path=/dev/sda,disk_type=hdd,instance=main,model=ST4000VN008,node=pve1,serial=ZDH1,wwn=0x5000c500a1b2c3d4 info=1 1700000000000000000", lines[3])
+	assert.Contains(t, lines[4], "glint_disk,instance=main,node=pve1,wwn=0x5000c500a1b2c3d4 health_passed=1,status=0,size_bytes=0,temperature=38 ")
+	assert.Equal(t, "glint_backup,backup_id=101,backup_type=ct,datastore=store1,pbs_instance=pbs backup_time=1699996400,size_bytes=2048,verified=1 1700000000000000000", lines[5])
+	assert.Equal(t, "glint_datastore,datastore=store1,pbs_instance=pbs total_bytes=1000,used_bytes=400 1700000000000000000", lines[6])
+}
+
+func TestInfluxWrite_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "partial write: field type conflict", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := NewInflux(srv.URL, nil).Write(context.Background(), testSnapshots())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+	assert.Contains(t, err.Error(), "field type conflict")
+}
+
+func TestInfluxWrite_Empty(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	require.NoError(t, NewInflux(srv.URL, nil).Write(context.Background(), &store.Snapshots{}))
+	assert.False(t, called)
+}
+
+func TestEncodeLineProtocol_Escaping(t *testing.T) {
+	got := encodeLineProtocol([]point{{
+		measurement: "guest",
+		tags:        []tag{{"name", "a,b=c d"}, {"node", ""}},
+		fields:      []field{{"cpu_pct", 1}},
+		ts:          1,
+	}})
+	assert.Equal(t, `glint_guest,name=a\,b\=c\ d cpu_pct=1 1000000000`+"\n", string(got))
+}
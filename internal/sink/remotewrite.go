@@ -0,0 +1,141 @@
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/darshan-rambhia/glint/internal/store"
+)
+
+// RemoteWriteSink writes snapshots using the Prometheus remote-write protocol
+// (v1), accepted by Prometheus (--web.enable-remote-write-receiver),
+// VictoriaMetrics (/api/v1/write), Mimir, Thanos and others.
+//
+// Each point field becomes a series named glint_<measurement>_<field>,
+// e.g. glint_node_cpu_pct{instance="main",node="pve1"}.
+type RemoteWriteSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewRemoteWrite creates a sink that posts remote-write requests to url.
+// Headers are sent with every request, e.g. Authorization: Bearer xxx.
+func NewRemoteWrite(url string, headers map[string]string) *RemoteWriteSink {
+	return &RemoteWriteSink{url: url, headers: headers, client: newClient()}
+}
+
+func (s *RemoteWriteSink) Name() string { return "remote_write" }
+
+func (s *RemoteWriteSink) Write(ctx context.Context, snaps *store.Snapshots) error {
+	req := encodeWriteRequest(points(snaps))
+	if len(req) == 0 {
+		return nil
+	}
+	headers := map[string]string{
+		"Content-Type":                      "application/x-protobuf",
+		"Content-Encoding":                  "snappy",
+		"X-Prometheus-Remote-Write-Version": "0.1.0",
+	}
+	for k, v := range s.headers {
+		headers[k] = v
+	}
+	if err := send(ctx, s.client, s.url, snappyEncode(req), headers); err != nil {
+		return fmt.Errorf("remote_write: %w", err)
+	}
+	return nil
+}
+
+// Protobuf field numbers and wire types of prometheus.WriteRequest. The
+// message is small enough that encoding it by hand avoids pulling in the
+// Prometheus and protobuf modules.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+
+	writeRequestTimeseries = 1 // repeated TimeSeries
+	timeSeriesLabels       = 1 // repeated Label
+	timeSeriesSamples      = 2 // repeated Sample
+	labelName              = 1 // string
+	labelValue             = 2 // string
+	sampleValue            = 1 // double
+	sampleTimestamp        = 2 // int64, milliseconds
+)
+
+// encodeWriteRequest renders points as a serialized prometheus.WriteRequest
+// with one single-sample series per field. Labels are sorted by name as the
+// protocol requires; empty tags are omitted.
+func encodeWriteRequest(pts []point) []byte {
+	var req []byte
+	for _, p := range pts {
+		labels := make([]tag, 0, len(p.tags)+1)
+		for _, t := range p.tags {
+			if t.value != "" {
+				labels = append(labels, t)
+			}
+		}
+		for _, f := range p.fields {
+			series := append(labels[:len(labels):len(labels)], tag{"__name__", "glint_" + p.measurement + "_" + f.name})
+			sort.Slice(series, func(i, j int) bool { return series[i].key < series[j].key })
+
+			var ts []byte
+			for _, l := range series {
+				var label []byte
+				label = appendString(label, labelName, l.key)
+				label = appendString(label, labelValue, l.value)
+				ts = appendBytes(ts, timeSeriesLabels, label)
+			}
+			var sample []byte
+			sample = appendTag(sample, sampleValue, wireFixed64)
+			sample = binary.LittleEndian.AppendUint64(sample, math.Float64bits(f.value))
+			sample = appendTag(sample, sampleTimestamp, wireVarint)
+			sample = binary.AppendUvarint(sample, uint64(p.ts*1000))
+			ts = appendBytes(ts, timeSeriesSamples, sample)
+
+			req = appendBytes(req, writeRequestTimeseries, ts)
+		}
+	}
+	return req
+}
+
+func appendTag(b []byte, num, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(num<<3|wire))
+}
+
+func appendBytes(b []byte, num int, v []byte) []byte {
+	b = appendTag(b, num, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendString(b []byte, num int, v string) []byte {
+	return appendBytes(b, num, []byte(v))
+}
+
+// snappyEncode wraps src in the snappy block format that remote write
+// requires. It emits literal chunks only: the output is valid snappy that any
+// decoder accepts, just not compressed. Payloads per poll are small, so this
+// trades bandwidth for not depending on a compression library.
+func snappyEncode(src []byte) []byte {
+	const maxLiteral = 1 << 16
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)+len(src)/maxLiteral*3+8), uint64(len(src)))
+	for len(src) > 0 {
+		n := min(len(src), maxLiteral)
+		switch l := n - 1; {
+		case l < 60:
+			dst = append(dst, byte(l)<<2)
+		case l < 1<<8:
+			dst = append(dst, 60<<2, byte(l))
+		default:
+			dst = append(dst, 61<<2, byte(l), byte(l>>8))
+		}
+		dst = append(dst, src[:n]...)
+		src = src[n:]
+	}
+	return dst
+}
@@ -0,0 +1,222 @@
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// series is a decoded remote-write time series with a single sample.
+type series struct {
+	labels map[string]string
+	value  float64
+	tsMs   int64
+}
+
+// snappyDecode decodes the snappy block format, supporting the literal
+// chunks snappyEncode emits.
+func snappyDecode(src []byte) ([]byte, error) {
+	n, k := binary.Uvarint(src)
+	if k <= 0 {
+		return nil, errors.New("bad length")
+	}
+	src = src[k:]
+	var dst []byte
+	for len(src) > 0 {
+		tag := src[0]
+		if tag&3 != 0 {
+			return nil, fmt.Errorf("unexpected copy tag %#x", tag)
+		}
+		l, hdr := int(tag>>2), 1
+		switch l {
+		case 60:
+			l, hdr = int(src[1]), 2
+		case 61:
+			l, hdr = int(src[1])|int(src[2])<<8, 3
+		}
+		l++
+		dst = append(dst, src[hdr:hdr+l]...)
+		src = src[hdr+l:]
+	}
+	if uint64(len(dst)) != n {
+		return nil, fmt.Errorf("decoded %d bytes, header says %d", len(dst), n)
+	}
+	return dst, nil
+}
+
+// protoFields splits a protobuf message into (field number, payload) pairs.
+// Varint payloads are returned as their 8-byte little-endian value.
+func protoFields(t *testing.T, b []byte) [][2]any {
+	t.Helper()
+	var out [][2]any
+	for len(b) > 0 {
+		key, k := binary.Uvarint(b)
+		require.Positive(t, k)
+		b = b[k:]
+		num := int(key >> 3)
+		switch key & 7 {
+		case wireVarint:
+			v, k := binary.Uvarint(b)
+			require.Positive(t, k)
+			out = append(out, [2]any{num, binary.LittleEndian.AppendUint64(nil, v)})
+			b = b[k:]
+		case wireFixed64:
+			out = append(out, [2]any{num, b[:8]})
+			b = b[8:]
+		case wireBytes:
+			l, k := binary.Uvarint(b)
+			require.Positive(t, k)
+			out = append(out, [2]any{num, b[k : k+int(l)]})
+			b = b[k+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+	}
+	return out
+}
+
+func decodeWriteRequest(t *testing.T, b []byte) []series {
+	t.Helper()
+	var out []series
+	for _, ts := range protoFields(t, b) {
+		require.Equal(t, writeRequestTimeseries, ts[0])
+		s := series{labels: map[string]string{}}
+		var prev string
+		for _, f := range protoFields(t, ts[1].([]byte)) {
+			switch f[0] {
+			case timeSeriesLabels:
+				var name, value string
+				for _, lf := range protoFields(t, f[1].([]byte)) {
+					if lf[0] == labelName {
+						name = string(lf[1].([]byte))
+					} else {
+						value = string(lf[1].([]byte))
+					}
+				}
+				assert.Less(t, prev, name, "labels must be sorted")
+				prev = name
+				s.labels[name] = value
+			case timeSeriesSamples:
+				for _, sf := range protoFields(t, f[1].([]byte)) {
+					v := binary.LittleEndian.Uint64(sf[1].([]byte))
+					if sf[0] == sampleValue {
+						s.value = math.Float64frombits(v)
+					} else {
+						s.tsMs = int64(v)
+					}
+				}
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func findSeries(all []series, name string) *series {
+	for i := range all {
+		if all[i].labels["__name__"] == name {
+			return &all[i]
+		}
+	}
+	return nil
+}
+
+func TestRemoteWriteName(t *testing.T) {
+	assert.Equal(t, "remote_write", NewRemoteWrite("http://localhost/api/v1/write", nil).Name())
+}
+
+func TestRemoteWriteWrite(t *testing.T) {
+	var got []series
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		b, _ := io.ReadAll(r.Body)
+		raw, err := snappyDecode(b)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = decodeWriteRequest(t, raw)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewRemoteWrite(srv.URL+"/api/v1/write", map[string]string{"Authorization": "Bearer tok"})
+	require.NoError(t, s.Write(context.Background(), testSnapshots()))
+
+	assert.Equal(t, "application/x-protobuf", headers.Get("Content-Type"))
+	assert.Equal(t, "snappy", headers.Get("Content-Encoding"))
+	assert.Equal(t, "0.1.0", headers.Get("X-Prometheus-Remote-Write-Version"))
+	assert.Equal(t, "Bearer tok", headers.Get("Authorization"))
+
+	cpu := findSeries(got, "glint_node_cpu_pct")
+	require.NotNil(t, cpu)
+	assert.Equal(t, map[string]string{"__name__": "glint_node_cpu_pct", "instance": "main", "node": "pve1"}, cpu.labels)
+	assert.Equal(t, 12.5, cpu.value)
+	assert.Equal(t, int64(1700000000000), cpu.tsMs)
+
+	net := findSeries(got, "glint_guest_net_in_rate")
+	require.NotNil(t, net)
+	assert.Equal(t, map[string]string{
+		"__name__": "glint_guest_net_in_rate", "guest_type": "lxc", "instance": "main", "node": "pve1", "vmid": "101",
+	}, net.labels, "name and status are not labels")
+	assert.Equal(t, 250.0, net.value)
+
+	running := findSeries(got, "glint_guest_running")
+	require.NotNil(t, running)
+	assert.Equal(t, 1.0, running.value)
+
+	info := findSeries(got, "glint_guest_info")
+	require.NotNil(t, info)
+	assert.Equal(t, "web server", info.labels["name"])
+	assert.Equal(t, 1.0, info.value)
+
+	temp := findSeries(got, "glint_disk_temperature")
+	require.NotNil(t, temp)
+	assert.Equal(t, map[string]string{
+		"__name__": "glint_disk_temperature", "instance": "main", "node": "pve1", "wwn": "0x5000c500a1b2c3d4",
+	}, temp.labels, "device path, model and serial are not labels")
+	assert.Equal(t, 38.0, temp.value)
+
+	diskInfo := findSeries(got, "glint_disk_info")
+	require.NotNil(t, diskInfo)
+	assert.Equal(t, "/dev/sda", diskInfo.labels["dev_path"])
+	assert.Equal(t, "ZDH1", diskInfo.labels["serial"])
+
+	assert.NotNil(t, findSeries(got, "glint_backup_verified"))
+	assert.NotNil(t, findSeries(got, "glint_datastore_used_bytes"))
+	assert.Nil(t, findSeries(got, "glint_node_cpu_temp"), "nil values are omitted")
+}
+
+func TestRemoteWriteWrite_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := NewRemoteWrite(srv.URL, nil).Write(context.Background(), testSnapshots())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remote_write")
+	assert.Contains(t, err.Error(), "out of order sample")
+}
+
+func TestSnappyEncode_RoundTrip(t *testing.T) {
+	for _, n := range []int{0, 1, 59, 60, 61, 255, 256, 257, 70000, 200000} {
+		src := make([]byte, n)
+		for i := range src {
+			src[i] = byte(i * 7)
+		}
+		got, err := snappyDecode(snappyEncode(src))
+		require.NoError(t, err, "n=%d", n)
+		assert.Equal(t, src, append([]byte{}, got...), "n=%d", n)
+	}
+}
@@ -0,0 +1,235 @@
+// Package sink forwards Glint snapshots to external time-series databases.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/store"
+)
+
+// tag is a series label, e.g. instance="main".
+type tag struct {
+	key, value string
+}
+
+// field is a single numeric value of a point.
+type field struct {
+	name  string
+	value float64
+}
+
+// point is one snapshot flattened into a measurement with tags and numeric
+// fields. Both wire formats are rendered from points so they carry the same
+// series.
+type point struct {
+	measurement string
+	tags        []tag // sorted by key
+	fields      []field
+	ts          int64 // unix seconds
+}
+
+// points flattens snapshots into points. Nil optional values are left out.
+func points(s *store.Snapshots) []point {
+	pts := make([]point, 0, s.Len())
+	for _, n := range s.Nodes {
+		p := point{
+			measurement: "node",
+			tags:        []tag{{"instance", n.Instance}, {"node", n.Node}},
+			ts:          n.Timestamp,
+		}
+		p.add("cpu_pct", n.CPUPct)
+		p.add("mem_used", float64(n.MemUsed))
+		p.add("mem_total", float64(n.MemTotal))
+		p.add("swap_used", float64(n.SwapUsed))
+		p.add("swap_total", float64(n.SwapTotal))
+		p.add("rootfs_used", float64(n.RootUsed))
+		p.add("rootfs_total", float64(n.RootTotal))
+		p.add("load_1m", n.Load1m)
+		p.add("load_5m", n.Load5m)
+		p.add("load_15m", n.Load15m)
+		p.add("io_wait", n.IOWait)
+		p.add("uptime_secs", float64(n.UptimeSecs))
+		p.addOpt("cpu_temp", n.CPUTemp)
+		p.addOpt("net_in_rate", n.NetIn)
+		p.addOpt("net_out_rate", n.NetOut)
+		p.addOpt("psi_cpu_some", n.PSICPU)
+		p.addOpt("psi_io_some", n.PSIIO)
+		p.addOpt("psi_io_full", n.PSIIOFull)
+		p.addOpt("psi_mem_some", n.PSIMem)
+		p.addOpt("psi_mem_full", n.PSIMemFull)
+		pts = append(pts, p)
+	}
+	for _, g := range s.Guests {
+		// Guest series carry only identity tags, so a start, stop or rename
+		// does not begin a new series. Status is a field and the name goes
+		// into a separate info point.
+		vmid := strconv.Itoa(g.VMID)
+		pts = append(pts, point{
+			measurement: "guest",
+			tags: []tag{
+				{"guest_type", g.GuestType}, {"instance", g.Instance}, {"name", g.Name},
+				{"node", g.Node}, {"vmid", vmid},
+			},
+			fields: []field{{"info", 1}},
+			ts:     g.Timestamp,
+		})
+		p := point{
+			measurement: "guest",
+			tags: []tag{
+				{"guest_type", g.GuestType}, {"instance", g.Instance}, {"node", g.Node}, {"vmid", vmid},
+			},
+			ts: g.Timestamp,
+		}
+		running := 0.0
+		if g.Status == "running" {
+			running = 1
+		}
+		p.add("running", running)
+		p.add("cpu_pct", g.CPUPct)
+		p.add("cpus", float64(g.CPUs))
+		p.add("mem_used", float64(g.MemUsed))
+		p.add("mem_total", float64(g.MemTotal))
+		p.add("disk_used", float64(g.DiskUsed))
+		p.add("disk_total", float64(g.DiskTotal))
+		p.add("net_in", float64(g.NetIn))
+		p.add("net_out", float64(g.NetOut))
+		p.add("disk_read", float64(g.DiskRead))
+		p.add("disk_write", float64(g.DiskWrite))
+		p.addOpt("net_in_rate", g.NetInRate)
+		p.addOpt("net_out_rate", g.NetOutRate)
+		p.addOpt("disk_read_rate", g.DiskReadRate)
+		p.addOpt("disk_write_rate", g.DiskWriteRate)
+		pts = append(pts, p)
+	}
+	for _, sm := range s.SMART {
+		// Disk series are keyed by WWN, so a device path that moves after a
+		// reboot does not begin a new series. The path, type, model and
+		// serial go into a separate info point.
+		d := sm.Disk
+		pts = append(pts, point{
+			measurement: "disk",
+			tags: []tag{
+				{"dev_path", d.DevPath}, {"disk_type", d.DiskType}, {"instance", d.Instance},
+				{"model", d.Model}, {"node", d.Node}, {"serial", d.Serial}, {"wwn", d.WWN},
+			},
+			fields: []field{{"info", 1}},
+			ts:     sm.Timestamp,
+		})
+		p := point{
+			measurement: "disk",
+			tags:        []tag{{"instance", d.Instance}, {"node", d.Node}, {"wwn", d.WWN}},
+			ts:          sm.Timestamp,
+		}
+		passed := 0.0
+		if d.Health == "PASSED" {
+			passed = 1
+		}
+		p.add("health_passed", passed)
+		p.add("status", float64(d.Status))
+		p.add("size_bytes", float64(d.SizeBytes))
+		p.addOptInt("temperature", d.Temperature)
+		p.addOptInt("power_on_hours", d.PowerOnHours)
+		p.addOptInt("wearout", d.Wearout)
+		pts = append(pts, p)
+	}
+	for _, bs := range s.Backups {
+		b := bs.Backup
+		p := point{
+			measurement: "backup",
+			tags: []tag{
+				{"backup_id", b.BackupID}, {"backup_type", b.BackupType},
+				{"datastore", b.Datastore}, {"pbs_instance", b.PBSInstance},
+			},
+			ts: bs.Timestamp,
+		}
+		p.add("backup_time", float64(b.BackupTime))
+		if b.SizeBytes != nil {
+			p.add("size_bytes", float64(*b.SizeBytes))
+		}
+		if b.Verified != nil {
+			verified := 0.0
+			if *b.Verified {
+				verified = 1
+			}
+			p.add("verified", verified)
+		}
+		pts = append(pts, p)
+	}
+	for _, dss := range s.Datastores {
+		ds := dss.Datastore
+		p := point{
+			measurement: "datastore",
+			tags:        []tag{{"datastore", ds.Name}, {"pbs_instance", ds.PBSInstance}},
+			ts:          dss.Timestamp,
+		}
+		if ds.TotalBytes != nil {
+			p.add("total_bytes", float64(*ds.TotalBytes))
+		}
+		if ds.UsedBytes != nil {
+			p.add("used_bytes", float64(*ds.UsedBytes))
+		}
+		if ds.AvailBytes != nil {
+			p.add("avail_bytes", float64(*ds.AvailBytes))
+		}
+		p.addOpt("dedup_ratio", ds.DedupRatio)
+		pts = append(pts, p)
+	}
+	return pts
+}
+
+// add appends a field. Non-finite values are dropped since neither wire
+// format can carry them reliably.
+func (p *point) add(name string, v float64) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+	p.fields = append(p.fields, field{name: name, value: v})
+}
+
+func (p *point) addOpt(name string, v *float64) {
+	if v != nil {
+		p.add(name, *v)
+	}
+}
+
+func (p *point) addOptInt(name string, v *int) {
+	if v != nil {
+		p.add(name, float64(*v))
+	}
+}
+
+// send posts body to url with the given headers and treats any non-2xx
+// status as an error, quoting the start of the response body.
+func send(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// newClient returns the HTTP client used by each sink.
+func newClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
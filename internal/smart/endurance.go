@@ -0,0 +1,155 @@
+package smart
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// ATATotalLBAsWritten is the ATA attribute most SSDs count host writes in.
+const ATATotalLBAsWritten = 241
+
+const (
+	// nvmeDataUnit is the size of an NVMe "data unit": 1000 512-byte blocks.
+	nvmeDataUnit = 512 * 1000
+
+	// minWriteSpan is the shortest history a write rate is computed from.
+	minWriteSpan = 24 * time.Hour
+	// minWearSpan is the shortest history a wear rate is computed from. Wear
+	// is reported in whole percent, so short spans mostly measure rounding.
+	minWearSpan = 7 * 24 * time.Hour
+	// maxProjection caps the projected life; slower wear counts as none.
+	maxProjection = 50 * 365 * 24 * time.Hour
+)
+
+// ProjectEndurance estimates how fast an SSD is consuming its rated write
+// endurance and when it will be used up. wear is the oldest recorded wearout
+// (percent remaining), nil if none; written is the history of the host
+// writes attribute (see HostWritesAttribute), oldest first. Returns nil for
+// disks without a wear indicator (HDDs, SSDs that do not report one).
+//
+// The wear rate comes from the most precise source available:
+//  1. Recent host writes (TB/day) times lifetime wear per TB written.
+//     This tracks workload changes within a day, and the wear per TB folds
+//     in the drive's write amplification.
+//  2. The change in wear across the recorded history (at least a week).
+//  3. Wear used averaged over the drive's power-on hours.
+func ProjectEndurance(disk *model.Disk, wear *model.SparklinePoint, written []model.AttributeSample, now time.Time) *model.Endurance {
+	if disk.Wearout == nil || disk.DiskType == "hdd" {
+		return nil
+	}
+	used := min(max(100-*disk.Wearout, 0), 100)
+	e := &model.Endurance{WearUsedPct: used}
+
+	if id, unit, ok := HostWritesAttribute(disk); ok {
+		units, _ := rawValue(disk, id)
+		tb := float64(units) * unit / 1e12
+		e.WrittenTB = &tb
+		if len(written) > 0 {
+			oldest := written[0]
+			span := now.Sub(time.Unix(oldest.Timestamp, 0))
+			if span >= minWriteSpan && units >= oldest.RawValue {
+				rate := float64(units-oldest.RawValue) * unit / 1e12 / days(span)
+				e.WriteTBPerDay = &rate
+			}
+		}
+	}
+
+	var wearSpan time.Duration
+	var wearDelta float64
+	if wear != nil {
+		wearSpan = now.Sub(time.Unix(wear.Timestamp, 0))
+		wearDelta = wear.Value - float64(*disk.Wearout)
+	}
+
+	switch {
+	case e.WriteTBPerDay != nil && *e.WrittenTB > 0 && used > 0:
+		e.WearPctPerDay = float64(used) / *e.WrittenTB * *e.WriteTBPerDay
+		e.Basis = "writes"
+	case wearSpan >= minWearSpan && wearDelta > 0:
+		e.WearPctPerDay = wearDelta / days(wearSpan)
+		e.Basis = "history"
+	case disk.PowerOnHours != nil && *disk.PowerOnHours >= int(minWearSpan.Hours()) && used > 0:
+		e.WearPctPerDay = float64(used) / (float64(*disk.PowerOnHours) / 24)
+		e.Basis = "lifetime"
+	}
+
+	if used >= 100 {
+		eol := now
+		e.EndOfLife = &eol
+	} else if e.WearPctPerDay > 0 {
+		left := float64(100-used) / e.WearPctPerDay * 24 * float64(time.Hour)
+		if left < float64(maxProjection) {
+			eol := now.Add(time.Duration(left))
+			e.EndOfLife = &eol
+		}
+	}
+	return e
+}
+
+// HostWritesAttribute returns the ID of the attribute that counts the disk's
+// host writes and the bytes each raw unit stands for. NVMe drives report Data
+// Units Written; ATA SSDs report 241, or a vendor attribute such as Crucial's
+// 246, whose unit follows from the attribute name (LBAs, 32 MiB or GiB).
+func HostWritesAttribute(disk *model.Disk) (id int, unit float64, ok bool) {
+	switch disk.Protocol {
+	case "nvme":
+		if _, ok := rawValue(disk, NVMeDataUnitsWritten); ok {
+			return NVMeDataUnitsWritten, nvmeDataUnit, true
+		}
+	case "ata":
+		var ids []int
+		if v, ok := LookupVendor(disk.Model); ok {
+			for id, va := range v.Attributes {
+				if va.Writes {
+					ids = append(ids, id)
+				}
+			}
+			slices.Sort(ids)
+		}
+		ids = append(ids, ATATotalLBAsWritten)
+		for _, id := range ids {
+			for _, a := range disk.Attributes {
+				if a.ID != id {
+					continue
+				}
+				if unit, ok := ataWriteUnit(a.Name); ok {
+					return id, unit, true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// ataWriteUnit returns the bytes per raw unit of a host writes attribute,
+// judged by its smartctl name.
+func ataWriteUnit(name string) (float64, bool) {
+	n := strings.ToLower(name)
+	switch {
+	case strings.Contains(n, "32mib"):
+		return 32 << 20, true
+	case strings.Contains(n, "gib"):
+		return 1 << 30, true
+	case strings.Contains(n, "lba"), strings.Contains(n, "sector"):
+		return 512, true
+	default:
+		return 0, false
+	}
+}
+
+func days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
+
+// rawValue returns the raw value of the disk's attribute with the given ID.
+func rawValue(disk *model.Disk, id int) (int64, bool) {
+	for _, a := range disk.Attributes {
+		if a.ID == id {
+			return a.RawValue, true
+		}
+	}
+	return 0, false
+}
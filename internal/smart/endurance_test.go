@@ -0,0 +1,174 @@
+package smart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectEndurance_NVMeWrites(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	// 20% used after 200 TB written (lifetime 0.1% per TB); 1 TB written
+	// in the last 10 days.
+	const tb = 1e12 / nvmeDataUnit
+	disk := &model.Disk{
+		DiskType: "nvme", Protocol: "nvme", Wearout: new(80),
+		Attributes: []model.SMARTAttribute{{ID: NVMeDataUnitsWritten, RawValue: 200 * tb}},
+	}
+	written := []model.AttributeSample{
+		{Timestamp: now.Add(-10 * 24 * time.Hour).Unix(), ID: NVMeDataUnitsWritten, RawValue: 199 * tb},
+		{Timestamp: now.Add(-time.Hour).Unix(), ID: NVMeDataUnitsWritten, RawValue: 200 * tb},
+	}
+
+	e := ProjectEndurance(disk, nil, written, now)
+	require.NotNil(t, e)
+	assert.Equal(t, 20, e.WearUsedPct)
+	assert.Equal(t, "writes", e.Basis)
+	require.NotNil(t, e.WrittenTB)
+	assert.InDelta(t, 200, *e.WrittenTB, 1e-6)
+	require.NotNil(t, e.WriteTBPerDay)
+	assert.InDelta(t, 0.1, *e.WriteTBPerDay, 1e-9)
+	assert.InDelta(t, 0.01, e.WearPctPerDay, 1e-9)
+
+	// 80% left at 0.01%/day is 8000 days.
+	require.NotNil(t, e.EndOfLife)
+	assert.WithinDuration(t, now.Add(8000*24*time.Hour), *e.EndOfLife, time.Minute)
+	months, ok := e.MonthsLeft(now)
+	assert.True(t, ok)
+	assert.InDelta(t, 8000/30.44, months, 0.01)
+}
+
+func TestProjectEndurance_ATAWrites(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	// Samsung 870 EVO: 241 in 512-byte LBAs. 10% used after 100 TB written;
+	// 0.5 TB written in the last 5 days.
+	const tb = 1e12 / 512
+	disk := &model.Disk{
+		DiskType: "ssd", Protocol: "ata", Model: "Samsung SSD 870 EVO 1TB", Wearout: new(90),
+		Attributes: []model.SMARTAttribute{
+			{ID: 177, Name: "Wear_Leveling_Count", Value: 90, RawValue: 63},
+			{ID: ATATotalLBAsWritten, Name: "Total_LBAs_Written", RawValue: 100 * tb},
+		},
+	}
+	written := []model.AttributeSample{
+		{Timestamp: now.Add(-5 * 24 * time.Hour).Unix(), ID: ATATotalLBAsWritten, RawValue: 99.5 * tb},
+	}
+
+	e := ProjectEndurance(disk, nil, written, now)
+	require.NotNil(t, e)
+	assert.Equal(t, "writes", e.Basis)
+	require.NotNil(t, e.WrittenTB)
+	assert.InDelta(t, 100, *e.WrittenTB, 1e-6)
+	require.NotNil(t, e.WriteTBPerDay)
+	assert.InDelta(t, 0.1, *e.WriteTBPerDay, 1e-9)
+	assert.InDelta(t, 0.01, e.WearPctPerDay, 1e-9)
+}
+
+func TestHostWritesAttribute(t *testing.T) {
+	attr := func(id int, name string) []model.SMARTAttribute {
+		return []model.SMARTAttribute{{ID: id, Name: name, RawValue: 1}}
+	}
+	tests := []struct {
+		name   string
+		disk   *model.Disk
+		wantID int
+		unit   float64
+	}{
+		{"nvme", &model.Disk{Protocol: "nvme", Attributes: attr(NVMeDataUnitsWritten, "Data Units Written")}, NVMeDataUnitsWritten, nvmeDataUnit},
+		{"samsung lbas", &model.Disk{Protocol: "ata", Model: "Samsung SSD 870 EVO 1TB", Attributes: attr(241, "Total_LBAs_Written")}, 241, 512},
+		{"intel 32mib", &model.Disk{Protocol: "ata", Model: "INTEL SSDSC2KB480G8", Attributes: attr(241, "Host_Writes_32MiB")}, 241, 32 << 20},
+		{"wd gib", &model.Disk{Protocol: "ata", Model: "WDC WDS500G2B0A", Attributes: attr(241, "Total_Writes_GiB")}, 241, 1 << 30},
+		{"crucial 246", &model.Disk{Protocol: "ata", Model: "CT1000MX500SSD1", Attributes: attr(246, "Total_LBAs_Written")}, 246, 512},
+		{"246 elsewhere is not writes", &model.Disk{Protocol: "ata", Model: "WDC WDS500G2B0A", Attributes: attr(246, "Total_LBAs_Written")}, 0, 0},
+		{"unknown unit", &model.Disk{Protocol: "ata", Model: "KINGSTON SA400", Attributes: attr(241, "Unknown_Attribute")}, 0, 0},
+		{"nvme without counter", &model.Disk{Protocol: "nvme"}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, unit, ok := HostWritesAttribute(tt.disk)
+			assert.Equal(t, tt.wantID != 0, ok)
+			assert.Equal(t, tt.wantID, id)
+			assert.InDelta(t, tt.unit, unit, 0)
+		})
+	}
+}
+
+func TestProjectEndurance_History(t *testing.T) {
+	now := time.Now()
+	disk := &model.Disk{DiskType: "ssd", Protocol: "ata", Wearout: new(90), PowerOnHours: new(40000)}
+	wear := &model.SparklinePoint{Timestamp: now.Add(-20 * 24 * time.Hour).Unix(), Value: 92}
+
+	e := ProjectEndurance(disk, wear, nil, now)
+	require.NotNil(t, e)
+	assert.Equal(t, "history", e.Basis)
+	assert.InDelta(t, 0.1, e.WearPctPerDay, 1e-6)
+	assert.Nil(t, e.WriteTBPerDay)
+	require.NotNil(t, e.EndOfLife)
+	assert.WithinDuration(t, now.Add(900*24*time.Hour), *e.EndOfLife, time.Hour)
+}
+
+func TestProjectEndurance_LifetimeFallback(t *testing.T) {
+	now := time.Now()
+	// Wear has not moved within the recorded history: fall back to the
+	// lifetime average, 10% over 1000 days.
+	disk := &model.Disk{DiskType: "ssd", Protocol: "ata", Wearout: new(90), PowerOnHours: new(24000)}
+	wear := &model.SparklinePoint{Timestamp: now.Add(-10 * 24 * time.Hour).Unix(), Value: 90}
+
+	e := ProjectEndurance(disk, wear, nil, now)
+	require.NotNil(t, e)
+	assert.Equal(t, "lifetime", e.Basis)
+	assert.InDelta(t, 0.01, e.WearPctPerDay, 1e-9)
+	require.NotNil(t, e.EndOfLife)
+	assert.WithinDuration(t, now.Add(9000*24*time.Hour), *e.EndOfLife, time.Hour)
+}
+
+func TestProjectEndurance_NoProjection(t *testing.T) {
+	now := time.Now()
+
+	// A new drive with no measurable wear has no end-of-life date.
+	e := ProjectEndurance(&model.Disk{DiskType: "ssd", Wearout: new(100), PowerOnHours: new(500)}, nil, nil, now)
+	require.NotNil(t, e)
+	assert.Equal(t, 0, e.WearUsedPct)
+	assert.Empty(t, e.Basis)
+	assert.Nil(t, e.EndOfLife)
+	_, ok := e.MonthsLeft(now)
+	assert.False(t, ok)
+
+	// Barely any wear: projections past the cap count as none.
+	e = ProjectEndurance(&model.Disk{DiskType: "ssd", Wearout: new(99), PowerOnHours: new(80000)}, nil, nil, now)
+	assert.Equal(t, "lifetime", e.Basis)
+	assert.Nil(t, e.EndOfLife)
+
+	// HDDs and SSDs without a wear indicator are not projected.
+	assert.Nil(t, ProjectEndurance(&model.Disk{DiskType: "hdd", Wearout: new(100)}, nil, nil, now))
+	assert.Nil(t, ProjectEndurance(&model.Disk{DiskType: "ssd"}, nil, nil, now))
+}
+
+func TestProjectEndurance_WornOut(t *testing.T) {
+	now := time.Now()
+	// NVMe Percentage Used can exceed 100.
+	e := ProjectEndurance(&model.Disk{DiskType: "nvme", Protocol: "nvme", Wearout: new(-4), PowerOnHours: new(30000)}, nil, nil, now)
+	require.NotNil(t, e)
+	assert.Equal(t, 100, e.WearUsedPct)
+	require.NotNil(t, e.EndOfLife)
+	months, ok := e.MonthsLeft(now)
+	assert.True(t, ok)
+	assert.Zero(t, months)
+}
+
+func TestProjectEndurance_ShortWriteHistory(t *testing.T) {
+	now := time.Now()
+	disk := &model.Disk{
+		DiskType: "nvme", Protocol: "nvme", Wearout: new(95), PowerOnHours: new(2400),
+		Attributes: []model.SMARTAttribute{{ID: NVMeDataUnitsWritten, RawValue: 1000}},
+	}
+	written := []model.AttributeSample{{Timestamp: now.Add(-time.Hour).Unix(), ID: NVMeDataUnitsWritten, RawValue: 900}}
+
+	e := ProjectEndurance(disk, nil, written, now)
+	require.NotNil(t, e)
+	assert.Nil(t, e.WriteTBPerDay, "an hour of history is too short for a rate")
+	assert.Equal(t, "lifetime", e.Basis)
+}
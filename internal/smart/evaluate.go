@@ -1,12 +1,34 @@
 package smart
 
 import (
+	"cmp"
+	"strconv"
+	"strings"
+
 	"github.com/darshan-rambhia/glint/internal/model"
 )
 
 // EvaluateAttribute assesses a single SMART attribute and sets its Status and FailureRate.
 // It returns the resulting status bitfield value.
 func EvaluateAttribute(attr *model.SMARTAttribute, protocol string) int {
+	return scoreAttribute(attr, attr.RawValue, AttrRule{})
+}
+
+// scoreAttribute assesses attr with raw as its count and rule applied over
+// the built-in thresholds.
+func scoreAttribute(attr *model.SMARTAttribute, raw int64, rule AttrRule) int {
+	if rule.Ignore != nil && *rule.Ignore {
+		attr.Status = model.StatusPassed
+		return model.StatusPassed
+	}
+
+	// SCSI cycle counters are rated against the specified lifetime maximum
+	// the drive reports, not against failure statistics.
+	if attr.ID == SCSIStartStopCycles || attr.ID == SCSILoadUnloadCycles {
+		attr.Status = scoreSCSICycles(attr)
+		return attr.Status
+	}
+
 	// Step 1: Check manufacturer threshold (raw value >= threshold means SMART failure).
 	// Threshold of 0 means "always passing" per ATA spec, so skip it.
 	if attr.Threshold > 0 && attr.Value > 0 && attr.Value <= attr.Threshold {
@@ -14,8 +36,11 @@ func EvaluateAttribute(attr *model.SMARTAttribute, protocol string) int {
 		return model.StatusFailedSmart
 	}
 
-	// Step 2: Look up Backblaze-derived thresholds.
+	// Step 2: Look up Backblaze-derived thresholds, or the configured buckets.
 	thresh, ok := LookupThreshold(attr.ID)
+	if len(rule.Buckets) > 0 {
+		thresh, ok = AttrThreshold{ID: attr.ID, Name: attr.Name, Buckets: rule.Buckets}, true
+	}
 	if !ok {
 		// No statistical data for this attribute.
 		attr.Status = model.StatusPassed
@@ -23,8 +48,11 @@ func EvaluateAttribute(attr *model.SMARTAttribute, protocol string) int {
 	}
 
 	// Step 3: Find which bucket the raw value falls into.
-	bucket := FindBucket(thresh, attr.RawValue)
+	bucket := FindBucket(thresh, raw)
 	critical := IsCritical(attr.ID)
+	if rule.Critical != nil {
+		critical = *rule.Critical
+	}
 
 	if bucket != nil {
 		rate := bucket.AnnualFailureRate
@@ -56,14 +84,48 @@ func EvaluateAttribute(attr *model.SMARTAttribute, protocol string) int {
 	return model.StatusPassed
 }
 
+// EvaluateDisk evaluates all SMART attributes on a disk with the built-in
+// thresholds. See Rules.EvaluateDisk.
+func EvaluateDisk(disk *model.Disk) int {
+	var r *Rules
+	return r.EvaluateDisk(disk)
+}
+
 // EvaluateDisk evaluates all SMART attributes on a disk and sets the disk's
 // aggregate Status as the bitwise OR of all attribute statuses.
 // Returns the aggregate status.
-func EvaluateDisk(disk *model.Disk) int {
+//
+// ATA attributes are read through the vendor table for the disk's model:
+// unnamed attributes get the vendor's name, packed raw values are scored by
+// the count they hold (RawValue is left as reported), and an SSD without a
+// wearout takes it from the vendor's wear attribute. The rules then adjust
+// or skip the scoring per attribute.
+func (r *Rules) EvaluateDisk(disk *model.Disk) int {
+	var vendor map[int]VendorAttr
+	var rules map[int]AttrRule
+	if disk.Protocol == "ata" {
+		if v, ok := LookupVendor(disk.Model); ok {
+			vendor = v.Attributes
+		}
+		rules = r.forModel(disk.Model)
+	}
+
 	status := model.StatusPassed
 	for i := range disk.Attributes {
-		s := EvaluateAttribute(&disk.Attributes[i], disk.Protocol)
-		status |= s
+		attr := &disk.Attributes[i]
+		va := vendor[attr.ID]
+		if va.Name != "" && (attr.Name == "" || strings.HasPrefix(attr.Name, "Unknown")) {
+			attr.Name = va.Name
+		}
+		if va.Wear && disk.Wearout == nil && disk.DiskType != "hdd" && attr.Value > 0 {
+			remaining := safeInt(min(attr.Value, 100))
+			disk.Wearout = &remaining
+		}
+		count := attr.RawValue
+		if va.Raw != RawPlain {
+			count = va.Raw.Decode(cmp.Or(attr.RawString, strconv.FormatInt(attr.RawValue, 10)))
+		}
+		status |= scoreAttribute(attr, count, rules[attr.ID])
 	}
 	disk.Status = status
 	return status
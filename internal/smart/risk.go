@@ -0,0 +1,147 @@
+package smart
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// Risk factor weights, as annual failure probabilities (0-1). Attribute and
+// age factors come from the Backblaze buckets; the rest are heuristics for
+// signals Backblaze does not quantify.
+const (
+	// baselineRisk is the annualized failure rate of the Backblaze fleet,
+	// the risk of a disk with no warning signs.
+	baselineRisk = 0.015
+	// healthRisk is a manufacturer SMART failure or NVMe critical warning.
+	healthRisk = 0.50
+	// selfTestRisk is a failed newest self-test.
+	selfTestRisk = 0.30
+	// mediaErrorRisk is an NVMe drive that has logged media errors.
+	mediaErrorRisk = 0.10
+	// wornRisk and wearingRisk are an SSD past, or within 10% of, its rated
+	// write endurance.
+	wornRisk    = 0.25
+	wearingRisk = 0.10
+)
+
+// trendRisk is the risk added by a growing error counter, by the shortest
+// trend window it grew in: recent growth means faster degradation.
+var trendRisk = []struct {
+	window string
+	risk   float64
+}{{"24h", 0.20}, {"7d", 0.15}, {"30d", 0.10}}
+
+// ScoreRisk estimates a disk's annual failure probability with the built-in
+// thresholds. See Rules.ScoreRisk.
+func ScoreRisk(disk *model.Disk) *model.DiskRisk {
+	var r *Rules
+	return r.ScoreRisk(disk)
+}
+
+// ScoreRisk estimates a disk's annual failure probability and explains it.
+// It reads the results of EvaluateDisk, EvaluateTrends and ProjectEndurance,
+// so it runs after them; r must be the rules EvaluateDisk used. Returns nil
+// for a disk whose SMART data could not be read.
+//
+// Each attribute contributes the failure rate of its bucket above that of its
+// healthiest bucket, so a disk whose attributes all sit in their first bucket
+// scores the baseline. Power-on hours are scored through the buckets of ATA
+// attribute 9 for every protocol. NVMe pseudo IDs overlap ATA IDs, so NVMe
+// attributes are read by meaning instead.
+func (r *Rules) ScoreRisk(disk *model.Disk) *model.DiskRisk {
+	if disk.Status&model.StatusInternalError != 0 {
+		return nil
+	}
+
+	factors := []model.RiskFactor{{Source: model.RiskBaseline, Name: "Healthy disk", Pct: baselineRisk * 100}}
+	add := func(source, name, detail string, risk float64) {
+		if risk > 0 {
+			factors = append(factors, model.RiskFactor{Source: source, Name: name, Detail: detail, Pct: risk * 100})
+		}
+	}
+
+	if disk.Health == "FAILED" || disk.Status&model.StatusFailedSmart != 0 {
+		add(model.RiskHealth, "SMART health check failed", "", healthRisk)
+	}
+	if disk.PowerOnHours != nil {
+		add(model.RiskAge, "Power-on hours", fmt.Sprintf("%d h", *disk.PowerOnHours),
+			excessRisk(thresholdTable[9].Buckets, int64(*disk.PowerOnHours)))
+	}
+
+	if disk.Protocol == "nvme" {
+		for _, a := range disk.Attributes {
+			switch {
+			case a.ID == NVMeCriticalWarning && a.RawValue != 0:
+				add(model.RiskHealth, a.Name, fmt.Sprintf("0x%02x", a.RawValue), healthRisk)
+			case a.ID == NVMeMediaErrors && a.RawValue > 0:
+				add(model.RiskAttribute, a.Name, fmt.Sprintf("raw %d", a.RawValue), mediaErrorRisk)
+			}
+		}
+	} else {
+		var rules map[int]AttrRule
+		if disk.Protocol == "ata" {
+			rules = r.forModel(disk.Model)
+		}
+		for _, a := range disk.Attributes {
+			if a.FailureRate == nil || a.ID == 9 {
+				continue
+			}
+			buckets := rules[a.ID].Buckets
+			if len(buckets) == 0 {
+				buckets = thresholdTable[a.ID].Buckets
+			}
+			if len(buckets) > 0 {
+				add(model.RiskAttribute, a.Name, "raw "+a.RawString, *a.FailureRate-buckets[0].AnnualFailureRate)
+			}
+		}
+	}
+
+	for _, t := range disk.Trends {
+		for i, d := range []*int64{t.Delta24h, t.Delta7d, t.Delta30d} {
+			if d != nil && *d > 0 {
+				add(model.RiskTrend, t.Name, fmt.Sprintf("+%d in %s", *d, trendRisk[i].window), trendRisk[i].risk)
+				break
+			}
+		}
+	}
+
+	if log := disk.SelfTestLog; log != nil {
+		if t := log.Latest(); t != nil && t.Result == "failed" {
+			add(model.RiskSelfTest, "Self-test failed", t.Type, selfTestRisk)
+		}
+	}
+
+	if e := disk.Endurance; e != nil {
+		detail := fmt.Sprintf("%d%% used", e.WearUsedPct)
+		switch {
+		case e.WearUsedPct >= 100:
+			add(model.RiskWear, "Rated endurance used up", detail, wornRisk)
+		case e.WearUsedPct >= 90:
+			add(model.RiskWear, "Rated endurance nearly used", detail, wearingRisk)
+		}
+	}
+
+	survive := 1.0
+	for _, f := range factors {
+		survive *= 1 - f.Pct/100
+	}
+	slices.SortStableFunc(factors, func(a, b model.RiskFactor) int {
+		return cmp.Compare(b.Pct, a.Pct)
+	})
+	return &model.DiskRisk{AnnualFailurePct: (1 - survive) * 100, Factors: factors}
+}
+
+// excessRisk returns how far the bucket of raw lies above the first bucket.
+func excessRisk(buckets []Bucket, raw int64) float64 {
+	if len(buckets) == 0 {
+		return 0
+	}
+	b := FindBucket(AttrThreshold{Buckets: buckets}, raw)
+	if b == nil {
+		return 0
+	}
+	return b.AnnualFailureRate - buckets[0].AnnualFailureRate
+}
@@ -0,0 +1,117 @@
+package smart
+
+import (
+	"testing"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func riskSources(r *model.DiskRisk) []string {
+	var sources []string
+	for _, f := range r.Factors {
+		sources = append(sources, f.Source+" "+f.Name)
+	}
+	return sources
+}
+
+func TestScoreRisk_Healthy(t *testing.T) {
+	disk := &model.Disk{
+		Protocol: "ata", PowerOnHours: new(5000),
+		Attributes: []model.SMARTAttribute{
+			{ID: 5, Name: "Reallocated_Sector_Ct", Value: 100, Threshold: 10, RawValue: 0, RawString: "0"},
+			{ID: 9, Name: "Power_On_Hours", Value: 95, RawValue: 5000, RawString: "5000"},
+			{ID: 194, Name: "Temperature_Celsius", Value: 70, RawValue: 30, RawString: "30"},
+			{ID: 240, Name: "Head_Flying_Hours", Value: 100, RawValue: 4000, RawString: "4000"},
+		},
+	}
+	EvaluateDisk(disk)
+	r := ScoreRisk(disk)
+	require.NotNil(t, r)
+	assert.InDelta(t, 1.5, r.AnnualFailurePct, 1e-9)
+	assert.Equal(t, []string{"baseline Healthy disk"}, riskSources(r))
+}
+
+func TestScoreRisk_Failing(t *testing.T) {
+	disk := &model.Disk{
+		Protocol: "ata", PowerOnHours: new(45000),
+		Attributes: []model.SMARTAttribute{
+			{ID: 5, Name: "Reallocated_Sector_Ct", Value: 100, Threshold: 10, RawValue: 20, RawString: "20"},
+			{ID: 9, Name: "Power_On_Hours", Value: 50, RawValue: 45000, RawString: "45000"},
+		},
+		Trends: []model.AttributeTrend{
+			{ID: 5, Name: "Reallocated_Sector_Ct", Current: 20, Delta24h: new(int64(0)), Delta7d: new(int64(6)), Delta30d: new(int64(6))},
+			{ID: 197, Name: "Current_Pending_Sector", Current: 0, Delta24h: new(int64(-2))},
+		},
+		SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{
+			{Type: "short", Result: "in_progress"},
+			{Type: "long", Result: "failed"},
+			{Type: "long", Result: "passed"},
+		}},
+	}
+	EvaluateDisk(disk)
+	r := ScoreRisk(disk)
+	require.NotNil(t, r)
+
+	assert.Equal(t, []string{
+		"self_test Self-test failed",
+		"attribute Reallocated_Sector_Ct",
+		"trend Reallocated_Sector_Ct",
+		"age Power-on hours",
+		"baseline Healthy disk",
+	}, riskSources(r))
+	assert.InDelta(t, 30.0, r.Factors[0].Pct, 1e-9)
+	assert.Equal(t, "long", r.Factors[0].Detail)
+	assert.InDelta(t, 21.1, r.Factors[1].Pct, 1e-9)
+	assert.Equal(t, "raw 20", r.Factors[1].Detail)
+	assert.Equal(t, "+6 in 7d", r.Factors[2].Detail)
+	assert.InDelta(t, 4.0, r.Factors[3].Pct, 1e-9)
+	assert.Equal(t, "45000 h", r.Factors[3].Detail)
+
+	want := 1 - 0.70*0.789*0.85*0.96*0.985
+	assert.InDelta(t, want*100, r.AnnualFailurePct, 1e-9)
+}
+
+func TestScoreRisk_NVMe(t *testing.T) {
+	disk := &model.Disk{
+		Protocol: "nvme", DiskType: "nvme", PowerOnHours: new(100),
+		Attributes: []model.SMARTAttribute{
+			{ID: NVMeCriticalWarning, Name: "Critical Warning", RawValue: 0x04},
+			{ID: NVMePercentageUsed, Name: "Percentage Used", RawValue: 95},
+			{ID: NVMeMediaErrors, Name: "Media Errors", RawValue: 3},
+		},
+		Endurance: &model.Endurance{WearUsedPct: 95},
+	}
+	EvaluateDisk(disk)
+	r := ScoreRisk(disk)
+	require.NotNil(t, r)
+	assert.Equal(t, []string{
+		"health Critical Warning",
+		"attribute Media Errors",
+		"wear Rated endurance nearly used",
+		"baseline Healthy disk",
+	}, riskSources(r), "percentage used is not scored as reallocated sectors")
+	assert.Equal(t, "0x04", r.Factors[0].Detail)
+
+	disk.Endurance.WearUsedPct = 100
+	assert.Contains(t, riskSources(ScoreRisk(disk)), "wear Rated endurance used up")
+}
+
+func TestScoreRisk_Unreadable(t *testing.T) {
+	assert.Nil(t, ScoreRisk(&model.Disk{Status: model.StatusInternalError}))
+}
+
+func TestRules_ScoreRisk_CustomBuckets(t *testing.T) {
+	r := &Rules{Attributes: map[int]AttrRule{
+		194: {Buckets: []Bucket{{Low: 0, High: 60, AnnualFailureRate: 0.01}, {Low: 61, High: 1<<62 - 1, AnnualFailureRate: 0.3}}},
+	}}
+	disk := &model.Disk{
+		Protocol:   "ata",
+		Attributes: []model.SMARTAttribute{{ID: 194, Name: "Temperature_Celsius", Value: 30, RawValue: 70, RawString: "70"}},
+	}
+	r.EvaluateDisk(disk)
+	risk := r.ScoreRisk(disk)
+	require.Len(t, risk.Factors, 2)
+	assert.InDelta(t, 29.0, risk.Factors[0].Pct, 1e-9, "measured from the configured healthy bucket")
+}
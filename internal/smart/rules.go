@@ -0,0 +1,59 @@
+package smart
+
+import "regexp"
+
+// AttrRule overrides the built-in assessment of one ATA attribute. Unset
+// fields keep the built-in behaviour.
+type AttrRule struct {
+	Buckets  []Bucket // replaces the built-in failure buckets
+	Critical *bool    // whether a 10% failure rate already fails the disk
+	Ignore   *bool    // the attribute is never scored
+}
+
+// ModelRules applies attribute rules to disks whose model matches Model.
+type ModelRules struct {
+	Model      *regexp.Regexp
+	Attributes map[int]AttrRule
+}
+
+// Rules tailors ATA attribute scoring to an installation's disks. Model
+// rules take precedence over the global Attributes, later models over
+// earlier ones. A nil *Rules scores with the built-in thresholds only.
+type Rules struct {
+	Attributes map[int]AttrRule
+	Models     []ModelRules
+}
+
+// forModel merges the rules that apply to a disk model, keyed by attribute ID.
+func (r *Rules) forModel(model string) map[int]AttrRule {
+	if r == nil {
+		return nil
+	}
+	merged := make(map[int]AttrRule, len(r.Attributes))
+	for id, rule := range r.Attributes {
+		merged[id] = rule
+	}
+	for _, m := range r.Models {
+		if !m.Model.MatchString(model) {
+			continue
+		}
+		for id, rule := range m.Attributes {
+			merged[id] = merged[id].merge(rule)
+		}
+	}
+	return merged
+}
+
+// merge overlays the fields set in o onto a.
+func (a AttrRule) merge(o AttrRule) AttrRule {
+	if len(o.Buckets) > 0 {
+		a.Buckets = o.Buckets
+	}
+	if o.Critical != nil {
+		a.Critical = o.Critical
+	}
+	if o.Ignore != nil {
+		a.Ignore = o.Ignore
+	}
+	return a
+}
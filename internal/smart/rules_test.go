@@ -0,0 +1,125 @@
+package smart
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules_EvaluateDisk_SeagatePackedRaw(t *testing.T) {
+	attrs := func() []model.SMARTAttribute {
+		return []model.SMARTAttribute{
+			{ID: 1, Name: "Raw_Read_Error_Rate", Value: 83, Threshold: 44, RawValue: 215863568},
+			{ID: 188, Name: "Command_Timeout", Value: 100, RawValue: 1<<32 | 1<<16 | 1},
+		}
+	}
+
+	seagate := &model.Disk{Protocol: "ata", Model: "ST8000VN004-2M2101", Attributes: attrs()}
+	assert.Equal(t, model.StatusPassed, EvaluateDisk(seagate))
+	assert.Equal(t, new(0.02), seagate.Attributes[0].FailureRate, "no errors in the high word")
+	assert.Equal(t, new(0.03), seagate.Attributes[1].FailureRate, "three timeouts")
+	assert.Equal(t, int64(215863568), seagate.Attributes[0].RawValue, "raw value kept as reported")
+
+	other := &model.Disk{Protocol: "ata", Model: "WDC WD40EFRX-68N32N0", Attributes: attrs()}
+	assert.Equal(t, model.StatusWarnScrutiny|model.StatusFailedScrutiny, EvaluateDisk(other))
+}
+
+func TestRules_EvaluateDisk_SeagateFormattedRaw(t *testing.T) {
+	// Raw strings as PVE and smartctl print them for an Exos drive, whose
+	// drive database entry splits the packed values.
+	disk := &model.Disk{Protocol: "ata", Model: "ST16000NM001G-2KK103", Attributes: []model.SMARTAttribute{
+		{ID: 1, Name: "Raw_Read_Error_Rate", Value: 82, Threshold: 44, RawValue: 0, RawString: "0/157342720"},
+		{ID: 7, Name: "Seek_Error_Rate", Value: 90, Threshold: 45, RawValue: 0, RawString: "0/946381282"},
+		{ID: 188, Name: "Command_Timeout", Value: 100, RawValue: 0, RawString: "0 0 3"},
+		{ID: 195, Name: "Hardware_ECC_Recovered", Value: 82, RawValue: 4, RawString: "4/157342720"},
+	}}
+	EvaluateDisk(disk)
+	assert.Equal(t, new(0.03), disk.Attributes[2].FailureRate, "three timeouts in the last word")
+	assert.Equal(t, int64(0), disk.Attributes[2].RawValue, "raw value kept as reported")
+	assert.Equal(t, model.StatusPassed, disk.Attributes[0].Status)
+}
+
+func TestRules_EvaluateDisk_Overrides(t *testing.T) {
+	r := &Rules{
+		Attributes: map[int]AttrRule{
+			5:   {Ignore: new(true)},
+			194: {Buckets: []Bucket{{Low: 0, High: 60, AnnualFailureRate: 0.02}, {Low: 61, High: 1<<62 - 1, AnnualFailureRate: 0.3}}},
+			199: {Critical: new(true)},
+		},
+		Models: []ModelRules{
+			{Model: regexp.MustCompile(`^WDC`), Attributes: map[int]AttrRule{5: {Ignore: new(false)}}},
+		},
+	}
+	attrs := func() []model.SMARTAttribute {
+		return []model.SMARTAttribute{
+			{ID: 5, Value: 100, RawValue: 100},
+			{ID: 194, Value: 100, RawValue: 58},
+			{ID: 199, Value: 200, RawValue: 150},
+		}
+	}
+
+	disk := &model.Disk{Protocol: "ata", Model: "TOSHIBA HDWG480", Attributes: attrs()}
+	assert.Equal(t, model.StatusFailedScrutiny, r.EvaluateDisk(disk))
+	assert.Equal(t, model.StatusPassed, disk.Attributes[0].Status, "ignored")
+	assert.Nil(t, disk.Attributes[0].FailureRate)
+	assert.Equal(t, model.StatusPassed, disk.Attributes[1].Status, "custom buckets")
+	assert.Equal(t, new(0.02), disk.Attributes[1].FailureRate)
+	assert.Equal(t, model.StatusFailedScrutiny, disk.Attributes[2].Status, "10% on a critical attribute")
+
+	// The model rule scores attribute 5 again
+	wd := &model.Disk{Protocol: "ata", Model: "WDC WD40EFRX-68N32N0", Attributes: attrs()}
+	r.EvaluateDisk(wd)
+	assert.Equal(t, model.StatusFailedScrutiny, wd.Attributes[0].Status)
+
+	// Rules only apply to ATA attribute IDs
+	nvme := &model.Disk{Protocol: "nvme", Model: "WDC WDS100T1X0E", Attributes: []model.SMARTAttribute{{ID: NVMeTemperature, RawValue: 40}}}
+	assert.Equal(t, model.StatusPassed, r.EvaluateDisk(nvme))
+}
+
+func TestRules_ForModel(t *testing.T) {
+	r := &Rules{
+		Attributes: map[int]AttrRule{1: {Ignore: new(true), Critical: new(false)}},
+		Models: []ModelRules{
+			{Model: regexp.MustCompile(`^ST`), Attributes: map[int]AttrRule{1: {Critical: new(true)}}},
+			{Model: regexp.MustCompile(`^ST8000`), Attributes: map[int]AttrRule{1: {Ignore: new(false)}}},
+		},
+	}
+	rule := r.forModel("ST8000VN004")[1]
+	assert.Equal(t, new(false), rule.Ignore)
+	assert.Equal(t, new(true), rule.Critical)
+	assert.Equal(t, new(true), r.forModel("WDC WD40")[1].Ignore)
+
+	var none *Rules
+	assert.Nil(t, none.forModel("ST8000VN004"))
+}
+
+func TestRules_EvaluateDisk_VendorWearAndNames(t *testing.T) {
+	disk := &model.Disk{
+		Protocol: "ata", DiskType: "ssd", Model: "Samsung SSD 870 EVO 1TB",
+		Attributes: []model.SMARTAttribute{
+			{ID: 177, Name: "Unknown_Attribute", Value: 87, RawValue: 412},
+			{ID: 181, Value: 100},
+		},
+	}
+	EvaluateDisk(disk)
+	require.NotNil(t, disk.Wearout)
+	assert.Equal(t, 87, *disk.Wearout)
+	assert.Equal(t, "Wear_Leveling_Count", disk.Attributes[0].Name)
+	assert.Equal(t, "Program_Fail_Cnt_Total", disk.Attributes[1].Name)
+
+	// A wearout reported by PVE is kept
+	disk = &model.Disk{
+		Protocol: "ata", DiskType: "ssd", Model: "CT1000MX500SSD1", Wearout: new(95),
+		Attributes: []model.SMARTAttribute{{ID: 202, Name: "Percent_Lifetime_Remain", Value: 90, RawValue: 10}},
+	}
+	EvaluateDisk(disk)
+	assert.Equal(t, 95, *disk.Wearout)
+
+	disk.Wearout = nil
+	EvaluateDisk(disk)
+	require.NotNil(t, disk.Wearout)
+	assert.Equal(t, 90, *disk.Wearout)
+}
@@ -1,6 +1,7 @@
 package smart
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 
@@ -9,10 +10,43 @@ import (
 
 // SCSI pseudo attribute IDs (high-range to avoid collision with ATA IDs 1-253).
 const (
-	SCSITemperature  = 300
-	SCSIPowerOnHours = 301
+	SCSITemperature       = 300
+	SCSIPowerOnHours      = 301
+	SCSIGrownDefects      = 302
+	SCSIReadUncorrected   = 303
+	SCSIWriteUncorrected  = 304
+	SCSIVerifyUncorrected = 305
+	SCSINonMediumErrors   = 306
+	SCSIStartStopCycles   = 307
+	SCSILoadUnloadCycles  = 308
 )
 
+// cycleWarnRatio is the share of a specified lifetime cycle count at which a
+// SCSI cycle counter warns; at the count itself it fails.
+const cycleWarnRatio = 0.9
+
+// scsiLabel maps a lower-case SCSI text label to a pseudo attribute.
+type scsiLabel struct {
+	label string
+	id    int
+	name  string
+}
+
+// scsiCounters are the SCSI text lines holding a single counter.
+var scsiCounters = []scsiLabel{
+	{"elements in grown defect list:", SCSIGrownDefects, "Grown Defect List"},
+	{"non-medium error count:", SCSINonMediumErrors, "Non-Medium Errors"},
+	{"accumulated start-stop cycles:", SCSIStartStopCycles, "Start-Stop Cycles"},
+	{"accumulated load-unload cycles:", SCSILoadUnloadCycles, "Load-Unload Cycles"},
+}
+
+// scsiErrorCounters are the rows of the error counter log.
+var scsiErrorCounters = []scsiLabel{
+	{"read:", SCSIReadUncorrected, "Read Uncorrected Errors"},
+	{"write:", SCSIWriteUncorrected, "Write Uncorrected Errors"},
+	{"verify:", SCSIVerifyUncorrected, "Verify Uncorrected Errors"},
+}
+
 // ParseSCSIText extracts metrics from smartctl -d scsi text output.
 //
 // SCSI (and SAT-translated SATA behind an HBA) output uses prose labels rather
@@ -21,8 +55,19 @@ const (
 //	"Current Drive Temperature:     32 C"
 //	"Number of hours powered up = 12345.23"
 //	"Accumulated power on time, hours:minutes 21867:04"
+//	"Elements in grown defect list: 0"
+//	"Non-medium error count:        3"
+//	"Specified cycle count over device lifetime:  50000"
+//	"Accumulated start-stop cycles:  36"
+//	"read:   0   0   0   0   0   12345.678   0"  (error counter log)
+//
+// The start-stop and load-unload cycle attributes carry the specified
+// lifetime maximum in Threshold, as in the smartctl JSON report. The error
+// counter log rows give the total uncorrected errors in their last column.
 func ParseSCSIText(text string) []model.SMARTAttribute {
 	var attrs []model.SMARTAttribute
+	var specCycles, specLoads int64
+	inErrorLog := false
 
 	for line := range strings.SplitSeq(text, "\n") {
 		line = strings.TrimSpace(line)
@@ -32,6 +77,40 @@ func ParseSCSIText(text string) []model.SMARTAttribute {
 
 		lower := strings.ToLower(line)
 
+		if strings.HasPrefix(lower, "error counter log:") {
+			inErrorLog = true
+			continue
+		}
+		if inErrorLog {
+			if a, ok := parseSCSIErrorCounterRow(lower); ok {
+				attrs = append(attrs, a)
+				continue
+			}
+		}
+
+		// Specified lifetime maxima: "Specified cycle count over device lifetime:  50000"
+		if strings.HasPrefix(lower, "specified cycle count over device lifetime:") {
+			if n, ok := extractIntAfterColon(line); ok {
+				specCycles = int64(n)
+			}
+			continue
+		}
+		if strings.HasPrefix(lower, "specified load-unload count over device lifetime:") {
+			if n, ok := extractIntAfterColon(line); ok {
+				specLoads = int64(n)
+			}
+			continue
+		}
+
+		if i := slices.IndexFunc(scsiCounters, func(c scsiLabel) bool {
+			return strings.HasPrefix(lower, c.label)
+		}); i >= 0 {
+			if n, ok := extractIntAfterColon(line); ok {
+				attrs = append(attrs, scsiAttribute(scsiCounters[i].id, scsiCounters[i].name, int64(n)))
+			}
+			continue
+		}
+
 		// Temperature: "Current Drive Temperature:     32 C"
 		if strings.HasPrefix(lower, "current drive temperature:") {
 			if t, ok := extractIntAfterColon(line); ok {
@@ -87,9 +166,54 @@ func ParseSCSIText(text string) []model.SMARTAttribute {
 		}
 	}
 
+	for i := range attrs {
+		switch attrs[i].ID {
+		case SCSIStartStopCycles:
+			attrs[i].Threshold = specCycles
+		case SCSILoadUnloadCycles:
+			attrs[i].Threshold = specLoads
+		}
+	}
 	return attrs
 }
 
+// scoreSCSICycles rates a start-stop or load-unload cycle counter against the
+// specified lifetime maximum in its Threshold. Drives that do not report a
+// maximum always pass.
+func scoreSCSICycles(attr *model.SMARTAttribute) int {
+	switch {
+	case attr.Threshold <= 0:
+		return model.StatusPassed
+	case attr.RawValue >= attr.Threshold:
+		return model.StatusFailedScrutiny
+	case float64(attr.RawValue) >= cycleWarnRatio*float64(attr.Threshold):
+		return model.StatusWarnScrutiny
+	default:
+		return model.StatusPassed
+	}
+}
+
+// parseSCSIErrorCounterRow parses an error counter log row such as
+// "read:   0   0   0   0   0   12345.678   0" into its uncorrected errors.
+func parseSCSIErrorCounterRow(lower string) (model.SMARTAttribute, bool) {
+	for _, c := range scsiErrorCounters {
+		rest, ok := strings.CutPrefix(lower, c.label)
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 7 {
+			return model.SMARTAttribute{}, false
+		}
+		n, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+		if err != nil || n < 0 {
+			return model.SMARTAttribute{}, false
+		}
+		return scsiAttribute(c.id, c.name, n), true
+	}
+	return model.SMARTAttribute{}, false
+}
+
 // extractIntAfterColon parses the first integer after the last colon in s.
 // "Current Drive Temperature:     32 C" → 32, true
 func extractIntAfterColon(s string) (int, bool) {
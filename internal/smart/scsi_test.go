@@ -2,6 +2,10 @@ package smart
 
 import (
 	"testing"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseSCSIText_Temperature(t *testing.T) {
@@ -98,13 +102,102 @@ func TestParseSCSIText_Empty(t *testing.T) {
 }
 
 func TestParseSCSIText_NoRelevantFields(t *testing.T) {
-	text := "Elements in grown defect list: 0\nError counter log:\n"
+	text := "SMART Health Status: OK\nError counter log:\nread: 0 0\n"
 	attrs := ParseSCSIText(text)
 	if len(attrs) != 0 {
 		t.Errorf("expected 0 attrs, got %d", len(attrs))
 	}
 }
 
+const sasText = `=== START OF READ SMART DATA SECTION ===
+SMART Health Status: OK
+
+Current Drive Temperature:     34 C
+Drive Trip Temperature:        65 C
+
+Manufactured in week 10 of year 2016
+Specified cycle count over device lifetime:  50000
+Accumulated start-stop cycles:  46000
+Specified load-unload count over device lifetime:  600000
+Accumulated load-unload cycles:  1103
+Elements in grown defect list: 20
+
+Error counter log:
+           Errors Corrected by           Total   Correction     Gigabytes    Total
+               ECC          rereads/    errors   algorithm      processed    uncorrected
+           fast | delayed   rewrites  corrected  invocations   [10^9 bytes]  errors
+read:          0        0         0         0          0      12345.678           2
+write:         0        0         0         0          0       2345.123           0
+verify:        0        0         0         0          0          0.000           0
+
+Non-medium error count:        3
+Accumulated power on time, hours:minutes 21867:04
+`
+
+func TestParseSCSIText_SAS(t *testing.T) {
+	attrs := ParseSCSIText(sasText)
+	got := make(map[int]model.SMARTAttribute, len(attrs))
+	for _, a := range attrs {
+		got[a.ID] = a
+	}
+	want := map[int]int64{
+		SCSITemperature:       34,
+		SCSIStartStopCycles:   46000,
+		SCSILoadUnloadCycles:  1103,
+		SCSIGrownDefects:      20,
+		SCSIReadUncorrected:   2,
+		SCSIWriteUncorrected:  0,
+		SCSIVerifyUncorrected: 0,
+		SCSINonMediumErrors:   3,
+		SCSIPowerOnHours:      21867,
+	}
+	require.Len(t, got, len(want))
+	for id, raw := range want {
+		assert.Equal(t, raw, got[id].RawValue, "attribute %d", id)
+	}
+	assert.Equal(t, "Grown Defect List", got[SCSIGrownDefects].Name)
+	assert.Equal(t, int64(50000), got[SCSIStartStopCycles].Threshold)
+	assert.Equal(t, int64(600000), got[SCSILoadUnloadCycles].Threshold)
+}
+
+func TestEvaluateDisk_SAS(t *testing.T) {
+	disk := &model.Disk{Protocol: "ata", Attributes: ParseSCSIText(sasText)}
+	status := EvaluateDisk(disk)
+	assert.Equal(t, model.StatusFailedScrutiny|model.StatusWarnScrutiny, status)
+
+	statuses := make(map[int]int)
+	for _, a := range disk.Attributes {
+		statuses[a.ID] = a.Status
+	}
+	assert.Equal(t, model.StatusFailedScrutiny, statuses[SCSIGrownDefects], "20 grown defects (23.6%) on a critical attribute")
+	assert.Equal(t, model.StatusPassed, statuses[SCSIReadUncorrected])
+	assert.Equal(t, model.StatusWarnScrutiny, statuses[SCSIStartStopCycles], "92% of the specified cycles")
+	assert.Equal(t, model.StatusPassed, statuses[SCSILoadUnloadCycles])
+	assert.Equal(t, model.StatusPassed, statuses[SCSINonMediumErrors])
+}
+
+func TestScoreSCSICycles(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw, spec int64
+		want      int
+	}{
+		{"no specified maximum", 1_000_000, 0, model.StatusPassed},
+		{"well below", 1000, 50000, model.StatusPassed},
+		{"at 90%", 45000, 50000, model.StatusWarnScrutiny},
+		{"at maximum", 50000, 50000, model.StatusFailedScrutiny},
+		{"beyond maximum", 70000, 50000, model.StatusFailedScrutiny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attr := model.SMARTAttribute{ID: SCSILoadUnloadCycles, RawValue: tt.raw, Threshold: tt.spec}
+			assert.Equal(t, tt.want, EvaluateAttribute(&attr, "scsi"))
+			assert.Equal(t, tt.want, attr.Status)
+			assert.Nil(t, attr.FailureRate)
+		})
+	}
+}
+
 func BenchmarkParseSCSIText(b *testing.B) {
 	text := `=== START OF READ SMART DATA SECTION ===
 SMART Health Status: OK
@@ -122,12 +215,13 @@ func FuzzParseSCSIText(f *testing.F) {
 	f.Add("Current Drive Temperature:     32 C\n")
 	f.Add("Number of hours powered up = 12345.67\n")
 	f.Add("Accumulated power on time, hours:minutes 21867:04\n")
+	f.Add(sasText)
 	f.Add("")
 	f.Fuzz(func(t *testing.T, s string) {
 		attrs := ParseSCSIText(s)
 		for _, a := range attrs {
-			if a.ID != SCSITemperature && a.ID != SCSIPowerOnHours {
-				t.Fatalf("unexpected attr ID %d (want %d or %d)", a.ID, SCSITemperature, SCSIPowerOnHours)
+			if a.ID < SCSITemperature || a.ID > SCSILoadUnloadCycles {
+				t.Fatalf("unexpected attr ID %d (want %d-%d)", a.ID, SCSITemperature, SCSILoadUnloadCycles)
 			}
 			if a.RawValue < 0 {
 				t.Fatalf("negative raw value %d for attr ID %d", a.RawValue, a.ID)
@@ -0,0 +1,149 @@
+package smart
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// Self-test log rows in smartctl text output. Each captures the test
+// description, status, power-on hours and LBA of first error ("-" if none).
+// Descriptions and statuses contain single spaces; columns are separated by
+// at least two.
+var (
+	// "# 1  Extended offline    Completed: read failure       90%     23651         1234567890"
+	ataSelfTestRow = regexp.MustCompile(`^#\s*\d+\s+(.+?)\s{2,}(.+?)\s+\d+%\s+(\d+)\s+(\S+)$`)
+	// "1   Short             Completed: failed segments            17950      88123456     1   2 0x0 0x00"
+	nvmeSelfTestRow = regexp.MustCompile(`^\d+\s+(.+?)\s{2,}(.+?)\s+(\d+)\s+(\S+)(?:\s.*)?$`)
+	// "# 2  Background short  Failed in segment -->       7   40800        2233445566 [0x3 0x11 0x0]"
+	scsiSelfTestRow = regexp.MustCompile(`^#\s*\d+\s+(.+?)\s{2,}(.+?)\s+\S+\s+(\d+|NOW)\s+(\S+)\s+\[`)
+)
+
+// ataLifetimeHoursWrap is where ATA self-test logs wrap: they record only the
+// low 16 bits of the power-on hours.
+const ataLifetimeHoursWrap = 1 << 16
+
+// ParseSelfTestText extracts the self-test log from smartctl text output
+// (ATA, NVMe or SCSI). It returns nil when the output has no self-test log
+// section, and an empty log when the section lists no tests.
+//
+//	SMART Self-test log structure revision number 1
+//	Num  Test_Description    Status                  Remaining  LifeTime(hours)  LBA_of_first_error
+//	# 1  Short offline       Completed without error       00%     23500         -
+func ParseSelfTestText(text string) *model.SelfTestLog {
+	var log *model.SelfTestLog
+	var row *regexp.Regexp
+
+	for line := range strings.SplitSeq(text, "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+
+		switch {
+		case strings.HasPrefix(lower, "smart self-test log structure"):
+			row = ataSelfTestRow
+		case strings.HasPrefix(lower, "self-test log (nvme"):
+			row = nvmeSelfTestRow
+		case lower == "smart self-test log":
+			row = scsiSelfTestRow
+		case line == "":
+			// A blank line ends the section.
+			row = nil
+			continue
+		default:
+			if row == nil {
+				continue
+			}
+			if m := row.FindStringSubmatch(line); m != nil {
+				log.Tests = append(log.Tests, parseSelfTestRow(m))
+			}
+			continue
+		}
+		log = &model.SelfTestLog{Tests: []model.SelfTest{}}
+	}
+
+	return log
+}
+
+// parseSelfTestRow converts the captures of a self-test row regexp.
+func parseSelfTestRow(m []string) model.SelfTest {
+	st := model.SelfTest{
+		Type:   selfTestTypeFromText(m[1]),
+		Result: selfTestResultFromText(m[2]),
+		Status: m[2],
+	}
+	// "NOW" marks a SCSI test still running.
+	if h, err := strconv.Atoi(m[3]); err == nil {
+		st.LifetimeHours = h
+	}
+	if lba, err := strconv.ParseInt(m[4], 0, 64); err == nil {
+		st.LBAFirstError = &lba
+	}
+	return st
+}
+
+// selfTestTypeFromText maps a test description such as "Extended offline",
+// "Short" or "Background long" to a test type.
+func selfTestTypeFromText(desc string) string {
+	d := strings.ToLower(desc)
+	switch {
+	case strings.Contains(d, "short"):
+		return "short"
+	case strings.Contains(d, "extended"), strings.Contains(d, "long"):
+		return "long"
+	case strings.Contains(d, "conveyance"):
+		return "conveyance"
+	case strings.Contains(d, "selective"):
+		return "selective"
+	case strings.HasPrefix(d, "offline"):
+		return "offline"
+	default:
+		return "vendor"
+	}
+}
+
+// selfTestResultFromText maps a self-test status. Only "Completed without
+// error" (ATA, NVMe) and a bare "Completed" (SCSI) are passes; statuses like
+// "Completed: read failure" report a failure.
+func selfTestResultFromText(status string) string {
+	s := strings.ToLower(status)
+	switch {
+	case strings.Contains(s, "in progress"):
+		return "in_progress"
+	case s == "completed", strings.HasPrefix(s, "completed without error"):
+		return "passed"
+	case strings.Contains(s, "abort"), strings.Contains(s, "interrupted"):
+		return "aborted"
+	default:
+		return "failed"
+	}
+}
+
+// DateSelfTests sets the Time of each finished test in the disk's self-test
+// log by counting back from its power-on hours at LastSeen. ATA logs wrap at
+// 65536 hours, so on older ATA disks the age is taken modulo the wrap. Tests
+// logged with more hours than the disk reports are left undated.
+func DateSelfTests(disk *model.Disk) {
+	if disk.SelfTestLog == nil || disk.PowerOnHours == nil || disk.LastSeen.IsZero() {
+		return
+	}
+	hours := *disk.PowerOnHours
+	for i := range disk.SelfTestLog.Tests {
+		t := &disk.SelfTestLog.Tests[i]
+		t.Time = nil
+		if t.Result == "in_progress" {
+			continue
+		}
+		age := hours - t.LifetimeHours
+		if disk.Protocol == "ata" && hours >= ataLifetimeHoursWrap {
+			age = ((age % ataLifetimeHoursWrap) + ataLifetimeHoursWrap) % ataLifetimeHoursWrap
+		}
+		if age < 0 {
+			continue
+		}
+		ran := disk.LastSeen.Add(-time.Duration(age) * time.Hour)
+		t.Time = &ran
+	}
+}
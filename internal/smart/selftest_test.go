@@ -0,0 +1,174 @@
+package smart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ataSelfTestOutput = `=== START OF READ SMART DATA SECTION ===
+SMART Error Log Version: 1
+No Errors Logged
+
+SMART Self-test log structure revision number 1
+Num  Test_Description    Status                  Remaining  LifeTime(hours)  LBA_of_first_error
+# 1  Extended offline    Completed: read failure       90%     23651         1234567890
+# 2  Short offline       Completed without error       00%     23500         -
+# 3  Short offline       Interrupted (host reset)      10%     23400         -
+# 4  Conveyance offline  Self-test routine in progress 60%     23712         -
+
+SMART Selective self-test log data structure revision number 1
+ SPAN  MIN_LBA  MAX_LBA  CURRENT_TEST_STATUS
+    1        0        0  Not_testing
+`
+
+const nvmeSelfTestOutput = `Error Information (NVMe Log 0x01, 16 of 64 entries)
+Num   ErrCount  SQId   CmdId  Status  PELoc          LBA  NSID    VS  Message
+  0        152     0  0x0012  0x4004      -            0     1     -  Invalid Field in Command
+
+Self-test Log (NVMe Log 0x06)
+Self-test status: No self-test in progress
+Num  Test_Description  Result                       Power_on_Hours  Failing_LBA  NSID Seg SCT Code
+ 0   Extended          Completed without error               18100             -     -   -   -    -
+ 1   Short             Completed: failed segments            17950      88123456     1   2 0x0 0x00
+ 2   Short             Aborted: Controller Reset             17900             -     -   -   -    -
+`
+
+const scsiSelfTestOutput = `SMART Self-test log
+Num  Test              Status                 segment  LifeTime  LBA_first_err [SK ASC ASQ]
+     Description                              number   (hours)
+# 1  Background short  Self test in progress ...   -     NOW                 - [-   -    -]
+# 2  Background long   Completed                   -   41000                 - [-   -    -]
+# 3  Background short  Failed in segment -->       7   40800        2233445566 [0x3 0x11 0x0]
+
+Long (extended) Self-test duration: 65535 seconds [1092.2 minutes]
+`
+
+func TestParseSelfTestText_ATA(t *testing.T) {
+	log := ParseSelfTestText(ataSelfTestOutput)
+	require.NotNil(t, log)
+	assert.Equal(t, []model.SelfTest{
+		{Type: "long", Result: "failed", Status: "Completed: read failure", LifetimeHours: 23651, LBAFirstError: new(int64(1234567890))},
+		{Type: "short", Result: "passed", Status: "Completed without error", LifetimeHours: 23500},
+		{Type: "short", Result: "aborted", Status: "Interrupted (host reset)", LifetimeHours: 23400},
+		{Type: "conveyance", Result: "in_progress", Status: "Self-test routine in progress", LifetimeHours: 23712},
+	}, log.Tests)
+}
+
+func TestParseSelfTestText_NVMe(t *testing.T) {
+	log := ParseSelfTestText(nvmeSelfTestOutput)
+	require.NotNil(t, log)
+	// Error log rows before the section are not mistaken for tests.
+	assert.Equal(t, []model.SelfTest{
+		{Type: "long", Result: "passed", Status: "Completed without error", LifetimeHours: 18100},
+		{Type: "short", Result: "failed", Status: "Completed: failed segments", LifetimeHours: 17950, LBAFirstError: new(int64(88123456))},
+		{Type: "short", Result: "aborted", Status: "Aborted: Controller Reset", LifetimeHours: 17900},
+	}, log.Tests)
+}
+
+func TestParseSelfTestText_SCSI(t *testing.T) {
+	log := ParseSelfTestText(scsiSelfTestOutput)
+	require.NotNil(t, log)
+	assert.Equal(t, []model.SelfTest{
+		{Type: "short", Result: "in_progress", Status: "Self test in progress ..."},
+		{Type: "long", Result: "passed", Status: "Completed", LifetimeHours: 41000},
+		{Type: "short", Result: "failed", Status: "Failed in segment -->", LifetimeHours: 40800, LBAFirstError: new(int64(2233445566))},
+	}, log.Tests)
+}
+
+func TestParseSelfTestText_NoTests(t *testing.T) {
+	log := ParseSelfTestText("SMART Self-test log structure revision number 1\nNo self-tests have been logged.  [To run self-tests, use: smartctl -t]\n")
+	require.NotNil(t, log, "section present")
+	assert.Empty(t, log.Tests)
+
+	assert.Nil(t, ParseSelfTestText(nvmeSmartctlOutput), "no self-test section")
+	assert.Nil(t, ParseSelfTestText(""))
+}
+
+func TestSelfTestLog_Latest(t *testing.T) {
+	log := ParseSelfTestText(scsiSelfTestOutput)
+	require.NotNil(t, log)
+	latest := log.Latest()
+	require.NotNil(t, latest, "skips the running test")
+	assert.Equal(t, "passed", latest.Result)
+	assert.Equal(t, 41000, log.LatestLong().LifetimeHours)
+
+	log = ParseSelfTestText(nvmeSelfTestOutput)
+	log.Tests = log.Tests[1:]
+	assert.Equal(t, "failed", log.Latest().Result)
+	assert.Nil(t, log.LatestLong())
+
+	assert.Nil(t, (&model.SelfTestLog{}).Latest())
+}
+
+func TestDateSelfTests(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	disk := &model.Disk{
+		Protocol:     "ata",
+		PowerOnHours: new(23712),
+		LastSeen:     now,
+		SelfTestLog:  ParseSelfTestText(ataSelfTestOutput),
+	}
+	DateSelfTests(disk)
+
+	tests := disk.SelfTestLog.Tests
+	require.NotNil(t, tests[0].Time)
+	assert.Equal(t, now.Add(-61*time.Hour), *tests[0].Time)
+	assert.Equal(t, now.Add(-212*time.Hour), *tests[1].Time)
+	assert.Nil(t, tests[3].Time, "running tests are undated")
+}
+
+func TestDateSelfTests_ATAWrap(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// 70000 power-on hours; a test 100 hours ago was logged as 69900 - 65536.
+	disk := &model.Disk{
+		Protocol:     "ata",
+		PowerOnHours: new(70000),
+		LastSeen:     now,
+		SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{
+			{Type: "long", Result: "passed", LifetimeHours: 69900 - 65536},
+		}},
+	}
+	DateSelfTests(disk)
+	require.NotNil(t, disk.SelfTestLog.Tests[0].Time)
+	assert.Equal(t, now.Add(-100*time.Hour), *disk.SelfTestLog.Tests[0].Time)
+}
+
+func TestDateSelfTests_Inconsistent(t *testing.T) {
+	disk := &model.Disk{
+		Protocol:     "nvme",
+		PowerOnHours: new(100),
+		LastSeen:     time.Now(),
+		SelfTestLog:  &model.SelfTestLog{Tests: []model.SelfTest{{Type: "short", Result: "passed", LifetimeHours: 200}}},
+	}
+	DateSelfTests(disk)
+	assert.Nil(t, disk.SelfTestLog.Tests[0].Time)
+
+	// Without power-on hours nothing is dated.
+	disk.PowerOnHours = nil
+	disk.SelfTestLog.Tests[0].LifetimeHours = 50
+	DateSelfTests(disk)
+	assert.Nil(t, disk.SelfTestLog.Tests[0].Time)
+}
+
+func FuzzParseSelfTestText(f *testing.F) {
+	f.Add(ataSelfTestOutput)
+	f.Add(nvmeSelfTestOutput)
+	f.Add(scsiSelfTestOutput)
+	f.Add("SMART Self-test log\n# 1  x  y  z  NOW  - [")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, text string) {
+		log := ParseSelfTestText(text)
+		if log == nil {
+			return
+		}
+		for _, st := range log.Tests {
+			if st.Type == "" || st.Result == "" {
+				t.Fatalf("incomplete self-test %+v", st)
+			}
+		}
+	})
+}
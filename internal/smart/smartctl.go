@@ -0,0 +1,679 @@
+package smart
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// Report is a disk's identity and SMART data as parsed from `smartctl -a -j`
+// (or -x -j, which adds the ATA device statistics and extended error log).
+// It carries everything a collector needs without the PVE API, e.g. when
+// running smartctl over SSH.
+type Report struct {
+	Protocol       string // "ata", "nvme", "scsi"
+	DevPath        string
+	Model          string
+	Family         string
+	Serial         string
+	Firmware       string
+	WWN            string // formatted as PVE reports it, e.g. "0x5000c500a1b2c3d4"
+	SizeBytes      int64
+	RotationRate   *int   // rpm; 0 for solid-state, nil when not reported
+	FormFactor     string // e.g. "3.5 inches", "M.2"
+	Passed         *bool  // overall SMART health; nil when not reported
+	Temperature    *int
+	PowerOnHours   *int
+	PercentageUsed *int // SSD endurance used; may exceed 100
+	Attributes     []model.SMARTAttribute
+	SelfTestLog    *model.SelfTestLog // nil when the output has no self-test log
+	ErrorCount     int64              // errors logged over the device lifetime
+	Errors         []LoggedError      // most recent error log entries, newest first
+	DeviceStats    []DeviceStat
+}
+
+// LoggedError is one entry of a device's error log.
+type LoggedError struct {
+	LifetimeHours *int // ATA only
+	Description   string
+}
+
+// DeviceStat is one ATA device statistic (smartctl -x or -l devstat).
+type DeviceStat struct {
+	Page  string
+	Name  string
+	Value int64
+}
+
+// Exit status bits of smartctl that mean it could not read the device at all.
+// Other bits (failing health, logged errors) come with complete output.
+const (
+	smartctlExitCmdLine    = 1 << 0
+	smartctlExitDeviceOpen = 1 << 1
+)
+
+// smartctlOutput is the subset of smartctl's JSON output (smartctl 7.0+) that
+// Glint reads. Field names follow smartctl's schema.
+type smartctlOutput struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	Device struct {
+		Name     string `json:"name"`
+		Protocol string `json:"protocol"`
+	} `json:"device"`
+	ModelFamily     string `json:"model_family"`
+	ModelName       string `json:"model_name"`
+	SCSIVendor      string `json:"scsi_vendor"`
+	SCSIProduct     string `json:"scsi_product"`
+	SerialNumber    string `json:"serial_number"`
+	FirmwareVersion string `json:"firmware_version"`
+	WWN             *struct {
+		NAA uint64 `json:"naa"`
+		OUI uint64 `json:"oui"`
+		ID  uint64 `json:"id"`
+	} `json:"wwn"`
+	UserCapacity struct {
+		Bytes int64 `json:"bytes"`
+	} `json:"user_capacity"`
+	NVMeTotalCapacity int64 `json:"nvme_total_capacity"`
+	RotationRate      *int  `json:"rotation_rate"`
+	FormFactor        struct {
+		Name string `json:"name"`
+	} `json:"form_factor"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current *int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours *int `json:"hours"`
+	} `json:"power_on_time"`
+
+	ATASmartAttributes struct {
+		Table []struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Value  int64  `json:"value"`
+			Worst  int64  `json:"worst"`
+			Thresh int64  `json:"thresh"`
+			Raw    struct {
+				Value  int64  `json:"value"`
+				String string `json:"string"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	ATASelfTestLog *struct {
+		Standard struct {
+			Table []struct {
+				Type   smartctlCode `json:"type"`
+				Status struct {
+					smartctlCode
+					Passed *bool `json:"passed"`
+				} `json:"status"`
+				LifetimeHours int             `json:"lifetime_hours"`
+				LBA           *smartctlNumber `json:"lba"`
+			} `json:"table"`
+		} `json:"standard"`
+	} `json:"ata_smart_self_test_log"`
+	ATAErrorLog struct {
+		Summary  smartctlATAErrorLog `json:"summary"`
+		Extended smartctlATAErrorLog `json:"extended"`
+	} `json:"ata_smart_error_log"`
+	ATADeviceStatistics struct {
+		Pages []struct {
+			Name  string `json:"name"`
+			Table []struct {
+				Name  string `json:"name"`
+				Value int64  `json:"value"`
+				Flags struct {
+					Valid bool `json:"valid"`
+				} `json:"flags"`
+			} `json:"table"`
+		} `json:"pages"`
+	} `json:"ata_device_statistics"`
+
+	NVMeHealth *struct {
+		CriticalWarning         int64 `json:"critical_warning"`
+		Temperature             int64 `json:"temperature"`
+		AvailableSpare          int64 `json:"available_spare"`
+		AvailableSpareThreshold int64 `json:"available_spare_threshold"`
+		PercentageUsed          int64 `json:"percentage_used"`
+		DataUnitsRead           int64 `json:"data_units_read"`
+		DataUnitsWritten        int64 `json:"data_units_written"`
+		PowerOnHours            int64 `json:"power_on_hours"`
+		MediaErrors             int64 `json:"media_errors"`
+		NumErrLogEntries        int64 `json:"num_err_log_entries"`
+	} `json:"nvme_smart_health_information_log"`
+	NVMeSelfTestLog *struct {
+		Table []struct {
+			Code         smartctlCode    `json:"self_test_code"`
+			Result       smartctlCode    `json:"self_test_result"`
+			PowerOnHours int             `json:"power_on_hours"`
+			LBA          *smartctlNumber `json:"lba"`
+		} `json:"table"`
+	} `json:"nvme_self_test_log"`
+	NVMeErrorLog struct {
+		Table []struct {
+			StatusField struct {
+				String string `json:"string"`
+			} `json:"status_field"`
+		} `json:"table"`
+	} `json:"nvme_error_information_log"`
+
+	SCSIGrownDefectList *int64 `json:"scsi_grown_defect_list"`
+	SCSIErrorCounterLog struct {
+		Read   *smartctlSCSIErrorCounters `json:"read"`
+		Write  *smartctlSCSIErrorCounters `json:"write"`
+		Verify *smartctlSCSIErrorCounters `json:"verify"`
+	} `json:"scsi_error_counter_log"`
+	SCSINonMediumErrorCount *int64 `json:"scsi_nonmedium_error_count"`
+	SCSIStartStopCycles     *struct {
+		SpecifiedCycles     int64 `json:"specified_cycle_count_over_device_lifetime"`
+		StartStopCycles     int64 `json:"accumulated_start_stop_cycles"`
+		SpecifiedLoadCycles int64 `json:"specified_load_unload_count_over_device_lifetime"`
+		LoadUnloadCycles    int64 `json:"accumulated_load_unload_cycles"`
+	} `json:"scsi_start_stop_cycle_counter"`
+	SCSIPercentageUsed *int `json:"scsi_percentage_used_endurance_indicator"`
+}
+
+// smartctlCode is smartctl's {"value": n, "string": "..."} pair.
+type smartctlCode struct {
+	Value  int    `json:"value"`
+	String string `json:"string"`
+}
+
+// smartctlNumber is an integer that smartctl emits either bare or as
+// {"value": n}, depending on its version and the protocol.
+type smartctlNumber int64
+
+func (n *smartctlNumber) UnmarshalJSON(b []byte) error {
+	var v int64
+	if err := json.Unmarshal(b, &v); err == nil {
+		*n = smartctlNumber(v)
+		return nil
+	}
+	var obj struct {
+		Value int64 `json:"value"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	*n = smartctlNumber(obj.Value)
+	return nil
+}
+
+type smartctlATAErrorLog struct {
+	Count int64 `json:"count"`
+	Table []struct {
+		LifetimeHours int    `json:"lifetime_hours"`
+		Description   string `json:"error_description"`
+	} `json:"table"`
+}
+
+type smartctlSCSIErrorCounters struct {
+	TotalCorrected   int64 `json:"total_errors_corrected"`
+	TotalUncorrected int64 `json:"total_uncorrected_errors"`
+}
+
+// smartctlSCSISelfTest is one scsi_self_test_N entry. SCSI self-tests are
+// emitted as numbered top-level keys rather than an array.
+type smartctlSCSISelfTest struct {
+	Code        smartctlCode `json:"code"`
+	Result      smartctlCode `json:"result"`
+	PowerOnTime struct {
+		Hours int `json:"hours"`
+	} `json:"power_on_time"`
+	LBA *smartctlNumber `json:"lba_first_failure"`
+}
+
+// ParseSmartctlJSON parses the JSON output of `smartctl -a -j` for ATA, NVMe
+// and SCSI devices. It fails when the output is not smartctl JSON or smartctl
+// could not open the device; a failing disk still parses.
+func ParseSmartctlJSON(data []byte) (*Report, error) {
+	var out smartctlOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing smartctl JSON: %w", err)
+	}
+	if out.Smartctl.ExitStatus&(smartctlExitCmdLine|smartctlExitDeviceOpen) != 0 {
+		return nil, fmt.Errorf("smartctl could not read the device (exit status %d): %s",
+			out.Smartctl.ExitStatus, smartctlMessage(&out))
+	}
+
+	r := &Report{
+		DevPath:      out.Device.Name,
+		Model:        out.ModelName,
+		Family:       out.ModelFamily,
+		Serial:       out.SerialNumber,
+		Firmware:     out.FirmwareVersion,
+		SizeBytes:    out.UserCapacity.Bytes,
+		RotationRate: out.RotationRate,
+		FormFactor:   out.FormFactor.Name,
+		Temperature:  out.Temperature.Current,
+		PowerOnHours: out.PowerOnTime.Hours,
+	}
+	if r.Model == "" {
+		r.Model = strings.TrimSpace(out.SCSIVendor + " " + out.SCSIProduct)
+	}
+	if r.SizeBytes == 0 {
+		r.SizeBytes = out.NVMeTotalCapacity
+	}
+	if w := out.WWN; w != nil {
+		r.WWN = fmt.Sprintf("0x%016x", w.NAA<<60|w.OUI<<36|w.ID)
+	}
+	if out.SmartStatus != nil {
+		passed := out.SmartStatus.Passed
+		r.Passed = &passed
+	}
+
+	switch strings.ToLower(out.Device.Protocol) {
+	case "ata":
+		r.Protocol = "ata"
+		parseSmartctlATA(&out, r)
+	case "nvme":
+		r.Protocol = "nvme"
+		parseSmartctlNVMe(&out, r)
+	case "scsi":
+		r.Protocol = "scsi"
+		if err := parseSmartctlSCSI(data, &out, r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported smartctl device protocol %q", out.Device.Protocol)
+	}
+	return r, nil
+}
+
+// smartctlMessage joins smartctl's error messages for an error report.
+func smartctlMessage(out *smartctlOutput) string {
+	var msgs []string
+	for _, m := range out.Smartctl.Messages {
+		if m.Severity == "error" {
+			msgs = append(msgs, m.String)
+		}
+	}
+	if len(msgs) == 0 {
+		return "no message"
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func parseSmartctlATA(out *smartctlOutput, r *Report) {
+	for _, a := range out.ATASmartAttributes.Table {
+		// The raw string is what the PVE API reports; its leading integer is
+		// the counter (raw.value packs extra fields, e.g. min/max temperature).
+		raw := a.Raw.String
+		rawValue := extractLeadingInt(raw)
+		if raw == "" {
+			raw = strconv.FormatInt(a.Raw.Value, 10)
+			rawValue = a.Raw.Value
+		}
+		r.Attributes = append(r.Attributes, model.SMARTAttribute{
+			ID:        a.ID,
+			Name:      a.Name,
+			Value:     a.Value,
+			Worst:     a.Worst,
+			Threshold: a.Thresh,
+			RawValue:  rawValue,
+			RawString: raw,
+		})
+	}
+
+	if l := out.ATASelfTestLog; l != nil {
+		r.SelfTestLog = &model.SelfTestLog{Tests: []model.SelfTest{}}
+		for _, t := range l.Standard.Table {
+			r.SelfTestLog.Tests = append(r.SelfTestLog.Tests, model.SelfTest{
+				Type:          ataSelfTestType(t.Type.Value),
+				Result:        ataSelfTestResult(t.Status.Value, t.Status.Passed),
+				Status:        t.Status.String,
+				LifetimeHours: t.LifetimeHours,
+				LBAFirstError: lbaPtr(t.LBA),
+			})
+		}
+	}
+
+	// The extended log (smartctl -x) holds more entries than the summary.
+	log := out.ATAErrorLog.Summary
+	if out.ATAErrorLog.Extended.Count > 0 || len(out.ATAErrorLog.Extended.Table) > 0 {
+		log = out.ATAErrorLog.Extended
+	}
+	r.ErrorCount = log.Count
+	for _, e := range log.Table {
+		hours := e.LifetimeHours
+		r.Errors = append(r.Errors, LoggedError{LifetimeHours: &hours, Description: e.Description})
+	}
+
+	for _, page := range out.ATADeviceStatistics.Pages {
+		for _, s := range page.Table {
+			if !s.Flags.Valid {
+				continue
+			}
+			r.DeviceStats = append(r.DeviceStats, DeviceStat{Page: page.Name, Name: s.Name, Value: s.Value})
+			if s.Name == "Percentage Used Endurance Indicator" {
+				used := safeInt(s.Value)
+				r.PercentageUsed = &used
+			}
+		}
+	}
+}
+
+// ataSelfTestType maps an ATA self-test subcommand to a test type. Bit 7 marks
+// captive mode, which does not change the test.
+func ataSelfTestType(v int) string {
+	switch v & 0x7f {
+	case 0:
+		return "offline"
+	case 1:
+		return "short"
+	case 2:
+		return "long"
+	case 3:
+		return "conveyance"
+	case 4:
+		return "selective"
+	default:
+		return "vendor"
+	}
+}
+
+// ataSelfTestResult maps an ATA self-test execution status byte; the high
+// nibble is the outcome. smartctl's passed flag is authoritative when present.
+func ataSelfTestResult(v int, passed *bool) string {
+	switch v >> 4 {
+	case 0:
+		return "passed"
+	case 1, 2:
+		return "aborted"
+	case 15:
+		return "in_progress"
+	}
+	if passed != nil && *passed {
+		return "passed"
+	}
+	return "failed"
+}
+
+func parseSmartctlNVMe(out *smartctlOutput, r *Report) {
+	if h := out.NVMeHealth; h != nil {
+		for _, f := range []struct {
+			id    int
+			value int64
+		}{
+			{NVMeCriticalWarning, h.CriticalWarning},
+			{NVMeTemperature, h.Temperature},
+			{NVMeAvailableSpare, h.AvailableSpare},
+			{NVMeAvailableSpareThresh, h.AvailableSpareThreshold},
+			{NVMePercentageUsed, h.PercentageUsed},
+			{NVMeDataUnitsRead, h.DataUnitsRead},
+			{NVMeDataUnitsWritten, h.DataUnitsWritten},
+			{NVMePowerOnHours, h.PowerOnHours},
+			{NVMeMediaErrors, h.MediaErrors},
+			{NVMeNumErrLogEntries, h.NumErrLogEntries},
+		} {
+			r.Attributes = append(r.Attributes, model.SMARTAttribute{
+				ID:        f.id,
+				Name:      nvmeAttributeName(f.id),
+				RawValue:  f.value,
+				RawString: strconv.FormatInt(f.value, 10),
+			})
+		}
+		used := safeInt(h.PercentageUsed)
+		r.PercentageUsed = &used
+		r.ErrorCount = h.NumErrLogEntries
+		if r.Temperature == nil {
+			t := safeInt(h.Temperature)
+			r.Temperature = &t
+		}
+		if r.PowerOnHours == nil {
+			hours := safeInt(h.PowerOnHours)
+			r.PowerOnHours = &hours
+		}
+	}
+
+	if l := out.NVMeSelfTestLog; l != nil {
+		r.SelfTestLog = &model.SelfTestLog{Tests: []model.SelfTest{}}
+		for _, t := range l.Table {
+			r.SelfTestLog.Tests = append(r.SelfTestLog.Tests, model.SelfTest{
+				Type:          nvmeSelfTestType(t.Code.Value),
+				Result:        nvmeSelfTestResult(t.Result.Value),
+				Status:        t.Result.String,
+				LifetimeHours: t.PowerOnHours,
+				LBAFirstError: lbaPtr(t.LBA),
+			})
+		}
+	}
+
+	for _, e := range out.NVMeErrorLog.Table {
+		r.Errors = append(r.Errors, LoggedError{Description: e.StatusField.String})
+	}
+}
+
+// nvmeAttributeName returns the display name ParseNVMeText gives a pseudo
+// attribute, so both parsers produce identical attributes.
+func nvmeAttributeName(id int) string {
+	for _, f := range nvmeFieldMap {
+		if f.ID == id {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+func nvmeSelfTestType(v int) string {
+	switch v {
+	case 1:
+		return "short"
+	case 2:
+		return "long"
+	default:
+		return "vendor"
+	}
+}
+
+// nvmeSelfTestResult maps the low nibble of an NVMe self-test result.
+func nvmeSelfTestResult(v int) string {
+	switch v & 0x0f {
+	case 0:
+		return "passed"
+	case 5, 6, 7:
+		return "failed"
+	default:
+		return "aborted"
+	}
+}
+
+func parseSmartctlSCSI(data []byte, out *smartctlOutput, r *Report) error {
+	if r.Temperature != nil {
+		r.Attributes = append(r.Attributes, scsiAttribute(SCSITemperature, "Temperature", int64(*r.Temperature)))
+	}
+	if r.PowerOnHours != nil {
+		r.Attributes = append(r.Attributes, scsiAttribute(SCSIPowerOnHours, "Power On Hours", int64(*r.PowerOnHours)))
+	}
+	if n := out.SCSIGrownDefectList; n != nil {
+		r.Attributes = append(r.Attributes, scsiAttribute(SCSIGrownDefects, "Grown Defect List", *n))
+	}
+	log := out.SCSIErrorCounterLog
+	for _, c := range []struct {
+		id       int
+		name     string
+		counters *smartctlSCSIErrorCounters
+	}{
+		{SCSIReadUncorrected, "Read Uncorrected Errors", log.Read},
+		{SCSIWriteUncorrected, "Write Uncorrected Errors", log.Write},
+		{SCSIVerifyUncorrected, "Verify Uncorrected Errors", log.Verify},
+	} {
+		if c.counters != nil {
+			r.Attributes = append(r.Attributes, scsiAttribute(c.id, c.name, c.counters.TotalUncorrected))
+			r.ErrorCount += c.counters.TotalUncorrected
+		}
+	}
+	if n := out.SCSINonMediumErrorCount; n != nil {
+		r.Attributes = append(r.Attributes, scsiAttribute(SCSINonMediumErrors, "Non-Medium Errors", *n))
+	}
+	if c := out.SCSIStartStopCycles; c != nil {
+		// Threshold holds the specified lifetime maximum, where reported.
+		cycles := scsiAttribute(SCSIStartStopCycles, "Start-Stop Cycles", c.StartStopCycles)
+		cycles.Threshold = c.SpecifiedCycles
+		loads := scsiAttribute(SCSILoadUnloadCycles, "Load-Unload Cycles", c.LoadUnloadCycles)
+		loads.Threshold = c.SpecifiedLoadCycles
+		r.Attributes = append(r.Attributes, cycles, loads)
+	}
+	r.PercentageUsed = out.SCSIPercentageUsed
+
+	tests, err := parseSCSISelfTests(data)
+	if err != nil {
+		return err
+	}
+	// An empty SCSI log emits no keys at all, which cannot be told apart from
+	// a device without one, so only a log with entries is reported.
+	if len(tests) > 0 {
+		r.SelfTestLog = &model.SelfTestLog{Tests: tests}
+	}
+	return nil
+}
+
+func scsiAttribute(id int, name string, value int64) model.SMARTAttribute {
+	return model.SMARTAttribute{ID: id, Name: name, RawValue: value, RawString: strconv.FormatInt(value, 10)}
+}
+
+// parseSCSISelfTests collects the scsi_self_test_N entries in log order
+// (newest first).
+func parseSCSISelfTests(data []byte) ([]model.SelfTest, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("parsing smartctl JSON: %w", err)
+	}
+	type indexed struct {
+		n    int
+		test model.SelfTest
+	}
+	var entries []indexed
+	for key, raw := range fields {
+		suffix, ok := strings.CutPrefix(key, "scsi_self_test_")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(suffix)
+		if err != nil {
+			continue
+		}
+		var t smartctlSCSISelfTest
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", key, err)
+		}
+		entries = append(entries, indexed{n, model.SelfTest{
+			Type:          scsiSelfTestType(t.Code.Value),
+			Result:        scsiSelfTestResult(t.Result.Value),
+			Status:        t.Result.String,
+			LifetimeHours: t.PowerOnTime.Hours,
+			LBAFirstError: lbaPtr(t.LBA),
+		}})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].n < entries[j].n })
+	tests := make([]model.SelfTest, 0, len(entries))
+	for _, e := range entries {
+		tests = append(tests, e.test)
+	}
+	if len(tests) == 0 {
+		return nil, nil
+	}
+	return tests, nil
+}
+
+// scsiSelfTestType maps a SCSI self-test code: 1 and 5 are background and
+// foreground short tests, 2 and 6 extended.
+func scsiSelfTestType(v int) string {
+	switch v {
+	case 1, 5:
+		return "short"
+	case 2, 6:
+		return "long"
+	default:
+		return "vendor"
+	}
+}
+
+func scsiSelfTestResult(v int) string {
+	switch v {
+	case 0:
+		return "passed"
+	case 1, 2:
+		return "aborted"
+	case 15:
+		return "in_progress"
+	default:
+		return "failed"
+	}
+}
+
+func lbaPtr(n *smartctlNumber) *int64 {
+	if n == nil {
+		return nil
+	}
+	v := int64(*n)
+	return &v
+}
+
+// DiskType derives the disk type from the protocol and rotation rate, or
+// returns "" when smartctl did not report enough to tell.
+func (r *Report) DiskType() string {
+	switch {
+	case r.Protocol == "nvme":
+		return "nvme"
+	case r.RotationRate == nil:
+		return ""
+	case *r.RotationRate == 0:
+		return "ssd"
+	default:
+		return "hdd"
+	}
+}
+
+// Apply copies the report onto disk: identity fields the disk does not
+// already have, health, attributes, the self-test log and the scalar
+// metrics. Callers then run EvaluateDisk, as for the PVE API parsers.
+func (r *Report) Apply(disk *model.Disk) {
+	disk.Protocol = r.Protocol
+	if t := r.DiskType(); t != "" {
+		disk.DiskType = t
+	}
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&disk.DevPath, r.DevPath},
+		{&disk.Model, r.Model},
+		{&disk.Serial, r.Serial},
+		{&disk.WWN, r.WWN},
+	} {
+		if *f.dst == "" {
+			*f.dst = f.src
+		}
+	}
+	if disk.SizeBytes == 0 {
+		disk.SizeBytes = r.SizeBytes
+	}
+	if r.Passed != nil {
+		disk.Health = "FAILED"
+		if *r.Passed {
+			disk.Health = "PASSED"
+		}
+	}
+	disk.Attributes = r.Attributes
+	disk.SelfTestLog = r.SelfTestLog
+	disk.Temperature = r.Temperature
+	disk.PowerOnHours = r.PowerOnHours
+	if r.PercentageUsed != nil {
+		remaining := 100 - *r.PercentageUsed
+		disk.Wearout = &remaining
+	}
+}
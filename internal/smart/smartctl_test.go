@@ -0,0 +1,288 @@
+package smart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readSmartctlFixture(t testing.TB, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "smartctl", name))
+	require.NoError(t, err)
+	return data
+}
+
+func attrByID(attrs []model.SMARTAttribute, id int) *model.SMARTAttribute {
+	for i := range attrs {
+		if attrs[i].ID == id {
+			return &attrs[i]
+		}
+	}
+	return nil
+}
+
+func TestParseSmartctlJSON_ATAHDD(t *testing.T) {
+	r, err := ParseSmartctlJSON(readSmartctlFixture(t, "ata-hdd.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ata", r.Protocol)
+	assert.Equal(t, "/dev/sda", r.DevPath)
+	assert.Equal(t, "ST8000VN004-2M2101", r.Model)
+	assert.Equal(t, "Seagate IronWolf", r.Family)
+	assert.Equal(t, "WSD1ABCD", r.Serial)
+	assert.Equal(t, "SC60", r.Firmware)
+	assert.Equal(t, "0x5000c500ce0a6a14", r.WWN)
+	assert.Equal(t, int64(8001563222016), r.SizeBytes)
+	require.NotNil(t, r.RotationRate)
+	assert.Equal(t, 7200, *r.RotationRate)
+	assert.Equal(t, "hdd", r.DiskType())
+	assert.Equal(t, "3.5 inches", r.FormFactor)
+	require.NotNil(t, r.Passed)
+	assert.True(t, *r.Passed)
+	assert.Equal(t, new(38), r.Temperature)
+	assert.Equal(t, new(23712), r.PowerOnHours)
+	assert.Nil(t, r.PercentageUsed)
+
+	require.Len(t, r.Attributes, 5)
+	realloc := attrByID(r.Attributes, 5)
+	require.NotNil(t, realloc)
+	assert.Equal(t, model.SMARTAttribute{
+		ID: 5, Name: "Reallocated_Sector_Ct", Value: 100, Worst: 100, Threshold: 10, RawValue: 8, RawString: "8",
+	}, *realloc)
+	// The packed raw value is ignored in favour of the leading integer of the
+	// raw string, matching the PVE API parser.
+	temp := attrByID(r.Attributes, 194)
+	require.NotNil(t, temp)
+	assert.Equal(t, int64(38), temp.RawValue)
+	assert.Equal(t, "38 (0 19 0 0 0)", temp.RawString)
+
+	require.NotNil(t, r.SelfTestLog)
+	assert.Equal(t, []model.SelfTest{
+		{Type: "long", Result: "failed", Status: "Completed: read failure", LifetimeHours: 23651, LBAFirstError: new(int64(1234567890))},
+		{Type: "short", Result: "passed", Status: "Completed without error", LifetimeHours: 23500},
+		{Type: "short", Result: "aborted", Status: "Interrupted (host reset)", LifetimeHours: 23400},
+	}, r.SelfTestLog.Tests)
+
+	assert.Equal(t, int64(2), r.ErrorCount)
+	require.Len(t, r.Errors, 2)
+	assert.Equal(t, new(23650), r.Errors[0].LifetimeHours)
+	assert.Equal(t, "Error: UNC at LBA = 0x499602d2 = 1234567890", r.Errors[0].Description)
+
+	// Invalid statistics are dropped.
+	assert.Equal(t, []DeviceStat{
+		{Page: "General Statistics", Name: "Lifetime Power-On Resets", Value: 41},
+		{Page: "General Statistics", Name: "Power-on Hours", Value: 23712},
+		{Page: "Temperature Statistics", Name: "Current Temperature", Value: 38},
+		{Page: "Temperature Statistics", Name: "Highest Temperature", Value: 51},
+	}, r.DeviceStats)
+}
+
+func TestParseSmartctlJSON_ATASSD(t *testing.T) {
+	r, err := ParseSmartctlJSON(readSmartctlFixture(t, "ata-ssd.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ssd", r.DiskType())
+	assert.Equal(t, "2.5 inches", r.FormFactor)
+	assert.Equal(t, new(4), r.PercentageUsed, "from device statistics")
+	require.NotNil(t, r.SelfTestLog, "log read but empty")
+	assert.Empty(t, r.SelfTestLog.Tests)
+	assert.Zero(t, r.ErrorCount)
+	assert.Empty(t, r.Errors)
+}
+
+func TestParseSmartctlJSON_NVMe(t *testing.T) {
+	r, err := ParseSmartctlJSON(readSmartctlFixture(t, "nvme.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "nvme", r.Protocol)
+	assert.Equal(t, "nvme", r.DiskType())
+	assert.Equal(t, "Samsung SSD 970 EVO Plus 1TB", r.Model)
+	assert.Equal(t, int64(1000204886016), r.SizeBytes, "falls back to nvme_total_capacity")
+	assert.Empty(t, r.WWN)
+	assert.Equal(t, new(41), r.Temperature)
+	assert.Equal(t, new(18234), r.PowerOnHours)
+	assert.Equal(t, new(7), r.PercentageUsed)
+	assert.Equal(t, int64(152), r.ErrorCount)
+
+	// The same pseudo attributes as the text parser.
+	text, err := ParseNVMeText(nvmeSmartctlOutput)
+	require.NoError(t, err)
+	require.Len(t, r.Attributes, len(text))
+	for _, want := range text {
+		got := attrByID(r.Attributes, want.ID)
+		require.NotNil(t, got, want.Name)
+		assert.Equal(t, want.Name, got.Name)
+	}
+	assert.Equal(t, int64(91877654), attrByID(r.Attributes, NVMeDataUnitsWritten).RawValue)
+
+	require.NotNil(t, r.SelfTestLog)
+	assert.Equal(t, []model.SelfTest{
+		{Type: "long", Result: "passed", Status: "Completed without error", LifetimeHours: 18100},
+		{Type: "short", Result: "failed", Status: "Completed: failed segments", LifetimeHours: 17950, LBAFirstError: new(int64(88123456))},
+	}, r.SelfTestLog.Tests)
+	require.Len(t, r.Errors, 1)
+	assert.Equal(t, "Invalid Field in Command", r.Errors[0].Description)
+	assert.Nil(t, r.Errors[0].LifetimeHours)
+}
+
+func TestParseSmartctlJSON_SCSI(t *testing.T) {
+	r, err := ParseSmartctlJSON(readSmartctlFixture(t, "scsi.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "scsi", r.Protocol)
+	assert.Equal(t, "HGST HUH721212AL5200", r.Model, "built from vendor and product")
+	assert.Equal(t, "hdd", r.DiskType())
+	assert.Equal(t, new(36), r.Temperature)
+	assert.Equal(t, new(41201), r.PowerOnHours)
+	assert.Equal(t, int64(1), r.ErrorCount, "uncorrected errors across read, write and verify")
+
+	raw := make(map[int]int64)
+	for _, a := range r.Attributes {
+		raw[a.ID] = a.RawValue
+	}
+	assert.Equal(t, map[int]int64{
+		SCSITemperature:       36,
+		SCSIPowerOnHours:      41201,
+		SCSIGrownDefects:      12,
+		SCSIReadUncorrected:   1,
+		SCSIWriteUncorrected:  0,
+		SCSIVerifyUncorrected: 0,
+		SCSINonMediumErrors:   5,
+		SCSIStartStopCycles:   94,
+		SCSILoadUnloadCycles:  2153,
+	}, raw)
+	assert.Equal(t, int64(50000), attrByID(r.Attributes, SCSIStartStopCycles).Threshold)
+	assert.Equal(t, int64(600000), attrByID(r.Attributes, SCSILoadUnloadCycles).Threshold)
+
+	require.NotNil(t, r.SelfTestLog)
+	assert.Equal(t, []model.SelfTest{
+		{Type: "long", Result: "passed", Status: "Completed", LifetimeHours: 41000},
+		{Type: "short", Result: "failed", Status: "Failed in segment --> 7", LifetimeHours: 40800, LBAFirstError: new(int64(2233445566))},
+	}, r.SelfTestLog.Tests)
+}
+
+func TestParseSmartctlJSON_DeviceOpenFailed(t *testing.T) {
+	data := []byte(`{
+		"smartctl": {
+			"exit_status": 2,
+			"messages": [{"string": "Smartctl open device: /dev/sdz failed: No such device", "severity": "error"}]
+		},
+		"device": {"name": "/dev/sdz"}
+	}`)
+	_, err := ParseSmartctlJSON(data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "No such device")
+}
+
+func TestParseSmartctlJSON_FailingDiskStillParses(t *testing.T) {
+	// Bit 3 (SMART status failing) comes with complete output.
+	data := []byte(`{
+		"smartctl": {"exit_status": 8},
+		"device": {"name": "/dev/sdd", "protocol": "ATA"},
+		"smart_status": {"passed": false}
+	}`)
+	r, err := ParseSmartctlJSON(data)
+	require.NoError(t, err)
+	require.NotNil(t, r.Passed)
+	assert.False(t, *r.Passed)
+}
+
+func TestParseSmartctlJSON_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":         "Smartctl open device: /dev/sda failed",
+		"unknown protocol": `{"device": {"name": "/dev/sg0", "protocol": "USB"}}`,
+		"no device":        `{}`,
+		"wrong type":       `{"device": {"protocol": "ATA"}, "rotation_rate": "fast"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSmartctlJSON([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSelfTestResultCodes(t *testing.T) {
+	assert.Equal(t, "in_progress", ataSelfTestResult(0xf9, nil))
+	assert.Equal(t, "aborted", ataSelfTestResult(0x10, nil))
+	assert.Equal(t, "failed", ataSelfTestResult(0x50, nil))
+	assert.Equal(t, "long", ataSelfTestType(0x82), "captive extended")
+	assert.Equal(t, "aborted", nvmeSelfTestResult(0x21))
+	assert.Equal(t, "failed", nvmeSelfTestResult(0x05))
+	assert.Equal(t, "in_progress", scsiSelfTestResult(15))
+	assert.Equal(t, "long", scsiSelfTestType(6))
+}
+
+func TestReport_Apply(t *testing.T) {
+	r, err := ParseSmartctlJSON(readSmartctlFixture(t, "nvme.json"))
+	require.NoError(t, err)
+
+	disk := &model.Disk{WWN: "eui.0025385b91234567", Node: "pve1"}
+	r.Apply(disk)
+
+	assert.Equal(t, "eui.0025385b91234567", disk.WWN, "existing identity is kept")
+	assert.Equal(t, "/dev/nvme0", disk.DevPath)
+	assert.Equal(t, "Samsung SSD 970 EVO Plus 1TB", disk.Model)
+	assert.Equal(t, "S4EWNX0R123456", disk.Serial)
+	assert.Equal(t, "nvme", disk.DiskType)
+	assert.Equal(t, "nvme", disk.Protocol)
+	assert.Equal(t, int64(1000204886016), disk.SizeBytes)
+	assert.Equal(t, "PASSED", disk.Health)
+	assert.Equal(t, new(41), disk.Temperature)
+	assert.Equal(t, new(18234), disk.PowerOnHours)
+	assert.Equal(t, new(93), disk.Wearout)
+	assert.Len(t, disk.Attributes, 10)
+}
+
+func TestReport_ApplyUnknownRotation(t *testing.T) {
+	r := &Report{Protocol: "ata"}
+	disk := &model.Disk{DiskType: "ssd"}
+	r.Apply(disk)
+	assert.Equal(t, "ssd", disk.DiskType, "kept when smartctl reports no rotation rate")
+	assert.Empty(t, disk.Health)
+	assert.Nil(t, disk.Wearout)
+}
+
+func FuzzParseSmartctlJSON(f *testing.F) {
+	// Real output for each protocol, covering every section the parser reads.
+	for _, name := range []string{"ata-hdd.json", "ata-ssd.json", "nvme.json", "scsi.json"} {
+		f.Add(readSmartctlFixture(f, name))
+	}
+	// Device open failure — returns an error.
+	f.Add([]byte(`{"smartctl": {"exit_status": 2, "messages": [{"string": "open failed", "severity": "error"}]}}`))
+	// LBA in both the bare and {"value": n} forms.
+	f.Add([]byte(`{"device": {"protocol": "ATA"}, "ata_smart_self_test_log": {"standard": {"table": [{"type": {"value": 2}, "status": {"value": 119}, "lba": {"value": 5}}]}}}`))
+	f.Add([]byte(`{"device": {"protocol": "SCSI"}, "scsi_self_test_3": {"result": {"value": 7}, "lba_first_failure": 9}, "scsi_self_test_x": {}}`))
+	// Not JSON, empty, and JSON of the wrong shape.
+	f.Add([]byte("smartctl 7.3 2022-02-28 r5338"))
+	f.Add([]byte(""))
+	f.Add([]byte(`[]`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := ParseSmartctlJSON(data)
+		if err != nil {
+			return
+		}
+		switch r.Protocol {
+		case "ata", "nvme", "scsi":
+		default:
+			t.Fatalf("unexpected protocol %q", r.Protocol)
+		}
+		if r.SelfTestLog == nil {
+			return
+		}
+		for _, st := range r.SelfTestLog.Tests {
+			switch st.Result {
+			case "passed", "failed", "aborted", "in_progress":
+			default:
+				t.Fatalf("unexpected self-test result %q", st.Result)
+			}
+		}
+		var disk model.Disk
+		r.Apply(&disk)
+		EvaluateDisk(&disk)
+	})
+}
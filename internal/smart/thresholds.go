@@ -15,8 +15,10 @@ type AttrThreshold struct {
 	Buckets []Bucket
 }
 
-// thresholdTable maps ATA attribute IDs to their failure-rate buckets.
-// Data derived from Backblaze hard drive statistics reports.
+// thresholdTable maps ATA attribute IDs and SCSI pseudo IDs to their
+// failure-rate buckets. Data derived from Backblaze hard drive statistics
+// reports; Backblaze publishes none for SAS drives, so the SCSI buckets
+// follow their nearest ATA counterpart.
 var thresholdTable = map[int]AttrThreshold{
 	// --- Critical attributes ---
 
@@ -81,6 +83,22 @@ var thresholdTable = map[int]AttrThreshold{
 		},
 	},
 
+	// --- Critical SCSI attributes ---
+
+	SCSIGrownDefects: {
+		ID: SCSIGrownDefects, Name: "Grown Defect List", // as 5 Reallocated Sectors
+		Buckets: []Bucket{
+			{Low: 0, High: 0, AnnualFailureRate: 0.025},
+			{Low: 1, High: 4, AnnualFailureRate: 0.027},
+			{Low: 4, High: 16, AnnualFailureRate: 0.075},
+			{Low: 16, High: 70, AnnualFailureRate: 0.236},
+			{Low: 70, High: 1<<62 - 1, AnnualFailureRate: 0.50},
+		},
+	},
+	SCSIReadUncorrected:   scsiUncorrected(SCSIReadUncorrected, "Read Uncorrected Errors"),
+	SCSIWriteUncorrected:  scsiUncorrected(SCSIWriteUncorrected, "Write Uncorrected Errors"),
+	SCSIVerifyUncorrected: scsiUncorrected(SCSIVerifyUncorrected, "Verify Uncorrected Errors"),
+
 	// --- Important (non-critical) attributes ---
 
 	1: {
@@ -126,6 +144,31 @@ var thresholdTable = map[int]AttrThreshold{
 			{Low: 100, High: 1<<62 - 1, AnnualFailureRate: 0.15},
 		},
 	},
+
+	// --- Important (non-critical) SCSI attributes ---
+
+	SCSINonMediumErrors: {
+		ID: SCSINonMediumErrors, Name: "Non-Medium Errors", // as 199 UDMA CRC Errors
+		Buckets: []Bucket{
+			{Low: 0, High: 0, AnnualFailureRate: 0.025},
+			{Low: 1, High: 100, AnnualFailureRate: 0.03},
+			{Low: 100, High: 1<<62 - 1, AnnualFailureRate: 0.10},
+		},
+	},
+}
+
+// scsiUncorrected builds the buckets of a SCSI uncorrected error counter,
+// as 187 Reported Uncorrectable Errors.
+func scsiUncorrected(id int, name string) AttrThreshold {
+	return AttrThreshold{
+		ID: id, Name: name,
+		Buckets: []Bucket{
+			{Low: 0, High: 0, AnnualFailureRate: 0.015},
+			{Low: 1, High: 10, AnnualFailureRate: 0.05},
+			{Low: 10, High: 50, AnnualFailureRate: 0.15},
+			{Low: 50, High: 1<<62 - 1, AnnualFailureRate: 0.40},
+		},
+	}
 }
 
 // criticalAttributes is the set of SMART attribute IDs considered critical
@@ -138,6 +181,11 @@ var criticalAttributes = map[int]bool{
 	196: true,
 	197: true,
 	198: true,
+
+	SCSIGrownDefects:      true,
+	SCSIReadUncorrected:   true,
+	SCSIWriteUncorrected:  true,
+	SCSIVerifyUncorrected: true,
 }
 
 // IsCritical reports whether the given SMART attribute ID is considered critical.
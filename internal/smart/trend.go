@@ -0,0 +1,95 @@
+package smart
+
+import (
+	"slices"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// TrendSpan is the longest window over which counter growth is measured;
+// older history does not affect trends.
+const TrendSpan = 30 * 24 * time.Hour
+
+// trendWindows are the spans over which counter growth is measured, matching
+// the Delta24h, Delta7d and Delta30d fields of model.AttributeTrend.
+var trendWindows = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, TrendSpan}
+
+// trendAttributes are the error counters whose growth signals a degrading
+// disk, per protocol. Their absolute buckets are scored by EvaluateAttribute;
+// a counter moving from 0 to 8 in a week matters more than its bucket.
+var trendAttributes = map[string][]int{
+	"ata": append([]int{
+		5,   // Reallocated Sectors Count
+		197, // Current Pending Sector Count
+		199, // UDMA CRC Error Count
+	}, scsiTrendAttributes...), // the PVE API reports SAS disks as ata
+	"nvme": {NVMeMediaErrors},
+	"scsi": scsiTrendAttributes,
+}
+
+// scsiTrendAttributes are the SCSI pseudo attributes tracked for growth.
+var scsiTrendAttributes = []int{
+	SCSIGrownDefects,
+	SCSIReadUncorrected,
+	SCSIWriteUncorrected,
+	SCSIVerifyUncorrected,
+}
+
+// TrendAttributeIDs returns the attribute IDs tracked for growth on a
+// protocol, or nil if none are.
+func TrendAttributeIDs(protocol string) []int {
+	return trendAttributes[protocol]
+}
+
+// EvaluateTrends evaluates counter growth with the built-in rules. See
+// Rules.EvaluateTrends.
+func EvaluateTrends(disk *model.Disk, history []model.AttributeSample, now time.Time) int {
+	var r *Rules
+	return r.EvaluateTrends(disk, history, now)
+}
+
+// EvaluateTrends compares the disk's current trend attributes against their
+// recorded history and sets disk.Trends. history must be ordered by
+// timestamp; each window's baseline is the oldest sample inside it. If any
+// counter grew, StatusDegrading is added to disk.Status. Returns the trend
+// status bit. ATA attributes the rules ignore are not tracked.
+func (r *Rules) EvaluateTrends(disk *model.Disk, history []model.AttributeSample, now time.Time) int {
+	disk.Trends = nil
+	ids := TrendAttributeIDs(disk.Protocol)
+	if len(ids) == 0 {
+		return model.StatusPassed
+	}
+	var rules map[int]AttrRule
+	if disk.Protocol == "ata" {
+		rules = r.forModel(disk.Model)
+	}
+
+	status := model.StatusPassed
+	for _, attr := range disk.Attributes {
+		if !slices.Contains(ids, attr.ID) {
+			continue
+		}
+		if rule := rules[attr.ID]; rule.Ignore != nil && *rule.Ignore {
+			continue
+		}
+		trend := model.AttributeTrend{ID: attr.ID, Name: attr.Name, Current: attr.RawValue}
+		deltas := []**int64{&trend.Delta24h, &trend.Delta7d, &trend.Delta30d}
+		for i, window := range trendWindows {
+			since := now.Add(-window).Unix()
+			for _, h := range history {
+				if h.ID == attr.ID && h.Timestamp >= since {
+					d := attr.RawValue - h.RawValue
+					*deltas[i] = &d
+					break
+				}
+			}
+		}
+		if trend.Growing() {
+			status = model.StatusDegrading
+		}
+		disk.Trends = append(disk.Trends, trend)
+	}
+	disk.Status |= status
+	return status
+}
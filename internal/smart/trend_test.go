@@ -0,0 +1,158 @@
+package smart
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateTrends(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) int64 { return now.Add(-d).Unix() }
+
+	disk := &model.Disk{
+		Protocol: "ata",
+		Status:   model.StatusPassed,
+		Attributes: []model.SMARTAttribute{
+			{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: 8},
+			{ID: 9, Name: "Power_On_Hours", RawValue: 20000},
+			{ID: 197, Name: "Current_Pending_Sector", RawValue: 0},
+			{ID: 199, Name: "UDMA_CRC_Error_Count", RawValue: 12},
+		},
+	}
+	history := []model.AttributeSample{
+		{Timestamp: ago(20 * 24 * time.Hour), ID: 5, RawValue: 0},
+		{Timestamp: ago(20 * 24 * time.Hour), ID: 197, RawValue: 4},
+		{Timestamp: ago(20 * 24 * time.Hour), ID: 199, RawValue: 12},
+		{Timestamp: ago(6 * 24 * time.Hour), ID: 5, RawValue: 0},
+		{Timestamp: ago(6 * 24 * time.Hour), ID: 197, RawValue: 2},
+		{Timestamp: ago(6 * 24 * time.Hour), ID: 199, RawValue: 12},
+		{Timestamp: ago(2 * time.Hour), ID: 5, RawValue: 8},
+		{Timestamp: ago(2 * time.Hour), ID: 197, RawValue: 0},
+		{Timestamp: ago(2 * time.Hour), ID: 199, RawValue: 12},
+	}
+
+	status := EvaluateTrends(disk, history, now)
+	assert.Equal(t, model.StatusDegrading, status)
+	assert.Equal(t, model.StatusDegrading, disk.Status)
+
+	require.Len(t, disk.Trends, 3)
+	realloc := disk.Trends[0]
+	assert.Equal(t, 5, realloc.ID)
+	assert.Equal(t, int64(8), realloc.Current)
+	assert.Equal(t, new(int64(0)), realloc.Delta24h)
+	assert.Equal(t, new(int64(8)), realloc.Delta7d)
+	assert.Equal(t, new(int64(8)), realloc.Delta30d)
+	assert.True(t, realloc.Growing())
+
+	// Pending sectors falling back to zero (remapped) is not degradation.
+	pending := disk.Trends[1]
+	assert.Equal(t, new(int64(-4)), pending.Delta30d)
+	assert.False(t, pending.Growing())
+
+	assert.False(t, disk.Trends[2].Growing())
+}
+
+func TestEvaluateTrends_Stable(t *testing.T) {
+	now := time.Now()
+	disk := &model.Disk{
+		Protocol:   "ata",
+		Status:     model.StatusWarnScrutiny,
+		Attributes: []model.SMARTAttribute{{ID: 5, RawValue: 20}},
+	}
+	history := []model.AttributeSample{{Timestamp: now.Add(-time.Hour).Unix(), ID: 5, RawValue: 20}}
+
+	assert.Equal(t, model.StatusPassed, EvaluateTrends(disk, history, now))
+	assert.Equal(t, model.StatusWarnScrutiny, disk.Status)
+	require.Len(t, disk.Trends, 1)
+	assert.Equal(t, new(int64(0)), disk.Trends[0].Delta24h)
+}
+
+func TestEvaluateTrends_NoHistory(t *testing.T) {
+	disk := &model.Disk{
+		Protocol:   "nvme",
+		Attributes: []model.SMARTAttribute{{ID: NVMeMediaErrors, Name: "Media Errors", RawValue: 3}},
+	}
+
+	assert.Equal(t, model.StatusPassed, EvaluateTrends(disk, nil, time.Now()))
+	require.Len(t, disk.Trends, 1)
+	assert.Nil(t, disk.Trends[0].Delta24h)
+	assert.Nil(t, disk.Trends[0].Delta7d)
+	assert.Nil(t, disk.Trends[0].Delta30d)
+}
+
+func TestEvaluateTrends_NVMeMediaErrors(t *testing.T) {
+	now := time.Now()
+	disk := &model.Disk{
+		Protocol:   "nvme",
+		Attributes: []model.SMARTAttribute{{ID: NVMeMediaErrors, Name: "Media Errors", RawValue: 3}},
+	}
+	history := []model.AttributeSample{{Timestamp: now.Add(-12 * time.Hour).Unix(), ID: NVMeMediaErrors, RawValue: 1}}
+
+	assert.Equal(t, model.StatusDegrading, EvaluateTrends(disk, history, now))
+	assert.Equal(t, new(int64(2)), disk.Trends[0].Delta24h)
+}
+
+func TestEvaluateTrends_UntrackedProtocol(t *testing.T) {
+	disk := &model.Disk{Protocol: "scsi", Attributes: []model.SMARTAttribute{{ID: SCSITemperature, RawValue: 40}}}
+	assert.Equal(t, model.StatusPassed, EvaluateTrends(disk, nil, time.Now()))
+	assert.Empty(t, disk.Trends)
+}
+
+func TestEvaluateTrends_SAS(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	// The PVE API reports SAS disks as ata, with SCSI pseudo attributes.
+	for _, protocol := range []string{"ata", "scsi"} {
+		disk := &model.Disk{
+			Protocol: protocol,
+			Attributes: []model.SMARTAttribute{
+				{ID: SCSIGrownDefects, Name: "Grown Defect List", RawValue: 3},
+				{ID: SCSIReadUncorrected, Name: "Read Uncorrected Errors", RawValue: 0},
+				{ID: SCSINonMediumErrors, Name: "Non-Medium Errors", RawValue: 40},
+			},
+		}
+		history := []model.AttributeSample{
+			{Timestamp: now.Add(-3 * 24 * time.Hour).Unix(), ID: SCSIGrownDefects, RawValue: 1},
+			{Timestamp: now.Add(-3 * 24 * time.Hour).Unix(), ID: SCSIReadUncorrected, RawValue: 0},
+			{Timestamp: now.Add(-3 * 24 * time.Hour).Unix(), ID: SCSINonMediumErrors, RawValue: 10},
+		}
+
+		assert.Equal(t, model.StatusDegrading, EvaluateTrends(disk, history, now), protocol)
+		require.Len(t, disk.Trends, 2, "non-medium errors are not tracked")
+		assert.Equal(t, new(int64(2)), disk.Trends[0].Delta7d)
+		assert.False(t, disk.Trends[1].Growing())
+	}
+}
+
+func TestRules_EvaluateTrends_Ignored(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	disk := func() *model.Disk {
+		return &model.Disk{
+			Protocol:   "ata",
+			Model:      "WDC WD40EFRX-68N32N0",
+			Attributes: []model.SMARTAttribute{{ID: 199, Name: "UDMA_CRC_Error_Count", RawValue: 40}},
+		}
+	}
+	history := []model.AttributeSample{{Timestamp: now.Add(-time.Hour).Unix(), ID: 199, RawValue: 12}}
+
+	// A flaky backplane: CRC errors are ignored for this model
+	r := &Rules{Models: []ModelRules{
+		{Model: regexp.MustCompile(`^WDC`), Attributes: map[int]AttrRule{199: {Ignore: new(true)}}},
+	}}
+	d := disk()
+	assert.Equal(t, model.StatusPassed, r.EvaluateTrends(d, history, now))
+	assert.Empty(t, d.Trends)
+	assert.Zero(t, d.Status&model.StatusDegrading)
+	for _, f := range r.ScoreRisk(d).Factors {
+		assert.NotEqual(t, model.RiskTrend, f.Source, "no trend factor")
+	}
+
+	d = disk()
+	assert.Equal(t, model.StatusDegrading, EvaluateTrends(d, history, now))
+	require.Len(t, d.Trends, 1)
+}
@@ -0,0 +1,121 @@
+package smart
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RawFormat says how a vendor packs an ATA attribute's 48-bit raw value.
+type RawFormat int
+
+const (
+	// RawPlain is a raw value that is the count itself.
+	RawPlain RawFormat = iota
+	// RawErrorCount16 holds an error count in bits 32-47 and an operation
+	// count in bits 0-31 (Seagate read, seek and ECC error rates).
+	RawErrorCount16
+	// RawSum16 holds three 16-bit counters that are added up (Seagate
+	// command timeouts).
+	RawSum16
+)
+
+// Decode returns the count a raw value represents. raw is the string smartctl
+// prints, and PVE reports, which the drive database may already have split:
+// "0 0 3" lists the 16-bit words, which are summed, and "3/123456789" leads
+// with the error count. Bits are unpacked only when raw is a plain integer.
+func (f RawFormat) Decode(raw string) int64 {
+	raw = strings.TrimSpace(raw)
+	if f == RawPlain {
+		return extractLeadingInt(raw)
+	}
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		switch f {
+		case RawErrorCount16:
+			return n >> 32 & 0xffff
+		case RawSum16:
+			return n&0xffff + n>>16&0xffff + n>>32&0xffff
+		}
+		return n
+	}
+	if before, _, ok := strings.Cut(raw, "/"); ok {
+		return extractLeadingInt(before)
+	}
+	var sum int64
+	for _, word := range strings.Fields(raw) {
+		n, err := strconv.ParseInt(word, 10, 64)
+		if err != nil {
+			return extractLeadingInt(raw)
+		}
+		sum += n
+	}
+	return sum
+}
+
+// VendorAttr is a vendor's meaning for one ATA attribute.
+type VendorAttr struct {
+	Name   string    // vendor name, used when the disk reports none
+	Raw    RawFormat // how the raw value is scored
+	Wear   bool      // normalized value is the percentage of rated life remaining
+	Writes bool      // raw value counts host writes, in the unit its name gives
+}
+
+// Vendor holds the attributes a vendor's firmware reports differently from
+// the common meaning, for disks whose model matches Model.
+type Vendor struct {
+	Name       string
+	Model      *regexp.Regexp
+	Attributes map[int]VendorAttr
+}
+
+// vendorTable lists the built-in vendor attribute maps; the first match wins.
+var vendorTable = []Vendor{
+	{
+		Name:  "Seagate",
+		Model: regexp.MustCompile(`(?i)^(seagate )?st\d`),
+		Attributes: map[int]VendorAttr{
+			1:   {Name: "Raw_Read_Error_Rate", Raw: RawErrorCount16},
+			7:   {Name: "Seek_Error_Rate", Raw: RawErrorCount16},
+			188: {Name: "Command_Timeout", Raw: RawSum16},
+			195: {Name: "Hardware_ECC_Recovered", Raw: RawErrorCount16},
+		},
+	},
+	{
+		Name:  "Samsung",
+		Model: regexp.MustCompile(`(?i)^samsung`),
+		Attributes: map[int]VendorAttr{
+			177: {Name: "Wear_Leveling_Count", Wear: true},
+			179: {Name: "Used_Rsvd_Blk_Cnt_Tot"},
+			181: {Name: "Program_Fail_Cnt_Total"},
+			182: {Name: "Erase_Fail_Count_Total"},
+			235: {Name: "POR_Recovery_Count"},
+		},
+	},
+	{
+		Name:  "Crucial/Micron",
+		Model: regexp.MustCompile(`(?i)^(crucial|micron|ct\d)`),
+		Attributes: map[int]VendorAttr{
+			173: {Name: "Ave_Block-Erase_Count"},
+			202: {Name: "Percent_Lifetime_Remain", Wear: true},
+			246: {Name: "Total_LBAs_Written", Writes: true},
+		},
+	},
+	{
+		Name:  "Intel",
+		Model: regexp.MustCompile(`(?i)^intel`),
+		Attributes: map[int]VendorAttr{
+			233: {Name: "Media_Wearout_Indicator", Wear: true},
+		},
+	},
+}
+
+// LookupVendor returns the vendor attribute map for a disk model, if any.
+func LookupVendor(model string) (*Vendor, bool) {
+	model = strings.TrimSpace(model)
+	for i := range vendorTable {
+		if vendorTable[i].Model.MatchString(model) {
+			return &vendorTable[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,68 @@
+package smart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawFormat_Decode(t *testing.T) {
+	tests := []struct {
+		name   string
+		format RawFormat
+		raw    string
+		want   int64
+	}{
+		{"plain", RawPlain, "123456789", 123456789},
+		{"plain, temperature with min/max", RawPlain, "34 (Min/Max 21/45)", 34},
+		{"error count, operations only", RawErrorCount16, "123456789", 0},
+		{"error count", RawErrorCount16, "12884901888", 3},
+		{"error count ignores bits above 47", RawErrorCount16, "281483566645248", 2},
+		// smartctl -v 1,raw24/raw32 (Seagate Exos, IronWolf Pro)
+		{"error count, split", RawErrorCount16, "3/123456789", 3},
+		{"error count, split, no errors", RawErrorCount16, "0/157342720", 0},
+		{"sum of words", RawSum16, "4295098371", 6},
+		{"sum, small value", RawSum16, "7", 7},
+		// smartctl -v 188,raw16 (Seagate)
+		{"sum, split words", RawSum16, "0 0 3", 3},
+		{"sum, split words all set", RawSum16, "1 2 3", 6},
+		{"sum, split zero", RawSum16, "0 0 0", 0},
+		{"unparsable falls back to the leading integer", RawSum16, "5 (abc)", 5},
+		{"empty", RawErrorCount16, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.format.Decode(tt.raw))
+		})
+	}
+}
+
+func TestLookupVendor(t *testing.T) {
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"ST8000VN004-2M2101", "Seagate"},
+		{"Seagate ST4000DM004", "Seagate"},
+		{"Samsung SSD 870 EVO 1TB", "Samsung"},
+		{"SAMSUNG MZ7LH960HAJR-00005", "Samsung"},
+		{"CT1000MX500SSD1", "Crucial/Micron"},
+		{"Crucial_CT525MX300SSD1", "Crucial/Micron"},
+		{"Micron_5300_MTFDDAK960TDS", "Crucial/Micron"},
+		{"INTEL SSDSC2KB480G8", "Intel"},
+		{"WDC WD40EFRX-68N32N0", ""},
+		{"STORE N GO", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			v, ok := LookupVendor(tt.model)
+			if tt.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.want, v.Name)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// Batch collects the rows written by one poll cycle so that WriteBatch can
+// store them in a single transaction. The zero value is an empty batch. A
+// Batch is not safe for concurrent use.
+type Batch struct {
+	rows  []batchRow
+	snaps Snapshots // forwarded to metrics sinks after commit
+}
+
+type batchRow struct {
+	query string
+	what  string // error context, e.g. "inserting node snapshot"
+	args  []any
+}
+
+func (b *Batch) add(query, what string, args []any) {
+	b.rows = append(b.rows, batchRow{query: query, what: what, args: args})
+}
+
+// Len returns the number of rows in the batch.
+func (b *Batch) Len() int {
+	return len(b.rows)
+}
+
+// AddNodeSnapshot adds a node metric snapshot to the batch.
+func (b *Batch) AddNodeSnapshot(snap model.NodeSnapshot) {
+	b.add(insertNodeSnapshotSQL, "inserting node snapshot", nodeSnapshotArgs(snap))
+	b.snaps.Nodes = append(b.snaps.Nodes, snap)
+}
+
+// AddGuestSnapshot adds a guest metric snapshot to the batch.
+func (b *Batch) AddGuestSnapshot(snap model.GuestSnapshot) {
+	b.add(insertGuestSnapshotSQL, "inserting guest snapshot", guestSnapshotArgs(snap))
+	b.snaps.Guests = append(b.snaps.Guests, snap)
+}
+
+// AddDisk adds a disk metadata upsert to the batch.
+func (b *Batch) AddDisk(d *model.Disk) {
+	b.add(upsertDiskSQL, "upserting disk "+d.WWN, diskArgs(d, time.Now().Unix()))
+}
+
+// AddDiskAbsence adds an update of a known disk's missed poll count,
+// removal time and replacement to the batch. Sightings reset these through
+// AddDisk.
+func (b *Batch) AddDiskAbsence(d *model.InventoryDisk) {
+	b.add(updateDiskAbsenceSQL, "updating disk absence "+d.WWN, diskAbsenceArgs(d))
+}
+
+// AddDiskEvent adds a disk lifecycle event to the batch.
+func (b *Batch) AddDiskEvent(e *model.DiskEvent) {
+	b.add(insertDiskEventSQL, "inserting disk event", diskEventArgs(e))
+}
+
+// AddSMARTSnapshot adds a disk SMART snapshot to the batch. A snapshot whose
+// attributes cannot be encoded is logged and skipped, so one bad disk does
+// not cost the rest of the poll.
+func (b *Batch) AddSMARTSnapshot(ts int64, disk *model.Disk) {
+	if err := b.addSMARTSnapshot(ts, disk); err != nil {
+		slog.Warn("skipping SMART snapshot", "disk", disk.WWN, "error", err)
+	}
+}
+
+func (b *Batch) addSMARTSnapshot(ts int64, disk *model.Disk) error {
+	args, err := smartSnapshotArgs(ts, disk)
+	if err != nil {
+		return err
+	}
+	b.add(insertSMARTSnapshotSQL, "inserting SMART snapshot", args)
+	b.snaps.SMART = append(b.snaps.SMART, SMARTSnapshot{Timestamp: ts, Disk: disk})
+	return nil
+}
+
+// AddPVETask adds a PVE task upsert to the batch.
+func (b *Batch) AddPVETask(t *model.PVETask) {
+	b.add(upsertPVETaskSQL, "upserting PVE task "+t.UPID, pveTaskArgs(t))
+}
+
+// AddBackupSnapshot adds a PBS backup snapshot to the batch.
+func (b *Batch) AddBackupSnapshot(ts int64, bk *model.Backup) {
+	b.add(insertBackupSnapshotSQL, "inserting backup snapshot", backupSnapshotArgs(ts, bk))
+	b.snaps.Backups = append(b.snaps.Backups, BackupSnapshot{Timestamp: ts, Backup: bk})
+}
+
+// AddDatastoreSnapshot adds a PBS datastore usage snapshot to the batch.
+func (b *Batch) AddDatastoreSnapshot(ts int64, ds *model.DatastoreStatus) {
+	b.add(insertDatastoreSnapshotSQL, "inserting datastore snapshot", datastoreSnapshotArgs(ts, ds))
+	b.snaps.Datastores = append(b.snaps.Datastores, DatastoreSnapshot{Timestamp: ts, Datastore: ds})
+}
+
+// WriteBatch writes every row in the batch in one transaction, preparing each
+// statement once. If any row fails, nothing is written. Once committed, the
+// snapshots are queued for the metrics sinks.
+func (s *Store) WriteBatch(b *Batch) error {
+	if len(b.rows) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning batch: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	// Statements prepared on the transaction are closed when it ends.
+	stmts := make(map[string]*sql.Stmt)
+	for _, r := range b.rows {
+		stmt, ok := stmts[r.query]
+		if !ok {
+			if stmt, err = tx.Prepare(r.query); err != nil {
+				return fmt.Errorf("%s: preparing statement: %w", r.what, err)
+			}
+			stmts[r.query] = stmt
+		}
+		if _, err := stmt.Exec(r.args...); err != nil {
+			return fmt.Errorf("%s: %w", r.what, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing batch: %w", err)
+	}
+	snaps := b.snaps
+	s.forward(&snaps)
+	return nil
+}
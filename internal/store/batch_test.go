@@ -0,0 +1,179 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBatch(t *testing.T) {
+	s := newTestStore(t)
+	ts := time.Now().Unix()
+	size := int64(1024)
+	verified := true
+
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve", CPUPct: 10})
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve2", CPUPct: 20})
+	b.AddGuestSnapshot(model.GuestSnapshot{Timestamp: ts, Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc", Name: "ct", Status: "running"})
+	disk := &model.Disk{WWN: "0x5000", Instance: "main", Node: "pve", DevPath: "/dev/sda", DiskType: "hdd", Protocol: "ata", Health: "PASSED"}
+	b.AddDisk(disk)
+	b.AddSMARTSnapshot(ts, disk)
+	b.AddPVETask(&model.PVETask{Instance: "main", UPID: "UPID:pve:1:vzdump::root@pam:", Node: "pve", Type: "vzdump", StartTime: ts})
+	b.AddBackupSnapshot(ts, &model.Backup{PBSInstance: "pbs", Datastore: "store1", BackupType: "ct", BackupID: "101", BackupTime: ts, SizeBytes: &size, Verified: &verified})
+	b.AddDatastoreSnapshot(ts, &model.DatastoreStatus{PBSInstance: "pbs", Name: "store1", TotalBytes: &size})
+	assert.Equal(t, 8, b.Len())
+
+	require.NoError(t, s.WriteBatch(&b))
+
+	for table, want := range map[string]int{
+		"node_snapshots": 2, "guest_snapshots": 1, "disks": 1, "smart_snapshots": 1,
+		"pve_tasks": 1, "backup_snapshots": 1, "datastore_snapshots": 1,
+	} {
+		assert.Equal(t, want, countRows(t, s, table), table)
+	}
+
+	var verifiedCol int
+	require.NoError(t, s.db.QueryRow(`SELECT verified FROM backup_snapshots`).Scan(&verifiedCol))
+	assert.Equal(t, 1, verifiedCol)
+}
+
+func TestWriteBatch_Empty(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.Close())
+
+	// An empty batch does not touch the database.
+	var b Batch
+	assert.NoError(t, s.WriteBatch(&b))
+}
+
+func TestWriteBatch_RollsBack(t *testing.T) {
+	s := newTestStore(t)
+	ts := time.Now().Unix()
+
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve"})
+	b.add("INSERT INTO no_such_table VALUES (?)", "inserting nothing", []any{1})
+
+	err := s.WriteBatch(&b)
+	assert.ErrorContains(t, err, "inserting nothing")
+	assert.Zero(t, countRows(t, s, "node_snapshots"))
+
+	// A row that fails to execute, not just to prepare, also rolls back.
+	var b2 Batch
+	b2.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve"})
+	b2.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve2", CPUPct: math.NaN()})
+	err = s.WriteBatch(&b2)
+	assert.ErrorContains(t, err, "inserting node snapshot")
+	assert.Zero(t, countRows(t, s, "node_snapshots"))
+}
+
+func TestWriteBatch_SMARTEncodingError(t *testing.T) {
+	s := newTestStore(t)
+	nan := math.NaN()
+
+	ts := time.Now().Unix()
+
+	// The disk that cannot be encoded is skipped; the rest of the batch is
+	// written.
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve"})
+	b.AddSMARTSnapshot(ts, &model.Disk{WWN: "0x5000", Attributes: []model.SMARTAttribute{{FailureRate: &nan}}})
+	b.AddSMARTSnapshot(ts, &model.Disk{WWN: "0x5001", Attributes: []model.SMARTAttribute{{ID: 5, RawValue: 1}}})
+	assert.Equal(t, 2, b.Len())
+	require.NoError(t, s.WriteBatch(&b))
+	assert.Equal(t, 1, countRows(t, s, "node_snapshots"))
+	assert.Equal(t, 1, countRows(t, s, "smart_snapshots"))
+
+	// A single insert still reports the error.
+	err := s.InsertSMARTSnapshot(ts, &model.Disk{WWN: "0x5000", Attributes: []model.SMARTAttribute{{FailureRate: &nan}}})
+	assert.ErrorContains(t, err, "marshaling SMART attributes")
+}
+
+func TestWriteBatch_ClosedDB(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.Close())
+
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: time.Now().Unix(), Instance: "main", Node: "pve"})
+	assert.ErrorContains(t, s.WriteBatch(&b), "beginning batch")
+}
+
+// pollCycle returns the rows one PVE poll writes for a cluster with the
+// given number of guests: three nodes, one SMART-monitored disk per node.
+func pollCycle(ts int64, guests int) ([]model.NodeSnapshot, []model.GuestSnapshot, []*model.Disk) {
+	rate := 1024.0
+	var nodes []model.NodeSnapshot
+	var disks []*model.Disk
+	for i := range 3 {
+		name := fmt.Sprintf("pve%d", i+1)
+		nodes = append(nodes, model.NodeSnapshot{
+			Timestamp: ts, Instance: "main", Node: name, CPUPct: 25, MemUsed: 8_000_000_000, MemTotal: 16_000_000_000,
+			Load1m: 1.0, UptimeSecs: 100000, NetIn: &rate, NetOut: &rate,
+		})
+		disks = append(disks, &model.Disk{
+			WWN: fmt.Sprintf("0x5000%d", i), Instance: "main", Node: name, DevPath: "/dev/sda",
+			DiskType: "ssd", Protocol: "nvme", Health: "PASSED",
+			Attributes: []model.SMARTAttribute{{ID: 5, Name: "Reallocated_Sector_Ct"}, {ID: 9, Name: "Power_On_Hours"}},
+		})
+	}
+	snaps := make([]model.GuestSnapshot, guests)
+	for i := range snaps {
+		snaps[i] = model.GuestSnapshot{
+			Timestamp: ts, Instance: "main", VMID: 100 + i, Node: nodes[i%3].Node, GuestType: "lxc",
+			Name: fmt.Sprintf("ct%d", i), Status: "running", CPUPct: 5, CPUs: 2,
+			MemUsed: 312_000_000, MemTotal: 2_048_000_000, NetIn: 1_000_000, NetOut: 500_000,
+			NetInRate: &rate, NetOutRate: &rate,
+		}
+	}
+	return nodes, snaps, disks
+}
+
+// BenchmarkWritePollCycle compares writing one 200-guest poll cycle row by
+// row, as the collectors used to, with a single batch.
+func BenchmarkWritePollCycle(b *testing.B) {
+	b.Run("per_row", func(b *testing.B) {
+		s := newTestStore(b)
+		ts := time.Now().Unix()
+		for b.Loop() {
+			ts++
+			nodes, guests, disks := pollCycle(ts, 200)
+			for _, n := range nodes {
+				_ = s.InsertNodeSnapshot(n)
+			}
+			for _, g := range guests {
+				_ = s.InsertGuestSnapshot(g)
+			}
+			for _, d := range disks {
+				_ = s.UpsertDisk(d)
+				_ = s.InsertSMARTSnapshot(ts, d)
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		s := newTestStore(b)
+		ts := time.Now().Unix()
+		for b.Loop() {
+			ts++
+			nodes, guests, disks := pollCycle(ts, 200)
+			var batch Batch
+			for _, n := range nodes {
+				batch.AddNodeSnapshot(n)
+			}
+			for _, g := range guests {
+				batch.AddGuestSnapshot(g)
+			}
+			for _, d := range disks {
+				batch.AddDisk(d)
+				batch.AddSMARTSnapshot(ts, d)
+			}
+			_ = s.WriteBatch(&batch)
+		}
+	})
+}
@@ -0,0 +1,402 @@
+package store
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownTable is returned when an export names a table that does not
+// exist in the schema.
+var ErrUnknownTable = errors.New("unknown table")
+
+// archiveManifest is the manifest.json entry of an export archive.
+type archiveManifest struct {
+	Format        string           `json:"format"`
+	SchemaVersion int              `json:"schema_version"`
+	ExportedAt    int64            `json:"exported_at"`
+	Tables        map[string]int64 `json:"tables"` // row counts
+}
+
+const (
+	archiveFormat   = "glint-export/1"
+	manifestName    = "manifest.json"
+	archiveTableDir = "tables/"
+)
+
+// Tables returns the data tables that can be exported, in name order.
+// Internal bookkeeping (schema_version) is left out.
+func (s *Store) Tables() ([]string, error) {
+	return listTables(s.db)
+}
+
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func listTables(q queryer) ([]string, error) {
+	rows, err := q.Query(`
+		SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'schema_version'
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scanning table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// tableColumns returns the columns of table in schema order.
+func tableColumns(q queryer, table string) ([]string, error) {
+	rows, err := q.Query(`SELECT name FROM pragma_table_info(?) ORDER BY cid`, table)
+	if err != nil {
+		return nil, fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scanning column of %s: %w", table, err)
+		}
+		cols = append(cols, name)
+	}
+	return cols, rows.Err()
+}
+
+// scanTable calls fn for each row of table with the column names and values.
+// Tables with a ts column are filtered to ts >= since and ordered by it;
+// other tables are read whole.
+func scanTable(q queryer, table string, since int64, fn func(cols []string, vals []any) error) error {
+	cols, err := tableColumns(q, table)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`SELECT * FROM "%s"`, table)
+	var args []any
+	if slices.Contains(cols, "ts") {
+		query += ` WHERE ts >= ? ORDER BY ts`
+		args = append(args, since)
+	}
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("querying %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	cols, err = rows.Columns()
+	if err != nil {
+		return fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return fmt.Errorf("scanning %s: %w", table, err)
+		}
+		for i, v := range vals {
+			if b, ok := v.([]byte); ok {
+				vals[i] = string(b)
+			}
+		}
+		if err := fn(cols, vals); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Export writes every data table to w as a zip archive holding one JSON Lines
+// file per table (tables/<name>.jsonl) and a manifest.json with the schema
+// version and row counts. The tables are read in one transaction, so the
+// archive is a consistent snapshot while collectors keep writing.
+func (s *Store) Export(w io.Writer) error {
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("beginning export: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // read-only
+
+	version, err := schemaVersion(s.db)
+	if err != nil {
+		return err
+	}
+	tables, err := listTables(tx)
+	if err != nil {
+		return err
+	}
+
+	manifest := archiveManifest{
+		Format:        archiveFormat,
+		SchemaVersion: version,
+		ExportedAt:    time.Now().Unix(),
+		Tables:        make(map[string]int64, len(tables)),
+	}
+	zw := zip.NewWriter(w)
+	create := func(name string) (io.Writer, error) {
+		return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Unix(manifest.ExportedAt, 0)})
+	}
+	for _, table := range tables {
+		f, err := create(archiveTableDir + table + ".jsonl")
+		if err != nil {
+			return fmt.Errorf("adding %s to archive: %w", table, err)
+		}
+		bw := bufio.NewWriter(f)
+		enc := json.NewEncoder(bw)
+		var n int64
+		err = scanTable(tx, table, 0, func(cols []string, vals []any) error {
+			row := make(map[string]any, len(cols))
+			for i, c := range cols {
+				row[c] = vals[i]
+			}
+			n++
+			return enc.Encode(row)
+		})
+		if err != nil {
+			return fmt.Errorf("exporting %s: %w", table, err)
+		}
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("writing %s: %w", table, err)
+		}
+		manifest.Tables[table] = n
+	}
+
+	f, err := create(manifestName)
+	if err != nil {
+		return fmt.Errorf("adding manifest: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return zw.Close()
+}
+
+// Import loads an archive written by Export into the store in a single
+// transaction, replacing rows with the same primary key. Archives from an
+// older schema version import into the current schema; columns added since
+// take their defaults. Archives from a newer version are rejected. It returns
+// the number of rows imported per table.
+func (s *Store) Import(r io.ReaderAt, size int64) (map[string]int64, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+
+	var manifest archiveManifest
+	var files []*zip.File
+	for _, f := range zr.File {
+		switch {
+		case f.Name == manifestName:
+			if err := readManifest(f, &manifest); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(f.Name, archiveTableDir) && strings.HasSuffix(f.Name, ".jsonl"):
+			files = append(files, f)
+		}
+	}
+	if manifest.Format != archiveFormat {
+		return nil, fmt.Errorf("not a Glint export archive (format %q)", manifest.Format)
+	}
+	if manifest.SchemaVersion > len(migrations) {
+		return nil, fmt.Errorf("archive schema version %d is newer than this build supports (%d)",
+			manifest.SchemaVersion, len(migrations))
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("beginning import: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	tables, err := listTables(tx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(files))
+	for _, f := range files {
+		table := strings.TrimSuffix(path.Base(f.Name), ".jsonl")
+		if !slices.Contains(tables, table) {
+			return nil, fmt.Errorf("importing %s: %w", table, ErrUnknownTable)
+		}
+		n, err := importTable(tx, table, f)
+		if err != nil {
+			return nil, fmt.Errorf("importing %s: %w", table, err)
+		}
+		counts[table] = n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing import: %w", err)
+	}
+	return counts, nil
+}
+
+func readManifest(f *zip.File, m *archiveManifest) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("opening manifest: %w", err)
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(m); err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+	return nil
+}
+
+// importTable inserts every row of a JSON Lines table file. Rows may carry
+// different column sets (an older archive lacks newer columns), so one
+// statement is prepared per distinct set.
+func importTable(tx *sql.Tx, table string, f *zip.File) (int64, error) {
+	cols, err := tableColumns(tx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	stmts := make(map[string]*sql.Stmt)
+	dec := json.NewDecoder(rc)
+	dec.UseNumber()
+	var n int64
+	for {
+		var row map[string]any
+		if err := dec.Decode(&row); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return n, fmt.Errorf("row %d: %w", n+1, err)
+		}
+
+		names := make([]string, 0, len(row))
+		for c := range row {
+			if !slices.Contains(cols, c) {
+				return n, fmt.Errorf("row %d: unknown column %q", n+1, c)
+			}
+			names = append(names, c)
+		}
+		slices.Sort(names)
+		key := strings.Join(names, ",")
+
+		stmt, ok := stmts[key]
+		if !ok {
+			query := fmt.Sprintf(`INSERT OR REPLACE INTO "%s" ("%s") VALUES (%s)`,
+				table, strings.Join(names, `", "`), strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", "))
+			if stmt, err = tx.Prepare(query); err != nil {
+				return n, fmt.Errorf("preparing insert: %w", err)
+			}
+			stmts[key] = stmt
+		}
+
+		args := make([]any, len(names))
+		for i, c := range names {
+			args[i] = jsonValue(row[c])
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return n, fmt.Errorf("row %d: %w", n+1, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// jsonValue converts a decoded JSON value back to the SQLite type it was
+// exported from: integers stay integers, everything else is unchanged.
+func jsonValue(v any) any {
+	num, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := num.Int64(); err == nil {
+		return i
+	}
+	f, _ := num.Float64()
+	return f
+}
+
+// ExportCSV writes one table as CSV with a header row. Tables with a ts
+// column are limited to rows with ts >= since.
+func (s *Store) ExportCSV(w io.Writer, table string, since int64) error {
+	tables, err := s.Tables()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(tables, table) {
+		return fmt.Errorf("exporting %q: %w", table, ErrUnknownTable)
+	}
+
+	cw := csv.NewWriter(w)
+	header := false
+	record := []string(nil)
+	err = scanTable(s.db, table, since, func(cols []string, vals []any) error {
+		if !header {
+			if err := cw.Write(cols); err != nil {
+				return err
+			}
+			header = true
+		}
+		record = record[:0]
+		for _, v := range vals {
+			record = append(record, csvValue(v))
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return fmt.Errorf("exporting %s: %w", table, err)
+	}
+	if !header {
+		// Still emit the header so an empty range opens as an empty sheet.
+		cols, err := tableColumns(s.db, table)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(cols); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
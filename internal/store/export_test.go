@@ -0,0 +1,210 @@
+package store
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedExport writes one row into most tables, including NULLs, floats and a
+// JSON text column.
+func seedExport(t *testing.T, s *Store, ts int64) {
+	t.Helper()
+	rate := 1234.5
+	temp := 41
+	require.NoError(t, s.UpsertPVEInstance("main", "https://pve:8006", true, "homelab"))
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve", CPUPct: 12.25, NetIn: &rate})
+	b.AddGuestSnapshot(model.GuestSnapshot{Timestamp: ts, Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc", Name: "ct, \"quoted\"", Status: "running"})
+	disk := &model.Disk{WWN: "0x5000", Instance: "main", Node: "pve", DevPath: "/dev/sda", DiskType: "hdd", Protocol: "ata",
+		Health: "PASSED", Temperature: &temp, Attributes: []model.SMARTAttribute{{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: 8}}}
+	b.AddDisk(disk)
+	b.AddSMARTSnapshot(ts, disk)
+	require.NoError(t, s.WriteBatch(&b))
+	require.NoError(t, s.InsertAlert(ts, "node_cpu_high", "main", "pve", "CPU at 95%", "warning"))
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	src := newTestStore(t)
+	ts := time.Now().Unix()
+	seedExport(t, src, ts)
+
+	var buf bytes.Buffer
+	require.NoError(t, src.Export(&buf))
+
+	dst := newTestStore(t)
+	counts, err := dst.Import(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), counts["node_snapshots"])
+	assert.Equal(t, int64(1), counts["smart_snapshots"])
+	assert.Equal(t, int64(0), counts["pve_tasks"])
+
+	for _, table := range []string{"pve_instances", "node_snapshots", "guest_snapshots", "disks", "smart_snapshots", "alert_log"} {
+		assert.Equal(t, countRows(t, src, table), countRows(t, dst, table), table)
+	}
+
+	points, err := dst.QueryNodeSparkline("main", "pve", "netin", ts-1)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	assert.Equal(t, 1234.5, points[0].Value)
+
+	var name string
+	var cpuTemp *float64
+	require.NoError(t, dst.db.QueryRow(`SELECT name FROM guest_snapshots`).Scan(&name))
+	require.NoError(t, dst.db.QueryRow(`SELECT cpu_temp FROM node_snapshots`).Scan(&cpuTemp))
+	assert.Equal(t, "ct, \"quoted\"", name)
+	assert.Nil(t, cpuTemp, "NULL survives the round trip")
+
+	var attrs string
+	require.NoError(t, dst.db.QueryRow(`SELECT attributes_json FROM smart_snapshots`).Scan(&attrs))
+	assert.Contains(t, attrs, "Reallocated_Sector_Ct")
+
+	// Importing the same archive again replaces rows instead of duplicating them.
+	_, err = dst.Import(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, 1, countRows(t, dst, "alert_log"))
+}
+
+func TestExport_Manifest(t *testing.T) {
+	s := newTestStore(t)
+	seedExport(t, s, time.Now().Unix())
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Export(&buf))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	var names []string
+	var manifest archiveManifest
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if f.Name == manifestName {
+			require.NoError(t, readManifest(f, &manifest))
+		}
+	}
+	assert.Contains(t, names, "tables/node_snapshots.jsonl")
+	assert.NotContains(t, names, "tables/schema_version.jsonl")
+	assert.Equal(t, archiveFormat, manifest.Format)
+	assert.Equal(t, len(migrations), manifest.SchemaVersion)
+	assert.Equal(t, int64(1), manifest.Tables["guest_snapshots"])
+}
+
+func TestExport_ReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glint.db")
+	src, err := New(path)
+	require.NoError(t, err)
+	seedExport(t, src, time.Now().Unix())
+	require.NoError(t, src.Close())
+
+	s, err := OpenReadOnly(path)
+	require.NoError(t, err)
+	defer s.Close()
+	var buf bytes.Buffer
+	require.NoError(t, s.Export(&buf))
+
+	dst := newTestStore(t)
+	counts, err := dst.Import(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), counts["guest_snapshots"])
+}
+
+// writeArchive builds an archive by hand from a manifest and table contents.
+func writeArchive(t *testing.T, m archiveManifest, tables map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range tables {
+		f, err := zw.Create(archiveTableDir + name + ".jsonl")
+		require.NoError(t, err)
+		_, err = f.Write([]byte(body))
+		require.NoError(t, err)
+	}
+	f, err := zw.Create(manifestName)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(f).Encode(m))
+	require.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestImport_OlderSchema(t *testing.T) {
+	s := newTestStore(t)
+
+	// A v2 archive predates the guest throughput columns.
+	r := writeArchive(t, archiveManifest{Format: archiveFormat, SchemaVersion: 2}, map[string]string{
+		"guest_snapshots": `{"ts":100,"instance":"main","vmid":101,"node":"pve","cluster_id":null,"guest_type":"qemu","name":"vm","status":"running","cpu_pct":1.5,"cpus":2,"mem_used":1,"mem_total":2,"disk_used":3,"disk_total":4,"net_in":5,"net_out":6}` + "\n",
+	})
+	counts, err := s.Import(r, r.Size())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), counts["guest_snapshots"])
+
+	var diskRead int64
+	var rate *float64
+	require.NoError(t, s.db.QueryRow(`SELECT disk_read, net_in_rate FROM guest_snapshots`).Scan(&diskRead, &rate))
+	assert.Zero(t, diskRead)
+	assert.Nil(t, rate)
+}
+
+func TestImport_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest archiveManifest
+		tables   map[string]string
+		wantErr  string
+	}{
+		{"not an archive manifest", archiveManifest{Format: "other"}, nil, "not a Glint export archive"},
+		{"newer schema", archiveManifest{Format: archiveFormat, SchemaVersion: len(migrations) + 1}, nil, "newer than this build"},
+		{"unknown table", archiveManifest{Format: archiveFormat, SchemaVersion: 1}, map[string]string{"bogus": "{}\n"}, "unknown table"},
+		{"unknown column", archiveManifest{Format: archiveFormat, SchemaVersion: 1}, map[string]string{"pbs_instances": `{"name":"x","host":"y","evil":1}` + "\n"}, `unknown column "evil"`},
+		{"bad row", archiveManifest{Format: archiveFormat, SchemaVersion: 1}, map[string]string{"pbs_instances": "{not json\n"}, "row 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			require.NoError(t, s.UpsertPBSInstance("keep", "h"))
+			r := writeArchive(t, tt.manifest, tt.tables)
+			_, err := s.Import(r, r.Size())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, 1, countRows(t, s, "pbs_instances"), "failed import leaves the database unchanged")
+		})
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().Unix()
+	seedExport(t, s, now-7200)
+	seedExport(t, s, now)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.ExportCSV(&buf, "guest_snapshots", now-60))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2, "header plus the row within since")
+	assert.True(t, strings.HasPrefix(lines[0], "ts,instance,vmid,node,"))
+	assert.Contains(t, lines[1], `"ct, ""quoted"""`)
+
+	// Tables without ts are exported whole.
+	buf.Reset()
+	require.NoError(t, s.ExportCSV(&buf, "pve_instances", now))
+	assert.Equal(t, "name,host,is_cluster,cluster_id\nmain,https://pve:8006,1,homelab\n", buf.String())
+}
+
+func TestExportCSV_EmptyAndUnknown(t *testing.T) {
+	s := newTestStore(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.ExportCSV(&buf, "pbs_instances", 0))
+	assert.Equal(t, "name,host\n", buf.String())
+
+	err := s.ExportCSV(&buf, "schema_version", 0)
+	assert.ErrorIs(t, err, ErrUnknownTable)
+	assert.ErrorIs(t, s.ExportCSV(&buf, "x; DROP TABLE disks", 0), ErrUnknownTable)
+}
@@ -0,0 +1,277 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// autoVacuumIncremental is the PRAGMA auto_vacuum value for INCREMENTAL mode.
+const autoVacuumIncremental = 2
+
+// enableIncrementalVacuum switches the database to incremental auto-vacuum so
+// the pruner can return freed pages to the filesystem without a full VACUUM.
+// Changing the mode needs one full VACUUM, which runs once on the first
+// start after upgrading. It runs after migrations so the pre-migration
+// backup is a copy of the untouched file; on a new database the VACUUM only
+// rewrites the empty schema.
+func enableIncrementalVacuum(db *sql.DB) error {
+	ctx := context.Background()
+	// The mode change only takes effect if VACUUM runs on the same connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring connection: %w", err)
+	}
+	defer conn.Close()
+
+	var mode int
+	if err := conn.QueryRowContext(ctx, `PRAGMA auto_vacuum`).Scan(&mode); err != nil {
+		return fmt.Errorf("reading auto_vacuum: %w", err)
+	}
+	if mode == autoVacuumIncremental {
+		return nil
+	}
+	start := time.Now()
+	if _, err := conn.ExecContext(ctx, `PRAGMA auto_vacuum = INCREMENTAL`); err != nil {
+		return fmt.Errorf("setting auto_vacuum: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `VACUUM`); err != nil {
+		return fmt.Errorf("vacuuming database: %w", err)
+	}
+	slog.Info("enabled incremental auto-vacuum", "duration", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// IncrementalVacuum returns all free pages to the filesystem and reports how
+// many bytes were released.
+func (s *Store) IncrementalVacuum() (int64, error) {
+	_, before, err := s.Size()
+	if err != nil {
+		return 0, err
+	}
+	if before == 0 {
+		return 0, nil
+	}
+	// Each step of the pragma frees pages; drain the result rows so it runs
+	// to completion.
+	rows, err := s.db.Query(`PRAGMA incremental_vacuum`)
+	if err != nil {
+		return 0, fmt.Errorf("incremental vacuum: %w", err)
+	}
+	for rows.Next() { //nolint:revive // draining
+	}
+	if err := rows.Close(); err != nil {
+		return 0, fmt.Errorf("incremental vacuum: %w", err)
+	}
+	_, after, err := s.Size()
+	if err != nil {
+		return 0, err
+	}
+	return before - after, nil
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns the problems it
+// reports. An empty result means the database is intact.
+func (s *Store) IntegrityCheck() ([]string, error) {
+	rows, err := s.db.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, fmt.Errorf("scanning integrity result: %w", err)
+		}
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// RowCounts returns the number of rows in each data table.
+func (s *Store) RowCounts() (map[string]int64, error) {
+	tables, err := s.Tables()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(tables))
+	for _, t := range tables {
+		var n int64
+		if err := s.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, t)).Scan(&n); err != nil {
+			return nil, fmt.Errorf("counting %s: %w", t, err)
+		}
+		counts[t] = n
+	}
+	return counts, nil
+}
+
+const (
+	dbBackupPrefix = "glint-"
+	dbBackupSuffix = ".db"
+	dbBackupLayout = "20060102-150405"
+)
+
+// BackupTo writes a consistent copy of the live database into dir as
+// glint-<timestamp>.db using VACUUM INTO, and returns its path. Collectors
+// can keep writing while it runs.
+func (s *Store) BackupTo(dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("creating backup directory: %w", err)
+	}
+	path := filepath.Join(dir, dbBackupPrefix+now.UTC().Format(dbBackupLayout)+dbBackupSuffix)
+	// Write to a temporary name so rotation never sees a partial copy.
+	tmp := path + ".tmp"
+	if err := vacuumInto(s.db, tmp); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("backing up database: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("backing up database: %w", err)
+	}
+	return path, nil
+}
+
+// listBackups returns the names of the backups in dir, oldest first. Only
+// files named like BackupTo's output are considered.
+func listBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing backups: %w", err)
+	}
+	var backups []string
+	for _, e := range entries {
+		if _, ok := backupTime(e.Name()); ok && !e.IsDir() {
+			backups = append(backups, e.Name())
+		}
+	}
+	// The timestamp layout sorts chronologically.
+	slices.Sort(backups)
+	return backups, nil
+}
+
+// backupTime parses the timestamp out of a backup file name.
+func backupTime(name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, dbBackupPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	if stamp, ok = strings.CutSuffix(stamp, dbBackupSuffix); !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(dbBackupLayout, stamp)
+	return t, err == nil
+}
+
+// rotateBackups deletes all but the newest keep backups in dir.
+func rotateBackups(dir string, keep int) ([]string, error) {
+	backups, err := listBackups(dir)
+	if err != nil || len(backups) <= keep {
+		return nil, err
+	}
+	var removed []string
+	for _, name := range backups[:len(backups)-keep] {
+		path := filepath.Join(dir, name)
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("removing old backup: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// BackupConfig schedules online database backups.
+type BackupConfig struct {
+	Dir      string        // where backups are written
+	Interval time.Duration // default 24h
+	Keep     int           // backups kept after rotation, default 7
+}
+
+// BackupScheduler periodically copies the database into a backup directory
+// and rotates old copies.
+type BackupScheduler struct {
+	store  *Store
+	config BackupConfig
+}
+
+// NewBackupScheduler creates a backup scheduler. Zero interval and keep take
+// their defaults.
+func NewBackupScheduler(store *Store, cfg BackupConfig) *BackupScheduler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.Keep <= 0 {
+		cfg.Keep = 7
+	}
+	return &BackupScheduler{store: store, config: cfg}
+}
+
+// Run starts the backup loop. A backup is taken at startup only when the
+// newest one is older than the interval, so frequent restarts neither skip
+// backups nor pile them up. It blocks until the context is cancelled.
+func (b *BackupScheduler) Run(ctx context.Context) error {
+	slog.Info("database backups started", "dir", b.config.Dir, "interval", b.config.Interval, "keep", b.config.Keep)
+
+	if b.due(time.Now()) {
+		b.backup(time.Now())
+	}
+
+	ticker := time.NewTicker(b.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("database backups stopped")
+			return ctx.Err()
+		case <-ticker.C:
+			b.backup(time.Now())
+		}
+	}
+}
+
+// due reports whether the newest backup is older than the interval.
+func (b *BackupScheduler) due(now time.Time) bool {
+	backups, err := listBackups(b.config.Dir)
+	if err != nil {
+		slog.Warn("listing database backups", "dir", b.config.Dir, "error", err)
+		return true
+	}
+	if len(backups) == 0 {
+		return true
+	}
+	newest, _ := backupTime(backups[len(backups)-1])
+	return now.Sub(newest) >= b.config.Interval
+}
+
+func (b *BackupScheduler) backup(now time.Time) {
+	start := time.Now()
+	path, err := b.store.BackupTo(b.config.Dir, now)
+	if err != nil {
+		slog.Error("database backup failed", "dir", b.config.Dir, "error", err)
+		return
+	}
+	slog.Info("backed up database", "path", path, "duration", time.Since(start).Round(time.Millisecond))
+
+	removed, err := rotateBackups(b.config.Dir, b.config.Keep)
+	if err != nil {
+		slog.Error("rotating database backups", "dir", b.config.Dir, "error", err)
+	}
+	for _, p := range removed {
+		slog.Info("removed old database backup", "path", p)
+	}
+}
@@ -0,0 +1,229 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func autoVacuumMode(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var mode int
+	require.NoError(t, db.QueryRow(`PRAGMA auto_vacuum`).Scan(&mode))
+	return mode
+}
+
+func TestNew_EnablesIncrementalVacuum(t *testing.T) {
+	s := newTestStore(t)
+	assert.Equal(t, autoVacuumIncremental, autoVacuumMode(t, s.db))
+}
+
+func TestNew_EnablesIncrementalVacuumOnExistingDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// A database created before incremental vacuum, with auto_vacuum off.
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE legacy (v TEXT); INSERT INTO legacy VALUES ('x')`)
+	require.NoError(t, err)
+	require.Equal(t, 0, autoVacuumMode(t, db))
+	require.NoError(t, db.Close())
+
+	s, err := New(path)
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, autoVacuumIncremental, autoVacuumMode(t, s.db))
+	assert.Equal(t, 1, countRows(t, s, "legacy"))
+
+	// The pre-migration backup is taken before the conversion rewrites the
+	// file, so it keeps the original mode.
+	backup, err := sql.Open("sqlite", backupPath(path, 0))
+	require.NoError(t, err)
+	defer backup.Close()
+	assert.Equal(t, 0, autoVacuumMode(t, backup))
+}
+
+func TestIncrementalVacuum_ReleasesFreePages(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.db.Exec(`CREATE TABLE scratch (v BLOB)`)
+	require.NoError(t, err)
+	for range 50 {
+		_, err := s.db.Exec(`INSERT INTO scratch VALUES (randomblob(8192))`)
+		require.NoError(t, err)
+	}
+	_, err = s.db.Exec(`DELETE FROM scratch`)
+	require.NoError(t, err)
+	_, free, err := s.Size()
+	require.NoError(t, err)
+	require.Positive(t, free)
+
+	released, err := s.IncrementalVacuum()
+	require.NoError(t, err)
+	assert.Equal(t, free, released)
+
+	_, free, err = s.Size()
+	require.NoError(t, err)
+	assert.Zero(t, free)
+}
+
+func TestIncrementalVacuum_NothingToRelease(t *testing.T) {
+	s := newTestStore(t)
+	released, err := s.IncrementalVacuum()
+	require.NoError(t, err)
+	assert.Zero(t, released)
+}
+
+func TestIntegrityCheck_Healthy(t *testing.T) {
+	s := newTestStore(t)
+	problems, err := s.IntegrityCheck()
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestRowCounts(t *testing.T) {
+	s := newTestStore(t)
+	seedExport(t, s, time.Now().Unix())
+
+	counts, err := s.RowCounts()
+	require.NoError(t, err)
+	tables, err := s.Tables()
+	require.NoError(t, err)
+	assert.Len(t, counts, len(tables))
+	assert.NotContains(t, counts, "schema_version")
+	for table, n := range counts {
+		assert.Equal(t, countRows(t, s, table), int(n), table)
+	}
+}
+
+func TestBackupTo(t *testing.T) {
+	s := newTestStore(t)
+	seedExport(t, s, time.Now().Unix())
+	dir := filepath.Join(t.TempDir(), "backups")
+
+	now := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+	path, err := s.BackupTo(dir, now)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "glint-20260314-150926.db"), path)
+
+	backup, err := New(path)
+	require.NoError(t, err)
+	defer backup.Close()
+	problems, err := backup.IntegrityCheck()
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	want, err := s.RowCounts()
+	require.NoError(t, err)
+	got, err := backup.RowCounts()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// No temporary file is left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestRotateBackups(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"glint-20260101-000000.db",
+		"glint-20260102-000000.db",
+		"glint-20260103-000000.db",
+		"glint-20260104-000000.db",
+		"glint-20260105-000000.db.tmp", // in progress
+		"notes.txt",
+		"glint-latest.db",
+	}
+	for _, n := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, n), nil, 0o600))
+	}
+
+	removed, err := rotateBackups(dir, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "glint-20260101-000000.db"),
+		filepath.Join(dir, "glint-20260102-000000.db"),
+	}, removed)
+
+	backups, err := listBackups(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"glint-20260103-000000.db", "glint-20260104-000000.db"}, backups)
+	for _, n := range names[4:] {
+		assert.FileExists(t, filepath.Join(dir, n))
+	}
+}
+
+func TestRotateBackups_MissingDir(t *testing.T) {
+	removed, err := rotateBackups(filepath.Join(t.TempDir(), "missing"), 3)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestNewBackupScheduler_Defaults(t *testing.T) {
+	b := NewBackupScheduler(nil, BackupConfig{Dir: "/backups"})
+	assert.Equal(t, 24*time.Hour, b.config.Interval)
+	assert.Equal(t, 7, b.config.Keep)
+}
+
+func TestBackupScheduler_Due(t *testing.T) {
+	s := newTestStore(t)
+	dir := t.TempDir()
+	b := NewBackupScheduler(s, BackupConfig{Dir: dir, Interval: 6 * time.Hour, Keep: 2})
+
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	assert.True(t, b.due(now), "no backups yet")
+
+	b.backup(now)
+	assert.False(t, b.due(now.Add(time.Hour)))
+	assert.True(t, b.due(now.Add(6*time.Hour)))
+
+	// Rotation keeps the newest two.
+	b.backup(now.Add(6 * time.Hour))
+	b.backup(now.Add(12 * time.Hour))
+	backups, err := listBackups(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"glint-20260314-180000.db", "glint-20260315-000000.db"}, backups)
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// An unversioned database with auto_vacuum off: New would back it up,
+	// migrate and vacuum it.
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE legacy (v TEXT); INSERT INTO legacy VALUES ('x')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	s, err := OpenReadOnly(path)
+	require.NoError(t, err)
+	problems, err := s.IntegrityCheck()
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	counts, err := s.RowCounts()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"legacy": 1}, counts)
+	_, err = s.db.Exec(`INSERT INTO legacy VALUES ('y')`)
+	assert.Error(t, err)
+	require.NoError(t, s.Close())
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.NoFileExists(t, backupPath(path, 0))
+}
+
+func TestOpenReadOnly_Missing(t *testing.T) {
+	_, err := OpenReadOnly(filepath.Join(t.TempDir(), "missing.db"))
+	assert.Error(t, err)
+}
@@ -1,6 +1,64 @@
 package store
 
-const schema = `
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// migration is one ordered schema change. A migration's version is its
+// position in migrations, starting at 1. Released steps must never be edited
+// or reordered; schema changes are always appended as a new step.
+//
+// Databases created before schema_version existed report version 0, whatever
+// release created them, so every step is written to be a no-op when its
+// change is already present (CREATE ... IF NOT EXISTS, addColumns).
+type migration struct {
+	name string
+	up   func(tx *sql.Tx) error
+}
+
+var migrations = []migration{
+	{"initial schema", execSQL(schemaInitial)},
+	{"pve tasks", execSQL(schemaPVETasks)},
+	{"guest throughput", addColumns("guest_snapshots",
+		column{"disk_read", "INTEGER NOT NULL DEFAULT 0"},
+		column{"disk_write", "INTEGER NOT NULL DEFAULT 0"},
+		column{"net_in_rate", "REAL"},
+		column{"net_out_rate", "REAL"},
+		column{"disk_read_rate", "REAL"},
+		column{"disk_write_rate", "REAL"},
+	)},
+	{"node rrd metrics", addColumns("node_snapshots",
+		column{"net_in_rate", "REAL"},
+		column{"net_out_rate", "REAL"},
+		column{"psi_cpu_some", "REAL"},
+		column{"psi_io_some", "REAL"},
+		column{"psi_io_full", "REAL"},
+		column{"psi_mem_some", "REAL"},
+		column{"psi_mem_full", "REAL"},
+	)},
+	{"rollup tiers", execSQL(schemaRollups)},
+	{"disk inventory", addColumns("disks",
+		column{"missed_polls", "INTEGER NOT NULL DEFAULT 0"},
+		column{"removed_at", "INTEGER"},
+		column{"replaced_by", "TEXT"},
+	)},
+	{"disk events", execSQL(schemaDiskEvents)},
+}
+
+const schemaVersionTable = `
+CREATE TABLE IF NOT EXISTS schema_version (
+    version     INTEGER PRIMARY KEY,
+    name        TEXT    NOT NULL,
+    applied_at  INTEGER NOT NULL
+);
+`
+
+const schemaInitial = `
 -- Registered PVE instances from config
 CREATE TABLE IF NOT EXISTS pve_instances (
     name        TEXT PRIMARY KEY,
@@ -142,3 +200,280 @@ CREATE INDEX IF NOT EXISTS idx_guest_vmid ON guest_snapshots(instance, vmid, ts)
 CREATE INDEX IF NOT EXISTS idx_smart_wwn ON smart_snapshots(wwn, ts);
 CREATE INDEX IF NOT EXISTS idx_alert_ts ON alert_log(ts);
 `
+
+const schemaPVETasks = `
+-- PVE cluster tasks, keyed by UPID; ts is the task start time (7d retention)
+CREATE TABLE IF NOT EXISTS pve_tasks (
+    upid        TEXT PRIMARY KEY,
+    ts          INTEGER NOT NULL,
+    instance    TEXT    NOT NULL,
+    node        TEXT    NOT NULL,
+    task_type   TEXT    NOT NULL,
+    task_id     TEXT,
+    user        TEXT,
+    end_time    INTEGER,
+    status      TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_pve_tasks_ts ON pve_tasks(ts);
+`
+
+const schemaRollups = `
+-- Node and guest rollups: per-bucket averages (same column names as the raw
+-- tables) plus maxima. 5-minute buckets (30d retention) are computed from the
+-- raw snapshots, hourly buckets (365d retention) from the 5-minute tier.
+CREATE TABLE IF NOT EXISTS node_snapshots_5m (
+    ts           INTEGER NOT NULL,
+    instance     TEXT    NOT NULL,
+    node         TEXT    NOT NULL,
+    samples      INTEGER NOT NULL,
+    cpu_pct      REAL    NOT NULL,
+    cpu_pct_max  REAL    NOT NULL,
+    mem_used     REAL    NOT NULL,
+    mem_used_max INTEGER NOT NULL,
+    mem_total    REAL    NOT NULL,
+    rootfs_used  REAL    NOT NULL,
+    rootfs_total REAL    NOT NULL,
+    load_1m      REAL    NOT NULL,
+    io_wait      REAL    NOT NULL,
+    net_in_rate      REAL,
+    net_in_rate_max  REAL,
+    net_out_rate     REAL,
+    net_out_rate_max REAL,
+    psi_cpu_some REAL,
+    psi_io_some  REAL,
+    psi_mem_some REAL,
+    PRIMARY KEY (ts, instance, node)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS node_snapshots_1h (
+    ts           INTEGER NOT NULL,
+    instance     TEXT    NOT NULL,
+    node         TEXT    NOT NULL,
+    samples      INTEGER NOT NULL,
+    cpu_pct      REAL    NOT NULL,
+    cpu_pct_max  REAL    NOT NULL,
+    mem_used     REAL    NOT NULL,
+    mem_used_max INTEGER NOT NULL,
+    mem_total    REAL    NOT NULL,
+    rootfs_used  REAL    NOT NULL,
+    rootfs_total REAL    NOT NULL,
+    load_1m      REAL    NOT NULL,
+    io_wait      REAL    NOT NULL,
+    net_in_rate      REAL,
+    net_in_rate_max  REAL,
+    net_out_rate     REAL,
+    net_out_rate_max REAL,
+    psi_cpu_some REAL,
+    psi_io_some  REAL,
+    psi_mem_some REAL,
+    PRIMARY KEY (ts, instance, node)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS guest_snapshots_5m (
+    ts           INTEGER NOT NULL,
+    instance     TEXT    NOT NULL,
+    vmid         INTEGER NOT NULL,
+    samples      INTEGER NOT NULL,
+    cpu_pct      REAL    NOT NULL,
+    cpu_pct_max  REAL    NOT NULL,
+    mem_used     REAL    NOT NULL,
+    mem_used_max INTEGER NOT NULL,
+    mem_total    REAL    NOT NULL,
+    disk_used    REAL    NOT NULL,
+    disk_total   REAL    NOT NULL,
+    net_in_rate         REAL,
+    net_in_rate_max     REAL,
+    net_out_rate        REAL,
+    net_out_rate_max    REAL,
+    disk_read_rate      REAL,
+    disk_read_rate_max  REAL,
+    disk_write_rate     REAL,
+    disk_write_rate_max REAL,
+    PRIMARY KEY (ts, instance, vmid)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS guest_snapshots_1h (
+    ts           INTEGER NOT NULL,
+    instance     TEXT    NOT NULL,
+    vmid         INTEGER NOT NULL,
+    samples      INTEGER NOT NULL,
+    cpu_pct      REAL    NOT NULL,
+    cpu_pct_max  REAL    NOT NULL,
+    mem_used     REAL    NOT NULL,
+    mem_used_max INTEGER NOT NULL,
+    mem_total    REAL    NOT NULL,
+    disk_used    REAL    NOT NULL,
+    disk_total   REAL    NOT NULL,
+    net_in_rate         REAL,
+    net_in_rate_max     REAL,
+    net_out_rate        REAL,
+    net_out_rate_max    REAL,
+    disk_read_rate      REAL,
+    disk_read_rate_max  REAL,
+    disk_write_rate     REAL,
+    disk_write_rate_max REAL,
+    PRIMARY KEY (ts, instance, vmid)
+) WITHOUT ROWID;
+
+-- Rollup watermarks: each tier is complete up to (excluding) last_ts
+CREATE TABLE IF NOT EXISTS rollup_state (
+    name    TEXT PRIMARY KEY,
+    last_ts INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_guest_5m_vmid ON guest_snapshots_5m(instance, vmid, ts);
+CREATE INDEX IF NOT EXISTS idx_guest_1h_vmid ON guest_snapshots_1h(instance, vmid, ts);
+`
+
+const schemaDiskEvents = `
+-- Disk lifecycle events (added, removed, moved, replaced). A few rows per
+-- disk over its life, so they are kept indefinitely for the slot history.
+CREATE TABLE IF NOT EXISTS disk_events (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    ts            INTEGER NOT NULL,
+    event         TEXT    NOT NULL,
+    wwn           TEXT    NOT NULL,
+    instance      TEXT    NOT NULL,
+    node          TEXT    NOT NULL,
+    dev_path      TEXT    NOT NULL,
+    model         TEXT,
+    serial        TEXT,
+    prev_wwn      TEXT,
+    prev_serial   TEXT,
+    prev_node     TEXT,
+    prev_dev_path TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_disk_events_ts ON disk_events(ts);
+`
+
+// column is a column added to an existing table by a later migration.
+type column struct {
+	name, def string
+}
+
+// execSQL returns a migration step that runs the given statements.
+func execSQL(stmts string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(stmts)
+		return err
+	}
+}
+
+// addColumns returns a migration step that adds each column to table unless
+// it already exists.
+func addColumns(table string, cols ...column) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, c := range cols {
+			var n int
+			if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, c.name).Scan(&n); err != nil {
+				return fmt.Errorf("inspecting %s: %w", table, err)
+			}
+			if n > 0 {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, c.name, c.def)); err != nil {
+				return fmt.Errorf("adding column %s.%s: %w", table, c.name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// migrate brings the database at dbPath up to the latest schema version.
+// Before an existing database is changed, an exact copy is written next to it
+// with VACUUM INTO. Each step runs in its own transaction together with its
+// schema_version row, so a failing step leaves the database at the previous
+// version.
+func migrate(db *sql.DB, dbPath string) error {
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables); err != nil {
+		return fmt.Errorf("inspecting database: %w", err)
+	}
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	latest := len(migrations)
+	if current > latest {
+		return fmt.Errorf("database schema version %d is newer than this build supports (%d)", current, latest)
+	}
+	if current == latest {
+		return nil
+	}
+
+	if tables > 0 {
+		backup := backupPath(dbPath, current)
+		if err := vacuumInto(db, backup); err != nil {
+			return fmt.Errorf("backing up database before migration: %w", err)
+		}
+		slog.Info("backed up database before migration", "path", backup, "version", current)
+	}
+	if _, err := db.Exec(schemaVersionTable); err != nil {
+		return fmt.Errorf("creating schema_version: %w", err)
+	}
+
+	for i := current; i < latest; i++ {
+		if err := applyMigration(db, i+1, migrations[i]); err != nil {
+			return err
+		}
+	}
+	slog.Info("migrated database schema", "from", current, "to", latest)
+	return nil
+}
+
+func applyMigration(db *sql.DB, version int, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning migration %d: %w", version, err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if err := m.up(tx); err != nil {
+		return fmt.Errorf("migration %d (%s): %w", version, m.name, err)
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+		version, m.name, time.Now().Unix()); err != nil {
+		return fmt.Errorf("recording migration %d: %w", version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing migration %d: %w", version, err)
+	}
+	return nil
+}
+
+// schemaVersion returns the highest applied migration, or 0 for a database
+// that predates schema_version.
+func schemaVersion(db *sql.DB) (int, error) {
+	var exists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'`).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("reading schema version: %w", err)
+	}
+	if exists == 0 {
+		return 0, nil
+	}
+	var v sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_version`).Scan(&v); err != nil {
+		return 0, fmt.Errorf("reading schema version: %w", err)
+	}
+	return int(v.Int64), nil
+}
+
+// backupPath is where the copy of a database at the given schema version is
+// written before it is migrated.
+func backupPath(dbPath string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", dbPath, version)
+}
+
+// vacuumInto writes a compacted, consistent copy of the database to path,
+// replacing any file already there.
+func vacuumInto(db *sql.DB, path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing old copy: %w", err)
+	}
+	if _, err := db.Exec(`VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return os.Chmod(path, 0o600)
+}
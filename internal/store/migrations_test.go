@@ -0,0 +1,230 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaOf describes every column and index in the database, for comparing
+// an upgraded database with a freshly created one.
+func schemaOf(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.Query(`
+		SELECT m.type, m.name, p.name, p.type, p."notnull", COALESCE(p.dflt_value, ''), p.pk
+		FROM sqlite_master m LEFT JOIN pragma_table_info(m.name) p
+		WHERE m.type IN ('table', 'index') AND m.name NOT LIKE 'sqlite_%'
+		ORDER BY m.type, m.name, p.name`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var kind, table string
+		var col, typ, dflt sql.NullString
+		var notNull, pk sql.NullInt64
+		require.NoError(t, rows.Scan(&kind, &table, &col, &typ, &notNull, &dflt, &pk))
+		out = append(out, fmt.Sprintf("%s %s.%s %s notnull=%d default=%s pk=%d",
+			kind, table, col.String, typ.String, notNull.Int64, dflt.String, pk.Int64))
+	}
+	require.NoError(t, rows.Err())
+	return out
+}
+
+// openFixture creates a database at path from a SQL fixture.
+func openFixture(t *testing.T, fixture, path string) {
+	t.Helper()
+	stmts, err := os.ReadFile(fixture)
+	require.NoError(t, err)
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(string(stmts))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestMigrate_Fresh(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(filepath.Join(dir, "glint.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	v, err := schemaVersion(s.db)
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations), v)
+
+	var names []string
+	rows, err := s.db.Query(`SELECT name FROM schema_version ORDER BY version`)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	require.Len(t, names, len(migrations))
+	assert.Equal(t, migrations[0].name, names[0])
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.bak"))
+	require.NoError(t, err)
+	assert.Empty(t, matches, "a new database is not backed up")
+}
+
+// TestMigrate_UpgradeFixtures opens a database written by each earlier
+// release and checks that it ends up with the current schema and keeps its
+// data. When a release changes the schema, add a dump of a database it wrote
+// as testdata/migrations/vN.sql.
+func TestMigrate_UpgradeFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob("testdata/migrations/*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+
+	want := schemaOf(t, newTestStore(t).db)
+
+	for _, fixture := range fixtures {
+		t.Run(strings.TrimSuffix(filepath.Base(fixture), ".sql"), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "glint.db")
+			openFixture(t, fixture, path)
+
+			s, err := New(path)
+			require.NoError(t, err)
+			t.Cleanup(func() { s.Close() })
+
+			v, err := schemaVersion(s.db)
+			require.NoError(t, err)
+			assert.Equal(t, len(migrations), v)
+			assert.Equal(t, want, schemaOf(t, s.db))
+
+			for _, table := range []string{
+				"pve_instances", "nodes", "pbs_instances", "disks", "node_snapshots", "guest_snapshots",
+				"smart_snapshots", "backup_snapshots", "datastore_snapshots", "alert_log",
+			} {
+				assert.Equal(t, 1, countRows(t, s, table), table)
+			}
+
+			var diskRead int64
+			var rate sql.NullFloat64
+			require.NoError(t, s.db.QueryRow(`SELECT disk_read, net_in_rate FROM guest_snapshots`).Scan(&diskRead, &rate))
+			assert.Zero(t, diskRead)
+			assert.False(t, rate.Valid)
+
+			// The upgraded database accepts current writes.
+			r := 1.5
+			require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+				Timestamp: time.Now().Unix(), Instance: "main", VMID: 101, Node: "pve",
+				GuestType: "lxc", Name: "ct", Status: "running", NetInRate: &r,
+			}))
+
+			// The pre-migration backup holds the original database.
+			backup, err := sql.Open("sqlite", backupPath(path, 0))
+			require.NoError(t, err)
+			defer backup.Close()
+			var n int
+			require.NoError(t, backup.QueryRow(`SELECT COUNT(*) FROM guest_snapshots`).Scan(&n))
+			assert.Equal(t, 1, n)
+			var tables int
+			require.NoError(t, backup.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_version'`).Scan(&tables))
+			assert.Zero(t, tables)
+		})
+	}
+}
+
+func TestMigrate_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glint.db")
+	s, err := New(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = New(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	var n int
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&n))
+	assert.Equal(t, len(migrations), n)
+	_, err = os.Stat(backupPath(path, len(migrations)))
+	assert.ErrorIs(t, err, os.ErrNotExist, "an up-to-date database is not backed up")
+}
+
+func TestMigrate_FromVersioned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glint.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(schemaVersionTable)
+	require.NoError(t, err)
+	for i := range 2 {
+		require.NoError(t, applyMigration(db, i+1, migrations[i]))
+	}
+	require.NoError(t, db.Close())
+
+	s, err := New(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	v, err := schemaVersion(s.db)
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations), v)
+	_, err = os.Stat(backupPath(path, 2))
+	assert.NoError(t, err)
+}
+
+func TestMigrate_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glint.db")
+	s, err := New(path)
+	require.NoError(t, err)
+	_, err = s.db.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, 'future', 0)`, len(migrations)+1)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	_, err = New(path)
+	assert.ErrorContains(t, err, "newer than this build supports")
+}
+
+func TestApplyMigration_RollsBack(t *testing.T) {
+	s := newTestStore(t)
+	before, err := schemaVersion(s.db)
+	require.NoError(t, err)
+
+	broken := migration{"broken", execSQL(`CREATE TABLE half_done (a INTEGER); SELECT * FROM no_such_table`)}
+	err = applyMigration(s.db, before+1, broken)
+	assert.ErrorContains(t, err, "broken")
+
+	after, err := schemaVersion(s.db)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	var n int
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'half_done'`).Scan(&n))
+	assert.Zero(t, n)
+}
+
+func TestMigrate_ClosedDB(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.Close())
+	assert.Error(t, migrate(s.db, filepath.Join(t.TempDir(), "glint.db")))
+	assert.Error(t, applyMigration(s.db, 1, migrations[0]))
+}
+
+func TestMigrate_BackupFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glint.db")
+	openFixture(t, "testdata/migrations/v1.sql", path)
+	// A non-empty directory where the backup goes cannot be replaced.
+	require.NoError(t, os.MkdirAll(filepath.Join(backupPath(path, 0), "keep"), 0o750))
+
+	_, err := New(path)
+	assert.ErrorContains(t, err, "backing up database before migration")
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+	v, err := schemaVersion(db)
+	require.NoError(t, err)
+	assert.Zero(t, v, "the database is left untouched")
+}
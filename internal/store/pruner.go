@@ -15,6 +15,9 @@ type RetentionConfig struct {
 	BackupSnapshots    time.Duration // default 7d
 	DatastoreSnapshots time.Duration // default 7d
 	AlertLog           time.Duration // default 30d
+	PVETasks           time.Duration // default 7d
+	Rollups5m          time.Duration // node and guest 5-minute rollups, default 30d
+	Rollups1h          time.Duration // node and guest hourly rollups, default 365d
 }
 
 // DefaultRetention returns the default retention periods.
@@ -26,10 +29,15 @@ func DefaultRetention() RetentionConfig {
 		BackupSnapshots:    7 * 24 * time.Hour,
 		DatastoreSnapshots: 7 * 24 * time.Hour,
 		AlertLog:           30 * 24 * time.Hour,
+		PVETasks:           7 * 24 * time.Hour,
+		Rollups5m:          30 * 24 * time.Hour,
+		Rollups1h:          365 * 24 * time.Hour,
 	}
 }
 
-// Pruner periodically removes old data from the store.
+// Pruner periodically rolls up node and guest snapshots into the
+// downsampled tiers, removes old data from the store and releases the freed
+// pages with an incremental vacuum.
 type Pruner struct {
 	store     *Store
 	retention RetentionConfig
@@ -68,19 +76,39 @@ func (p *Pruner) Run(ctx context.Context) error {
 
 func (p *Pruner) prune() {
 	now := time.Now().Unix()
+
+	// Roll up before deleting so no raw sample is pruned un-aggregated. If
+	// the rollup fails, raw node and guest snapshots are kept until it
+	// succeeds on a later run.
+	n, rollupErr := p.store.rollup(now)
+	if rollupErr != nil {
+		slog.Error("rollup failed, keeping raw node and guest snapshots", "error", rollupErr)
+	} else if n > 0 {
+		slog.Info("rolled up snapshots", "buckets", n)
+	}
+
 	tables := []struct {
 		name      string
 		retention time.Duration
+		rolledUp  bool // raw source of a rollup tier
 	}{
-		{"node_snapshots", p.retention.NodeSnapshots},
-		{"guest_snapshots", p.retention.GuestSnapshots},
-		{"smart_snapshots", p.retention.SMARTSnapshots},
-		{"backup_snapshots", p.retention.BackupSnapshots},
-		{"datastore_snapshots", p.retention.DatastoreSnapshots},
-		{"alert_log", p.retention.AlertLog},
+		{"node_snapshots", p.retention.NodeSnapshots, true},
+		{"guest_snapshots", p.retention.GuestSnapshots, true},
+		{"smart_snapshots", p.retention.SMARTSnapshots, false},
+		{"backup_snapshots", p.retention.BackupSnapshots, false},
+		{"datastore_snapshots", p.retention.DatastoreSnapshots, false},
+		{"alert_log", p.retention.AlertLog, false},
+		{"pve_tasks", p.retention.PVETasks, false},
+		{"node_snapshots_5m", p.retention.Rollups5m, false},
+		{"guest_snapshots_5m", p.retention.Rollups5m, false},
+		{"node_snapshots_1h", p.retention.Rollups1h, false},
+		{"guest_snapshots_1h", p.retention.Rollups1h, false},
 	}
 
 	for _, t := range tables {
+		if t.rolledUp && rollupErr != nil {
+			continue
+		}
 		cutoff := now - int64(t.retention.Seconds())
 		result, err := p.store.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE ts < ?", t.name), cutoff)
 		if err != nil {
@@ -95,4 +123,11 @@ func (p *Pruner) prune() {
 			slog.Info("pruned old data", "table", t.name, "rows", rows)
 		}
 	}
+
+	// Hand the pages freed by pruning back to the filesystem.
+	if freed, err := p.store.IncrementalVacuum(); err != nil {
+		slog.Error("incremental vacuum failed", "error", err)
+	} else if freed > 0 {
+		slog.Info("released free database pages", "bytes", freed)
+	}
 }
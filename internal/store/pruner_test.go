@@ -18,6 +18,9 @@ func TestDefaultRetention(t *testing.T) {
 	assert.Equal(t, 7*24*time.Hour, r.BackupSnapshots)
 	assert.Equal(t, 7*24*time.Hour, r.DatastoreSnapshots)
 	assert.Equal(t, 30*24*time.Hour, r.AlertLog)
+	assert.Equal(t, 7*24*time.Hour, r.PVETasks)
+	assert.Equal(t, 30*24*time.Hour, r.Rollups5m)
+	assert.Equal(t, 365*24*time.Hour, r.Rollups1h)
 }
 
 func TestNewPruner(t *testing.T) {
@@ -85,15 +88,63 @@ func TestPrune_DeletesOldData(t *testing.T) {
 	p.prune()
 
 	// Old node snapshot should be deleted, recent one kept
-	points, err := s.QueryNodeSparkline("main", "pve", "cpu", 0)
+	points, err := s.QueryNodeSparkline("main", "pve", "cpu", now-3600)
 	require.NoError(t, err)
 	assert.Len(t, points, 1)
 	assert.Equal(t, now, points[0].Timestamp)
+	assert.Equal(t, 1, countRows(t, s, "node_snapshots"))
 
 	// Old guest snapshot should be deleted
-	guestPoints, err := s.QueryGuestSparkline("main", 101, 0)
+	assert.Zero(t, countRows(t, s, "guest_snapshots"))
+
+	// Both were rolled up before being pruned
+	assert.Equal(t, 1, countRows(t, s, "node_snapshots_5m"))
+	assert.Equal(t, 1, countRows(t, s, "guest_snapshots_5m"))
+}
+
+func TestPrune_RollupFailureKeepsRawSnapshots(t *testing.T) {
+	s := newTestStore(t)
+
+	now := time.Now().Unix()
+	oldTS := now - int64((49 * time.Hour).Seconds())
+	require.NoError(t, s.InsertNodeSnapshot(model.NodeSnapshot{Timestamp: oldTS, Instance: "main", Node: "pve", CPUPct: 50}))
+	require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+		Timestamp: oldTS, Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc", Name: "test", Status: "running",
+	}))
+	require.NoError(t, s.InsertAlert(now-int64((31*24*time.Hour).Seconds()), "test", "main", "pve", "old alert", "info"))
+
+	// Break the guest rollup tier so the rollup fails partway.
+	_, err := s.db.Exec("DROP TABLE guest_snapshots_5m")
+	require.NoError(t, err)
+
+	NewPruner(s, DefaultRetention()).prune()
+
+	assert.Equal(t, 1, countRows(t, s, "node_snapshots"))
+	assert.Equal(t, 1, countRows(t, s, "guest_snapshots"))
+	assert.Zero(t, countRows(t, s, "alert_log"), "other tables are still pruned")
+}
+
+// countRows returns the number of rows in a table.
+func countRows(t *testing.T, s *Store, table string) int {
+	t.Helper()
+	var n int
+	require.NoError(t, s.db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&n))
+	return n
+}
+
+func TestPrune_DeletesOldPVETasks(t *testing.T) {
+	s := newTestStore(t)
+
+	now := time.Now().Unix()
+	require.NoError(t, s.UpsertPVETask(&model.PVETask{UPID: "old", StartTime: now - int64((8 * 24 * time.Hour).Seconds()), Instance: "main", Node: "pve", Type: "qmstart"}))
+	require.NoError(t, s.UpsertPVETask(&model.PVETask{UPID: "new", StartTime: now, Instance: "main", Node: "pve", Type: "qmstart"}))
+
+	NewPruner(s, DefaultRetention()).prune()
+
+	tasks, err := s.QueryPVETasks(0)
 	require.NoError(t, err)
-	assert.Empty(t, guestPoints)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "new", tasks[0].UPID)
 }
 
 func TestPrune_ClosedDB(t *testing.T) {
@@ -148,7 +199,5 @@ func TestPrunerRun_PrunesOnStartup(t *testing.T) {
 	_ = p.Run(ctx)
 
 	// Old data should be pruned
-	points, err := s.QueryNodeSparkline("main", "pve", "cpu", 0)
-	require.NoError(t, err)
-	assert.Empty(t, points)
+	assert.Zero(t, countRows(t, s, "node_snapshots"))
 }
@@ -0,0 +1,213 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// rollupTier is a downsampled copy of a snapshot table. Averaged columns keep
+// the raw column names so the sparkline expressions work on every tier;
+// columns in max also keep their per-bucket maximum as <col>_max.
+type rollupTier struct {
+	table    string
+	source   string
+	fromTier bool // source is itself a rollup tier with samples and _max columns
+	bucket   int64
+	keys     string
+	avg      []string
+	max      []string
+}
+
+var (
+	nodeRollupAvg = []string{
+		"cpu_pct", "mem_used", "mem_total", "rootfs_used", "rootfs_total", "load_1m", "io_wait",
+		"net_in_rate", "net_out_rate", "psi_cpu_some", "psi_io_some", "psi_mem_some",
+	}
+	nodeRollupMax  = []string{"cpu_pct", "mem_used", "net_in_rate", "net_out_rate"}
+	guestRollupAvg = []string{
+		"cpu_pct", "mem_used", "mem_total", "disk_used", "disk_total",
+		"net_in_rate", "net_out_rate", "disk_read_rate", "disk_write_rate",
+	}
+	guestRollupMax = []string{"cpu_pct", "mem_used", "net_in_rate", "net_out_rate", "disk_read_rate", "disk_write_rate"}
+)
+
+// rollupTiers are computed in order, so each tier's source is up to date
+// before the tier itself is rolled up.
+var rollupTiers = []rollupTier{
+	{"node_snapshots_5m", "node_snapshots", false, 300, "instance, node", nodeRollupAvg, nodeRollupMax},
+	{"node_snapshots_1h", "node_snapshots_5m", true, 3600, "instance, node", nodeRollupAvg, nodeRollupMax},
+	{"guest_snapshots_5m", "guest_snapshots", false, 300, "instance, vmid", guestRollupAvg, guestRollupMax},
+	{"guest_snapshots_1h", "guest_snapshots_5m", true, 3600, "instance, vmid", guestRollupAvg, guestRollupMax},
+}
+
+// historyTable returns the snapshot table or rollup tier to read for a query
+// starting at since: the finest resolution whose retention still covers the
+// span. A minute of slack keeps e.g. a 48h request, whose since was computed
+// just before the query, on the raw table.
+func (s *Store) historyTable(base string, since int64) string {
+	raw := s.retention.NodeSnapshots
+	if base == "guest_snapshots" {
+		raw = s.retention.GuestSnapshots
+	}
+	span := time.Since(time.Unix(since, 0)) - time.Minute
+	switch {
+	case span <= raw:
+		return base
+	case span <= s.retention.Rollups5m:
+		return base + "_5m"
+	default:
+		return base + "_1h"
+	}
+}
+
+// historySource returns the FROM source for a query starting at since. When
+// the span needs a rollup tier, samples newer than the tier's watermark are
+// not rolled up yet, so the raw tail after the watermark is appended to the
+// tier's buckets. Only the averaged columns are available on the result.
+func (s *Store) historySource(base string, since int64) (string, error) {
+	table := s.historyTable(base, since)
+	if table == base {
+		return base, nil
+	}
+	idx := slices.IndexFunc(rollupTiers, func(t rollupTier) bool { return t.table == table })
+	if idx < 0 {
+		return "", fmt.Errorf("unknown rollup tier %q", table)
+	}
+	t := rollupTiers[idx]
+	watermark, _, err := s.rollupWatermark(table)
+	if err != nil {
+		return "", err
+	}
+	cols := "ts, " + t.keys + ", " + strings.Join(t.avg, ", ")
+	return fmt.Sprintf(`(
+		SELECT %[1]s FROM %[2]s WHERE ts < %[4]d
+		UNION ALL
+		SELECT %[1]s FROM %[3]s WHERE ts >= %[4]d)`,
+		cols, table, base, watermark), nil
+}
+
+// insertSQL builds the statement that recomputes the tier's buckets in
+// [start, end). Hourly buckets average the 5-minute averages, which all
+// cover close to the same number of samples.
+func (t rollupTier) insertSQL() string {
+	cols := []string{"ts", t.keys, "samples"}
+	sel := []string{fmt.Sprintf("(ts / %d) * %d", t.bucket, t.bucket), t.keys}
+	if t.fromTier {
+		sel = append(sel, "SUM(samples)")
+	} else {
+		sel = append(sel, "COUNT(*)")
+	}
+	for _, c := range t.avg {
+		cols = append(cols, c)
+		sel = append(sel, fmt.Sprintf("AVG(%s)", c))
+	}
+	for _, c := range t.max {
+		cols = append(cols, c+"_max")
+		src := c
+		if t.fromTier {
+			src = c + "_max"
+		}
+		sel = append(sel, fmt.Sprintf("MAX(%s)", src))
+	}
+	return fmt.Sprintf(`
+		INSERT OR REPLACE INTO %s (%s)
+		SELECT %s FROM %s
+		WHERE ts >= ? AND ts < ?
+		GROUP BY 1, %s`,
+		t.table, strings.Join(cols, ", "), strings.Join(sel, ", "), t.source, t.keys)
+}
+
+// rollup computes every complete bucket of each tier since its watermark and
+// returns the number of buckets written.
+func (s *Store) rollup(now int64) (int64, error) {
+	var total int64
+	for _, t := range rollupTiers {
+		n, err := s.rollupTier(t, now)
+		if err != nil {
+			return total, fmt.Errorf("rolling up %s: %w", t.table, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (s *Store) rollupTier(t rollupTier, now int64) (int64, error) {
+	end := now / t.bucket * t.bucket
+	if t.fromTier {
+		// Only roll up buckets whose source buckets are all computed.
+		srcEnd, ok, err := s.rollupWatermark(t.source)
+		if err != nil || !ok {
+			return 0, err
+		}
+		end = min(end, srcEnd/t.bucket*t.bucket)
+	}
+
+	start, ok, err := s.rollupWatermark(t.table)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		var first sql.NullInt64
+		if err := s.db.QueryRow(fmt.Sprintf("SELECT MIN(ts) FROM %s", t.source)).Scan(&first); err != nil {
+			return 0, fmt.Errorf("finding first sample: %w", err)
+		}
+		if !first.Valid {
+			return 0, nil
+		}
+		start = first.Int64
+	}
+	start = start / t.bucket * t.bucket
+	if start >= end {
+		return 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("beginning rollup: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	result, err := tx.Exec(t.insertSQL(), start, end)
+	if err != nil {
+		return 0, fmt.Errorf("computing buckets: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO rollup_state (name, last_ts) VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET last_ts = excluded.last_ts`,
+		t.table, end,
+	); err != nil {
+		return 0, fmt.Errorf("updating watermark: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing rollup: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n, nil
+}
+
+// rollupWatermark returns the timestamp up to which a tier is complete.
+func (s *Store) rollupWatermark(table string) (int64, bool, error) {
+	var ts int64
+	err := s.db.QueryRow(`SELECT last_ts FROM rollup_state WHERE name = ?`, table).Scan(&ts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("reading rollup watermark: %w", err)
+	}
+	return ts, true, nil
+}
+
+// RewindRollups moves every rollup watermark back to since, so that
+// snapshots inserted behind it (such as backfilled history) are included the
+// next time the pruner computes rollups.
+func (s *Store) RewindRollups(since int64) error {
+	if _, err := s.db.Exec(`UPDATE rollup_state SET last_ts = MIN(last_ts, ?)`, since); err != nil {
+		return fmt.Errorf("rewinding rollups: %w", err)
+	}
+	return nil
+}
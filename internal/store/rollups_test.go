@@ -0,0 +1,200 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertRollupFixture inserts one node and one guest sample per minute for
+// n minutes starting at base, with CPU equal to the minute index.
+func insertRollupFixture(t *testing.T, s *Store, base int64, n int) {
+	t.Helper()
+	for i := range n {
+		ts := base + int64(i*60)
+		rate := float64(i * 10)
+		require.NoError(t, s.InsertNodeSnapshot(model.NodeSnapshot{
+			Timestamp: ts, Instance: "main", Node: "pve",
+			CPUPct: float64(i), MemUsed: 4096, MemTotal: 8192, NetIn: &rate,
+		}))
+		snap := model.GuestSnapshot{
+			Timestamp: ts, Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc", Name: "ct", Status: "running",
+			CPUPct: float64(i), MemUsed: 256, MemTotal: 1024,
+		}
+		if i%2 == 0 {
+			snap.NetInRate = &rate
+		}
+		require.NoError(t, s.InsertGuestSnapshot(snap))
+	}
+}
+
+func TestRollup(t *testing.T) {
+	s := newTestStore(t)
+	base := (time.Now().Unix()/3600 - 3) * 3600
+	insertRollupFixture(t, s, base, 120)
+
+	n, err := s.rollup(base + 7200 + 10)
+	require.NoError(t, err)
+	// 24 five-minute and 2 hourly buckets, for nodes and guests each.
+	assert.Equal(t, int64(2*(24+2)), n)
+
+	var samples int
+	var avg, maxCPU float64
+	require.NoError(t, s.db.QueryRow(`SELECT samples, cpu_pct, cpu_pct_max FROM node_snapshots_5m WHERE ts = ?`, base).
+		Scan(&samples, &avg, &maxCPU))
+	assert.Equal(t, 5, samples)
+	assert.Equal(t, float64(2), avg)
+	assert.Equal(t, float64(4), maxCPU)
+
+	require.NoError(t, s.db.QueryRow(`SELECT samples, cpu_pct, cpu_pct_max FROM node_snapshots_1h WHERE ts = ?`, base).
+		Scan(&samples, &avg, &maxCPU))
+	assert.Equal(t, 60, samples)
+	assert.Equal(t, 29.5, avg)
+	assert.Equal(t, float64(59), maxCPU)
+
+	// Missing rates are ignored by the average: minutes 0, 2, 4 → 0, 20, 40.
+	var netIn, netInMax float64
+	require.NoError(t, s.db.QueryRow(`SELECT net_in_rate, net_in_rate_max FROM guest_snapshots_5m WHERE ts = ?`, base).
+		Scan(&netIn, &netInMax))
+	assert.Equal(t, float64(20), netIn)
+	assert.Equal(t, float64(40), netInMax)
+
+	// Nothing new to roll up.
+	n, err = s.rollup(base + 7200 + 10)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// Incremental: one more complete 5-minute bucket, no complete hour.
+	insertRollupFixture(t, s, base+7200, 6)
+	n, err = s.rollup(base + 7500)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, 25, countRows(t, s, "node_snapshots_5m"))
+	assert.Equal(t, 2, countRows(t, s, "node_snapshots_1h"))
+}
+
+func TestRollup_Empty(t *testing.T) {
+	s := newTestStore(t)
+	n, err := s.rollup(time.Now().Unix())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestRewindRollups(t *testing.T) {
+	s := newTestStore(t)
+	base := (time.Now().Unix()/3600 - 3) * 3600
+	insertRollupFixture(t, s, base+3600, 60)
+
+	_, err := s.rollup(base + 7200)
+	require.NoError(t, err)
+	assert.Equal(t, 1, countRows(t, s, "node_snapshots_1h"))
+
+	// History inserted behind the watermark is only rolled up after a rewind.
+	insertRollupFixture(t, s, base, 60)
+	n, err := s.rollup(base + 7200)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	require.NoError(t, s.RewindRollups(base+10))
+	n, err = s.rollup(base + 7200)
+	require.NoError(t, err)
+	assert.Positive(t, n)
+	assert.Equal(t, 24, countRows(t, s, "node_snapshots_5m"))
+	assert.Equal(t, 2, countRows(t, s, "node_snapshots_1h"))
+}
+
+func TestRollup_ClosedDB(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.Close())
+
+	_, err := s.rollup(time.Now().Unix())
+	assert.Error(t, err)
+	assert.Error(t, s.RewindRollups(0))
+}
+
+func TestHistoryTable(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+	assert.Equal(t, "node_snapshots", s.historyTable("node_snapshots", now.Add(-24*time.Hour).Unix()))
+	assert.Equal(t, "node_snapshots", s.historyTable("node_snapshots", now.Add(-48*time.Hour).Unix()))
+	assert.Equal(t, "node_snapshots_5m", s.historyTable("node_snapshots", now.Add(-7*24*time.Hour).Unix()))
+	assert.Equal(t, "guest_snapshots_1h", s.historyTable("guest_snapshots", now.Add(-90*24*time.Hour).Unix()))
+
+	// Tiers follow the configured retention.
+	r := DefaultRetention()
+	r.GuestSnapshots = 7 * 24 * time.Hour
+	r.Rollups5m = 90 * 24 * time.Hour
+	s.SetRetention(r)
+	assert.Equal(t, "node_snapshots_5m", s.historyTable("node_snapshots", now.Add(-7*24*time.Hour).Unix()))
+	assert.Equal(t, "guest_snapshots", s.historyTable("guest_snapshots", now.Add(-7*24*time.Hour).Unix()))
+	assert.Equal(t, "guest_snapshots_5m", s.historyTable("guest_snapshots", now.Add(-60*24*time.Hour).Unix()))
+}
+
+func TestQuerySparkline_Rollups(t *testing.T) {
+	s := newTestStore(t)
+	base := (time.Now().Unix()/3600 - 3) * 3600
+	insertRollupFixture(t, s, base, 120)
+	_, err := s.rollup(base + 7200)
+	require.NoError(t, err)
+
+	week := time.Now().Add(-7 * 24 * time.Hour).Unix()
+	points, err := s.QueryNodeSparkline("main", "pve", "cpu", week)
+	require.NoError(t, err)
+	require.Len(t, points, 24, "five-minute tier")
+	assert.Equal(t, float64(2), points[0].Value)
+
+	mem, err := s.QueryNodeSparkline("main", "pve", "memory", week)
+	require.NoError(t, err)
+	assert.Equal(t, float64(50), mem[0].Value)
+
+	quarter := time.Now().Add(-90 * 24 * time.Hour).Unix()
+	points, err = s.QueryGuestSparkline("main", 101, "netin", quarter)
+	require.NoError(t, err)
+	require.Len(t, points, 2, "hourly tier")
+
+	history, err := s.QueryGuestHistory("main", 101, week)
+	require.NoError(t, err)
+	require.Len(t, history, 24)
+	assert.Equal(t, base, history[0].Timestamp)
+	assert.Equal(t, int64(256), history[0].MemUsed)
+	require.NotNil(t, history[0].NetInRate)
+	assert.Equal(t, float64(20), *history[0].NetInRate)
+}
+
+func TestQuerySparkline_RollupRawTail(t *testing.T) {
+	s := newTestStore(t)
+	base := (time.Now().Unix()/3600 - 3) * 3600
+	insertRollupFixture(t, s, base, 120)
+	// Only the first hour is rolled up; the second is still raw.
+	_, err := s.rollup(base + 3600)
+	require.NoError(t, err)
+
+	week := time.Now().Add(-7 * 24 * time.Hour).Unix()
+	points, err := s.QueryNodeSparkline("main", "pve", "cpu", week)
+	require.NoError(t, err)
+	require.Len(t, points, 12+60, "five-minute buckets then raw samples")
+	assert.Equal(t, float64(2), points[0].Value)
+	assert.Equal(t, base+3600, points[12].Timestamp)
+	assert.Equal(t, float64(119), points[len(points)-1].Value)
+
+	quarter := time.Now().Add(-90 * 24 * time.Hour).Unix()
+	points, err = s.QueryGuestSparkline("main", 101, "cpu", quarter)
+	require.NoError(t, err)
+	require.Len(t, points, 1+60, "hourly bucket then raw samples")
+
+	history, err := s.QueryGuestHistory("main", 101, week)
+	require.NoError(t, err)
+	require.Len(t, history, 12+60)
+	assert.Equal(t, base+119*60, history[len(history)-1].Timestamp)
+	assert.Equal(t, int64(256), history[len(history)-1].MemUsed)
+}
+
+func TestQueryGuestHistory_RollupClosedDB(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.Close())
+	_, err := s.QueryGuestHistory("main", 101, time.Now().Add(-7*24*time.Hour).Unix())
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+)
+
+// sinkTimeout bounds how long a single sink may take to accept a write.
+const sinkTimeout = 10 * time.Second
+
+// sinkQueueSize is how many writes may wait for the sinks, a little over 15
+// minutes of 15-second polls for one instance.
+const sinkQueueSize = 64
+
+// MetricsSink receives every snapshot written to the store, so metrics can be
+// forwarded to an external time-series database alongside SQLite.
+type MetricsSink interface {
+	Name() string
+	Write(ctx context.Context, s *Snapshots) error
+}
+
+// Snapshots holds the time-series rows of one write.
+type Snapshots struct {
+	Nodes      []model.NodeSnapshot
+	Guests     []model.GuestSnapshot
+	SMART      []SMARTSnapshot
+	Backups    []BackupSnapshot
+	Datastores []DatastoreSnapshot
+}
+
+// SMARTSnapshot is a disk's SMART state at a point in time.
+type SMARTSnapshot struct {
+	Timestamp int64
+	Disk      *model.Disk
+}
+
+// BackupSnapshot is a PBS backup as seen at a point in time.
+type BackupSnapshot struct {
+	Timestamp int64
+	Backup    *model.Backup
+}
+
+// DatastoreSnapshot is a PBS datastore's usage at a point in time.
+type DatastoreSnapshot struct {
+	Timestamp int64
+	Datastore *model.DatastoreStatus
+}
+
+// Len returns the total number of snapshots.
+func (s *Snapshots) Len() int {
+	return len(s.Nodes) + len(s.Guests) + len(s.SMART) + len(s.Backups) + len(s.Datastores)
+}
+
+// AddSink registers a sink that receives every snapshot after it is stored.
+// The first sink starts the goroutine that writes to the sinks. It must be
+// called before the store is shared between goroutines.
+func (s *Store) AddSink(sink MetricsSink) {
+	s.sinks = append(s.sinks, sink)
+	if s.sinkQueue == nil {
+		s.sinkQueue = make(chan *Snapshots, sinkQueueSize)
+		s.sinkDone = make(chan struct{})
+		s.sinkCtx, s.sinkCancel = context.WithCancel(context.Background())
+		go s.runSinks()
+	}
+}
+
+// SinkStats reports the writes waiting for the metrics sinks and how many
+// were dropped because the queue was full. ok is false without sinks.
+func (s *Store) SinkStats() (queued int, dropped int64, ok bool) {
+	if s.sinkQueue == nil {
+		return 0, 0, false
+	}
+	return len(s.sinkQueue), s.sinkDropped.Load(), true
+}
+
+// forward queues stored snapshots for the sinks. SQLite stays the source of
+// truth, so a slow sink never holds up the write: when the queue is full the
+// snapshots are dropped and counted.
+func (s *Store) forward(snaps *Snapshots) {
+	if len(s.sinks) == 0 || snaps.Len() == 0 {
+		return
+	}
+	s.sinkMu.RLock()
+	defer s.sinkMu.RUnlock()
+	if s.sinkClosed {
+		return
+	}
+	select {
+	case s.sinkQueue <- snaps:
+	default:
+		dropped := s.sinkDropped.Add(1)
+		slog.Warn("metrics sink queue full, dropping write", "snapshots", snaps.Len(), "dropped", dropped)
+	}
+}
+
+// runSinks writes queued snapshots to every sink until the queue is closed.
+func (s *Store) runSinks() {
+	defer close(s.sinkDone)
+	for snaps := range s.sinkQueue {
+		if s.sinkCtx.Err() != nil {
+			s.sinkDropped.Add(1)
+			continue
+		}
+		for _, sink := range s.sinks {
+			ctx, cancel := context.WithTimeout(s.sinkCtx, sinkTimeout)
+			if err := sink.Write(ctx, snaps); err != nil {
+				slog.Error("writing to metrics sink", "sink", sink.Name(), "snapshots", snaps.Len(), "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// stopSinks stops accepting writes and gives the queued ones up to
+// sinkTimeout to reach the sinks; the rest are dropped.
+func (s *Store) stopSinks() {
+	if s.sinkQueue == nil {
+		return
+	}
+	s.sinkMu.Lock()
+	if s.sinkClosed {
+		s.sinkMu.Unlock()
+		return
+	}
+	s.sinkClosed = true
+	close(s.sinkQueue)
+	s.sinkMu.Unlock()
+
+	select {
+	case <-s.sinkDone:
+	case <-time.After(sinkTimeout):
+		s.sinkCancel()
+		<-s.sinkDone
+	}
+	s.sinkCancel()
+}
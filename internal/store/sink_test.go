@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/darshan-rambhia/glint/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records its writes. Sinks run on the store's sink goroutine,
+// so tests read writes after Close has flushed the queue.
+type recordingSink struct {
+	writes []*Snapshots
+	err    error
+}
+
+func (r *recordingSink) Name() string { return "recording" }
+
+func (r *recordingSink) Write(_ context.Context, s *Snapshots) error {
+	r.writes = append(r.writes, s)
+	return r.err
+}
+
+// blockingSink holds every write until release is closed or the write's
+// context ends.
+type blockingSink struct {
+	release chan struct{}
+	writes  atomic.Int64
+}
+
+func (b *blockingSink) Name() string { return "blocking" }
+
+func (b *blockingSink) Write(ctx context.Context, _ *Snapshots) error {
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	b.writes.Add(1)
+	return nil
+}
+
+func TestWriteBatch_ForwardsToSinks(t *testing.T) {
+	s := newTestStore(t)
+	sink := &recordingSink{}
+	s.AddSink(sink)
+	ts := time.Now().Unix()
+
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve"})
+	b.AddGuestSnapshot(model.GuestSnapshot{Timestamp: ts, Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc", Name: "ct", Status: "running"})
+	disk := &model.Disk{WWN: "0x5000", Instance: "main", Node: "pve", Health: "PASSED"}
+	b.AddDisk(disk)
+	b.AddSMARTSnapshot(ts, disk)
+	b.AddPVETask(&model.PVETask{Instance: "main", UPID: "UPID:pve:1", Node: "pve", Type: "vzdump", StartTime: ts})
+	b.AddBackupSnapshot(ts, &model.Backup{PBSInstance: "pbs", Datastore: "store1", BackupType: "ct", BackupID: "101", BackupTime: ts})
+	b.AddDatastoreSnapshot(ts, &model.DatastoreStatus{PBSInstance: "pbs", Name: "store1"})
+	require.NoError(t, s.WriteBatch(&b))
+	require.NoError(t, s.Close())
+
+	require.Len(t, sink.writes, 1)
+	got := sink.writes[0]
+	assert.Len(t, got.Nodes, 1)
+	assert.Len(t, got.Guests, 1)
+	require.Len(t, got.SMART, 1)
+	assert.Equal(t, "0x5000", got.SMART[0].Disk.WWN)
+	assert.Equal(t, ts, got.SMART[0].Timestamp)
+	assert.Len(t, got.Backups, 1)
+	assert.Len(t, got.Datastores, 1)
+	assert.Equal(t, 5, got.Len(), "disks and tasks are not time series")
+}
+
+func TestWriteBatch_SinkErrorKeepsRows(t *testing.T) {
+	s := newTestStore(t)
+	s.AddSink(&recordingSink{err: errors.New("unreachable")})
+
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: time.Now().Unix(), Instance: "main", Node: "pve"})
+	require.NoError(t, s.WriteBatch(&b))
+	assert.Equal(t, 1, countRows(t, s, "node_snapshots"))
+}
+
+func TestWriteBatch_RollbackSkipsSinks(t *testing.T) {
+	s := newTestStore(t)
+	sink := &recordingSink{}
+	s.AddSink(sink)
+	require.NoError(t, s.Close())
+
+	var b Batch
+	b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: time.Now().Unix(), Instance: "main", Node: "pve"})
+	require.Error(t, s.WriteBatch(&b))
+	assert.Empty(t, sink.writes)
+}
+
+func TestWriteBatch_SlowSinkDoesNotBlock(t *testing.T) {
+	s := newTestStore(t)
+	sink := &blockingSink{release: make(chan struct{})}
+	s.AddSink(sink)
+
+	// One write is held by the sink and sinkQueueSize wait in the queue; the
+	// rest are dropped without waiting.
+	start := time.Now()
+	writes := sinkQueueSize + 5
+	for i := range writes {
+		var b Batch
+		b.AddNodeSnapshot(model.NodeSnapshot{Timestamp: int64(i), Instance: "main", Node: "pve"})
+		require.NoError(t, s.WriteBatch(&b))
+	}
+	assert.Less(t, time.Since(start), sinkTimeout)
+	assert.Equal(t, writes, countRows(t, s, "node_snapshots"))
+
+	_, dropped, ok := s.SinkStats()
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, dropped, int64(4))
+
+	close(sink.release)
+	require.NoError(t, s.Close())
+	assert.Equal(t, int64(writes)-dropped, sink.writes.Load())
+}
+
+func TestSinkStats_NoSinks(t *testing.T) {
+	s := newTestStore(t)
+	_, _, ok := s.SinkStats()
+	assert.False(t, ok)
+}
+
+func TestInsert_ForwardsToSinks(t *testing.T) {
+	s := newTestStore(t)
+	sink := &recordingSink{}
+	s.AddSink(sink)
+	ts := time.Now().Unix()
+
+	require.NoError(t, s.InsertNodeSnapshot(model.NodeSnapshot{Timestamp: ts, Instance: "main", Node: "pve"}))
+	require.NoError(t, s.InsertDatastoreSnapshot(ts, &model.DatastoreStatus{PBSInstance: "pbs", Name: "store1"}))
+	require.NoError(t, s.Close())
+
+	require.Len(t, sink.writes, 2)
+	assert.Len(t, sink.writes[0].Nodes, 1)
+	assert.Len(t, sink.writes[1].Datastores, 1)
+}
@@ -2,11 +2,17 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -16,7 +22,18 @@ import (
 
 // Store wraps a SQLite database for Glint data persistence.
 type Store struct {
-	db *sql.DB
+	db        *sql.DB
+	retention RetentionConfig
+
+	// Metrics sinks are written from one goroutine fed by sinkQueue.
+	sinks       []MetricsSink
+	sinkQueue   chan *Snapshots
+	sinkDone    chan struct{}
+	sinkCtx     context.Context
+	sinkCancel  context.CancelFunc
+	sinkMu      sync.RWMutex // guards sends on sinkQueue against its close
+	sinkClosed  bool
+	sinkDropped atomic.Int64
 }
 
 // New opens or creates a SQLite database at the given path and runs migrations.
@@ -43,76 +60,160 @@ func New(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("pinging database: %w", err)
 	}
 
-	if _, err := db.Exec(schema); err != nil {
+	if err := migrate(db, dbPath); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("running migrations: %w", err)
 	}
 
-	return &Store{db: db}, nil
+	if err := enableIncrementalVacuum(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enabling incremental vacuum: %w", err)
+	}
+
+	return &Store{db: db, retention: DefaultRetention()}, nil
 }
 
-// Close closes the database connection.
+// OpenReadOnly opens an existing database for inspection without changing
+// it: no migrations, vacuum mode conversion or permission changes, and every
+// write fails. A damaged file can still be checked with IntegrityCheck.
+func OpenReadOnly(dbPath string) (*Store, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("opening database %s: %w", dbPath, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+	return &Store{db: db, retention: DefaultRetention()}, nil
+}
+
+// SetRetention tells the store how long each table is kept, so history
+// queries read the finest table that covers the requested span. It must be
+// called before the store is shared between goroutines.
+func (s *Store) SetRetention(r RetentionConfig) {
+	s.retention = r
+}
+
+// Size returns the database file size in bytes and the bytes held by free
+// pages that a vacuum would release.
+func (s *Store) Size() (size, free int64, err error) {
+	err = s.db.QueryRow(`
+		SELECT p.page_count * s.page_size, f.freelist_count * s.page_size
+		FROM pragma_page_count() p, pragma_page_size() s, pragma_freelist_count() f`,
+	).Scan(&size, &free)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading database size: %w", err)
+	}
+	return size, free, nil
+}
+
+// Close stops the metrics sinks, flushing queued writes for up to
+// sinkTimeout, and closes the database connection.
 func (s *Store) Close() error {
+	s.stopSinks()
 	return s.db.Close()
 }
 
-// InsertNodeSnapshot records a point-in-time node metric snapshot.
-func (s *Store) InsertNodeSnapshot(snap model.NodeSnapshot) error {
-	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO node_snapshots
-		(ts, instance, node, cpu_pct, mem_used, mem_total, swap_used, swap_total,
-		 rootfs_used, rootfs_total, load_1m, load_5m, load_15m, io_wait, uptime_secs, cpu_temp)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+const insertNodeSnapshotSQL = `
+	INSERT OR REPLACE INTO node_snapshots
+	(ts, instance, node, cpu_pct, mem_used, mem_total, swap_used, swap_total,
+	 rootfs_used, rootfs_total, load_1m, load_5m, load_15m, io_wait, uptime_secs, cpu_temp,
+	 net_in_rate, net_out_rate, psi_cpu_some, psi_io_some, psi_io_full, psi_mem_some, psi_mem_full)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+func nodeSnapshotArgs(snap model.NodeSnapshot) []any {
+	return []any{
 		snap.Timestamp, snap.Instance, snap.Node, snap.CPUPct,
 		snap.MemUsed, snap.MemTotal, snap.SwapUsed, snap.SwapTotal,
 		snap.RootUsed, snap.RootTotal, snap.Load1m, snap.Load5m, snap.Load15m,
 		snap.IOWait, snap.UptimeSecs, snap.CPUTemp,
-	)
-	if err != nil {
-		return fmt.Errorf("inserting node snapshot: %w", err)
+		snap.NetIn, snap.NetOut, snap.PSICPU, snap.PSIIO, snap.PSIIOFull, snap.PSIMem, snap.PSIMemFull,
 	}
-	return nil
 }
 
-// InsertGuestSnapshot records a point-in-time guest metric snapshot.
-func (s *Store) InsertGuestSnapshot(snap model.GuestSnapshot) error {
-	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO guest_snapshots
-		(ts, instance, vmid, node, cluster_id, guest_type, name, status,
-		 cpu_pct, cpus, mem_used, mem_total, disk_used, disk_total, net_in, net_out)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+// InsertNodeSnapshot records a point-in-time node metric snapshot as a
+// batch of one. Poll cycles use a Batch.
+func (s *Store) InsertNodeSnapshot(snap model.NodeSnapshot) error {
+	var b Batch
+	b.AddNodeSnapshot(snap)
+	return s.WriteBatch(&b)
+}
+
+const insertGuestSnapshotSQL = `
+	INSERT OR REPLACE INTO guest_snapshots
+	(ts, instance, vmid, node, cluster_id, guest_type, name, status,
+	 cpu_pct, cpus, mem_used, mem_total, disk_used, disk_total, net_in, net_out,
+	 disk_read, disk_write, net_in_rate, net_out_rate, disk_read_rate, disk_write_rate)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+func guestSnapshotArgs(snap model.GuestSnapshot) []any {
+	return []any{
 		snap.Timestamp, snap.Instance, snap.VMID, snap.Node, snap.ClusterID,
 		snap.GuestType, snap.Name, snap.Status, snap.CPUPct, snap.CPUs,
 		snap.MemUsed, snap.MemTotal, snap.DiskUsed, snap.DiskTotal,
-		snap.NetIn, snap.NetOut,
-	)
+		snap.NetIn, snap.NetOut, snap.DiskRead, snap.DiskWrite,
+		snap.NetInRate, snap.NetOutRate, snap.DiskReadRate, snap.DiskWriteRate,
+	}
+}
+
+// InsertGuestSnapshot records a point-in-time guest metric snapshot as a
+// batch of one.
+func (s *Store) InsertGuestSnapshot(snap model.GuestSnapshot) error {
+	var b Batch
+	b.AddGuestSnapshot(snap)
+	return s.WriteBatch(&b)
+}
+
+// HasSnapshots reports whether any node or guest snapshots exist for the
+// instance.
+func (s *Store) HasSnapshots(instance string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM node_snapshots WHERE instance = ?)
+		    OR EXISTS (SELECT 1 FROM guest_snapshots WHERE instance = ?)`,
+		instance, instance,
+	).Scan(&exists)
 	if err != nil {
-		return fmt.Errorf("inserting guest snapshot: %w", err)
+		return false, fmt.Errorf("checking snapshots: %w", err)
 	}
-	return nil
+	return exists, nil
 }
 
-// InsertSMARTSnapshot records a disk SMART snapshot.
-func (s *Store) InsertSMARTSnapshot(ts int64, disk *model.Disk) error {
+const insertSMARTSnapshotSQL = `
+	INSERT OR REPLACE INTO smart_snapshots
+	(ts, wwn, health, status, temperature, power_on_hours, wearout, attributes_json)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+func smartSnapshotArgs(ts int64, disk *model.Disk) ([]any, error) {
 	attrsJSON, err := json.Marshal(disk.Attributes)
 	if err != nil {
-		return fmt.Errorf("marshaling SMART attributes: %w", err)
+		return nil, fmt.Errorf("marshaling SMART attributes: %w", err)
 	}
-	_, err = s.db.Exec(`
-		INSERT OR REPLACE INTO smart_snapshots
-		(ts, wwn, health, status, temperature, power_on_hours, wearout, attributes_json)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+	return []any{
 		ts, disk.WWN, disk.Health, disk.Status,
 		disk.Temperature, disk.PowerOnHours, disk.Wearout, string(attrsJSON),
-	)
-	if err != nil {
-		return fmt.Errorf("inserting SMART snapshot: %w", err)
+	}, nil
+}
+
+// InsertSMARTSnapshot records a disk SMART snapshot as a batch of one.
+func (s *Store) InsertSMARTSnapshot(ts int64, disk *model.Disk) error {
+	var b Batch
+	if err := b.addSMARTSnapshot(ts, disk); err != nil {
+		return err
 	}
-	return nil
+	return s.WriteBatch(&b)
 }
 
-// InsertBackupSnapshot records a PBS backup snapshot.
-func (s *Store) InsertBackupSnapshot(ts int64, b *model.Backup) error {
+const insertBackupSnapshotSQL = `
+	INSERT OR REPLACE INTO backup_snapshots
+	(ts, pbs_instance, datastore, backup_type, backup_id, backup_time, size_bytes, verified)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+func backupSnapshotArgs(ts int64, b *model.Backup) []any {
 	var verified *int
 	if b.Verified != nil {
 		v := 0
@@ -121,32 +222,37 @@ func (s *Store) InsertBackupSnapshot(ts int64, b *model.Backup) error {
 		}
 		verified = &v
 	}
-	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO backup_snapshots
-		(ts, pbs_instance, datastore, backup_type, backup_id, backup_time, size_bytes, verified)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+	return []any{
 		ts, b.PBSInstance, b.Datastore, b.BackupType, b.BackupID,
 		b.BackupTime, b.SizeBytes, verified,
-	)
-	if err != nil {
-		return fmt.Errorf("inserting backup snapshot: %w", err)
 	}
-	return nil
 }
 
-// InsertDatastoreSnapshot records a PBS datastore usage snapshot.
-func (s *Store) InsertDatastoreSnapshot(ts int64, ds *model.DatastoreStatus) error {
-	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO datastore_snapshots
-		(ts, pbs_instance, store_name, total_bytes, used_bytes, avail_bytes, dedup_ratio, est_full_date)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+// InsertBackupSnapshot records a PBS backup snapshot as a batch of one.
+func (s *Store) InsertBackupSnapshot(ts int64, bk *model.Backup) error {
+	var b Batch
+	b.AddBackupSnapshot(ts, bk)
+	return s.WriteBatch(&b)
+}
+
+const insertDatastoreSnapshotSQL = `
+	INSERT OR REPLACE INTO datastore_snapshots
+	(ts, pbs_instance, store_name, total_bytes, used_bytes, avail_bytes, dedup_ratio, est_full_date)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+func datastoreSnapshotArgs(ts int64, ds *model.DatastoreStatus) []any {
+	return []any{
 		ts, ds.PBSInstance, ds.Name, ds.TotalBytes, ds.UsedBytes,
 		ds.AvailBytes, ds.DedupRatio, ds.EstFullDate,
-	)
-	if err != nil {
-		return fmt.Errorf("inserting datastore snapshot: %w", err)
 	}
-	return nil
+}
+
+// InsertDatastoreSnapshot records a PBS datastore usage snapshot as a batch
+// of one.
+func (s *Store) InsertDatastoreSnapshot(ts int64, ds *model.DatastoreStatus) error {
+	var b Batch
+	b.AddDatastoreSnapshot(ts, ds)
+	return s.WriteBatch(&b)
 }
 
 // InsertAlert logs an alert.
@@ -162,29 +268,256 @@ func (s *Store) InsertAlert(ts int64, alertType, instance, subject, message, sev
 	return nil
 }
 
-// UpsertDisk inserts or updates a disk metadata record.
-func (s *Store) UpsertDisk(d *model.Disk) error {
-	now := time.Now().Unix()
-	_, err := s.db.Exec(`
-		INSERT INTO disks (wwn, instance, node, dev_path, model, serial, disk_type, protocol, size_bytes, first_seen, last_seen)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(wwn) DO UPDATE SET
-			instance = excluded.instance,
-			node = excluded.node,
-			dev_path = excluded.dev_path,
-			model = excluded.model,
-			serial = excluded.serial,
-			last_seen = excluded.last_seen`,
+const upsertPVETaskSQL = `
+	INSERT INTO pve_tasks (upid, ts, instance, node, task_type, task_id, user, end_time, status)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(upid) DO UPDATE SET
+		end_time = excluded.end_time,
+		status = excluded.status`
+
+func pveTaskArgs(t *model.PVETask) []any {
+	return []any{t.UPID, t.StartTime, t.Instance, t.Node, t.Type, t.ID, t.User, t.EndTime, t.Status}
+}
+
+// UpsertPVETask inserts or updates a PVE task as a batch of one. Tasks are
+// first seen while running and updated once they finish.
+func (s *Store) UpsertPVETask(t *model.PVETask) error {
+	var b Batch
+	b.AddPVETask(t)
+	return s.WriteBatch(&b)
+}
+
+// QueryPVETasks returns PVE tasks started at or after since, newest first.
+func (s *Store) QueryPVETasks(since int64) ([]*model.PVETask, error) {
+	rows, err := s.db.Query(`
+		SELECT upid, ts, instance, node, task_type, task_id, user, end_time, status
+		FROM pve_tasks
+		WHERE ts >= ?
+		ORDER BY ts DESC`, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying PVE tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*model.PVETask
+	for rows.Next() {
+		var t model.PVETask
+		var id, user, status sql.NullString
+		var endTime sql.NullInt64
+		if err := rows.Scan(&t.UPID, &t.StartTime, &t.Instance, &t.Node, &t.Type, &id, &user, &endTime, &status); err != nil {
+			return nil, fmt.Errorf("scanning PVE task: %w", err)
+		}
+		t.ID, t.User, t.Status = id.String, user.String, status.String
+		if endTime.Valid {
+			t.EndTime = &endTime.Int64
+		}
+		tasks = append(tasks, &t)
+	}
+	return tasks, rows.Err()
+}
+
+// QuerySMARTAttributeHistory returns the recorded raw values of the given
+// SMART attributes for a disk since the given time, oldest first.
+func (s *Store) QuerySMARTAttributeHistory(wwn string, since int64, ids []int) ([]model.AttributeSample, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := []any{wwn, since}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.Query(`
+		SELECT s.ts, json_extract(a.value, '$.id'), json_extract(a.value, '$.raw_value')
+		FROM smart_snapshots s, json_each(s.attributes_json) a
+		WHERE s.wwn = ? AND s.ts >= ?
+		  AND json_extract(a.value, '$.id') IN (`+strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")+`)
+		ORDER BY s.ts`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying SMART history for %s: %w", wwn, err)
+	}
+	defer rows.Close()
+
+	var samples []model.AttributeSample
+	for rows.Next() {
+		var h model.AttributeSample
+		if err := rows.Scan(&h.Timestamp, &h.ID, &h.RawValue); err != nil {
+			return nil, fmt.Errorf("scanning SMART history: %w", err)
+		}
+		samples = append(samples, h)
+	}
+	return samples, rows.Err()
+}
+
+// QueryDiskHistory returns the SMART snapshots of a disk since the given
+// time, oldest first. When ids is non-empty only those attributes are kept.
+func (s *Store) QueryDiskHistory(wwn string, since int64, ids []int) ([]model.DiskSnapshot, error) {
+	rows, err := s.db.Query(`
+		SELECT ts, health, status, temperature, power_on_hours, wearout, attributes_json
+		FROM smart_snapshots
+		WHERE wwn = ? AND ts >= ?
+		ORDER BY ts ASC`, wwn, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying disk history for %s: %w", wwn, err)
+	}
+	defer rows.Close()
+
+	var snaps []model.DiskSnapshot
+	for rows.Next() {
+		var d model.DiskSnapshot
+		var attrsJSON sql.NullString
+		if err := rows.Scan(&d.Timestamp, &d.Health, &d.Status, &d.Temperature, &d.PowerOnHours, &d.Wearout, &attrsJSON); err != nil {
+			return nil, fmt.Errorf("scanning disk history: %w", err)
+		}
+		if attrsJSON.Valid {
+			if err := json.Unmarshal([]byte(attrsJSON.String), &d.Attributes); err != nil {
+				return nil, fmt.Errorf("decoding SMART attributes: %w", err)
+			}
+		}
+		if len(ids) > 0 {
+			d.Attributes = slices.DeleteFunc(d.Attributes, func(a model.SMARTAttribute) bool {
+				return !slices.Contains(ids, a.ID)
+			})
+		}
+		snaps = append(snaps, d)
+	}
+	return snaps, rows.Err()
+}
+
+const upsertDiskSQL = `
+	INSERT INTO disks (wwn, instance, node, dev_path, model, serial, disk_type, protocol, size_bytes, first_seen, last_seen)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(wwn) DO UPDATE SET
+		instance = excluded.instance,
+		node = excluded.node,
+		dev_path = excluded.dev_path,
+		model = excluded.model,
+		serial = excluded.serial,
+		last_seen = excluded.last_seen,
+		missed_polls = 0,
+		removed_at = NULL,
+		replaced_by = NULL`
+
+func diskArgs(d *model.Disk, now int64) []any {
+	return []any{
 		d.WWN, d.Instance, d.Node, d.DevPath, d.Model, d.Serial,
 		d.DiskType, d.Protocol, d.SizeBytes, now, now,
-	)
+	}
+}
+
+// UpsertDisk inserts or updates a disk metadata record as a batch of one.
+func (s *Store) UpsertDisk(d *model.Disk) error {
+	var b Batch
+	b.AddDisk(d)
+	return s.WriteBatch(&b)
+}
+
+const updateDiskAbsenceSQL = `
+	UPDATE disks SET missed_polls = ?, removed_at = ?, replaced_by = ?
+	WHERE wwn = ? AND instance = ?`
+
+func diskAbsenceArgs(d *model.InventoryDisk) []any {
+	var removedAt *int64
+	if d.RemovedAt != nil {
+		removedAt = new(d.RemovedAt.Unix())
+	}
+	var replacedBy *string
+	if d.ReplacedBy != "" {
+		replacedBy = &d.ReplacedBy
+	}
+	return []any{d.MissedPolls, removedAt, replacedBy, d.WWN, d.Instance}
+}
+
+// QueryDiskInventory returns every disk recorded for the instance, present
+// or not, ordered by node and device path. An empty instance returns the
+// disks of all instances.
+func (s *Store) QueryDiskInventory(instance string) ([]*model.InventoryDisk, error) {
+	rows, err := s.db.Query(`
+		SELECT wwn, instance, node, dev_path, model, serial, disk_type, size_bytes,
+			first_seen, last_seen, missed_polls, removed_at, replaced_by
+		FROM disks
+		WHERE ? = '' OR instance = ?
+		ORDER BY instance, node, dev_path, wwn`, instance, instance)
 	if err != nil {
-		return fmt.Errorf("upserting disk %s: %w", d.WWN, err)
+		return nil, fmt.Errorf("querying disk inventory: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	var disks []*model.InventoryDisk
+	for rows.Next() {
+		var d model.InventoryDisk
+		var diskModel, serial, replacedBy sql.NullString
+		var firstSeen, lastSeen int64
+		var removedAt sql.NullInt64
+		if err := rows.Scan(&d.WWN, &d.Instance, &d.Node, &d.DevPath, &diskModel, &serial, &d.DiskType, &d.SizeBytes,
+			&firstSeen, &lastSeen, &d.MissedPolls, &removedAt, &replacedBy); err != nil {
+			return nil, fmt.Errorf("scanning disk inventory: %w", err)
+		}
+		d.Model, d.Serial, d.ReplacedBy = diskModel.String, serial.String, replacedBy.String
+		d.FirstSeen, d.LastSeen = time.Unix(firstSeen, 0), time.Unix(lastSeen, 0)
+		if removedAt.Valid {
+			d.RemovedAt = new(time.Unix(removedAt.Int64, 0))
+		}
+		disks = append(disks, &d)
+	}
+	return disks, rows.Err()
 }
 
-// QueryNodeSparkline returns CPU or memory data points for sparkline rendering.
+// HasDisk reports whether the disk has ever been recorded in the inventory.
+func (s *Store) HasDisk(wwn string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM disks WHERE wwn = ?)`, wwn).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("checking disk %s: %w", wwn, err)
+	}
+	return exists, nil
+}
+
+const insertDiskEventSQL = `
+	INSERT INTO disk_events (ts, event, wwn, instance, node, dev_path, model, serial,
+		prev_wwn, prev_serial, prev_node, prev_dev_path)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+func diskEventArgs(e *model.DiskEvent) []any {
+	return []any{
+		e.Timestamp, e.Type, e.WWN, e.Instance, e.Node, e.DevPath, e.Model, e.Serial,
+		e.PrevWWN, e.PrevSerial, e.PrevNode, e.PrevDevPath,
+	}
+}
+
+// QueryDiskEvents returns disk lifecycle events recorded at or after since,
+// newest first.
+func (s *Store) QueryDiskEvents(since int64) ([]*model.DiskEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT ts, event, wwn, instance, node, dev_path, model, serial,
+			prev_wwn, prev_serial, prev_node, prev_dev_path
+		FROM disk_events
+		WHERE ts >= ?
+		ORDER BY ts DESC, id DESC`, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying disk events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.DiskEvent
+	for rows.Next() {
+		var e model.DiskEvent
+		var diskModel, serial, prevWWN, prevSerial, prevNode, prevDevPath sql.NullString
+		if err := rows.Scan(&e.Timestamp, &e.Type, &e.WWN, &e.Instance, &e.Node, &e.DevPath, &diskModel, &serial,
+			&prevWWN, &prevSerial, &prevNode, &prevDevPath); err != nil {
+			return nil, fmt.Errorf("scanning disk event: %w", err)
+		}
+		e.Model, e.Serial = diskModel.String, serial.String
+		e.PrevWWN, e.PrevSerial, e.PrevNode, e.PrevDevPath = prevWWN.String, prevSerial.String, prevNode.String, prevDevPath.String
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// QueryNodeSparkline returns data points for a node metric: cpu, memory,
+// netin/netout (bytes/sec) or psi_cpu/psi_io/psi_mem (PSI "some" percent).
+// Samples without rrddata are omitted. Spans longer than the raw retention
+// read averages from the rollup tiers, followed by the raw samples that are
+// not rolled up yet.
 func (s *Store) QueryNodeSparkline(instance, node, metric string, since int64) ([]model.SparklinePoint, error) {
 	var col string
 	switch metric {
@@ -192,14 +525,28 @@ func (s *Store) QueryNodeSparkline(instance, node, metric string, since int64) (
 		col = "cpu_pct"
 	case "memory":
 		col = "CAST(mem_used AS REAL) / mem_total * 100"
+	case "netin":
+		col = "net_in_rate"
+	case "netout":
+		col = "net_out_rate"
+	case "psi_cpu":
+		col = "psi_cpu_some"
+	case "psi_io":
+		col = "psi_io_some"
+	case "psi_mem":
+		col = "psi_mem_some"
 	default:
 		return nil, fmt.Errorf("unknown metric %q", metric)
 	}
 
+	source, err := s.historySource("node_snapshots", since)
+	if err != nil {
+		return nil, fmt.Errorf("querying node sparkline: %w", err)
+	}
 	query := fmt.Sprintf(`
-		SELECT ts, %s FROM node_snapshots
-		WHERE instance = ? AND node = ? AND ts >= ?
-		ORDER BY ts ASC`, col)
+		SELECT ts, %[1]s FROM %[2]s
+		WHERE instance = ? AND node = ? AND ts >= ? AND %[1]s IS NOT NULL
+		ORDER BY ts ASC`, col, source)
 
 	rows, err := s.db.Query(query, instance, node, since)
 	if err != nil {
@@ -218,12 +565,38 @@ func (s *Store) QueryNodeSparkline(instance, node, metric string, since int64) (
 	return points, rows.Err()
 }
 
-// QueryGuestSparkline returns CPU data points for a specific guest.
-func (s *Store) QueryGuestSparkline(instance string, vmid int, since int64) ([]model.SparklinePoint, error) {
-	rows, err := s.db.Query(`
-		SELECT ts, cpu_pct FROM guest_snapshots
-		WHERE instance = ? AND vmid = ? AND ts >= ?
-		ORDER BY ts ASC`, instance, vmid, since)
+// QueryGuestSparkline returns data points for a specific guest metric. Rate
+// metrics (netin, netout, diskread, diskwrite) are in bytes/sec; intervals
+// without a rate (first sample, counter reset) are omitted.
+func (s *Store) QueryGuestSparkline(instance string, vmid int, metric string, since int64) ([]model.SparklinePoint, error) {
+	var col string
+	switch metric {
+	case "cpu":
+		col = "cpu_pct"
+	case "memory":
+		col = "CAST(mem_used AS REAL) / mem_total * 100"
+	case "netin":
+		col = "net_in_rate"
+	case "netout":
+		col = "net_out_rate"
+	case "diskread":
+		col = "disk_read_rate"
+	case "diskwrite":
+		col = "disk_write_rate"
+	default:
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+
+	source, err := s.historySource("guest_snapshots", since)
+	if err != nil {
+		return nil, fmt.Errorf("querying guest sparkline: %w", err)
+	}
+	query := fmt.Sprintf(`
+		SELECT ts, %[1]s FROM %[2]s
+		WHERE instance = ? AND vmid = ? AND ts >= ? AND %[1]s IS NOT NULL
+		ORDER BY ts ASC`, col, source)
+
+	rows, err := s.db.Query(query, instance, vmid, since)
 	if err != nil {
 		return nil, fmt.Errorf("querying guest sparkline: %w", err)
 	}
@@ -240,6 +613,135 @@ func (s *Store) QueryGuestSparkline(instance string, vmid int, since int64) ([]m
 	return points, rows.Err()
 }
 
+// QueryDiskSparkline returns data points for a disk metric recorded with
+// each SMART snapshot: temperature (°C), wearout (percent of rated endurance
+// remaining) or power_on_hours. Snapshots without the value are omitted.
+func (s *Store) QueryDiskSparkline(wwn, metric string, since int64) ([]model.SparklinePoint, error) {
+	col, err := diskMetricColumn(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT ts, %[1]s FROM smart_snapshots
+		WHERE wwn = ? AND ts >= ? AND %[1]s IS NOT NULL
+		ORDER BY ts ASC`, col)
+
+	rows, err := s.db.Query(query, wwn, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying disk sparkline: %w", err)
+	}
+	defer rows.Close()
+
+	var points []model.SparklinePoint
+	for rows.Next() {
+		var p model.SparklinePoint
+		if err := rows.Scan(&p.Timestamp, &p.Value); err != nil {
+			return nil, fmt.Errorf("scanning sparkline point: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// QueryFirstDiskPoint returns the oldest recorded value of a disk metric
+// (see QueryDiskSparkline), or nil if none is recorded.
+func (s *Store) QueryFirstDiskPoint(wwn, metric string) (*model.SparklinePoint, error) {
+	col, err := diskMetricColumn(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	var p model.SparklinePoint
+	err = s.db.QueryRow(fmt.Sprintf(`
+		SELECT ts, %[1]s FROM smart_snapshots
+		WHERE wwn = ? AND %[1]s IS NOT NULL
+		ORDER BY ts ASC LIMIT 1`, col), wwn).Scan(&p.Timestamp, &p.Value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying first disk point: %w", err)
+	}
+	return &p, nil
+}
+
+// diskMetricColumn maps a disk metric name to its smart_snapshots column.
+func diskMetricColumn(metric string) (string, error) {
+	switch metric {
+	case "temperature", "wearout", "power_on_hours":
+		return metric, nil
+	default:
+		return "", fmt.Errorf("unknown metric %q", metric)
+	}
+}
+
+// QueryGuestHistory returns full guest snapshots for a guest, oldest first.
+// Spans longer than the raw retention return rollup averages, which carry
+// only the metric fields.
+func (s *Store) QueryGuestHistory(instance string, vmid int, since int64) ([]model.GuestSnapshot, error) {
+	if s.historyTable("guest_snapshots", since) != "guest_snapshots" {
+		return s.queryGuestRollup(instance, vmid, since)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT ts, instance, vmid, node, COALESCE(cluster_id, ''), guest_type, name, status,
+		       cpu_pct, cpus, mem_used, mem_total, disk_used, disk_total, net_in, net_out,
+		       disk_read, disk_write, net_in_rate, net_out_rate, disk_read_rate, disk_write_rate
+		FROM guest_snapshots
+		WHERE instance = ? AND vmid = ? AND ts >= ?
+		ORDER BY ts ASC`, instance, vmid, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying guest history: %w", err)
+	}
+	defer rows.Close()
+
+	var snaps []model.GuestSnapshot
+	for rows.Next() {
+		var g model.GuestSnapshot
+		if err := rows.Scan(&g.Timestamp, &g.Instance, &g.VMID, &g.Node, &g.ClusterID, &g.GuestType, &g.Name, &g.Status,
+			&g.CPUPct, &g.CPUs, &g.MemUsed, &g.MemTotal, &g.DiskUsed, &g.DiskTotal, &g.NetIn, &g.NetOut,
+			&g.DiskRead, &g.DiskWrite, &g.NetInRate, &g.NetOutRate, &g.DiskReadRate, &g.DiskWriteRate); err != nil {
+			return nil, fmt.Errorf("scanning guest snapshot: %w", err)
+		}
+		snaps = append(snaps, g)
+	}
+	return snaps, rows.Err()
+}
+
+// queryGuestRollup returns bucket averages from a guest rollup tier, followed
+// by the raw samples not rolled up yet, as snapshots, oldest first.
+func (s *Store) queryGuestRollup(instance string, vmid int, since int64) ([]model.GuestSnapshot, error) {
+	source, err := s.historySource("guest_snapshots", since)
+	if err != nil {
+		return nil, fmt.Errorf("querying guest history: %w", err)
+	}
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT ts, instance, vmid, cpu_pct,
+		       CAST(mem_used AS INTEGER), CAST(mem_total AS INTEGER),
+		       CAST(disk_used AS INTEGER), CAST(disk_total AS INTEGER),
+		       net_in_rate, net_out_rate, disk_read_rate, disk_write_rate
+		FROM %s
+		WHERE instance = ? AND vmid = ? AND ts >= ?
+		ORDER BY ts ASC`, source), instance, vmid, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying guest history: %w", err)
+	}
+	defer rows.Close()
+
+	var snaps []model.GuestSnapshot
+	for rows.Next() {
+		var g model.GuestSnapshot
+		if err := rows.Scan(&g.Timestamp, &g.Instance, &g.VMID, &g.CPUPct,
+			&g.MemUsed, &g.MemTotal, &g.DiskUsed, &g.DiskTotal,
+			&g.NetInRate, &g.NetOutRate, &g.DiskReadRate, &g.DiskWriteRate); err != nil {
+			return nil, fmt.Errorf("scanning guest rollup: %w", err)
+		}
+		snaps = append(snaps, g)
+	}
+	return snaps, rows.Err()
+}
+
 // UpsertPVEInstance inserts or updates a PVE instance record.
 func (s *Store) UpsertPVEInstance(name, host string, isCluster bool, clusterID string) error {
 	ic := 0
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -30,6 +32,49 @@ func TestNew_InvalidPath(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNew_AddsMissingColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// A guest_snapshots table as created by releases before throughput rates.
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE guest_snapshots (
+		ts INTEGER NOT NULL, instance TEXT NOT NULL, vmid INTEGER NOT NULL, node TEXT NOT NULL,
+		cluster_id TEXT, guest_type TEXT NOT NULL, name TEXT NOT NULL, status TEXT NOT NULL,
+		cpu_pct REAL NOT NULL, cpus INTEGER NOT NULL, mem_used INTEGER NOT NULL, mem_total INTEGER NOT NULL,
+		disk_used INTEGER NOT NULL, disk_total INTEGER NOT NULL, net_in INTEGER NOT NULL, net_out INTEGER NOT NULL,
+		PRIMARY KEY (ts, instance, vmid)
+	) WITHOUT ROWID`)
+	require.NoError(t, err)
+	now := time.Now().Unix()
+	_, err = db.Exec(`INSERT INTO guest_snapshots VALUES (?, 'main', 101, 'pve', 'main', 'lxc', 'ct', 'running', 1, 1, 1, 1, 1, 1, 10, 20)`, now-60)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	s, err := New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	rate := 42.0
+	require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+		Timestamp: now, Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc", Name: "ct", Status: "running",
+		DiskRead: 5, NetInRate: &rate,
+	}))
+
+	snaps, err := s.QueryGuestHistory("main", 101, now-3600)
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.Nil(t, snaps[0].NetInRate, "existing rows have no rate")
+	assert.Zero(t, snaps[0].DiskRead)
+	assert.Equal(t, rate, *snaps[1].NetInRate)
+
+	// Reopening is a no-op once the columns exist.
+	require.NoError(t, s.Close())
+	s2, err := New(path)
+	require.NoError(t, err)
+	s2.Close()
+}
+
 func TestInsertNodeSnapshot(t *testing.T) {
 	s := newTestStore(t)
 
@@ -97,6 +142,129 @@ func TestInsertSMARTSnapshot(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestQuerySMARTAttributeHistory(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().Unix()
+
+	for i, realloc := range []int64{0, 4, 8} {
+		disk := &model.Disk{
+			WWN:    "0x5000c500dc4e3541",
+			Health: "PASSED",
+			Attributes: []model.SMARTAttribute{
+				{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: realloc},
+				{ID: 9, Name: "Power_On_Hours", RawValue: 20000 + int64(i)},
+				{ID: 199, Name: "UDMA_CRC_Error_Count", RawValue: 1},
+			},
+		}
+		require.NoError(t, s.InsertSMARTSnapshot(now-int64((2-i)*3600), disk))
+	}
+	// Other disks and snapshots without attributes are ignored.
+	require.NoError(t, s.InsertSMARTSnapshot(now, &model.Disk{WWN: "other", Attributes: []model.SMARTAttribute{{ID: 5, RawValue: 99}}}))
+	require.NoError(t, s.InsertSMARTSnapshot(now-1800, &model.Disk{WWN: "0x5000c500dc4e3541"}))
+
+	samples, err := s.QuerySMARTAttributeHistory("0x5000c500dc4e3541", now-3600, []int{5, 199})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []model.AttributeSample{
+		{Timestamp: now - 3600, ID: 5, RawValue: 4},
+		{Timestamp: now - 3600, ID: 199, RawValue: 1},
+		{Timestamp: now, ID: 5, RawValue: 8},
+		{Timestamp: now, ID: 199, RawValue: 1},
+	}, samples)
+	assert.Equal(t, now-3600, samples[0].Timestamp)
+
+	samples, err = s.QuerySMARTAttributeHistory("0x5000c500dc4e3541", 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+}
+
+func TestQueryDiskHistory(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().Unix()
+
+	temp := 38
+	for i, realloc := range []int64{0, 4} {
+		disk := &model.Disk{
+			WWN:         "0x5000c500dc4e3541",
+			Health:      "PASSED",
+			Status:      i,
+			Temperature: &temp,
+			Attributes: []model.SMARTAttribute{
+				{ID: 5, Name: "Reallocated_Sector_Ct", RawValue: realloc},
+				{ID: 199, Name: "UDMA_CRC_Error_Count", RawValue: 1},
+			},
+		}
+		require.NoError(t, s.InsertSMARTSnapshot(now-int64((1-i)*3600), disk))
+	}
+	require.NoError(t, s.InsertSMARTSnapshot(now-7200, &model.Disk{WWN: "0x5000c500dc4e3541", Health: "PASSED"}))
+	require.NoError(t, s.InsertSMARTSnapshot(now, &model.Disk{WWN: "other", Health: "PASSED"}))
+
+	snaps, err := s.QueryDiskHistory("0x5000c500dc4e3541", 0, nil)
+	require.NoError(t, err)
+	require.Len(t, snaps, 3)
+	assert.Equal(t, now-7200, snaps[0].Timestamp)
+	assert.Nil(t, snaps[0].Temperature)
+	assert.Empty(t, snaps[0].Attributes)
+	assert.Equal(t, 1, snaps[2].Status)
+	require.NotNil(t, snaps[2].Temperature)
+	assert.Equal(t, 38, *snaps[2].Temperature)
+	assert.Len(t, snaps[2].Attributes, 2)
+
+	snaps, err = s.QueryDiskHistory("0x5000c500dc4e3541", now-3600, []int{5})
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	require.Len(t, snaps[1].Attributes, 1)
+	assert.Equal(t, "Reallocated_Sector_Ct", snaps[1].Attributes[0].Name)
+	assert.Equal(t, int64(4), snaps[1].Attributes[0].RawValue)
+
+	snaps, err = s.QueryDiskHistory("missing", 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, snaps)
+}
+
+func TestQueryDiskSparkline(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().Unix()
+
+	temp, wear := 41, 88
+	require.NoError(t, s.InsertSMARTSnapshot(now-7200, &model.Disk{WWN: "nvme-1", Health: "PASSED", Temperature: &temp, Wearout: &wear}))
+	require.NoError(t, s.InsertSMARTSnapshot(now-3600, &model.Disk{WWN: "nvme-1", Health: "PASSED"}))
+	wear = 87
+	require.NoError(t, s.InsertSMARTSnapshot(now, &model.Disk{WWN: "nvme-1", Health: "PASSED", Temperature: &temp, Wearout: &wear}))
+
+	points, err := s.QueryDiskSparkline("nvme-1", "wearout", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []model.SparklinePoint{{Timestamp: now - 7200, Value: 88}, {Timestamp: now, Value: 87}}, points)
+
+	points, err = s.QueryDiskSparkline("nvme-1", "temperature", now-3600)
+	require.NoError(t, err)
+	assert.Equal(t, []model.SparklinePoint{{Timestamp: now, Value: 41}}, points)
+
+	_, err = s.QueryDiskSparkline("nvme-1", "bogus", 0)
+	assert.Error(t, err)
+}
+
+func TestQueryFirstDiskPoint(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().Unix()
+
+	first, err := s.QueryFirstDiskPoint("nvme-1", "wearout")
+	require.NoError(t, err)
+	assert.Nil(t, first)
+
+	wear := 88
+	require.NoError(t, s.InsertSMARTSnapshot(now-7200, &model.Disk{WWN: "nvme-1", Health: "PASSED"}))
+	require.NoError(t, s.InsertSMARTSnapshot(now-3600, &model.Disk{WWN: "nvme-1", Health: "PASSED", Wearout: &wear}))
+	wear = 87
+	require.NoError(t, s.InsertSMARTSnapshot(now, &model.Disk{WWN: "nvme-1", Health: "PASSED", Wearout: &wear}))
+
+	first, err = s.QueryFirstDiskPoint("nvme-1", "wearout")
+	require.NoError(t, err)
+	assert.Equal(t, &model.SparklinePoint{Timestamp: now - 3600, Value: 88}, first)
+
+	_, err = s.QueryFirstDiskPoint("nvme-1", "bogus")
+	assert.Error(t, err)
+}
+
 func TestQueryNodeSparkline(t *testing.T) {
 	s := newTestStore(t)
 	now := time.Now().Unix()
@@ -130,6 +298,66 @@ func TestQueryNodeSparkline(t *testing.T) {
 	assert.Equal(t, float64(60), points[4].Value)
 }
 
+func TestQueryNodeSparkline_RRDMetrics(t *testing.T) {
+	s := newTestStore(t)
+	val := func(v float64) *float64 { return &v }
+	now := time.Now().Unix()
+
+	for i, v := range []*float64{nil, val(100), val(200)} {
+		require.NoError(t, s.InsertNodeSnapshot(model.NodeSnapshot{
+			Timestamp: now - int64((2-i)*60), Instance: "main", Node: "pve",
+			NetIn: v, NetOut: v, PSICPU: v, PSIIO: v, PSIIOFull: v, PSIMem: v, PSIMemFull: v,
+		}))
+	}
+
+	for _, metric := range []string{"netin", "netout", "psi_cpu", "psi_io", "psi_mem"} {
+		points, err := s.QueryNodeSparkline("main", "pve", metric, now-3600)
+		require.NoError(t, err, metric)
+		require.Len(t, points, 2, "samples without rrddata are omitted: %s", metric)
+		assert.Equal(t, float64(200), points[1].Value)
+	}
+
+	cpu, err := s.QueryNodeSparkline("main", "pve", "cpu", now-3600)
+	require.NoError(t, err)
+	assert.Len(t, cpu, 3)
+}
+
+func TestHasSnapshots(t *testing.T) {
+	s := newTestStore(t)
+
+	has, err := s.HasSnapshots("main")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+		Timestamp: 1000, Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc", Name: "ct", Status: "running",
+	}))
+	has, err = s.HasSnapshots("main")
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	has, err = s.HasSnapshots("other")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	require.NoError(t, s.Close())
+	_, err = s.HasSnapshots("main")
+	assert.Error(t, err)
+}
+
+func TestSize(t *testing.T) {
+	s := newTestStore(t)
+	size, free, err := s.Size()
+	require.NoError(t, err)
+	assert.Positive(t, size)
+	assert.GreaterOrEqual(t, free, int64(0))
+	assert.Less(t, free, size)
+
+	require.NoError(t, s.Close())
+	_, _, err = s.Size()
+	assert.Error(t, err)
+}
+
 func TestQueryNodeSparkline_InvalidMetric(t *testing.T) {
 	s := newTestStore(t)
 	_, err := s.QueryNodeSparkline("main", "pve", "invalid", 0)
@@ -247,20 +475,87 @@ func TestQueryGuestSparkline(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	points, err := s.QueryGuestSparkline("main", 101, now-300)
+	points, err := s.QueryGuestSparkline("main", 101, "cpu", now-300)
 	require.NoError(t, err)
 	assert.Len(t, points, 5)
 	assert.Equal(t, float64(10), points[0].Value)
 	assert.Equal(t, float64(30), points[4].Value)
 }
 
+func TestQueryGuestSparkline_Metrics(t *testing.T) {
+	s := newTestStore(t)
+	rate := func(v float64) *float64 { return &v }
+	now := time.Now().Unix()
+
+	for i, r := range []*float64{nil, rate(100), rate(200)} {
+		require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+			Timestamp: now - int64((2-i)*60), Instance: "main", VMID: 101, Node: "pve", GuestType: "lxc",
+			Name: "ct", Status: "running", MemUsed: 25, MemTotal: 100,
+			NetInRate: r, NetOutRate: r, DiskReadRate: r, DiskWriteRate: r,
+		}))
+	}
+
+	mem, err := s.QueryGuestSparkline("main", 101, "memory", now-3600)
+	require.NoError(t, err)
+	require.Len(t, mem, 3)
+	assert.Equal(t, float64(25), mem[0].Value)
+
+	for _, metric := range []string{"netin", "netout", "diskread", "diskwrite"} {
+		points, err := s.QueryGuestSparkline("main", 101, metric, now-3600)
+		require.NoError(t, err, metric)
+		require.Len(t, points, 2, "samples without a rate are omitted: %s", metric)
+		assert.Equal(t, float64(200), points[1].Value)
+	}
+
+	_, err = s.QueryGuestSparkline("main", 101, "bogus", 0)
+	assert.ErrorContains(t, err, "unknown metric")
+}
+
 func TestQueryGuestSparkline_Empty(t *testing.T) {
 	s := newTestStore(t)
-	points, err := s.QueryGuestSparkline("main", 999, 0)
+	points, err := s.QueryGuestSparkline("main", 999, "cpu", 0)
 	require.NoError(t, err)
 	assert.Empty(t, points)
 }
 
+func TestQueryGuestHistory(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().Unix()
+
+	for i := range 3 {
+		require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+			Timestamp: now - int64((2-i)*60),
+			Instance:  "main",
+			VMID:      101,
+			Node:      "pve",
+			ClusterID: "main",
+			GuestType: "lxc",
+			Name:      "network-services",
+			Status:    "running",
+			CPUPct:    float64(10 + i),
+			CPUs:      2,
+			MemUsed:   312_000_000,
+			MemTotal:  2_048_000_000,
+			NetIn:     int64(1_000_000 * (i + 1)),
+			NetOut:    500_000,
+		}))
+	}
+	// Another guest is not returned
+	require.NoError(t, s.InsertGuestSnapshot(model.GuestSnapshot{
+		Timestamp: now, Instance: "main", VMID: 102, Node: "pve", GuestType: "qemu", Name: "other", Status: "running",
+	}))
+
+	snaps, err := s.QueryGuestHistory("main", 101, now-300)
+	require.NoError(t, err)
+	require.Len(t, snaps, 3)
+	assert.Equal(t, now-120, snaps[0].Timestamp)
+	assert.Equal(t, float64(10), snaps[0].CPUPct)
+	assert.Equal(t, int64(3_000_000), snaps[2].NetIn)
+	assert.Equal(t, "network-services", snaps[2].Name)
+	assert.Equal(t, "main", snaps[2].ClusterID)
+	assert.Equal(t, 2, snaps[2].CPUs)
+}
+
 func TestInsertBackupSnapshot_NilVerified(t *testing.T) {
 	s := newTestStore(t)
 
@@ -387,7 +682,13 @@ func TestQueryNodeSparkline_ClosedDB(t *testing.T) {
 
 func TestQueryGuestSparkline_ClosedDB(t *testing.T) {
 	s := closedTestStore(t)
-	_, err := s.QueryGuestSparkline("a", 1, 0)
+	_, err := s.QueryGuestSparkline("a", 1, "cpu", 0)
+	assert.Error(t, err)
+}
+
+func TestQueryGuestHistory_ClosedDB(t *testing.T) {
+	s := closedTestStore(t)
+	_, err := s.QueryGuestHistory("a", 1, 0)
 	assert.Error(t, err)
 }
 
@@ -493,7 +794,7 @@ func BenchmarkQueryGuestSparkline(b *testing.B) {
 	}
 	b.ResetTimer()
 	for b.Loop() {
-		_, _ = s.QueryGuestSparkline("main", 101, now-3600)
+		_, _ = s.QueryGuestSparkline("main", 101, "cpu", now-3600)
 	}
 }
 
@@ -508,3 +809,147 @@ func TestNew_WritesToDisk(t *testing.T) {
 	_, err = os.Stat(dbPath)
 	assert.NoError(t, err)
 }
+
+func TestUpsertPVETask(t *testing.T) {
+	s := newTestStore(t)
+
+	// First seen while running
+	running := &model.PVETask{
+		Instance: "main", UPID: "UPID:pve:1:qmigrate:100:root@pam:", Node: "pve",
+		Type: "qmigrate", ID: "100", User: "root@pam", StartTime: 1000,
+	}
+	require.NoError(t, s.UpsertPVETask(running))
+
+	tasks, err := s.QueryPVETasks(0)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Nil(t, tasks[0].EndTime)
+	assert.Empty(t, tasks[0].Status)
+	assert.Equal(t, "qmigrate", tasks[0].Type)
+	assert.Equal(t, "root@pam", tasks[0].User)
+
+	// Updated once finished
+	end := int64(1060)
+	finished := *running
+	finished.EndTime = &end
+	finished.Status = "migration aborted"
+	require.NoError(t, s.UpsertPVETask(&finished))
+
+	tasks, err = s.QueryPVETasks(0)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.NotNil(t, tasks[0].EndTime)
+	assert.Equal(t, int64(1060), *tasks[0].EndTime)
+	assert.Equal(t, "migration aborted", tasks[0].Status)
+}
+
+func TestQueryPVETasks_SinceAndOrder(t *testing.T) {
+	s := newTestStore(t)
+	for i, ts := range []int64{1000, 3000, 2000} {
+		require.NoError(t, s.UpsertPVETask(&model.PVETask{
+			Instance: "main", UPID: fmt.Sprintf("UPID:%d", i), Node: "pve", Type: "vzstart", StartTime: ts,
+		}))
+	}
+
+	tasks, err := s.QueryPVETasks(1500)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, int64(3000), tasks[0].StartTime)
+	assert.Equal(t, int64(2000), tasks[1].StartTime)
+}
+
+func TestUpsertPVETask_ClosedDB(t *testing.T) {
+	s := closedTestStore(t)
+	err := s.UpsertPVETask(&model.PVETask{UPID: "a", Instance: "b", Node: "c", Type: "d"})
+	assert.Error(t, err)
+}
+
+func TestQueryPVETasks_ClosedDB(t *testing.T) {
+	s := closedTestStore(t)
+	_, err := s.QueryPVETasks(0)
+	assert.Error(t, err)
+}
+
+func TestHasDisk(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.UpsertDisk(&model.Disk{WWN: "wwn-a", Instance: "main", Node: "pve", DevPath: "/dev/sda", DiskType: "hdd", Protocol: "ata"}))
+
+	has, err := s.HasDisk("wwn-a")
+	require.NoError(t, err)
+	assert.True(t, has)
+	has, err = s.HasDisk("wwn-b")
+	require.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestQueryDiskInventory(t *testing.T) {
+	s := newTestStore(t)
+
+	var b Batch
+	b.AddDisk(&model.Disk{WWN: "wwn-a", Instance: "main", Node: "pve", DevPath: "/dev/sdb", Model: "WD Red", Serial: "WD-1", DiskType: "hdd", Protocol: "ata", SizeBytes: 4e12})
+	b.AddDisk(&model.Disk{WWN: "wwn-b", Instance: "main", Node: "pve", DevPath: "/dev/sda", DiskType: "ssd", Protocol: "ata"})
+	b.AddDisk(&model.Disk{WWN: "wwn-c", Instance: "other", Node: "pve2", DevPath: "/dev/sda", DiskType: "ssd", Protocol: "ata"})
+	removed := time.Unix(1_700_000_000, 0)
+	b.AddDiskAbsence(&model.InventoryDisk{WWN: "wwn-a", Instance: "main", MissedPolls: 2, RemovedAt: &removed, ReplacedBy: "wwn-d"})
+	// An absence recorded by another instance leaves the disk alone
+	b.AddDiskAbsence(&model.InventoryDisk{WWN: "wwn-c", Instance: "main", MissedPolls: 1})
+	require.NoError(t, s.WriteBatch(&b))
+
+	disks, err := s.QueryDiskInventory("main")
+	require.NoError(t, err)
+	require.Len(t, disks, 2)
+	assert.Equal(t, "wwn-b", disks[0].WWN)
+	assert.True(t, disks[0].Present())
+	a := disks[1]
+	assert.Equal(t, "WD Red", a.Model)
+	assert.Equal(t, "WD-1", a.Serial)
+	assert.Equal(t, int64(4e12), a.SizeBytes)
+	assert.Equal(t, 2, a.MissedPolls)
+	require.NotNil(t, a.RemovedAt)
+	assert.Equal(t, removed.Unix(), a.RemovedAt.Unix())
+	assert.Equal(t, "wwn-d", a.ReplacedBy)
+	assert.False(t, a.FirstSeen.IsZero())
+
+	all, err := s.QueryDiskInventory("")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Zero(t, all[2].MissedPolls)
+
+	// Seeing the disk again clears its absence
+	require.NoError(t, s.UpsertDisk(&model.Disk{WWN: "wwn-a", Instance: "main", Node: "pve", DevPath: "/dev/sdc", DiskType: "hdd", Protocol: "ata"}))
+	disks, err = s.QueryDiskInventory("main")
+	require.NoError(t, err)
+	assert.True(t, disks[1].Present())
+	assert.Empty(t, disks[1].ReplacedBy)
+	assert.Equal(t, "/dev/sdc", disks[1].DevPath)
+}
+
+func TestQueryDiskEvents(t *testing.T) {
+	s := newTestStore(t)
+
+	var b Batch
+	b.AddDiskEvent(&model.DiskEvent{Timestamp: 100, Type: model.DiskEventAdded, WWN: "wwn-a", Instance: "main", Node: "pve", DevPath: "/dev/sda", Serial: "A"})
+	b.AddDiskEvent(&model.DiskEvent{Timestamp: 200, Type: model.DiskEventRemoved, WWN: "wwn-a", Instance: "main", Node: "pve", DevPath: "/dev/sda", Serial: "A"})
+	b.AddDiskEvent(&model.DiskEvent{Timestamp: 200, Type: model.DiskEventReplaced, WWN: "wwn-b", Instance: "main", Node: "pve", DevPath: "/dev/sda",
+		Model: "WD Red", Serial: "B", PrevWWN: "wwn-a", PrevSerial: "A"})
+	require.NoError(t, s.WriteBatch(&b))
+
+	events, err := s.QueryDiskEvents(150)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	// Same timestamp: the later insert comes first
+	assert.Equal(t, model.DiskEventReplaced, events[0].Type)
+	assert.Equal(t, "WD Red", events[0].Model)
+	assert.Equal(t, "A", events[0].PrevSerial)
+	assert.Equal(t, "wwn-a", events[0].PrevWWN)
+	assert.Empty(t, events[0].PrevNode)
+	assert.Equal(t, model.DiskEventRemoved, events[1].Type)
+}
+
+func TestQueryDiskInventory_ClosedDB(t *testing.T) {
+	s := closedTestStore(t)
+	_, err := s.QueryDiskInventory("")
+	assert.Error(t, err)
+	_, err = s.QueryDiskEvents(0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+-- Database as written by the initial release (schema version 1),
+-- before schema_version existed. Generated from the schema of that release.
+
+-- Registered PVE instances from config
+CREATE TABLE IF NOT EXISTS pve_instances (
+    name        TEXT PRIMARY KEY,
+    host        TEXT NOT NULL,
+    is_cluster  INTEGER NOT NULL DEFAULT 0,
+    cluster_id  TEXT
+);
+
+-- Discovered nodes (refreshed on each poll)
+CREATE TABLE IF NOT EXISTS nodes (
+    instance    TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    cpu_model   TEXT,
+    cpu_cores   INTEGER,
+    cpu_threads INTEGER,
+    cpu_sockets INTEGER,
+    pve_version TEXT,
+    kernel_ver  TEXT,
+    PRIMARY KEY (instance, name),
+    FOREIGN KEY (instance) REFERENCES pve_instances(name)
+);
+
+-- Registered PBS instances from config
+CREATE TABLE IF NOT EXISTS pbs_instances (
+    name        TEXT PRIMARY KEY,
+    host        TEXT NOT NULL
+);
+
+-- Known disks (identified by WWN, persistent across reboots)
+CREATE TABLE IF NOT EXISTS disks (
+    wwn         TEXT PRIMARY KEY,
+    instance    TEXT NOT NULL,
+    node        TEXT NOT NULL,
+    dev_path    TEXT NOT NULL,
+    model       TEXT,
+    serial      TEXT,
+    disk_type   TEXT NOT NULL,
+    protocol    TEXT NOT NULL,
+    size_bytes  INTEGER NOT NULL,
+    first_seen  INTEGER NOT NULL,
+    last_seen   INTEGER NOT NULL
+);
+
+-- Host/node metrics (48h retention)
+CREATE TABLE IF NOT EXISTS node_snapshots (
+    ts          INTEGER NOT NULL,
+    instance    TEXT NOT NULL,
+    node        TEXT NOT NULL,
+    cpu_pct     REAL    NOT NULL,
+    mem_used    INTEGER NOT NULL,
+    mem_total   INTEGER NOT NULL,
+    swap_used   INTEGER NOT NULL,
+    swap_total  INTEGER NOT NULL,
+    rootfs_used INTEGER NOT NULL,
+    rootfs_total INTEGER NOT NULL,
+    load_1m     REAL    NOT NULL,
+    load_5m     REAL    NOT NULL,
+    load_15m    REAL    NOT NULL,
+    io_wait     REAL    NOT NULL,
+    uptime_secs INTEGER NOT NULL,
+    cpu_temp    REAL,
+    PRIMARY KEY (ts, instance, node)
+) WITHOUT ROWID;
+
+-- Guest metrics (48h retention)
+CREATE TABLE IF NOT EXISTS guest_snapshots (
+    ts          INTEGER NOT NULL,
+    instance    TEXT NOT NULL,
+    vmid        INTEGER NOT NULL,
+    node        TEXT NOT NULL,
+    cluster_id  TEXT,
+    guest_type  TEXT NOT NULL,
+    name        TEXT    NOT NULL,
+    status      TEXT    NOT NULL,
+    cpu_pct     REAL    NOT NULL,
+    cpus        INTEGER NOT NULL,
+    mem_used    INTEGER NOT NULL,
+    mem_total   INTEGER NOT NULL,
+    disk_used   INTEGER NOT NULL,
+    disk_total  INTEGER NOT NULL,
+    net_in      INTEGER NOT NULL,
+    net_out     INTEGER NOT NULL,
+    PRIMARY KEY (ts, instance, vmid)
+) WITHOUT ROWID;
+
+-- Disk SMART snapshots (30d retention)
+CREATE TABLE IF NOT EXISTS smart_snapshots (
+    ts              INTEGER NOT NULL,
+    wwn             TEXT    NOT NULL,
+    health          TEXT    NOT NULL,
+    status          INTEGER NOT NULL,
+    temperature     INTEGER,
+    power_on_hours  INTEGER,
+    wearout         INTEGER,
+    attributes_json TEXT,
+    PRIMARY KEY (ts, wwn)
+) WITHOUT ROWID;
+
+-- PBS backup snapshots (7d retention)
+CREATE TABLE IF NOT EXISTS backup_snapshots (
+    ts             INTEGER NOT NULL,
+    pbs_instance   TEXT    NOT NULL,
+    datastore      TEXT    NOT NULL,
+    backup_type    TEXT    NOT NULL,
+    backup_id      TEXT    NOT NULL,
+    backup_time    INTEGER NOT NULL,
+    size_bytes     INTEGER,
+    verified       INTEGER,
+    PRIMARY KEY (ts, pbs_instance, backup_id, backup_time)
+) WITHOUT ROWID;
+
+-- PBS datastore usage (7d retention)
+CREATE TABLE IF NOT EXISTS datastore_snapshots (
+    ts              INTEGER NOT NULL,
+    pbs_instance    TEXT    NOT NULL,
+    store_name      TEXT    NOT NULL,
+    total_bytes     INTEGER,
+    used_bytes      INTEGER,
+    avail_bytes     INTEGER,
+    dedup_ratio     REAL,
+    est_full_date   INTEGER,
+    PRIMARY KEY (ts, pbs_instance, store_name)
+) WITHOUT ROWID;
+
+-- Alert log (30d retention)
+CREATE TABLE IF NOT EXISTS alert_log (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    ts          INTEGER NOT NULL,
+    alert_type  TEXT    NOT NULL,
+    instance    TEXT,
+    subject     TEXT    NOT NULL,
+    message     TEXT    NOT NULL,
+    severity    TEXT    NOT NULL
+);
+
+-- Secondary indexes
+CREATE INDEX IF NOT EXISTS idx_guest_vmid ON guest_snapshots(instance, vmid, ts);
+CREATE INDEX IF NOT EXISTS idx_smart_wwn ON smart_snapshots(wwn, ts);
+CREATE INDEX IF NOT EXISTS idx_alert_ts ON alert_log(ts);
+
+-- Sample data, valid for every release.
+INSERT INTO pve_instances (name, host, is_cluster) VALUES ('main', 'https://pve:8006', 0);
+INSERT INTO nodes (instance, name, status, pve_version) VALUES ('main', 'pve', 'online', '8.3.0');
+INSERT INTO pbs_instances (name, host) VALUES ('pbs', 'https://pbs:8007');
+INSERT INTO disks (wwn, instance, node, dev_path, model, disk_type, protocol, size_bytes, first_seen, last_seen)
+VALUES ('0x5000c500a1b2c3d4', 'main', 'pve', '/dev/sda', 'ST4000', 'hdd', 'ata', 4000787030016, 1767225600, 1767229200);
+INSERT INTO node_snapshots (ts, instance, node, cpu_pct, mem_used, mem_total, swap_used, swap_total,
+    rootfs_used, rootfs_total, load_1m, load_5m, load_15m, io_wait, uptime_secs)
+VALUES (1767225600, 'main', 'pve', 12.5, 4096, 8192, 0, 1024, 100, 200, 0.5, 0.4, 0.3, 0.01, 86400);
+INSERT INTO guest_snapshots (ts, instance, vmid, node, guest_type, name, status, cpu_pct, cpus,
+    mem_used, mem_total, disk_used, disk_total, net_in, net_out)
+VALUES (1767225600, 'main', 101, 'pve', 'lxc', 'ct', 'running', 3.5, 2, 256, 1024, 10, 100, 5000, 6000);
+INSERT INTO smart_snapshots (ts, wwn, health, status, temperature, power_on_hours)
+VALUES (1767225600, '0x5000c500a1b2c3d4', 'passed', 0, 34, 12000);
+INSERT INTO backup_snapshots (ts, pbs_instance, datastore, backup_type, backup_id, backup_time, size_bytes, verified)
+VALUES (1767225600, 'pbs', 'store1', 'ct', '101', 1767222000, 1048576, 1);
+INSERT INTO datastore_snapshots (ts, pbs_instance, store_name, total_bytes, used_bytes, avail_bytes)
+VALUES (1767225600, 'pbs', 'store1', 1000, 400, 600);
+INSERT INTO alert_log (ts, alert_type, instance, subject, message, severity)
+VALUES (1767225600, 'node_cpu_high', 'main', 'pve', 'CPU at 95%', 'warning');
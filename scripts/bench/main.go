@@ -1,7 +1,9 @@
 // Benchmark report tool for Glint.
 //
 // Runs all benchmarks, captures output, and writes a timestamped report to
-// target/reports/bench.txt. Exits non-zero if any benchmarks fail.
+// target/reports/bench.txt, followed by a side-by-side summary of the
+// benchmark pairs listed in comparisons. Exits non-zero if any benchmarks
+// fail.
 //
 // Usage:
 //
@@ -17,11 +19,30 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
 
+type comparison struct {
+	Title     string
+	Baseline  string // benchmark name without the -GOMAXPROCS suffix
+	Contender string
+}
+
+// comparisons are benchmark pairs measuring two approaches to the same work.
+var comparisons = []comparison{
+	{
+		Title:     "Store writes for one 200-guest poll cycle",
+		Baseline:  "BenchmarkWritePollCycle/per_row",
+		Contender: "BenchmarkWritePollCycle/batch",
+	},
+}
+
+var reBenchLine = regexp.MustCompile(`^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op`)
+
 func main() {
 	projectRoot := findProjectRoot()
 	reportDir := filepath.Join(projectRoot, "target", "reports")
@@ -65,6 +86,7 @@ func main() {
 	fmt.Fprintf(&report, "Benchmark Time: %s per benchmark\n", benchTime)
 	report.WriteString(sep + "\n\n")
 	report.WriteString(buf.String())
+	report.WriteString(buildComparisons(buf.String()))
 	if runErr != nil {
 		fmt.Fprintf(&report, "\n[ERROR] %v\n", runErr)
 	}
@@ -81,6 +103,34 @@ func main() {
 	fmt.Println("Benchmark run complete.")
 }
 
+// buildComparisons summarizes each comparison whose benchmarks both ran.
+func buildComparisons(output string) string {
+	nsPerOp := make(map[string]float64)
+	for line := range strings.SplitSeq(output, "\n") {
+		if m := reBenchLine.FindStringSubmatch(line); m != nil {
+			if v, err := strconv.ParseFloat(m[2], 64); err == nil {
+				nsPerOp[m[1]] = v
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, c := range comparisons {
+		base, ok1 := nsPerOp[c.Baseline]
+		cont, ok2 := nsPerOp[c.Contender]
+		if !ok1 || !ok2 || cont == 0 {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString("\nComparisons\n" + strings.Repeat("-", 72) + "\n")
+		}
+		fmt.Fprintf(&b, "%s\n", c.Title)
+		fmt.Fprintf(&b, "  %-40s %14.0f ns/op\n", c.Baseline, base)
+		fmt.Fprintf(&b, "  %-40s %14.0f ns/op  (%.1fx faster)\n", c.Contender, cont, base/cont)
+	}
+	return b.String()
+}
+
 func captureGoVersion() string {
 	out, err := exec.Command("go", "version").Output()
 	if err != nil {
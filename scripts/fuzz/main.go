@@ -39,6 +39,8 @@ var fuzzTargets = []fuzzTarget{
 	{Function: "FuzzParseATAAttributes", Package: "./internal/smart/"},
 	{Function: "FuzzParseSCSIText", Package: "./internal/smart/"},
 	{Function: "FuzzParseNVMeText", Package: "./internal/smart/"},
+	{Function: "FuzzParseSmartctlJSON", Package: "./internal/smart/"},
+	{Function: "FuzzParseSelfTestText", Package: "./internal/smart/"},
 	// Sensor parsing
 	{Function: "FuzzParseSensorsJSON", Package: "./internal/collector/"},
 	// Config parsing
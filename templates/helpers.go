@@ -21,6 +21,24 @@ var CSSVersion = "dev"
 // dashboard chip matches the alerter threshold.
 var BackupStaleHours float64 = 36
 
+// EnduranceLowMonths is the projected SSD life (in months) below which the
+// disk detail highlights it. Set at startup from the disk_endurance_low alert.
+var EnduranceLowMonths float64 = 6
+
+// DiskTempLimit returns the temperature (°C) at or above which a disk of the
+// given type is highlighted. Set at startup from the disk_temp_high alert so
+// the dashboard and the alerter use the same per-type limits.
+var DiskTempLimit = func(diskType string) float64 {
+	switch diskType {
+	case "nvme":
+		return 70
+	case "ssd":
+		return 60
+	default:
+		return 50
+	}
+}
+
 // FormatBytes formats bytes into human-readable form.
 func FormatBytes(b int64) string {
 	const unit = 1024
@@ -93,7 +111,7 @@ func DiskStatusClass(status int) string {
 	if status&model.StatusFailedSmart != 0 || status&model.StatusFailedScrutiny != 0 {
 		return "chip-crit"
 	}
-	if status&model.StatusWarnScrutiny != 0 {
+	if status&(model.StatusWarnScrutiny|model.StatusDegrading) != 0 {
 		return "chip-warn"
 	}
 	if status&model.StatusUnknown != 0 {
@@ -251,6 +269,102 @@ func SortedDiskList(disks map[string]*model.Disk) []*model.Disk {
 	return list
 }
 
+// HottestDisks returns up to n disks with a temperature reading, hottest
+// first.
+func HottestDisks(disks map[string]*model.Disk, n int) []*model.Disk {
+	var list []*model.Disk
+	for _, d := range disks {
+		if d.Temperature != nil {
+			list = append(list, d)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if *list[i].Temperature != *list[j].Temperature {
+			return *list[i].Temperature > *list[j].Temperature
+		}
+		return list[i].DevPath < list[j].DevPath
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// ReplaceSoonPct is the annual failure probability, in percent, at which a
+// disk is listed as due for replacement.
+const ReplaceSoonPct = 10.0
+
+// RiskRanking returns the disks with a risk score, most likely to fail first.
+func RiskRanking(disks map[string]*model.Disk) []*model.Disk {
+	var list []*model.Disk
+	for _, d := range disks {
+		if d.Risk != nil {
+			list = append(list, d)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Risk.AnnualFailurePct != list[j].Risk.AnnualFailurePct {
+			return list[i].Risk.AnnualFailurePct > list[j].Risk.AnnualFailurePct
+		}
+		if list[i].Instance != list[j].Instance {
+			return list[i].Instance < list[j].Instance
+		}
+		if list[i].Node != list[j].Node {
+			return list[i].Node < list[j].Node
+		}
+		return list[i].DevPath < list[j].DevPath
+	})
+	return list
+}
+
+// ReplaceSoon returns up to n disks at or above ReplaceSoonPct, riskiest
+// first.
+func ReplaceSoon(disks map[string]*model.Disk, n int) []*model.Disk {
+	list := RiskRanking(disks)
+	i := sort.Search(len(list), func(i int) bool { return list[i].Risk.AnnualFailurePct < ReplaceSoonPct })
+	return list[:min(i, n)]
+}
+
+// RiskDisplay formats a disk's annual failure probability, or "--" when it
+// has no score.
+func RiskDisplay(r *model.DiskRisk) string {
+	if r == nil {
+		return "--"
+	}
+	return fmt.Sprintf("%.1f%%", r.AnnualFailurePct)
+}
+
+// RiskSortValue returns the failure probability for table sorting; disks
+// without a score sort below every scored disk.
+func RiskSortValue(r *model.DiskRisk) string {
+	if r == nil {
+		return "-1"
+	}
+	return fmt.Sprintf("%.4f", r.AnnualFailurePct)
+}
+
+// DiskRiskClass colours a failure probability like an attribute's failure
+// rate: high from 10%, medium from 5%.
+func DiskRiskClass(pct float64) string {
+	switch {
+	case pct >= 10:
+		return "risk-high"
+	case pct >= 5:
+		return "risk-medium"
+	default:
+		return "risk-low"
+	}
+}
+
+// DiskTempClass highlights a disk at or above the temperature limit for its
+// type.
+func DiskTempClass(disk *model.Disk) string {
+	if disk.Temperature != nil && float64(*disk.Temperature) >= DiskTempLimit(disk.DiskType) {
+		return "text-warn"
+	}
+	return ""
+}
+
 // AllBackupsSorted returns all backups sorted by backup ID.
 func AllBackupsSorted(backups map[string]map[string]*model.Backup) []*model.Backup {
 	var list []*model.Backup
@@ -312,6 +426,129 @@ func AllTasksSorted(tasks map[string][]*model.PBSTask) []*model.PBSTask {
 	return list
 }
 
+// EventsFilter holds the events panel filter selection. Empty fields match everything.
+type EventsFilter struct {
+	Source string // "pbs" or "pve"
+	Type   string // task type, e.g. "qmigrate"
+	Status string // "failed" or "running"
+}
+
+// TaskEvent is a PBS or PVE task as shown in the events panel.
+type TaskEvent struct {
+	Source    string // "pbs" or "pve"
+	Instance  string
+	Node      string
+	Type      string
+	ID        string
+	User      string
+	StartTime int64
+	EndTime   *int64
+	Status    string
+}
+
+// AllPVETasksSorted returns all cached PVE tasks sorted by start time descending.
+func AllPVETasksSorted(tasks map[string][]*model.PVETask) []*model.PVETask {
+	var list []*model.PVETask
+	for _, instanceTasks := range tasks {
+		list = append(list, instanceTasks...)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].StartTime > list[j].StartTime })
+	return list
+}
+
+// BuildEvents merges PBS and PVE tasks into a single list, applies the filter
+// and sorts by start time descending.
+func BuildEvents(pbsTasks map[string][]*model.PBSTask, pveTasks []*model.PVETask, f EventsFilter) []TaskEvent {
+	var all []TaskEvent
+	for _, t := range AllTasksSorted(pbsTasks) {
+		all = append(all, TaskEvent{
+			Source: "pbs", Instance: t.PBSInstance, Type: t.Type, ID: t.ID, User: t.User,
+			StartTime: t.StartTime, EndTime: t.EndTime, Status: t.Status,
+		})
+	}
+	for _, t := range pveTasks {
+		all = append(all, TaskEvent{
+			Source: "pve", Instance: t.Instance, Node: t.Node, Type: t.Type, ID: t.ID, User: t.User,
+			StartTime: t.StartTime, EndTime: t.EndTime, Status: t.Status,
+		})
+	}
+
+	var list []TaskEvent
+	for _, e := range all {
+		if f.Source != "" && e.Source != f.Source {
+			continue
+		}
+		if f.Type != "" && e.Type != f.Type {
+			continue
+		}
+		switch f.Status {
+		case "failed":
+			if EventStatusClass(e) != "chip-crit" {
+				continue
+			}
+		case "running":
+			if e.EndTime != nil {
+				continue
+			}
+		}
+		list = append(list, e)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].StartTime > list[j].StartTime })
+	return list
+}
+
+// EventTypes returns the distinct task types across PBS and PVE tasks, sorted,
+// for the events panel type filter.
+func EventTypes(pbsTasks map[string][]*model.PBSTask, pveTasks []*model.PVETask) []string {
+	seen := make(map[string]bool)
+	for _, tasks := range pbsTasks {
+		for _, t := range tasks {
+			seen[t.Type] = true
+		}
+	}
+	for _, t := range pveTasks {
+		seen[t.Type] = true
+	}
+	types := make([]string, 0, len(seen))
+	for t := range seen {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// EventDurationSeconds returns the event duration in seconds, or -1 while running.
+func EventDurationSeconds(e TaskEvent) int64 {
+	if e.EndTime == nil {
+		return -1
+	}
+	return *e.EndTime - e.StartTime
+}
+
+// EventDuration returns the formatted event duration.
+func EventDuration(e TaskEvent) string {
+	if e.EndTime == nil {
+		return "running"
+	}
+	return FormatDuration(time.Duration(*e.EndTime-e.StartTime) * time.Second)
+}
+
+// EventStatusClass returns CSS chip class for an event status. PBS reports
+// failures as "Error: ..."; PVE reports the bare error message.
+func EventStatusClass(e TaskEvent) string {
+	if e.Source == "pbs" {
+		return TaskStatusClass(e.Status)
+	}
+	switch {
+	case e.Status == "OK" || e.Status == "":
+		return "chip-ok"
+	case strings.HasPrefix(e.Status, "WARNINGS"):
+		return "chip-warn"
+	default:
+		return "chip-crit"
+	}
+}
+
 // LatestBackupTime returns the most recent backup timestamp for a guest, or 0 if none.
 func LatestBackupTime(backups map[string]map[string]*model.Backup, vmid int) int64 {
 	bs := BackupsForGuest(backups, vmid)
@@ -321,6 +558,216 @@ func LatestBackupTime(backups map[string]map[string]*model.Backup, vmid int) int
 	return bs[0].BackupTime
 }
 
+// LatestBackupTimeOf returns the most recent backup time in a list, or 0 if empty.
+func LatestBackupTimeOf(backups []*model.Backup) int64 {
+	var latest int64
+	for _, b := range backups {
+		latest = max(latest, b.BackupTime)
+	}
+	return latest
+}
+
+// PVEBackupFor returns the vzdump backup state for a guest, or the zero value
+// when none has been collected.
+func PVEBackupFor(pveBackups map[string]map[int]*model.PVEGuestBackup, g *model.Guest) model.PVEGuestBackup {
+	if gb, ok := pveBackups[g.ClusterID][g.VMID]; ok {
+		return *gb
+	}
+	return model.PVEGuestBackup{}
+}
+
+// AllBackupJobsSorted returns all PVE backup jobs sorted by instance then job ID.
+func AllBackupJobsSorted(jobs map[string][]*model.PVEBackupJob) []*model.PVEBackupJob {
+	var list []*model.PVEBackupJob
+	for _, instanceJobs := range jobs {
+		list = append(list, instanceJobs...)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Instance != list[j].Instance {
+			return list[i].Instance < list[j].Instance
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// BackupJobSelection describes which guests a backup job includes.
+func BackupJobSelection(job *model.PVEBackupJob) string {
+	var sel string
+	switch {
+	case job.All && len(job.Exclude) > 0:
+		sel = "all except " + joinInts(job.Exclude)
+	case job.All:
+		sel = "all"
+	case job.Pool != "":
+		sel = "pool " + job.Pool
+	default:
+		sel = joinInts(job.VMIDs)
+	}
+	if job.Node != "" {
+		sel += " on " + job.Node
+	}
+	return sel
+}
+
+func joinInts(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// UncoveredGuests returns guests on PVE instances with backup job data that
+// are not included in any enabled vzdump job, sorted by VMID. Guests of
+// clusters without job data are skipped: absence of data is not absence of jobs.
+func UncoveredGuests(guests map[string]map[int]*model.Guest, pveBackups map[string]map[int]*model.PVEGuestBackup) []*model.Guest {
+	var list []*model.Guest
+	for clusterID, clusterGuests := range guests {
+		backups, ok := pveBackups[clusterID]
+		if !ok {
+			continue
+		}
+		for vmid, g := range clusterGuests {
+			if gb, ok := backups[vmid]; ok && len(gb.Jobs) == 0 {
+				list = append(list, g)
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].VMID < list[j].VMID })
+	return list
+}
+
+// GuestHistoryWindows are the selectable guest detail chart windows, in hours.
+// Windows beyond the 48h raw retention are served from the rollup tiers.
+var GuestHistoryWindows = []int{1, 6, 24, 48, 7 * 24, 30 * 24}
+
+// WindowLabel formats a history window in hours, using days for whole weeks
+// and longer: "6h", "48h", "7d", "30d".
+func WindowLabel(hours int) string {
+	if hours >= 7*24 && hours%24 == 0 {
+		return fmt.Sprintf("%dd", hours/24)
+	}
+	return fmt.Sprintf("%dh", hours)
+}
+
+// FindGuest returns the cached guest with the given PVE instance and VMID, or nil.
+func FindGuest(guests map[string]map[int]*model.Guest, instance string, vmid int) *model.Guest {
+	for _, clusterGuests := range guests {
+		if g, ok := clusterGuests[vmid]; ok && g.Instance == instance {
+			return g
+		}
+	}
+	return nil
+}
+
+// GuestConfigFor returns the collected configuration for a guest, or nil.
+func GuestConfigFor(configs map[string]map[int]*model.GuestConfig, g *model.Guest) *model.GuestConfig {
+	return configs[g.ClusterID][g.VMID]
+}
+
+// GuestMetricSeries extracts a chart series from guest history. Supported
+// metrics are "cpu" and "memory" (percent), "disk" (bytes used) and the
+// throughput rates "netin", "netout", "diskread" and "diskwrite" (bytes/sec).
+// Samples without a rate (first poll, counter reset) are skipped.
+func GuestMetricSeries(history []model.GuestSnapshot, metric string) []model.SparklinePoint {
+	var points []model.SparklinePoint
+	for _, h := range history {
+		var v float64
+		switch metric {
+		case "cpu":
+			v = h.CPUPct
+		case "memory":
+			v = MemPct(h.MemUsed, h.MemTotal)
+		case "disk":
+			v = float64(h.DiskUsed)
+		case "netin", "netout", "diskread", "diskwrite":
+			rate := guestRate(h, metric)
+			if rate == nil {
+				continue
+			}
+			v = *rate
+		default:
+			return nil
+		}
+		points = append(points, model.SparklinePoint{Timestamp: h.Timestamp, Value: v})
+	}
+	return points
+}
+
+// guestRate returns the stored rate for a throughput metric, or nil.
+func guestRate(h model.GuestSnapshot, metric string) *float64 {
+	switch metric {
+	case "netin":
+		return h.NetInRate
+	case "netout":
+		return h.NetOutRate
+	case "diskread":
+		return h.DiskReadRate
+	default:
+		return h.DiskWriteRate
+	}
+}
+
+// FormatGuestMetric formats the latest value of a guest metric series, or "—" when empty.
+func FormatGuestMetric(points []model.SparklinePoint, metric string) string {
+	if len(points) == 0 {
+		return "—"
+	}
+	v := points[len(points)-1].Value
+	switch metric {
+	case "cpu", "memory":
+		return FormatPct(v)
+	case "netin", "netout", "diskread", "diskwrite":
+		return FormatRate(v)
+	default:
+		return FormatBytes(int64(v))
+	}
+}
+
+// GuestMetricLabel returns the display label for a guest chart metric.
+func GuestMetricLabel(metric string) string {
+	switch metric {
+	case "cpu":
+		return "CPU"
+	case "memory":
+		return "Memory"
+	case "disk":
+		return "Disk used"
+	case "netin":
+		return "Net in"
+	case "netout":
+		return "Net out"
+	case "diskread":
+		return "Disk read"
+	case "diskwrite":
+		return "Disk write"
+	default:
+		return metric
+	}
+}
+
+// FormatRate formats a bytes/sec rate.
+func FormatRate(bytesPerSec float64) string {
+	return FormatBytes(int64(bytesPerSec)) + "/s"
+}
+
+// GuestTasks returns the most recent PVE tasks for a guest, newest first.
+func GuestTasks(tasks []*model.PVETask, instance string, vmid int, limit int) []*model.PVETask {
+	id := fmt.Sprintf("%d", vmid)
+	var list []*model.PVETask
+	for _, t := range tasks {
+		if t.Instance == instance && t.ID == id {
+			list = append(list, t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].StartTime > list[j].StartTime })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
 // IntPtrSortValue returns the integer as a string, or "-1" if the pointer is nil.
 // Used for data-sort-value attributes so nil values sort before valid ones.
 func IntPtrSortValue(p *int) string {
@@ -359,6 +806,82 @@ func TaskStatusClass(status string) string {
 	return "chip-warn"
 }
 
+// CephHealthClass returns CSS chip class for a Ceph health status or check severity.
+func CephHealthClass(health string) string {
+	switch health {
+	case "HEALTH_OK":
+		return "chip-ok"
+	case "HEALTH_WARN":
+		return "chip-warn"
+	case "HEALTH_ERR":
+		return "chip-crit"
+	default:
+		return "chip-unk"
+	}
+}
+
+// CephHealthLabel strips the HEALTH_ prefix for display ("HEALTH_WARN" → "WARN").
+func CephHealthLabel(health string) string {
+	return strings.TrimPrefix(health, "HEALTH_")
+}
+
+// SortedCephList returns all Ceph statuses sorted by PVE instance name.
+func SortedCephList(ceph map[string]*model.CephStatus) []*model.CephStatus {
+	list := make([]*model.CephStatus, 0, len(ceph))
+	for _, c := range ceph {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Instance < list[j].Instance })
+	return list
+}
+
+// CephQuorumSummary returns "in quorum / total" for the Ceph monitors.
+func CephQuorumSummary(mons []model.CephMonitor) string {
+	in := 0
+	for _, m := range mons {
+		if m.InQuorum {
+			in++
+		}
+	}
+	return fmt.Sprintf("%d/%d", in, len(mons))
+}
+
+// CephOSDStatusClass returns CSS chip class for an OSD: down is critical, up but out is a warning.
+func CephOSDStatusClass(osd model.CephOSD) string {
+	if osd.Status != "up" {
+		return "chip-crit"
+	}
+	if !osd.In {
+		return "chip-warn"
+	}
+	return "chip-ok"
+}
+
+// SortedClusterList returns all cluster statuses sorted by PVE instance name.
+func SortedClusterList(clusters map[string]*model.ClusterStatus) []*model.ClusterStatus {
+	list := make([]*model.ClusterStatus, 0, len(clusters))
+	for _, cl := range clusters {
+		list = append(list, cl)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Instance < list[j].Instance })
+	return list
+}
+
+// HAStateClass returns CSS chip class for an HA resource CRM state.
+func HAStateClass(state string) string {
+	switch state {
+	case "started", "stopped", "ignored":
+		return "chip-ok"
+	case "error", "fence":
+		return "chip-crit"
+	case "":
+		return "chip-unk"
+	default:
+		// freeze, migrate, relocate, recovery, request_stop, ...
+		return "chip-warn"
+	}
+}
+
 // HeaderSummary returns a compact summary string for the dashboard header badge.
 func HeaderSummary(snap cache.CacheSnapshot) string {
 	nodeCount := 0
@@ -416,6 +939,248 @@ func WearoutDisplay(w *int) string {
 	return fmt.Sprintf("%d%%", *w)
 }
 
+// DeltaDisplay returns a SMART counter change as "+8", "0" or "-4", or "--"
+// when there is no history for the window.
+func DeltaDisplay(d *int64) string {
+	if d == nil {
+		return "--"
+	}
+	if *d > 0 {
+		return fmt.Sprintf("+%d", *d)
+	}
+	return fmt.Sprintf("%d", *d)
+}
+
+// DeltaClass highlights a growing SMART error counter.
+func DeltaClass(d *int64) string {
+	if d != nil && *d > 0 {
+		return "text-warn"
+	}
+	return "text-dim"
+}
+
+// EnduranceLifeDisplay returns the projected remaining SSD life, e.g.
+// "~54 months (Apr 2031)", or why there is none.
+func EnduranceLifeDisplay(e *model.Endurance) string {
+	months, ok := e.MonthsLeft(time.Now())
+	switch {
+	case e == nil:
+		return "--"
+	case !ok:
+		return "No measurable wear"
+	case e.WearUsedPct >= 100:
+		return "Rated endurance used up"
+	case months < 1:
+		return fmt.Sprintf("< 1 month (%s)", e.EndOfLife.Format("Jan 2006"))
+	default:
+		return fmt.Sprintf("~%.0f months (%s)", months, e.EndOfLife.Format("Jan 2006"))
+	}
+}
+
+// EnduranceClass highlights an SSD whose projected life is below the
+// disk_endurance_low alert threshold.
+func EnduranceClass(e *model.Endurance) string {
+	if months, ok := e.MonthsLeft(time.Now()); ok && months < EnduranceLowMonths {
+		return "text-crit"
+	}
+	return ""
+}
+
+// TBPerDayDisplay formats a write rate in TB/day, or "--" if unknown.
+func TBPerDayDisplay(rate *float64) string {
+	if rate == nil {
+		return "--"
+	}
+	return fmt.Sprintf("%.2f TB/day", *rate)
+}
+
+// SelfTestDisplay summarizes a disk's newest finished self-test, e.g.
+// "long passed, 12d ago". Disks without a readable log show "--"; disks
+// whose log is empty show "never".
+func SelfTestDisplay(disk *model.Disk) string {
+	if disk.SelfTestLog == nil {
+		return "--"
+	}
+	t := disk.SelfTestLog.Latest()
+	if t == nil {
+		return "never"
+	}
+	if t.Time == nil {
+		return fmt.Sprintf("%s %s", t.Type, t.Result)
+	}
+	return fmt.Sprintf("%s %s, %s ago", t.Type, t.Result, FormatAge(t.Time.Unix()))
+}
+
+// SelfTestClass highlights a failed newest self-test, and a disk whose log
+// holds no completed long test.
+func SelfTestClass(disk *model.Disk) string {
+	log := disk.SelfTestLog
+	switch {
+	case log == nil:
+		return "text-dim"
+	case log.Latest() != nil && log.Latest().Result == "failed":
+		return "text-crit"
+	case log.LatestLong() == nil:
+		return "text-warn"
+	default:
+		return ""
+	}
+}
+
+// SelfTestSortValue sorts disks by when their newest self-test ran, oldest
+// (or never) first.
+func SelfTestSortValue(disk *model.Disk) string {
+	if disk.SelfTestLog != nil {
+		if t := disk.SelfTestLog.Latest(); t != nil && t.Time != nil {
+			return fmt.Sprintf("%d", t.Time.Unix())
+		}
+	}
+	return "0"
+}
+
+// SelfTestResultClass colours a self-test result.
+func SelfTestResultClass(result string) string {
+	switch result {
+	case "passed":
+		return "text-ok"
+	case "failed":
+		return "text-crit"
+	default:
+		return "text-dim"
+	}
+}
+
+// SelfTestTimeDisplay returns when a self-test ran, or "--" if unknown.
+func SelfTestTimeDisplay(t model.SelfTest) string {
+	if t.Time == nil {
+		return "--"
+	}
+	return FormatTime(t.Time.Unix())
+}
+
+// InventoryList returns the disk inventory of all instances, ordered by
+// instance, node and device path.
+func InventoryList(inventory map[string][]*model.InventoryDisk) []*model.InventoryDisk {
+	instances := make([]string, 0, len(inventory))
+	for inst := range inventory {
+		instances = append(instances, inst)
+	}
+	sort.Strings(instances)
+	var list []*model.InventoryDisk
+	for _, inst := range instances {
+		list = append(list, inventory[inst]...)
+	}
+	return list
+}
+
+// InventoryState describes whether an inventory disk is still present.
+func InventoryState(d *model.InventoryDisk) string {
+	switch {
+	case d.ReplacedBy != "":
+		return "replaced"
+	case d.RemovedAt != nil:
+		return "removed"
+	case d.MissedPolls == 1:
+		return "missing 1 poll"
+	case d.MissedPolls > 1:
+		return fmt.Sprintf("missing %d polls", d.MissedPolls)
+	default:
+		return "present"
+	}
+}
+
+// InventoryStateClass returns a CSS chip class for an inventory disk: a disk
+// that vanished without a replacement is critical.
+func InventoryStateClass(d *model.InventoryDisk) string {
+	switch {
+	case d.ReplacedBy != "":
+		return "chip-unk"
+	case d.RemovedAt != nil:
+		return "chip-crit"
+	case d.MissedPolls > 0:
+		return "chip-warn"
+	default:
+		return "chip-ok"
+	}
+}
+
+// DiskEventClass returns a CSS chip class for a disk lifecycle event.
+func DiskEventClass(eventType string) string {
+	switch eventType {
+	case model.DiskEventRemoved:
+		return "chip-crit"
+	case model.DiskEventReplaced, model.DiskEventMoved:
+		return "chip-warn"
+	default:
+		return "chip-ok"
+	}
+}
+
+// DiskEventDetail describes where a moved disk came from or which disk a new
+// one replaced.
+func DiskEventDetail(e *model.DiskEvent) string {
+	switch e.Type {
+	case model.DiskEventMoved:
+		return fmt.Sprintf("from %s %s", e.PrevNode, e.PrevDevPath)
+	case model.DiskEventReplaced:
+		return "replaced S/N " + e.PrevSerial
+	default:
+		return ""
+	}
+}
+
+// SlotHistory lists the disks that have occupied one slot (a device path on
+// a node), oldest first.
+type SlotHistory struct {
+	Instance string
+	Node     string
+	DevPath  string
+	Disks    []*model.DiskEvent // the event that put each disk in the slot
+}
+
+// SlotHistories groups the events that put a disk into a slot (added, moved,
+// replaced) by slot. Only slots that have held more than one disk are
+// returned, ordered by node and device path. events is newest first, as
+// returned by the store.
+func SlotHistories(events []*model.DiskEvent) []SlotHistory {
+	type slotKey struct{ instance, node, devPath string }
+	slots := make(map[slotKey]*SlotHistory)
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if e.Type == model.DiskEventRemoved {
+			continue
+		}
+		k := slotKey{e.Instance, e.Node, e.DevPath}
+		h, ok := slots[k]
+		if !ok {
+			h = &SlotHistory{Instance: e.Instance, Node: e.Node, DevPath: e.DevPath}
+			slots[k] = h
+		}
+		if n := len(h.Disks); n > 0 && h.Disks[n-1].WWN == e.WWN {
+			continue // the same disk back in its slot
+		}
+		h.Disks = append(h.Disks, e)
+	}
+
+	var list []SlotHistory
+	for _, h := range slots {
+		if len(h.Disks) > 1 {
+			list = append(list, *h)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Instance != b.Instance {
+			return a.Instance < b.Instance
+		}
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		return a.DevPath < b.DevPath
+	})
+	return list
+}
+
 // TempDisplay returns temperature as string or "--" if nil.
 func TempDisplay(t *int) string {
 	if t == nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/darshan-rambhia/glint/internal/cache"
 	"github.com/darshan-rambhia/glint/internal/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatBytes(t *testing.T) {
@@ -94,6 +95,7 @@ func TestDiskStatusClass(t *testing.T) {
 	assert.Equal(t, "chip-warn", DiskStatusClass(model.StatusWarnScrutiny))
 	assert.Equal(t, "chip-unk", DiskStatusClass(model.StatusUnknown))
 	assert.Equal(t, "chip-warn", DiskStatusClass(model.StatusInternalError))
+	assert.Equal(t, "chip-warn", DiskStatusClass(model.StatusDegrading))
 	// Combined flags
 	assert.Equal(t, "chip-crit", DiskStatusClass(model.StatusFailedSmart|model.StatusWarnScrutiny))
 }
@@ -104,6 +106,59 @@ func TestGuestStatusClass(t *testing.T) {
 	assert.Equal(t, "chip-warn", GuestStatusClass("paused"))
 }
 
+func TestCephHealthClass(t *testing.T) {
+	assert.Equal(t, "chip-ok", CephHealthClass("HEALTH_OK"))
+	assert.Equal(t, "chip-warn", CephHealthClass("HEALTH_WARN"))
+	assert.Equal(t, "chip-crit", CephHealthClass("HEALTH_ERR"))
+	assert.Equal(t, "chip-unk", CephHealthClass(""))
+}
+
+func TestCephHealthLabel(t *testing.T) {
+	assert.Equal(t, "WARN", CephHealthLabel("HEALTH_WARN"))
+	assert.Equal(t, "unknown", CephHealthLabel("unknown"))
+}
+
+func TestSortedCephList(t *testing.T) {
+	list := SortedCephList(map[string]*model.CephStatus{
+		"b": {Instance: "b"},
+		"a": {Instance: "a"},
+	})
+	assert.Len(t, list, 2)
+	assert.Equal(t, "a", list[0].Instance)
+	assert.Equal(t, "b", list[1].Instance)
+}
+
+func TestCephQuorumSummary(t *testing.T) {
+	assert.Equal(t, "2/3", CephQuorumSummary([]model.CephMonitor{
+		{Name: "a", InQuorum: true}, {Name: "b", InQuorum: true}, {Name: "c"},
+	}))
+	assert.Equal(t, "0/0", CephQuorumSummary(nil))
+}
+
+func TestCephOSDStatusClass(t *testing.T) {
+	assert.Equal(t, "chip-ok", CephOSDStatusClass(model.CephOSD{Status: "up", In: true}))
+	assert.Equal(t, "chip-warn", CephOSDStatusClass(model.CephOSD{Status: "up"}))
+	assert.Equal(t, "chip-crit", CephOSDStatusClass(model.CephOSD{Status: "down", In: true}))
+}
+
+func TestSortedClusterList(t *testing.T) {
+	list := SortedClusterList(map[string]*model.ClusterStatus{
+		"z": {Instance: "z"},
+		"a": {Instance: "a"},
+	})
+	assert.Len(t, list, 2)
+	assert.Equal(t, "a", list[0].Instance)
+}
+
+func TestHAStateClass(t *testing.T) {
+	assert.Equal(t, "chip-ok", HAStateClass("started"))
+	assert.Equal(t, "chip-ok", HAStateClass("stopped"))
+	assert.Equal(t, "chip-crit", HAStateClass("error"))
+	assert.Equal(t, "chip-crit", HAStateClass("fence"))
+	assert.Equal(t, "chip-warn", HAStateClass("recovery"))
+	assert.Equal(t, "chip-unk", HAStateClass(""))
+}
+
 func TestBackupStatusLabel(t *testing.T) {
 	now := time.Now()
 	assert.Equal(t, "Ok", BackupStatusLabel(now.Add(-10*time.Hour).Unix(), 36))
@@ -208,6 +263,84 @@ func TestAllTasksSorted(t *testing.T) {
 	assert.Empty(t, AllTasksSorted(nil))
 }
 
+func TestAllPVETasksSorted(t *testing.T) {
+	tasks := map[string][]*model.PVETask{
+		"a": {{UPID: "1", StartTime: 100}},
+		"b": {{UPID: "2", StartTime: 200}},
+	}
+	sorted := AllPVETasksSorted(tasks)
+	require.Len(t, sorted, 2)
+	assert.Equal(t, "2", sorted[0].UPID)
+	assert.Empty(t, AllPVETasksSorted(nil))
+}
+
+func TestBuildEvents(t *testing.T) {
+	end := int64(1100)
+	pbs := map[string][]*model.PBSTask{
+		"pbs1": {
+			{PBSInstance: "pbs1", Type: "verify", StartTime: 1000, EndTime: &end, Status: "OK"},
+			{PBSInstance: "pbs1", Type: "gc", StartTime: 3000, EndTime: &end, Status: "Error: out of space"},
+		},
+	}
+	pve := []*model.PVETask{
+		{Instance: "pve1", Node: "pve", Type: "qmigrate", ID: "100", StartTime: 2000, EndTime: &end, Status: "migration aborted"},
+		{Instance: "pve1", Node: "pve", Type: "qmstart", ID: "101", StartTime: 4000},
+		{Instance: "pve1", Node: "pve", Type: "vzsnapshot", ID: "102", StartTime: 500, EndTime: &end, Status: "WARNINGS: 1"},
+	}
+
+	all := BuildEvents(pbs, pve, EventsFilter{})
+	require.Len(t, all, 5)
+	assert.Equal(t, "qmstart", all[0].Type) // newest first
+	assert.Equal(t, "pve", all[0].Source)
+	assert.Equal(t, "gc", all[1].Type)
+	assert.Equal(t, "pbs", all[1].Source)
+
+	assert.Len(t, BuildEvents(pbs, pve, EventsFilter{Source: "pbs"}), 2)
+	assert.Len(t, BuildEvents(pbs, pve, EventsFilter{Source: "pve"}), 3)
+
+	byType := BuildEvents(pbs, pve, EventsFilter{Type: "qmigrate"})
+	require.Len(t, byType, 1)
+	assert.Equal(t, "100", byType[0].ID)
+
+	failed := BuildEvents(pbs, pve, EventsFilter{Status: "failed"})
+	require.Len(t, failed, 2)
+	assert.Equal(t, "gc", failed[0].Type)
+	assert.Equal(t, "qmigrate", failed[1].Type)
+
+	running := BuildEvents(pbs, pve, EventsFilter{Status: "running"})
+	require.Len(t, running, 1)
+	assert.Equal(t, "qmstart", running[0].Type)
+
+	assert.Empty(t, BuildEvents(nil, nil, EventsFilter{}))
+}
+
+func TestEventTypes(t *testing.T) {
+	pbs := map[string][]*model.PBSTask{"pbs1": {{Type: "verify"}, {Type: "gc"}}}
+	pve := []*model.PVETask{{Type: "qmigrate"}, {Type: "gc"}}
+	assert.Equal(t, []string{"gc", "qmigrate", "verify"}, EventTypes(pbs, pve))
+	assert.Empty(t, EventTypes(nil, nil))
+}
+
+func TestEventDuration(t *testing.T) {
+	end := int64(1000)
+	e := TaskEvent{StartTime: 900, EndTime: &end}
+	assert.Equal(t, int64(100), EventDurationSeconds(e))
+	assert.Contains(t, EventDuration(e), "s")
+
+	running := TaskEvent{StartTime: 900}
+	assert.Equal(t, int64(-1), EventDurationSeconds(running))
+	assert.Equal(t, "running", EventDuration(running))
+}
+
+func TestEventStatusClass(t *testing.T) {
+	assert.Equal(t, "chip-crit", EventStatusClass(TaskEvent{Source: "pbs", Status: "Error: x"}))
+	assert.Equal(t, "chip-warn", EventStatusClass(TaskEvent{Source: "pbs", Status: "unexpected"}))
+	assert.Equal(t, "chip-ok", EventStatusClass(TaskEvent{Source: "pve", Status: "OK"}))
+	assert.Equal(t, "chip-ok", EventStatusClass(TaskEvent{Source: "pve", Status: ""}))
+	assert.Equal(t, "chip-warn", EventStatusClass(TaskEvent{Source: "pve", Status: "WARNINGS: 2"}))
+	assert.Equal(t, "chip-crit", EventStatusClass(TaskEvent{Source: "pve", Status: "migration aborted"}))
+}
+
 func TestTaskDuration(t *testing.T) {
 	end := int64(1000)
 	task := &model.PBSTask{StartTime: 900, EndTime: &end}
@@ -260,12 +393,164 @@ func TestWearoutDisplay(t *testing.T) {
 	assert.Equal(t, "--", WearoutDisplay(nil))
 }
 
+func TestDeltaDisplay(t *testing.T) {
+	up, flat, down := int64(8), int64(0), int64(-4)
+	assert.Equal(t, "+8", DeltaDisplay(&up))
+	assert.Equal(t, "0", DeltaDisplay(&flat))
+	assert.Equal(t, "-4", DeltaDisplay(&down))
+	assert.Equal(t, "--", DeltaDisplay(nil))
+
+	assert.Equal(t, "text-warn", DeltaClass(&up))
+	assert.Equal(t, "text-dim", DeltaClass(&flat))
+	assert.Equal(t, "text-dim", DeltaClass(nil))
+}
+
+func TestEnduranceLifeDisplay(t *testing.T) {
+	eol := time.Now().Add(400 * 24 * time.Hour)
+	assert.Equal(t, fmt.Sprintf("~13 months (%s)", eol.Format("Jan 2006")),
+		EnduranceLifeDisplay(&model.Endurance{WearUsedPct: 40, EndOfLife: &eol}))
+
+	soon := time.Now().Add(10 * 24 * time.Hour)
+	assert.Equal(t, fmt.Sprintf("< 1 month (%s)", soon.Format("Jan 2006")),
+		EnduranceLifeDisplay(&model.Endurance{WearUsedPct: 99, EndOfLife: &soon}))
+
+	now := time.Now()
+	assert.Equal(t, "Rated endurance used up", EnduranceLifeDisplay(&model.Endurance{WearUsedPct: 100, EndOfLife: &now}))
+	assert.Equal(t, "No measurable wear", EnduranceLifeDisplay(&model.Endurance{}))
+	assert.Equal(t, "--", EnduranceLifeDisplay(nil))
+}
+
+func TestEnduranceClass(t *testing.T) {
+	soon := time.Now().Add(30 * 24 * time.Hour)
+	later := time.Now().Add(365 * 24 * time.Hour)
+	assert.Equal(t, "text-crit", EnduranceClass(&model.Endurance{EndOfLife: &soon}))
+	assert.Empty(t, EnduranceClass(&model.Endurance{EndOfLife: &later}))
+	assert.Empty(t, EnduranceClass(&model.Endurance{}))
+	assert.Empty(t, EnduranceClass(nil))
+}
+
+func TestTBPerDayDisplay(t *testing.T) {
+	rate := 0.5
+	assert.Equal(t, "0.50 TB/day", TBPerDayDisplay(&rate))
+	assert.Equal(t, "--", TBPerDayDisplay(nil))
+}
+
 func TestTempDisplay(t *testing.T) {
 	temp := 38
 	assert.Equal(t, "38C", TempDisplay(&temp))
 	assert.Equal(t, "--", TempDisplay(nil))
 }
 
+func TestDiskTempClass(t *testing.T) {
+	assert.Equal(t, "text-warn", DiskTempClass(&model.Disk{DiskType: "hdd", Temperature: new(50)}))
+	assert.Empty(t, DiskTempClass(&model.Disk{DiskType: "ssd", Temperature: new(50)}))
+	assert.Equal(t, "text-warn", DiskTempClass(&model.Disk{DiskType: "nvme", Temperature: new(72)}))
+	assert.Empty(t, DiskTempClass(&model.Disk{DiskType: "hdd"}))
+}
+
+func TestSelfTestDisplay(t *testing.T) {
+	ran := time.Now().Add(-50 * time.Hour)
+	disk := &model.Disk{SelfTestLog: &model.SelfTestLog{Tests: []model.SelfTest{
+		{Type: "short", Result: "in_progress"},
+		{Type: "long", Result: "passed", Time: &ran},
+	}}}
+	assert.Equal(t, "long passed, 2d ago", SelfTestDisplay(disk))
+	assert.Empty(t, SelfTestClass(disk))
+	assert.Equal(t, fmt.Sprintf("%d", ran.Unix()), SelfTestSortValue(disk))
+
+	disk.SelfTestLog.Tests = []model.SelfTest{{Type: "short", Result: "failed"}}
+	assert.Equal(t, "short failed", SelfTestDisplay(disk), "undated")
+	assert.Equal(t, "text-crit", SelfTestClass(disk))
+	assert.Equal(t, "0", SelfTestSortValue(disk))
+
+	disk.SelfTestLog.Tests = []model.SelfTest{{Type: "short", Result: "passed"}}
+	assert.Equal(t, "text-warn", SelfTestClass(disk), "no long test")
+
+	disk.SelfTestLog.Tests = nil
+	assert.Equal(t, "never", SelfTestDisplay(disk))
+
+	disk.SelfTestLog = nil
+	assert.Equal(t, "--", SelfTestDisplay(disk))
+	assert.Equal(t, "text-dim", SelfTestClass(disk))
+}
+
+func TestInventoryState(t *testing.T) {
+	removed := time.Now()
+	tests := []struct {
+		disk  model.InventoryDisk
+		state string
+		class string
+	}{
+		{model.InventoryDisk{}, "present", "chip-ok"},
+		{model.InventoryDisk{MissedPolls: 1}, "missing 1 poll", "chip-warn"},
+		{model.InventoryDisk{MissedPolls: 3}, "missing 3 polls", "chip-warn"},
+		{model.InventoryDisk{MissedPolls: 2, RemovedAt: &removed}, "removed", "chip-crit"},
+		{model.InventoryDisk{MissedPolls: 1, RemovedAt: &removed, ReplacedBy: "wwn-new"}, "replaced", "chip-unk"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.state, InventoryState(&tt.disk))
+		assert.Equal(t, tt.class, InventoryStateClass(&tt.disk), tt.state)
+	}
+}
+
+func TestInventoryList(t *testing.T) {
+	list := InventoryList(map[string][]*model.InventoryDisk{
+		"b": {{WWN: "b1"}},
+		"a": {{WWN: "a1"}, {WWN: "a2"}},
+	})
+	require.Len(t, list, 3)
+	assert.Equal(t, "a1", list[0].WWN)
+	assert.Equal(t, "b1", list[2].WWN)
+}
+
+func TestDiskEventDetail(t *testing.T) {
+	assert.Equal(t, "from pve2 /dev/sdb", DiskEventDetail(&model.DiskEvent{Type: model.DiskEventMoved, PrevNode: "pve2", PrevDevPath: "/dev/sdb"}))
+	assert.Equal(t, "replaced S/N WD-1", DiskEventDetail(&model.DiskEvent{Type: model.DiskEventReplaced, PrevSerial: "WD-1"}))
+	assert.Empty(t, DiskEventDetail(&model.DiskEvent{Type: model.DiskEventAdded}))
+	assert.Equal(t, "chip-crit", DiskEventClass(model.DiskEventRemoved))
+}
+
+func TestSlotHistories(t *testing.T) {
+	ev := func(ts int64, typ, wwn, node, devPath string) *model.DiskEvent {
+		return &model.DiskEvent{Timestamp: ts, Type: typ, WWN: wwn, Instance: "main", Node: node, DevPath: devPath}
+	}
+	// Newest first, as the store returns them
+	histories := SlotHistories([]*model.DiskEvent{
+		ev(600, model.DiskEventAdded, "b", "pve", "/dev/sdb"),
+		ev(500, model.DiskEventRemoved, "b", "pve", "/dev/sdb"),
+		ev(400, model.DiskEventReplaced, "c", "pve", "/dev/sda"),
+		ev(300, model.DiskEventRemoved, "a", "pve", "/dev/sda"),
+		ev(200, model.DiskEventMoved, "a", "pve", "/dev/sda"),
+		ev(100, model.DiskEventAdded, "b", "pve", "/dev/sdb"),
+		ev(100, model.DiskEventAdded, "a", "pve2", "/dev/sda"),
+	})
+	// /dev/sdb only ever held b; pve2 /dev/sda only a
+	require.Len(t, histories, 1)
+	h := histories[0]
+	assert.Equal(t, "pve", h.Node)
+	assert.Equal(t, "/dev/sda", h.DevPath)
+	require.Len(t, h.Disks, 2)
+	assert.Equal(t, "a", h.Disks[0].WWN)
+	assert.Equal(t, "c", h.Disks[1].WWN)
+}
+
+func TestHottestDisks(t *testing.T) {
+	disks := map[string]*model.Disk{
+		"a": {DevPath: "/dev/sda", Temperature: new(41)},
+		"b": {DevPath: "/dev/sdb", Temperature: new(55)},
+		"c": {DevPath: "/dev/sdc"},
+		"d": {DevPath: "/dev/nvme0n1", Temperature: new(48)},
+		"e": {DevPath: "/dev/sde", Temperature: new(48)},
+	}
+	var paths []string
+	for _, d := range HottestDisks(disks, 3) {
+		paths = append(paths, d.DevPath)
+	}
+	assert.Equal(t, []string{"/dev/sdb", "/dev/nvme0n1", "/dev/sde"}, paths)
+	assert.Len(t, HottestDisks(disks, 10), 4)
+	assert.Empty(t, HottestDisks(nil, 3))
+}
+
 func TestNodeTempDisplay(t *testing.T) {
 	temp := 52.3
 	assert.Equal(t, "52C", NodeTempDisplay(&temp))
@@ -471,6 +756,175 @@ func TestLatestBackupTime(t *testing.T) {
 	assert.Equal(t, int64(0), LatestBackupTime(nil, 101))
 }
 
+func TestLatestBackupTimeOf(t *testing.T) {
+	assert.Equal(t, int64(0), LatestBackupTimeOf(nil))
+	assert.Equal(t, int64(2000), LatestBackupTimeOf([]*model.Backup{{BackupTime: 1000}, {BackupTime: 2000}}))
+}
+
+func TestPVEBackupFor(t *testing.T) {
+	pve := map[string]map[int]*model.PVEGuestBackup{
+		"c1": {100: {VMID: 100, LastSuccess: 1000}},
+	}
+	assert.Equal(t, int64(1000), PVEBackupFor(pve, &model.Guest{ClusterID: "c1", VMID: 100}).LastSuccess)
+	assert.Zero(t, PVEBackupFor(pve, &model.Guest{ClusterID: "c1", VMID: 101}).VMID)
+	assert.Zero(t, PVEBackupFor(nil, &model.Guest{ClusterID: "c2", VMID: 100}).VMID)
+}
+
+func TestAllBackupJobsSorted(t *testing.T) {
+	jobs := map[string][]*model.PVEBackupJob{
+		"pve2": {{Instance: "pve2", ID: "a"}},
+		"pve1": {{Instance: "pve1", ID: "b"}, {Instance: "pve1", ID: "a"}},
+	}
+	list := AllBackupJobsSorted(jobs)
+	require.Len(t, list, 3)
+	assert.Equal(t, "pve1", list[0].Instance)
+	assert.Equal(t, "a", list[0].ID)
+	assert.Equal(t, "b", list[1].ID)
+	assert.Equal(t, "pve2", list[2].Instance)
+	assert.Empty(t, AllBackupJobsSorted(nil))
+}
+
+func TestBackupJobSelection(t *testing.T) {
+	assert.Equal(t, "all", BackupJobSelection(&model.PVEBackupJob{All: true}))
+	assert.Equal(t, "all except 100, 101", BackupJobSelection(&model.PVEBackupJob{All: true, Exclude: []int{100, 101}}))
+	assert.Equal(t, "pool prod", BackupJobSelection(&model.PVEBackupJob{Pool: "prod"}))
+	assert.Equal(t, "100, 101 on pve2", BackupJobSelection(&model.PVEBackupJob{VMIDs: []int{100, 101}, Node: "pve2"}))
+}
+
+func TestUncoveredGuests(t *testing.T) {
+	guests := map[string]map[int]*model.Guest{
+		"c1": {
+			100: {VMID: 100, ClusterID: "c1"},
+			101: {VMID: 101, ClusterID: "c1"},
+			102: {VMID: 102, ClusterID: "c1"},
+		},
+		"c2": {200: {VMID: 200, ClusterID: "c2"}},
+	}
+	pve := map[string]map[int]*model.PVEGuestBackup{
+		"c1": {
+			100: {VMID: 100, Jobs: []string{"nightly"}},
+			101: {VMID: 101},
+			102: {VMID: 102},
+		},
+	}
+	list := UncoveredGuests(guests, pve)
+	require.Len(t, list, 2)
+	assert.Equal(t, 101, list[0].VMID)
+	assert.Equal(t, 102, list[1].VMID)
+
+	// No job data at all → nothing reported
+	assert.Empty(t, UncoveredGuests(guests, nil))
+}
+
+func TestFindGuest(t *testing.T) {
+	guests := map[string]map[int]*model.Guest{
+		"c1": {101: {VMID: 101, Instance: "pve1", ClusterID: "c1"}},
+		"c2": {101: {VMID: 101, Instance: "pve2", ClusterID: "c2"}},
+	}
+	assert.Equal(t, "c2", FindGuest(guests, "pve2", 101).ClusterID)
+	assert.Nil(t, FindGuest(guests, "pve1", 999))
+	assert.Nil(t, FindGuest(guests, "pve3", 101))
+}
+
+func TestGuestConfigFor(t *testing.T) {
+	configs := map[string]map[int]*model.GuestConfig{"c1": {101: {VMID: 101, Cores: 2}}}
+	assert.Equal(t, 2, GuestConfigFor(configs, &model.Guest{ClusterID: "c1", VMID: 101}).Cores)
+	assert.Nil(t, GuestConfigFor(configs, &model.Guest{ClusterID: "c1", VMID: 102}))
+	assert.Nil(t, GuestConfigFor(configs, &model.Guest{ClusterID: "c2", VMID: 101}))
+}
+
+func TestGuestMetricSeries(t *testing.T) {
+	rate := func(v float64) *float64 { return &v }
+	history := []model.GuestSnapshot{
+		{Timestamp: 1000, CPUPct: 10, MemUsed: 50, MemTotal: 100, DiskUsed: 1000}, // first sample: no rates
+		{Timestamp: 1060, CPUPct: 20, MemUsed: 25, MemTotal: 100, DiskUsed: 2000,
+			NetInRate: rate(100), NetOutRate: rate(10), DiskReadRate: rate(4096), DiskWriteRate: rate(8192)},
+		{Timestamp: 1120, CPUPct: 30, MemUsed: 75, MemTotal: 100, DiskUsed: 3000,
+			NetOutRate: rate(20), DiskWriteRate: rate(0)}, // in counter reset
+	}
+
+	cpu := GuestMetricSeries(history, "cpu")
+	require.Len(t, cpu, 3)
+	assert.Equal(t, float64(20), cpu[1].Value)
+
+	mem := GuestMetricSeries(history, "memory")
+	assert.Equal(t, float64(75), mem[2].Value)
+
+	disk := GuestMetricSeries(history, "disk")
+	assert.Equal(t, float64(3000), disk[2].Value)
+
+	netin := GuestMetricSeries(history, "netin")
+	require.Len(t, netin, 1, "samples without a rate are skipped")
+	assert.Equal(t, int64(1060), netin[0].Timestamp)
+	assert.Equal(t, float64(100), netin[0].Value)
+
+	netout := GuestMetricSeries(history, "netout")
+	require.Len(t, netout, 2)
+	assert.Equal(t, float64(20), netout[1].Value)
+
+	diskread := GuestMetricSeries(history, "diskread")
+	require.Len(t, diskread, 1)
+	assert.Equal(t, float64(4096), diskread[0].Value)
+
+	diskwrite := GuestMetricSeries(history, "diskwrite")
+	require.Len(t, diskwrite, 2)
+	assert.Zero(t, diskwrite[1].Value)
+
+	assert.Nil(t, GuestMetricSeries(history, "bogus"))
+	assert.Empty(t, GuestMetricSeries(nil, "cpu"))
+}
+
+func TestFormatGuestMetric(t *testing.T) {
+	pts := []model.SparklinePoint{{Value: 1}, {Value: 42.4}}
+	assert.Equal(t, "—", FormatGuestMetric(nil, "cpu"))
+	assert.Equal(t, "42%", FormatGuestMetric(pts, "cpu"))
+	assert.Equal(t, "42%", FormatGuestMetric(pts, "memory"))
+	assert.Equal(t, "42 B/s", FormatGuestMetric(pts, "netin"))
+	assert.Equal(t, "42 B/s", FormatGuestMetric(pts, "diskwrite"))
+	assert.Equal(t, "2.0 KB", FormatGuestMetric([]model.SparklinePoint{{Value: 2048}}, "disk"))
+}
+
+func TestGuestMetricLabel(t *testing.T) {
+	assert.Equal(t, "CPU", GuestMetricLabel("cpu"))
+	assert.Equal(t, "Memory", GuestMetricLabel("memory"))
+	assert.Equal(t, "Disk used", GuestMetricLabel("disk"))
+	assert.Equal(t, "Net in", GuestMetricLabel("netin"))
+	assert.Equal(t, "Net out", GuestMetricLabel("netout"))
+	assert.Equal(t, "Disk read", GuestMetricLabel("diskread"))
+	assert.Equal(t, "Disk write", GuestMetricLabel("diskwrite"))
+	assert.Equal(t, "other", GuestMetricLabel("other"))
+}
+
+func TestWindowLabel(t *testing.T) {
+	assert.Equal(t, "6h", WindowLabel(6))
+	assert.Equal(t, "48h", WindowLabel(48))
+	assert.Equal(t, "7d", WindowLabel(7*24))
+	assert.Equal(t, "30d", WindowLabel(30*24))
+	assert.Equal(t, "170h", WindowLabel(170))
+}
+
+func TestFormatRate(t *testing.T) {
+	assert.Equal(t, "512 B/s", FormatRate(512))
+	assert.Equal(t, "1.5 MB/s", FormatRate(1.5*1024*1024))
+}
+
+func TestGuestTasks(t *testing.T) {
+	tasks := []*model.PVETask{
+		{Instance: "pve1", ID: "101", UPID: "a", StartTime: 100},
+		{Instance: "pve1", ID: "101", UPID: "b", StartTime: 300},
+		{Instance: "pve1", ID: "101", UPID: "c", StartTime: 200},
+		{Instance: "pve1", ID: "102", UPID: "d", StartTime: 400},
+		{Instance: "pve2", ID: "101", UPID: "e", StartTime: 500},
+	}
+	list := GuestTasks(tasks, "pve1", 101, 2)
+	require.Len(t, list, 2)
+	assert.Equal(t, "b", list[0].UPID)
+	assert.Equal(t, "c", list[1].UPID)
+
+	assert.Len(t, GuestTasks(tasks, "pve1", 101, 10), 3)
+	assert.Empty(t, GuestTasks(tasks, "pve1", 999, 10))
+}
+
 func TestIntPtrSortValue(t *testing.T) {
 	v := 42
 	assert.Equal(t, "42", IntPtrSortValue(&v))
@@ -488,6 +942,38 @@ func TestTaskDurationSeconds(t *testing.T) {
 	assert.Equal(t, int64(-1), TaskDurationSeconds(running))
 }
 
+func TestRiskRanking(t *testing.T) {
+	disks := map[string]*model.Disk{
+		"a": {Instance: "pve1", Node: "n1", DevPath: "/dev/sdb", Risk: &model.DiskRisk{AnnualFailurePct: 1.5}},
+		"b": {Instance: "pve1", Node: "n1", DevPath: "/dev/sda", Risk: &model.DiskRisk{AnnualFailurePct: 1.5}},
+		"c": {Instance: "pve1", Node: "n2", DevPath: "/dev/sda", Risk: &model.DiskRisk{AnnualFailurePct: 25}},
+		"d": {Instance: "pve1", Node: "n2", DevPath: "/dev/sdb", Risk: &model.DiskRisk{AnnualFailurePct: 12}},
+		"e": {Instance: "pve1", Node: "n2", DevPath: "/dev/sdc"},
+	}
+	list := RiskRanking(disks)
+	require.Len(t, list, 4)
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb", "/dev/sda", "/dev/sdb"},
+		[]string{list[0].DevPath, list[1].DevPath, list[2].DevPath, list[3].DevPath})
+	assert.Equal(t, "n2", list[0].Node)
+	assert.Equal(t, "n1", list[2].Node)
+
+	soon := ReplaceSoon(disks, 5)
+	require.Len(t, soon, 2)
+	assert.InDelta(t, 25, soon[0].Risk.AnnualFailurePct, 1e-9)
+	assert.Len(t, ReplaceSoon(disks, 1), 1)
+	assert.Empty(t, ReplaceSoon(map[string]*model.Disk{"a": disks["a"]}, 5))
+}
+
+func TestRiskDisplay(t *testing.T) {
+	assert.Equal(t, "--", RiskDisplay(nil))
+	assert.Equal(t, "12.3%", RiskDisplay(&model.DiskRisk{AnnualFailurePct: 12.34}))
+	assert.Equal(t, "-1", RiskSortValue(nil))
+	assert.Equal(t, "1.5000", RiskSortValue(&model.DiskRisk{AnnualFailurePct: 1.5}))
+	assert.Equal(t, "risk-high", DiskRiskClass(10))
+	assert.Equal(t, "risk-medium", DiskRiskClass(5))
+	assert.Equal(t, "risk-low", DiskRiskClass(1.5))
+}
+
 // ---------------------------------------------------------------------------
 // Benchmarks
 // ---------------------------------------------------------------------------